@@ -73,7 +73,73 @@ contract ContentStorage {
     event SponsorAdded(string indexed id, string name);
     event ContestantRegistered(string indexed contestId, string indexed contestantId);
     
-    constructor() {}
+    // ========== PHÂN QUYỀN (ACCESS CONTROL) ==========
+    // Vai trò được lưu on-chain để có thể kiểm toán; role = keccak256("<TÊN>_ROLE")
+    bytes32 public constant ADMIN_ROLE = keccak256("ADMIN_ROLE");
+    bytes32 public constant ORGANIZER_ROLE = keccak256("ORGANIZER_ROLE");
+    bytes32 public constant MODERATOR_ROLE = keccak256("MODERATOR_ROLE");
+    bytes32 public constant CONTESTANT_ROLE = keccak256("CONTESTANT_ROLE");
+    bytes32 public constant SPONSOR_ROLE = keccak256("SPONSOR_ROLE");
+    
+    mapping(bytes32 => mapping(address => bool)) private roles;
+    
+    event RoleGranted(bytes32 indexed role, address indexed account, address indexed sender);
+    event RoleRevoked(bytes32 indexed role, address indexed account, address indexed sender);
+    
+    modifier onlyAdmin() {
+        require(roles[ADMIN_ROLE][_msgSender()], "Caller is not an admin");
+        _;
+    }
+    
+    // ========== META-TRANSACTION (ERC-2771) ==========
+    // Forwarder được tin cậy gắn địa chỉ người ký vào 20 byte cuối calldata
+    address public immutable trustedForwarder;
+    
+    // Người tạo (người ký) của từng contest JSON
+    mapping(string => address) public contestCreators;
+    
+    constructor(address forwarder) {
+        trustedForwarder = forwarder;
+        // Ví deploy contract là admin đầu tiên
+        roles[ADMIN_ROLE][msg.sender] = true;
+        emit RoleGranted(ADMIN_ROLE, msg.sender, msg.sender);
+    }
+    
+    function isTrustedForwarder(address forwarder) public view returns (bool) {
+        return forwarder == trustedForwarder;
+    }
+    
+    // Địa chỉ người gọi thật: người ký nếu đi qua forwarder, msg.sender nếu gọi trực tiếp
+    function _msgSender() internal view returns (address sender) {
+        if (isTrustedForwarder(msg.sender) && msg.data.length >= 20) {
+            assembly {
+                sender := shr(96, calldataload(sub(calldatasize(), 20)))
+            }
+        } else {
+            sender = msg.sender;
+        }
+    }
+    
+    // Kiểm tra một địa chỉ có vai trò hay không
+    function hasRole(bytes32 role, address account) public view returns (bool) {
+        return roles[role][account];
+    }
+    
+    // Cấp vai trò cho một địa chỉ (chỉ admin)
+    function grantRole(bytes32 role, address account) public onlyAdmin {
+        if (!roles[role][account]) {
+            roles[role][account] = true;
+            emit RoleGranted(role, account, _msgSender());
+        }
+    }
+    
+    // Thu hồi vai trò của một địa chỉ (chỉ admin)
+    function revokeRole(bytes32 role, address account) public onlyAdmin {
+        if (roles[role][account]) {
+            roles[role][account] = false;
+            emit RoleRevoked(role, account, _msgSender());
+        }
+    }
     
     // Lưu nội dung mới
     function storeContent(string memory id, string memory title, string memory contentText, bool verified) public {
@@ -84,7 +150,7 @@ contract ContentStorage {
         contents[id] = Content({
             title: title,
             content: contentText,
-            creator: msg.sender,
+            creator: _msgSender(),
             timestamp: block.timestamp,
             verified: verified,
             exists: true
@@ -117,6 +183,25 @@ contract ContentStorage {
         return contentIds;
     }
     
+    // NEO PHIÊN BẢN NỘI DUNG WIKI
+    // Mỗi phiên bản được neo bằng JSON của bản ghi (tiêu đề, nội dung, danh mục, tag...) trong event,
+    // contract chỉ giữ hash keccak256 của từng phiên bản để đối chiếu khi kiểm toán.
+    // Chỉ backend (admin) hoặc moderator được neo, nên bài chưa được duyệt không thể lên chain.
+    mapping(string => bytes32[]) private contentRevisions;
+    
+    event ContentAnchored(string indexed id, uint256 revision, string jsonData, address sender);
+    
+    function anchorContent(string memory id, string memory jsonData) public {
+        require(roles[ADMIN_ROLE][_msgSender()] || roles[MODERATOR_ROLE][_msgSender()], "Caller cannot anchor content");
+        contentRevisions[id].push(keccak256(bytes(jsonData)));
+        emit ContentAnchored(id, contentRevisions[id].length, jsonData, _msgSender());
+    }
+    
+    // Hash của các phiên bản đã neo, theo thứ tự
+    function getContentRevisions(string memory id) public view returns (bytes32[] memory) {
+        return contentRevisions[id];
+    }
+    
     // QUẢN LÝ THÍ SINH
     
     // Thêm thí sinh mới
@@ -132,7 +217,7 @@ contract ContentStorage {
             id: id,
             name: name,
             details: details,
-            creator: msg.sender,
+            creator: _msgSender(),
             timestamp: block.timestamp,
             verified: verified,
             exists: true
@@ -161,6 +246,26 @@ contract ContentStorage {
         return contestantIds;
     }
     
+    // ========== LƯU THÍ SINH DẠNG JSON ==========
+    // Ví người tạo thí sinh nằm trong JSON (giao dịch do ví server gửi) và được dùng để
+    // trả giải thưởng, nên chỉ backend (admin) được ghi thí sinh.
+    event ContestantCreatedJson(string indexed id, string jsonData, address sender);
+    mapping(string => string) private contestantJsons;
+    
+    function createContestantJson(string memory id, string memory jsonData) public {
+        require(roles[ADMIN_ROLE][_msgSender()], "Caller cannot create contestants");
+        require(!contestants[id].exists && bytes(contestantJsons[id]).length == 0, "Contestant with this ID already exists");
+        require(bytes(jsonData).length > 0, "Contestant JSON is empty");
+        contestantJsons[id] = jsonData;
+        contestantIds.push(id);
+        emit ContestantCreatedJson(id, jsonData, _msgSender());
+    }
+    
+    // JSON của thí sinh, rỗng nếu chưa tạo
+    function getContestantJson(string memory id) public view returns (string memory) {
+        return contestantJsons[id];
+    }
+    
     // QUẢN LÝ CUỘC THI
     
     // Thêm cuộc thi mới
@@ -181,7 +286,7 @@ contract ContentStorage {
             description: description,
             startDate: startDate,
             endDate: endDate,
-            organizer: msg.sender,
+            organizer: _msgSender(),
             active: true,
             exists: true,
             imageURL: imageURL,
@@ -193,9 +298,14 @@ contract ContentStorage {
     }
     
     // ========== LƯU CONTEST DẠNG JSON (DỄ ĐỌC TRÊN EXPLORER) ==========
-    function createContestJson(string memory id, string memory jsonData) public {
+    // maxContestants (0: không giới hạn) và waitlist lặp lại luật đăng ký trong JSON để
+    // contract tự giữ số chỗ, kể cả khi nhiều giao dịch đăng ký cùng chờ đào
+    function createContestJson(string memory id, string memory jsonData, uint256 maxContestants, bool waitlist) public {
         require(bytes(contestJsons[id]).length == 0, "Contest with this ID already exists");
         contestJsons[id] = jsonData;
+        contestCreators[id] = _msgSender();
+        contestCapacity[id] = maxContestants;
+        contestWaitlist[id] = waitlist;
         contestIds.push(id);
         emit ContestCreatedJson(jsonData);
     }
@@ -237,7 +347,7 @@ contract ContentStorage {
             name: name,
             contactInfo: contactInfo,
             sponsorshipAmount: sponsorshipAmount,
-            walletAddress: msg.sender,
+            walletAddress: _msgSender(),
             exists: true
         });
         
@@ -389,4 +499,286 @@ contract ContentStorage {
         require(bytes(contestJsons[id]).length > 0, "Contest does not exist");
         contestJsons[id] = jsonData;
     }
+    
+    // ========== VÒNG ĐỜI CUỘC THI ==========
+    // Trạng thái hiện tại của từng contest JSON: draft, open, running, judging, closed, cancelled.
+    // Rỗng nghĩa là chưa chuyển lần nào (trạng thái nằm trong JSON lúc tạo).
+    mapping(string => string) public contestStates;
+    
+    event ContestStateChanged(string indexed id, string from, string to, string reason, address sender);
+    
+    // Ghi nhận một lần chuyển trạng thái; backend kiểm tra luật chuyển, contract chống ghi đè
+    // bằng cách yêu cầu `from` khớp trạng thái đang lưu
+    function setContestState(string memory id, string memory from, string memory to, string memory reason) public {
+        require(bytes(contestJsons[id]).length > 0, "Contest does not exist");
+        require(_canManageContest(id), "Caller cannot change this contest");
+        string memory current = contestStates[id];
+        require(
+            bytes(current).length == 0 || keccak256(bytes(current)) == keccak256(bytes(from)),
+            "Contest state has changed"
+        );
+        contestStates[id] = to;
+        emit ContestStateChanged(id, from, to, reason, _msgSender());
+    }
+    
+    // Người tạo contest hoặc admin được quản lý contest
+    function _canManageContest(string memory id) internal view returns (bool) {
+        address sender = _msgSender();
+        return contestCreators[id] == sender || roles[ADMIN_ROLE][sender];
+    }
+    
+    // ========== ĐĂNG KÝ VÀO CONTEST JSON ==========
+    // Danh sách thí sinh theo thứ tự đăng ký. Backend kiểm tra thời gian và điều kiện;
+    // contract chống đăng ký trùng và tự xếp thí sinh vào danh sách chờ khi đã đủ người.
+    uint8 constant ROSTER_REGISTERED = 1;
+    uint8 constant ROSTER_WAITLISTED = 2;
+    
+    mapping(string => string[]) private contestRoster;
+    mapping(string => mapping(string => uint8)) private rosterStatus; // contestId => (contestantId => trạng thái)
+    mapping(string => mapping(string => uint)) private rosterIndex;   // vị trí lần đăng ký gần nhất + 1
+    mapping(string => uint256) public contestCapacity;                // contestId => số thí sinh tối đa (0: không giới hạn)
+    mapping(string => bool) public contestWaitlist;                   // contestId => có danh sách chờ
+    mapping(string => uint256) public registeredCount;                // contestId => số thí sinh đã đăng ký
+    // Vị trí trong contestRoster bắt đầu tìm người chờ kế tiếp: mọi mục trước đó đã đăng ký,
+    // đã rút lui hoặc đã được chuyển lên, nên mỗi mục chỉ bị duyệt qua một lần
+    mapping(string => uint) private waitlistHead;
+    
+    event ContestantJoined(string indexed contestId, string contestantId, bool waitlisted, address sender);
+    // Bia mộ: đăng ký cũ vẫn nằm trong lịch sử event, chỉ trạng thái hiện tại bị xóa
+    event ContestantWithdrawn(string indexed contestId, string contestantId, string reason, bool removed, address sender);
+    event ContestantPromoted(string indexed contestId, string contestantId, address sender);
+    
+    // Thêm thí sinh vào contest, hoặc vào danh sách chờ khi contest đã đủ người
+    function joinContest(string memory id, string memory contestantId) public {
+        bool waitlisted = _join(id, contestantId);
+        emit ContestantJoined(id, contestantId, waitlisted, _msgSender());
+    }
+    
+    function _join(string memory id, string memory entryId) internal returns (bool waitlisted) {
+        require(bytes(contestJsons[id]).length > 0, "Contest does not exist");
+        require(_canManageContest(id), "Caller cannot change this contest");
+        require(rosterStatus[id][entryId] == 0, "Contestant already registered");
+        
+        uint256 capacity = contestCapacity[id];
+        waitlisted = capacity > 0 && registeredCount[id] >= capacity;
+        require(!waitlisted || contestWaitlist[id], "Contest is full");
+        
+        if (waitlisted) {
+            rosterStatus[id][entryId] = ROSTER_WAITLISTED;
+        } else {
+            rosterStatus[id][entryId] = ROSTER_REGISTERED;
+            registeredCount[id]++;
+        }
+        contestRoster[id].push(entryId);
+        rosterIndex[id][entryId] = contestRoster[id].length;
+    }
+    
+    // Thí sinh rút lui (removed = false) hoặc bị ban tổ chức loại (removed = true).
+    // Khi một chỗ trống ra, người đầu danh sách chờ được chuyển lên trong cùng giao dịch.
+    function leaveContest(
+        string memory id,
+        string memory contestantId,
+        string memory reason,
+        bool removed
+    ) public {
+        require(_canManageContest(id), "Caller cannot change this contest");
+        uint8 status = rosterStatus[id][contestantId];
+        require(status != 0, "Contestant is not registered");
+        
+        rosterStatus[id][contestantId] = 0;
+        // Đội rút lui không còn được đăng ký nên các thành viên có thể lập đội khác
+        if (bytes(teamJsons[contestantId]).length > 0) {
+            delete teamJsons[contestantId];
+        }
+        emit ContestantWithdrawn(id, contestantId, reason, removed, _msgSender());
+        if (status != ROSTER_REGISTERED) {
+            return;
+        }
+        registeredCount[id]--;
+        
+        uint256 capacity = contestCapacity[id];
+        if (capacity > 0 && registeredCount[id] >= capacity) {
+            return;
+        }
+        // Thí sinh mới luôn được thêm vào cuối nên không bao giờ nằm trước waitlistHead
+        string[] storage all = contestRoster[id];
+        uint head = waitlistHead[id];
+        while (head < all.length) {
+            string storage next = all[head];
+            head++;
+            if (_isCurrentEntry(id, next, head - 1) && rosterStatus[id][next] == ROSTER_WAITLISTED) {
+                rosterStatus[id][next] = ROSTER_REGISTERED;
+                registeredCount[id]++;
+                emit ContestantPromoted(id, next, _msgSender());
+                break;
+            }
+        }
+        waitlistHead[id] = head;
+    }
+    
+    // Danh sách thí sinh hiện tại của contest kèm trạng thái (1: đã đăng ký, 2: chờ)
+    function getContestRoster(string memory id) public view returns (string[] memory ids, uint8[] memory statuses) {
+        string[] storage all = contestRoster[id];
+        uint count = 0;
+        for (uint i = 0; i < all.length; i++) {
+            if (_isCurrentEntry(id, all[i], i)) {
+                count++;
+            }
+        }
+        
+        ids = new string[](count);
+        statuses = new uint8[](count);
+        uint j = 0;
+        for (uint i = 0; i < all.length; i++) {
+            uint8 status = rosterStatus[id][all[i]];
+            if (_isCurrentEntry(id, all[i], i)) {
+                ids[j] = all[i];
+                statuses[j] = status;
+                j++;
+            }
+        }
+    }
+    
+    // Thí sinh đăng ký lại sau khi rút lui xuất hiện ở vị trí lần đăng ký mới nhất
+    function _isCurrentEntry(string memory id, string memory contestantId, uint i) internal view returns (bool) {
+        return rosterStatus[id][contestantId] != 0 && rosterIndex[id][contestantId] == i + 1;
+    }
+    
+    // ========== ĐĂNG KÝ THEO ĐỘI ==========
+    // Một đội chiếm một chỗ trong danh sách thí sinh, với ID của đội. JSON của đội
+    // chứa chữ ký chấp nhận của từng thành viên; backend đã kiểm tra chữ ký và số thành viên.
+    mapping(string => string) public teamJsons;
+    
+    event TeamRegistered(string indexed contestId, string teamId, string jsonData, bool waitlisted, address sender);
+    
+    function registerTeam(string memory id, string memory teamId, string memory jsonData) public {
+        require(bytes(teamJsons[teamId]).length == 0, "Team already registered");
+        
+        bool waitlisted = _join(id, teamId);
+        teamJsons[teamId] = jsonData;
+        emit TeamRegistered(id, teamId, jsonData, waitlisted, _msgSender());
+    }
+    
+    // ========== CHẤM ĐIỂM ==========
+    // Điểm của từng giám khảo được giữ kín off-chain trong lúc chấm. Khi ban tổ chức chốt,
+    // kết quả cùng toàn bộ phiếu chấm được ghi một lần duy nhất và không thể sửa.
+    mapping(string => string) public contestResults;
+    
+    event ResultsFinalized(string indexed contestId, string jsonData, address sender);
+    
+    function finalizeResults(string memory id, string memory jsonData) public {
+        require(_canManageContest(id), "Caller cannot change this contest");
+        require(keccak256(bytes(contestStates[id])) == keccak256(bytes("judging")), "Contest is not being judged");
+        require(bytes(contestResults[id]).length == 0, "Results already finalized");
+        
+        contestResults[id] = jsonData;
+        emit ResultsFinalized(id, jsonData, _msgSender());
+    }
+    
+    // ========== BỎ PHIẾU COMMIT-REVEAL ==========
+    // Mỗi ví bỏ một phiếu cho mỗi cuộc thi. Lúc commit chỉ ghi hash
+    // keccak256(abi.encodePacked(contestId, contestantId, salt, voter)) cùng chữ ký của người bỏ phiếu;
+    // lúc reveal contract tự kiểm tra thí sinh và salt có khớp hash. Backend quản lý thời hạn các giai đoạn.
+    struct Vote {
+        bytes32 commitment;
+        string signature;
+        string contestantId;
+        bytes32 salt;
+        uint256 committedAt;
+        uint256 revealedAt;
+    }
+    
+    mapping(string => mapping(address => Vote)) private votes; // contestId => (ví => phiếu)
+    mapping(string => address[]) private contestVoters;
+    
+    event VoteCommitted(string indexed contestId, address indexed voter, bytes32 commitment, address sender);
+    event VoteRevealed(string indexed contestId, address indexed voter, string contestantId, address sender);
+    
+    function commitVote(string memory id, address voter, bytes32 commitment, string memory signature) public {
+        require(bytes(contestJsons[id]).length > 0, "Contest does not exist");
+        require(_canManageContest(id), "Caller cannot change this contest");
+        require(commitment != bytes32(0), "Commitment is empty");
+        require(votes[id][voter].commitment == bytes32(0), "Wallet already voted");
+        
+        Vote storage vote = votes[id][voter];
+        vote.commitment = commitment;
+        vote.signature = signature;
+        vote.committedAt = block.timestamp;
+        contestVoters[id].push(voter);
+        emit VoteCommitted(id, voter, commitment, _msgSender());
+    }
+    
+    function revealVote(string memory id, address voter, string memory contestantId, bytes32 salt) public {
+        require(_canManageContest(id), "Caller cannot change this contest");
+        Vote storage vote = votes[id][voter];
+        require(vote.commitment != bytes32(0), "No vote committed");
+        require(vote.revealedAt == 0, "Vote already revealed");
+        require(keccak256(abi.encodePacked(id, contestantId, salt, voter)) == vote.commitment, "Reveal does not match commitment");
+        
+        vote.contestantId = contestantId;
+        vote.salt = salt;
+        vote.revealedAt = block.timestamp;
+        emit VoteRevealed(id, voter, contestantId, _msgSender());
+    }
+    
+    // Trả về mọi phiếu của cuộc thi theo thứ tự commit để ai cũng có thể kiểm phiếu lại
+    function getVotes(string memory id) public view returns (
+        address[] memory voters,
+        bytes32[] memory commitments,
+        string[] memory signatures,
+        string[] memory choices,
+        bytes32[] memory salts,
+        uint256[] memory committedAt,
+        uint256[] memory revealedAt
+    ) {
+        voters = contestVoters[id];
+        commitments = new bytes32[](voters.length);
+        signatures = new string[](voters.length);
+        choices = new string[](voters.length);
+        salts = new bytes32[](voters.length);
+        committedAt = new uint256[](voters.length);
+        revealedAt = new uint256[](voters.length);
+        for (uint i = 0; i < voters.length; i++) {
+            Vote storage vote = votes[id][voters[i]];
+            commitments[i] = vote.commitment;
+            signatures[i] = vote.signature;
+            choices[i] = vote.contestantId;
+            salts[i] = vote.salt;
+            committedAt[i] = vote.committedAt;
+            revealedAt[i] = vote.revealedAt;
+        }
+    }
+    
+    // ========== TÀI TRỢ CUỘC THI ==========
+    // Mỗi lần nhà tài trợ cam kết một khoản cho cuộc thi được lưu dưới dạng JSON.
+    // Hạng tài trợ và quyền hiển thị do backend tính khi đọc; dữ liệu on-chain luôn công khai.
+    mapping(string => string[]) private contestPledges;        // contestId => JSON các khoản cam kết
+    mapping(string => uint256) public contestPledgeTotals;     // contestId => tổng số tiền cam kết
+    mapping(string => string[]) private sponsorContests;       // sponsorId => các cuộc thi đã tài trợ
+    mapping(string => mapping(string => bool)) private sponsoring; // sponsorId => (contestId => đã tài trợ)
+    
+    event SponsorPledged(string indexed contestId, string indexed sponsorId, uint256 amount, address sender);
+    
+    function pledgeSponsor(string memory id, string memory sponsorId, uint256 amount, string memory jsonData) public {
+        require(bytes(contestJsons[id]).length > 0, "Contest does not exist");
+        require(_canManageContest(id), "Caller cannot change this contest");
+        require(bytes(sponsorId).length > 0, "Sponsor is required");
+        require(amount > 0, "Amount must be positive");
+        
+        contestPledges[id].push(jsonData);
+        contestPledgeTotals[id] += amount;
+        if (!sponsoring[sponsorId][id]) {
+            sponsoring[sponsorId][id] = true;
+            sponsorContests[sponsorId].push(id);
+        }
+        emit SponsorPledged(id, sponsorId, amount, _msgSender());
+    }
+    
+    function getContestPledges(string memory id) public view returns (string[] memory) {
+        return contestPledges[id];
+    }
+    
+    function getSponsorContests(string memory sponsorId) public view returns (string[] memory) {
+        return sponsorContests[sponsorId];
+    }
 }
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface IERC20 {
+    function transfer(address to, uint256 amount) external returns (bool);
+    function transferFrom(address from, address to, uint256 amount) external returns (bool);
+    function balanceOf(address account) external view returns (uint256);
+}
+
+// Escrow giữ quỹ giải thưởng của từng cuộc thi (token gốc của chain hoặc một token ERC-20).
+// Nhà tài trợ tự gửi tiền vào quỹ từ ví của mình; backend (owner) mở quỹ với bảng chia giải,
+// trả giải cho người thắng khi có kết quả cuối cùng, hoặc hủy quỹ để hoàn tiền.
+// Số tiền mỗi giải do contract tính từ tổng quỹ và bảng chia giải, không do backend quyết định.
+contract PrizeEscrow {
+    uint16 public constant TOTAL_BPS = 10000;
+
+    enum State { None, Funding, Paid, Refunding }
+
+    struct Pool {
+        address token;     // address(0) = token gốc của chain
+        State state;
+        uint256 total;     // Tổng tiền đã gửi
+        uint16[] shares;   // Phần trăm giải (basis point) theo thứ hạng, từ hạng nhất
+    }
+
+    // Người thắng theo kết quả cuối cùng, từ hạng nhất. Các dòng đồng hạng có cùng rank
+    // và chia đều các giải của những vị trí họ chiếm
+    struct Winner {
+        uint256 rank;
+        string contestantId;
+        address wallet;
+    }
+
+    // Gas tối đa cho mỗi lần gửi token gốc, để ví người thắng không tiêu hết gas của cả giao dịch
+    uint256 private constant SEND_GAS = 50000;
+
+    address public owner;
+    mapping(string => Pool) private pools;                           // contestId => quỹ
+    mapping(string => mapping(address => uint256)) public deposits;  // contestId => (ví => số tiền chưa hoàn)
+    mapping(string => mapping(address => uint256)) public unclaimed; // contestId => (ví => giải gửi không thành công)
+
+    event PoolOpened(string indexed contestId, address token, uint16[] shares);
+    event Deposited(string indexed contestId, address indexed depositor, uint256 amount, uint256 total);
+    event PaidOut(string indexed contestId, address indexed winner, uint256 rank, string contestantId, uint256 amount);
+    event PrizeHeld(string indexed contestId, address indexed winner, uint256 rank, string contestantId, uint256 amount);
+    event PrizeClaimed(string indexed contestId, address indexed winner, address to, uint256 amount);
+    event PoolCancelled(string indexed contestId);
+    event Refunded(string indexed contestId, address indexed depositor, uint256 amount);
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "Caller is not the owner");
+        _;
+    }
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    function openPool(string memory contestId, address token, uint16[] memory shares) public onlyOwner {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.None, "Pool already exists");
+        require(token == address(0) || token.code.length > 0, "Token is not a contract");
+        require(shares.length > 0, "Shares are empty");
+        uint256 sum = 0;
+        for (uint i = 0; i < shares.length; i++) {
+            require(shares[i] > 0, "Share must be positive");
+            sum += shares[i];
+        }
+        require(sum == TOTAL_BPS, "Shares must add up to 10000");
+
+        pool.token = token;
+        pool.state = State.Funding;
+        pool.shares = shares;
+        emit PoolOpened(contestId, token, shares);
+    }
+
+    // Gửi token gốc vào quỹ
+    function deposit(string memory contestId) public payable {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.Funding, "Pool is not funding");
+        require(pool.token == address(0), "Pool takes an ERC-20 token");
+        require(msg.value > 0, "Amount must be positive");
+        _credit(contestId, pool, msg.sender, msg.value);
+    }
+
+    // Gửi token ERC-20 vào quỹ; người gửi phải approve cho escrow trước
+    function depositToken(string memory contestId, uint256 amount) public {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.Funding, "Pool is not funding");
+        require(pool.token != address(0), "Pool takes the native token");
+        require(amount > 0, "Amount must be positive");
+
+        // Ghi nhận số tiền thực nhận, cho token thu phí khi chuyển
+        uint256 before = IERC20(pool.token).balanceOf(address(this));
+        _callToken(pool.token, abi.encodeWithSelector(IERC20.transferFrom.selector, msg.sender, address(this), amount));
+        uint256 received = IERC20(pool.token).balanceOf(address(this)) - before;
+        require(received > 0, "Nothing received");
+        _credit(contestId, pool, msg.sender, received);
+    }
+
+    // Trả toàn bộ quỹ cho người thắng; mỗi quỹ chỉ trả một lần. Quỹ bị khóa trước khi tính
+    // giải, nên khoản gửi đến sau đó bị từ chối thay vì làm lệch số tiền. Giải không gửi được
+    // (ví từ chối nhận) được giữ lại để người thắng tự nhận bằng claim.
+    function payout(string memory contestId, Winner[] memory winners) public onlyOwner {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.Funding, "Pool is not funding");
+        require(pool.total > 0, "Pool is empty");
+        require(winners.length > 0, "No winners");
+
+        pool.state = State.Paid;
+        uint256[] memory amounts = _split(pool.total, pool.shares, winners);
+        for (uint i = 0; i < winners.length; i++) {
+            Winner memory winner = winners[i];
+            require(winner.wallet != address(0), "Winner has no wallet");
+            if (_trySend(pool.token, winner.wallet, amounts[i])) {
+                emit PaidOut(contestId, winner.wallet, winner.rank, winner.contestantId, amounts[i]);
+            } else {
+                unclaimed[contestId][winner.wallet] += amounts[i];
+                emit PrizeHeld(contestId, winner.wallet, winner.rank, winner.contestantId, amounts[i]);
+            }
+        }
+    }
+
+    // Người thắng nhận giải bị giữ lại, về ví to bất kỳ
+    function claim(string memory contestId, address to) public {
+        uint256 amount = unclaimed[contestId][msg.sender];
+        require(amount > 0, "Nothing to claim");
+        require(to != address(0), "Invalid recipient");
+
+        unclaimed[contestId][msg.sender] = 0;
+        _send(pools[contestId].token, to, amount);
+        emit PrizeClaimed(contestId, msg.sender, to, amount);
+    }
+
+    // Hủy quỹ khi cuộc thi bị hủy hoặc không trả được giải; sau đó ai cũng có thể gọi refund
+    // cho từng người gửi
+    function cancelPool(string memory contestId) public onlyOwner {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.Funding, "Pool is not funding");
+        pool.state = State.Refunding;
+        emit PoolCancelled(contestId);
+    }
+
+    // Tiền luôn được hoàn về đúng ví đã gửi
+    function refund(string memory contestId, address depositor) public {
+        Pool storage pool = pools[contestId];
+        require(pool.state == State.Refunding, "Pool is not refunding");
+        uint256 amount = deposits[contestId][depositor];
+        require(amount > 0, "Nothing to refund");
+
+        deposits[contestId][depositor] = 0;
+        _send(pool.token, depositor, amount);
+        emit Refunded(contestId, depositor, amount);
+    }
+
+    function getPool(string memory contestId) public view returns (
+        address token,
+        uint8 state,
+        uint256 total,
+        uint16[] memory shares
+    ) {
+        Pool storage pool = pools[contestId];
+        return (pool.token, uint8(pool.state), pool.total, pool.shares);
+    }
+
+    // Chia tổng quỹ theo bảng chia giải. Người thắng ở vị trí thứ i (từ 0) phải có rank i + 1,
+    // trừ khi đồng hạng với dòng trước; phần lẻ khi chia và giải của các vị trí không ai chiếm
+    // thuộc về hạng nhất
+    function _split(uint256 total, uint16[] storage shares, Winner[] memory winners) private view returns (uint256[] memory amounts) {
+        amounts = new uint256[](winners.length);
+        uint256 paid = 0;
+        uint256 start = 0;
+        while (start < winners.length) {
+            require(start < shares.length, "Winner past the last paid place");
+            require(winners[start].rank == start + 1, "Ranks do not match the places");
+            uint256 end = start + 1;
+            while (end < winners.length && winners[end].rank == winners[start].rank) {
+                end++;
+            }
+
+            uint256 bps = 0;
+            for (uint256 place = start; place < end && place < shares.length; place++) {
+                bps += shares[place];
+            }
+            uint256 each = total * bps / (uint256(TOTAL_BPS) * (end - start));
+            for (uint256 i = start; i < end; i++) {
+                amounts[i] = each;
+                paid += each;
+            }
+            start = end;
+        }
+        amounts[0] += total - paid;
+    }
+
+    function _credit(string memory contestId, Pool storage pool, address depositor, uint256 amount) private {
+        deposits[contestId][depositor] += amount;
+        pool.total += amount;
+        emit Deposited(contestId, depositor, amount, pool.total);
+    }
+
+    function _send(address token, address to, uint256 amount) private {
+        if (token == address(0)) {
+            (bool ok, ) = payable(to).call{value: amount}("");
+            require(ok, "Native transfer failed");
+        } else {
+            _callToken(token, abi.encodeWithSelector(IERC20.transfer.selector, to, amount));
+        }
+    }
+
+    // Như _send nhưng báo lỗi thay vì revert
+    function _trySend(address token, address to, uint256 amount) private returns (bool) {
+        if (token == address(0)) {
+            (bool ok, ) = payable(to).call{value: amount, gas: SEND_GAS}("");
+            return ok;
+        }
+        return _tryCallToken(token, abi.encodeWithSelector(IERC20.transfer.selector, to, amount));
+    }
+
+    // Chấp nhận cả token không trả về bool (như USDT)
+    function _callToken(address token, bytes memory data) private {
+        require(_tryCallToken(token, data), "Token transfer failed");
+    }
+
+    function _tryCallToken(address token, bytes memory data) private returns (bool) {
+        (bool ok, bytes memory result) = token.call(data);
+        return ok && (result.length == 0 || (result.length == 32 && abi.decode(result, (bool))));
+    }
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// Forwarder kiểu ERC-2771: người dùng ký ForwardRequest theo EIP-712,
+// server (relayer) gửi giao dịch và trả gas, contract đích nhận địa chỉ
+// người ký ở 20 byte cuối calldata.
+contract WikiChainForwarder {
+    struct ForwardRequest {
+        address from;
+        address to;
+        uint256 value;
+        uint256 gas;
+        uint256 nonce;
+        uint256 deadline;
+        bytes data;
+    }
+
+    bytes32 private constant EIP712_DOMAIN_TYPEHASH =
+        keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)");
+    bytes32 private constant FORWARD_REQUEST_TYPEHASH =
+        keccak256("ForwardRequest(address from,address to,uint256 value,uint256 gas,uint256 nonce,uint256 deadline,bytes data)");
+
+    bytes32 public immutable DOMAIN_SEPARATOR;
+
+    // Nonce của từng người ký, tăng sau mỗi request được thực thi
+    mapping(address => uint256) private nonces;
+
+    event Executed(address indexed from, address indexed to, uint256 nonce);
+
+    constructor() {
+        DOMAIN_SEPARATOR = keccak256(abi.encode(
+            EIP712_DOMAIN_TYPEHASH,
+            keccak256(bytes("WikiChainForwarder")),
+            keccak256(bytes("1")),
+            block.chainid,
+            address(this)
+        ));
+    }
+
+    // Lấy nonce tiếp theo của người ký
+    function getNonce(address from) public view returns (uint256) {
+        return nonces[from];
+    }
+
+    // Kiểm tra chữ ký, nonce và hạn của request
+    function verify(ForwardRequest calldata req, bytes calldata signature) public view returns (bool) {
+        bytes32 structHash = keccak256(abi.encode(
+            FORWARD_REQUEST_TYPEHASH,
+            req.from,
+            req.to,
+            req.value,
+            req.gas,
+            req.nonce,
+            req.deadline,
+            keccak256(req.data)
+        ));
+        bytes32 digest = keccak256(abi.encodePacked("\x19\x01", DOMAIN_SEPARATOR, structHash));
+        return nonces[req.from] == req.nonce
+            && block.timestamp <= req.deadline
+            && _recover(digest, signature) == req.from;
+    }
+
+    // Thực thi request đã ký; revert nếu lời gọi tới contract đích thất bại
+    function execute(ForwardRequest calldata req, bytes calldata signature) public payable returns (bytes memory) {
+        require(verify(req, signature), "Forwarder: signature does not match request");
+        require(msg.value == req.value, "Forwarder: value mismatch");
+        nonces[req.from] = req.nonce + 1;
+
+        (bool success, bytes memory returndata) = req.to.call{gas: req.gas, value: req.value}(
+            abi.encodePacked(req.data, req.from)
+        );
+        if (!success) {
+            assembly {
+                revert(add(returndata, 32), mload(returndata))
+            }
+        }
+        // Chặn relayer cố tình gửi thiếu gas cho lời gọi bên trong (EIP-150)
+        require(gasleft() > req.gas / 63, "Forwarder: insufficient gas");
+
+        emit Executed(req.from, req.to, req.nonce);
+        return returndata;
+    }
+
+    function _recover(bytes32 digest, bytes calldata signature) internal pure returns (address) {
+        if (signature.length != 65) {
+            return address(0);
+        }
+        bytes32 r = bytes32(signature[0:32]);
+        bytes32 s = bytes32(signature[32:64]);
+        uint8 v = uint8(signature[64]);
+        if (v < 27) {
+            v += 27;
+        }
+        return ecrecover(digest, v, r, s);
+    }
+}
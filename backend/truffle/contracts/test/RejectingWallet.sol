@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+interface IPrizeClaim {
+    function claim(string memory contestId, address to) external;
+}
+
+// Ví dạng contract không nhận token gốc, chỉ dùng để test giải bị giữ lại trong quỹ giải thưởng
+contract RejectingWallet {
+    function claim(address escrow, string memory contestId, address to) public {
+        IPrizeClaim(escrow).claim(contestId, to);
+    }
+}
@@ -1,6 +1,15 @@
+const WikiChainForwarder = artifacts.require("WikiChainForwarder");
 const ContentStorage = artifacts.require("ContentStorage");
+const PrizeEscrow = artifacts.require("PrizeEscrow");
 
-module.exports = function (deployer) {
-  // Triển khai smart contract ContentStorage
-  deployer.deploy(ContentStorage);
+module.exports = async function (deployer) {
+  // Triển khai forwarder (ERC-2771) cho meta-transaction
+  await deployer.deploy(WikiChainForwarder);
+  const forwarder = await WikiChainForwarder.deployed();
+
+  // Triển khai smart contract ContentStorage, tin cậy forwarder vừa tạo
+  await deployer.deploy(ContentStorage, forwarder.address);
+
+  // Triển khai escrow quỹ giải thưởng; tài khoản triển khai là owner nên phải trùng với ví của backend
+  await deployer.deploy(PrizeEscrow);
 };
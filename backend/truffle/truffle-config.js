@@ -120,13 +120,13 @@ module.exports = {
     solc: {
       version: "0.8.21",      // Fetch exact version from solc-bin (default: truffle's version)
       // docker: true,        // Use "0.5.1" you've installed locally with docker (default: false)
-      // settings: {          // See the solidity docs for advice about optimization and evmVersion
-      //  optimizer: {
-      //    enabled: false,
-      //    runs: 200
-      //  },
-      //  evmVersion: "byzantium"
-      // }
+      settings: {            // ContentStorage only fits the 24 KB code size limit when optimized
+        optimizer: {
+          enabled: true,
+          runs: 200
+        },
+        viaIR: true
+      }
     }
   },
 
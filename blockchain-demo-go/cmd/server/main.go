@@ -2,11 +2,25 @@ package main
 
 import (
 	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
 	"blockchain-demo/internal/config"
+	"blockchain-demo/internal/idempotency"
+	"blockchain-demo/internal/judging"
+	"blockchain-demo/internal/leaderboard"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/metatx"
+	"blockchain-demo/internal/middleware"
+	"blockchain-demo/internal/moderation"
+	"blockchain-demo/internal/ratelimit"
 	"blockchain-demo/internal/service"
-	"io"
+	"blockchain-demo/internal/teams"
+	"blockchain-demo/internal/wiki"
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -24,95 +38,161 @@ func main() {
 		log.Fatalf("Failed to initialize blockchain service: %v", err)
 	}
 
-	// Initialize API handlers
-	apiHandler := api.NewHandler(blockchainService)
-
-	// Setup routes
-	router := mux.NewRouter()
+	// Initialize wallet sign-in
+	chainID, _ := strconv.ParseInt(cfg.ChainID, 10, 64)
+	sessions := auth.NewSessionStore(cfg.SessionTTL)
+	siwe := auth.NewSIWE(cfg.SIWEDomain, chainID, auth.NewNonceStore(10*time.Minute), sessions)
 
-	// Add CORS middleware FIRST - with detailed logging
-	router.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			origin := r.Header.Get("Origin")
-			log.Printf("🌐 Request: %s %s from %s, Origin: %s", r.Method, r.URL.Path, r.RemoteAddr, origin)
-
-			// Set CORS headers for all responses
-			w.Header().Set("Access-Control-Allow-Origin", "*") // Cho phép tất cả origins
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-			w.Header().Set("Access-Control-Max-Age", "3600")
-
-			// Handle preflight OPTIONS request
-			if r.Method == "OPTIONS" {
-				log.Printf("✓ Handling OPTIONS preflight request")
-				w.WriteHeader(http.StatusOK)
-				return
+	// Initialize API keys and JWT bearer tokens
+	apiKeys, err := auth.NewKeyStore(cfg.APIKeysFile)
+	if err != nil {
+		log.Fatalf("Failed to load API keys: %v", err)
+	}
+	if cfg.AdminAPIKey != "" {
+		apiKeys.Import("admin", "Bootstrap admin key", cfg.AdminAPIKey, []string{auth.ScopeAdmin})
+	}
+	authOpts := []auth.Option{auth.WithAPIKeys(apiKeys), auth.WithWallets(cfg.WalletAllowlist)}
+	if cfg.JWTSecret != "" || cfg.JWTPublicKeyFile != "" {
+		var publicKey []byte
+		if cfg.JWTPublicKeyFile != "" {
+			if publicKey, err = os.ReadFile(cfg.JWTPublicKeyFile); err != nil {
+				log.Fatalf("Failed to read JWT public key: %v", err)
 			}
+		}
+		verifier, err := auth.NewJWTVerifier(cfg.JWTSecret, publicKey, cfg.JWTIssuer, cfg.JWTAudience)
+		if err != nil {
+			log.Fatalf("Failed to initialize JWT verifier: %v", err)
+		}
+		authOpts = append(authOpts, auth.WithJWT(verifier))
+	}
 
-			next.ServeHTTP(w, r)
-		})
-	})
+	// Initialize role-based access control
+	var roles *auth.RoleStore
+	if cfg.RBACEnabled {
+		roles = auth.NewRoleStore()
+		for _, wallet := range cfg.AdminWallets {
+			roles.Grant(wallet, auth.RoleAdmin, "config", "")
+		}
+		authOpts = append(authOpts, auth.WithRoles(roles))
+		log.Printf("🛡️ Role-based access control enabled (%d admin wallets)", len(cfg.AdminWallets))
+	}
+	authenticator := auth.NewAuthenticator(sessions, authOpts...)
 
-	// API routes
-	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	if cfg.AuthRequired {
+		log.Printf("🔐 Authentication required for write endpoints")
+	} else if !blockchainService.ReadOnly() {
+		log.Printf("⚠️ AUTH_REQUIRED=false: anonymous callers can spend the server wallet's gas")
+	}
 
-	// Test endpoint
-	apiRouter.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("✅ Test endpoint hit!")
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"success":true,"message":"Test endpoint working!"}`))
-	}).Methods("GET", "OPTIONS")
+	// Initialize rate limits and daily gas budgets
+	proxies, err := ratelimit.ParseProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	rateLimiter := ratelimit.New(
+		ratelimit.NewLimiter(cfg.RateLimitRead),
+		ratelimit.NewLimiter(cfg.RateLimitWrite),
+		ratelimit.NewGasBudget(cfg.GasBudgetDaily),
+		proxies,
+	)
+	log.Printf("🚦 Rate limits: %d reads/min, %d writes/min, %d gas/day per client", cfg.RateLimitRead, cfg.RateLimitWrite, cfg.GasBudgetDaily)
+
+	// Initialize idempotency keys, scoped to the same client identity as the rate limits
+	idempotencyStore, err := idempotency.NewStore(cfg.IdempotencyDB, cfg.IdempotencyTTL)
+	if err != nil {
+		log.Fatalf("Failed to initialize idempotency store: %v", err)
+	}
+	defer idempotencyStore.Close()
+	idempotent := idempotency.NewMiddleware(idempotencyStore, rateLimiter.ClientKey)
 
-	// Add POST test endpoint specifically for testing contest creation
-	apiRouter.HandleFunc("/test-post", func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("🧪 Test POST endpoint called")
+	// Initialize wiki revisions, kept across restarts
+	revisions, err := wiki.OpenRevisionStore(cfg.WikiDB)
+	if err != nil {
+		log.Fatalf("Failed to initialize wiki revision store: %v", err)
+	}
+	defer revisions.Close()
 
-		// Read the request body
-		body, err := io.ReadAll(r.Body)
+	// Initialize the judging board, kept across restarts
+	board, err := judging.OpenBoard(cfg.JudgingDB)
+	if err != nil {
+		log.Fatalf("Failed to initialize judging board: %v", err)
+	}
+	defer board.Close()
+
+	// Initialize API handlers
+	handlerOpts := []api.Option{
+		api.WithContentRules(moderation.NewRules(cfg.ModerationBannedWords, cfg.ModerationMaxLinks, cfg.ModerationMaxLength)),
+		api.WithSIWE(siwe, sessions),
+		api.WithAPIKeys(apiKeys),
+		api.WithRateLimiter(rateLimiter),
+		api.WithMaxBodyBytes(cfg.MaxBodyBytes),
+		api.WithRevisionStore(revisions),
+		api.WithTeams(teams.NewStore()),
+		api.WithJudging(board),
+		api.WithLeaderboard(leaderboard.NewHub()),
+	}
+	if roles != nil {
+		handlerOpts = append(handlerOpts, api.WithRoles(roles))
+	}
+	if cfg.ForwarderAddress != "" {
+		relayer := metatx.NewRelayer(chainID, cfg.ForwarderAddress, cfg.ContractAddress, cfg.MetaTxTTL, cfg.MetaTxGas)
+		handlerOpts = append(handlerOpts, api.WithRelayer(relayer))
+		log.Printf("📨 Meta-transactions enabled through forwarder %s", cfg.ForwarderAddress)
+	}
+	if cfg.EscrowAddress != "" {
+		if roles == nil {
+			log.Fatalf("ESCROW_ADDRESS requires RBAC_ENABLED=true: without it anyone could pay out or refund prize pools")
+		}
+		handlerOpts = append(handlerOpts, api.WithPrizePools())
+		log.Printf("💰 Prize pools enabled through escrow %s", cfg.EscrowAddress)
+	}
+	if cfg.ModerationEnabled {
+		// Pending submissions and the review history are kept across restarts
+		queue, err := moderation.OpenQueue(cfg.ModerationDB)
 		if err != nil {
-			log.Printf("❌ Error reading request body: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			log.Fatalf("Failed to initialize moderation queue: %v", err)
 		}
+		defer queue.Close()
+		handlerOpts = append(handlerOpts, api.WithModerationQueue(queue))
+		log.Printf("🕵️ Moderation enabled: content is pushed to blockchain only after approval")
+	}
+	apiHandler := api.NewHandler(blockchainService, handlerOpts...)
+	if err := apiHandler.Reindex(); err != nil {
+		log.Printf("⚠️ Failed to rebuild wiki indexes: %v", err)
+	}
+	if blockchainService.ReadOnly() {
+		log.Printf("👀 Read-only mode: write endpoints return 503")
+	} else if cfg.ContestSchedulerInterval > 0 {
+		go lifecycle.NewScheduler(blockchainService, cfg.ContestSchedulerInterval).Run(context.Background())
+		log.Printf("⏰ Contest scheduler checks start and end dates every %s", cfg.ContestSchedulerInterval)
+	}
 
-		log.Printf("📥 Received POST data: %s", string(body))
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"success":true,"message":"Test POST endpoint working!","txHash":"0xtest123","id":"test-id-123"}`))
-	}).Methods("POST", "OPTIONS")
-
-	// Content endpoints
-	apiRouter.HandleFunc("/content", apiHandler.CreateContent).Methods("POST", "OPTIONS")
-	apiRouter.HandleFunc("/content/{id}", apiHandler.GetContent).Methods("GET", "OPTIONS")
-	apiRouter.HandleFunc("/contents", apiHandler.ListContents).Methods("GET", "OPTIONS")
-
-	// Fix Contest endpoints by using explicit subrouter for method separation
-	contestsRouter := apiRouter.PathPrefix("/contests").Subrouter()
-	contestsRouter.HandleFunc("/search", apiHandler.SearchContestsHandler).Methods("GET", "OPTIONS")
+	// Setup routes
+	router := mux.NewRouter()
 
-	contestsRouter.HandleFunc("", apiHandler.ListContests).Methods("GET", "OPTIONS")    // GET /contests
-	contestsRouter.HandleFunc("", apiHandler.CreateContest).Methods("POST", "OPTIONS")  // POST /contests
-	contestsRouter.HandleFunc("/{id}", apiHandler.GetContest).Methods("GET", "OPTIONS") // GET /contests/{id}
-	contestsRouter.HandleFunc("/{contestId}/register", apiHandler.RegisterContestant).Methods("POST", "OPTIONS")
-	contestsRouter.HandleFunc("/{contestId}/contestants", apiHandler.GetContestantsInContest).Methods("GET", "OPTIONS")
+	// Log requests, then apply CORS FIRST so preflights are answered before anything else
+	cors, err := middleware.NewCORS(cfg)
+	if err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+	router.Use(middleware.Logger)
+	router.Use(cors.Middleware)
 
-	// Contestant endpoints
-	apiRouter.HandleFunc("/contestants", apiHandler.CreateContestant).Methods("POST", "OPTIONS")
-	apiRouter.HandleFunc("/contestants/{id}", apiHandler.GetContestant).Methods("GET", "OPTIONS")
-	apiRouter.HandleFunc("/contestants", apiHandler.ListContestants).Methods("GET", "OPTIONS")
+	// Cap request bodies before any middleware buffers them
+	router.Use(middleware.MaxBodyBytes(cfg.MaxBodyBytes))
 
-	// Sponsor endpoints
-	apiRouter.HandleFunc("/sponsors", apiHandler.CreateSponsor).Methods("POST", "OPTIONS")
-	apiRouter.HandleFunc("/sponsors/{id}", apiHandler.GetSponsor).Methods("GET", "OPTIONS")
-	apiRouter.HandleFunc("/sponsors", apiHandler.ListSponsors).Methods("GET", "OPTIONS")
+	// Resolve the caller identity after CORS so preflight requests stay anonymous
+	router.Use(authenticator.Middleware)
 
-	// Statistics endpoint
-	apiRouter.HandleFunc("/stats", apiHandler.GetStats).Methods("GET", "OPTIONS")
+	// Throttle per API key, wallet or IP once the caller is known
+	router.Use(rateLimiter.Middleware)
 
-	// Health check
-	apiRouter.HandleFunc("/health", apiHandler.HealthCheck).Methods("GET", "OPTIONS")
+	// API routes, write endpoints guarded by AUTH_REQUIRED, idempotency keys and gas budgets
+	apiHandler.RegisterRoutes(router, api.Routes{
+		AuthRequired: cfg.AuthRequired,
+		Idempotent:   idempotent.Wrap,
+		Budget:       rateLimiter.Budget,
+	})
 
 	log.Printf("🚀 Server starting on %s:%s", cfg.Host, cfg.Port)
 	log.Printf("📡 Connected to blockchain: %s", cfg.NetworkURL)
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// WithAPIKeys enables the API key administration endpoints
+func WithAPIKeys(keys *auth.KeyStore) Option {
+	return func(h *Handler) {
+		h.apiKeys = keys
+	}
+}
+
+// WithSIWE enables Sign-In with Ethereum endpoints
+func WithSIWE(siwe *auth.SIWE, sessions *auth.SessionStore) Option {
+	return func(h *Handler) {
+		h.siwe = siwe
+		h.sessions = sessions
+	}
+}
+
+// callerAddress returns the wallet address of the authenticated caller, empty if anonymous
+func callerAddress(r *http.Request) string {
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil {
+		return identity.Address
+	}
+	return ""
+}
+
+// attribute overrides a client supplied creator with the authenticated wallet
+func attribute(r *http.Request, creator *string) {
+	if address := callerAddress(r); address != "" {
+		*creator = address
+	}
+}
+
+// ============ AUTH HANDLERS ============
+
+// GetAuthNonce handles GET /api/v1/auth/nonce
+func (h *Handler) GetAuthNonce(w http.ResponseWriter, r *http.Request) {
+	if h.siwe == nil {
+		h.respondWithError(w, http.StatusNotFound, "Wallet sign-in is not enabled", "")
+		return
+	}
+
+	nonce, expiresAt := h.siwe.Nonce()
+	h.respondWithJSON(w, http.StatusOK, models.NonceResponse{
+		Success:   true,
+		Nonce:     nonce,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// VerifySIWE handles POST /api/v1/auth/verify
+func (h *Handler) VerifySIWE(w http.ResponseWriter, r *http.Request) {
+	if h.siwe == nil {
+		h.respondWithError(w, http.StatusNotFound, "Wallet sign-in is not enabled", "")
+		return
+	}
+
+	var req models.VerifySIWERequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	session, err := h.siwe.Verify(req.Message, req.Signature)
+	if err != nil {
+		log.Printf("🔒 SIWE verification failed: %v", err)
+		h.respondWithError(w, http.StatusUnauthorized, "Sign-in verification failed", err.Error())
+		return
+	}
+
+	log.Printf("🔑 Wallet signed in: %s", session.Address)
+
+	// The session token is a bearer credential, keep it out of caches
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondWithJSON(w, http.StatusOK, models.SessionResponse{
+		Success:   true,
+		Message:   "Signed in successfully",
+		Token:     session.Token,
+		Address:   session.Address,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
+
+// Logout handles POST /api/v1/auth/logout
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if h.sessions != nil {
+		if token := auth.BearerToken(r); token != "" {
+			h.sessions.Close(token)
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Signed out",
+	})
+}
+
+// GetMe handles GET /api/v1/auth/me
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil {
+		h.respondWithJSON(w, http.StatusOK, models.MeResponse{Success: true})
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.MeResponse{
+		Success:       true,
+		Authenticated: true,
+		Subject:       identity.Subject,
+		Address:       identity.Address,
+		Method:        identity.Method,
+		Scopes:        identity.Scopes,
+		Roles:         identity.Roles,
+	})
+}
+
+// ============ API KEY HANDLERS ============
+
+// CreateAPIKey handles POST /api/v1/admin/api-keys
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.respondWithError(w, http.StatusNotFound, "API keys are not enabled", "")
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.ExpiresIn); err != nil || ttl <= 0 {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid expires_in", "expires_in must be a positive duration such as \"720h\"")
+			return
+		}
+	}
+
+	plaintext, key, err := h.apiKeys.Create(req.Name, req.Scopes, ttl)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to create API key", err)
+		return
+	}
+
+	log.Printf("🔑 API key %s (%s) created with scopes %v", key.ID, key.Name, key.Scopes)
+
+	// The plaintext key must not be cached or stored anywhere
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondWithJSON(w, http.StatusCreated, models.CreateAPIKeyResponse{
+		Success: true,
+		Message: "Store this key now, it will not be shown again",
+		Key:     plaintext,
+		Data:    key,
+	})
+}
+
+// ListAPIKeys handles GET /api/v1/admin/api-keys
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.respondWithError(w, http.StatusNotFound, "API keys are not enabled", "")
+		return
+	}
+
+	keys := h.apiKeys.List()
+	h.respondWithJSON(w, http.StatusOK, models.ListAPIKeysResponse{
+		Success: true,
+		Data:    keys,
+		Total:   len(keys),
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/v1/admin/api-keys/{id}
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if h.apiKeys == nil {
+		h.respondWithError(w, http.StatusNotFound, "API keys are not enabled", "")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	key, err := h.apiKeys.Revoke(id)
+	if err == auth.ErrKeyNotFound {
+		h.respondWithError(w, http.StatusNotFound, "API key not found", "")
+		return
+	}
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to revoke API key", err)
+		return
+	}
+
+	log.Printf("🚫 API key %s (%s) revoked", key.ID, key.Name)
+
+	h.respondWithJSON(w, http.StatusOK, models.CreateAPIKeyResponse{
+		Success: true,
+		Message: "API key revoked",
+		Data:    key,
+	})
+}
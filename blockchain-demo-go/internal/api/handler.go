@@ -1,9 +1,21 @@
 package api
 
 import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/judging"
+	"blockchain-demo/internal/leaderboard"
+	"blockchain-demo/internal/metatx"
 	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/moderation"
+	"blockchain-demo/internal/ratelimit"
 	"blockchain-demo/internal/service"
+	"blockchain-demo/internal/signer"
+	"blockchain-demo/internal/teams"
+	"blockchain-demo/internal/validation"
+	"blockchain-demo/internal/wiki"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
@@ -13,50 +25,182 @@ import (
 // Handler handles HTTP requests
 type Handler struct {
 	blockchainService service.BlockchainServiceInterface
+	links             *wiki.LinkGraph
+	taxonomy          *wiki.Taxonomy
+	revisions         *wiki.RevisionStore
+
+	// Moderation (optional)
+	contentRules    *moderation.Rules
+	moderationQueue *moderation.Queue
+
+	// Wallet sign-in (optional)
+	siwe     *auth.SIWE
+	sessions *auth.SessionStore
+
+	// API key administration (optional)
+	apiKeys *auth.KeyStore
+
+	// Role-based access control (optional)
+	roles *auth.RoleStore
+
+	// Meta-transactions (optional)
+	relayer   *metatx.Relayer
+	metaCalls *metaCalls
+
+	// Rate limits and gas budgets (optional)
+	rateLimiter *ratelimit.RateLimiter
+
+	// Team registration (optional)
+	teams *teams.Store
+
+	// Judging (optional)
+	judging *judging.Board
+
+	// Live leaderboards (optional)
+	leaderboard *leaderboard.Hub
+
+	// Prize pools (optional)
+	prizePools bool
+
+	// Largest accepted request body
+	maxBodyBytes int64
+}
+
+// Option configures an optional feature of the handler
+type Option func(*Handler)
+
+// WithContentRules runs automatic checks on every content submission
+func WithContentRules(rules *moderation.Rules) Option {
+	return func(h *Handler) {
+		h.contentRules = rules
+	}
+}
+
+// WithModerationQueue holds content submissions for moderator approval
+// instead of pushing them to blockchain right away
+func WithModerationQueue(queue *moderation.Queue) Option {
+	return func(h *Handler) {
+		h.moderationQueue = queue
+	}
+}
+
+// WithMaxBodyBytes limits the size of request bodies
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodyBytes = n
+	}
 }
 
 // NewHandler creates a new API handler
-func NewHandler(blockchainService service.BlockchainServiceInterface) *Handler {
-	return &Handler{
+func NewHandler(blockchainService service.BlockchainServiceInterface, opts ...Option) *Handler {
+	h := &Handler{
 		blockchainService: blockchainService,
+		links:             wiki.NewLinkGraph(),
+		taxonomy:          wiki.NewTaxonomy(),
+		revisions:         wiki.NewRevisionStore(),
+		maxBodyBytes:      validation.DefaultMaxBodyBytes,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// chain returns the service bound to a request, so the transactions it sends
+// are charged to the caller's gas budget
+func (h *Handler) chain(r *http.Request) service.BlockchainServiceInterface {
+	return h.blockchainService.WithContext(r.Context())
 }
 
 // CreateContent handles POST /api/v1/content
 func (h *Handler) CreateContent(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateContentRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	// Attribute to the signed-in wallet, or default creator if not provided
+	attribute(r, &req.Creator)
+	if req.Creator == "" {
+		req.Creator = "anonymous"
+	}
+
+	// Normalize classification so the anchored record is canonical
+	req.Namespace = wiki.NormalizeNamespace(req.Namespace)
+	req.Categories = wiki.NormalizeCategories(req.Categories)
+	req.Tags = wiki.NormalizeTags(req.Tags)
+
+	if h.moderate(w, &req, "") {
+		return
+	}
+
+	log.Printf("📝 Creating content: %s by %s", req.Title, req.Creator)
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+	// Create content via blockchain service
+	response, err := h.chain(r).StoreContent(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to create content", err)
 		return
 	}
 
-	// Validate required fields
-	if req.Title == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Title is required", "")
+	// Record the revision and index links, categories and tags
+	h.indexContent(&req, response)
+
+	h.respondWithJSON(w, http.StatusCreated, response)
+}
+
+// UpdateContent handles PUT /api/v1/content/{id}
+func (h *Handler) UpdateContent(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if id == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Content ID is required", "")
 		return
 	}
 
-	if req.Content == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Content is required", "")
+	var req models.CreateContentRequest
+	if !h.decode(w, r, &req) {
 		return
 	}
 
-	// Set default creator if not provided
+	// Articles written before revisions were stored only exist on blockchain
+	if !h.revisions.Has(id) {
+		existing, err := h.blockchainService.GetContent(id)
+		if err != nil && apperr.KindOf(err) != apperr.KindNotFound {
+			h.respondWithServiceError(w, "Failed to get content", err)
+			return
+		}
+		if err != nil || !existing.Success {
+			h.respondWithError(w, http.StatusNotFound, "Content not found", "")
+			return
+		}
+	}
+
+	// Attribute to the signed-in wallet, or default creator if not provided
+	attribute(r, &req.Creator)
 	if req.Creator == "" {
 		req.Creator = "anonymous"
 	}
 
-	log.Printf("📝 Creating content: %s by %s", req.Title, req.Creator)
+	req.Namespace = wiki.NormalizeNamespace(req.Namespace)
+	req.Categories = wiki.NormalizeCategories(req.Categories)
+	req.Tags = wiki.NormalizeTags(req.Tags)
 
-	// Create content via blockchain service
-	response, err := h.blockchainService.StoreContent(&req)
+	if h.moderate(w, &req, id) {
+		return
+	}
+
+	log.Printf("✏️ Updating content: %s by %s", id, req.Creator)
+
+	response, err := h.chain(r).UpdateContent(id, &req)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to create content", err.Error())
+		h.respondWithServiceError(w, "Failed to update content", err)
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusCreated, response)
+	h.indexContent(&req, response)
+
+	h.respondWithJSON(w, http.StatusOK, response)
 }
 
 // GetContent handles GET /api/v1/content/{id}
@@ -74,12 +218,12 @@ func (h *Handler) GetContent(w http.ResponseWriter, r *http.Request) {
 	// Get content via blockchain service
 	response, err := h.blockchainService.GetContent(id)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get content", err.Error())
+		h.respondWithServiceError(w, "Failed to get content", err)
 		return
 	}
 
 	if !response.Success {
-		h.respondWithJSON(w, http.StatusNotFound, response)
+		h.respondWithError(w, http.StatusNotFound, "Content not found", response.Message)
 		return
 	}
 
@@ -93,20 +237,31 @@ func (h *Handler) ListContents(w http.ResponseWriter, r *http.Request) {
 	// Get all contents via blockchain service
 	response, err := h.blockchainService.GetAllContents()
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to list contents", err.Error())
+		h.respondWithServiceError(w, "Failed to list contents", err)
 		return
 	}
 
+	if filter := taxonomyFilter(r); !filter.IsEmpty() {
+		response.Data = filterContents(response.Data, filter)
+		response.Total = len(response.Data)
+	}
+
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
 // HealthCheck handles GET /api/v1/health
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	mode := "read-write"
+	if h.blockchainService.ReadOnly() {
+		mode = "read-only"
+	}
+
 	// Check blockchain connection
 	if err := h.blockchainService.HealthCheck(); err != nil {
 		response := map[string]interface{}{
 			"status":     "unhealthy",
 			"blockchain": "disconnected",
+			"mode":       mode,
 			"error":      err.Error(),
 		}
 		h.respondWithJSON(w, http.StatusServiceUnavailable, response)
@@ -116,6 +271,7 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"status":     "healthy",
 		"blockchain": "connected",
+		"mode":       mode,
 		"message":    "Service is running properly",
 	}
 	h.respondWithJSON(w, http.StatusOK, response)
@@ -128,40 +284,37 @@ func (h *Handler) CreateContest(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📝 CreateContest called - Method: %s, URL: %s", r.Method, r.URL.Path)
 
 	var req models.CreateContestRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("❌ Error decoding request body: %v", err)
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+	if !h.decode(w, r, &req) {
 		return
 	}
 
 	log.Printf("📋 Request data: %+v", req)
 
-	// Validate required fields
-	if req.Name == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contest name is required", "")
+	if !h.authorize(w, r, auth.RoleOrganizer) {
 		return
 	}
 
-	if req.Description == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contest description is required", "")
-		return
-	}
-
-	if req.StartDate == "" || req.EndDate == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Start date and end date are required", "")
-		return
-	}
+	req.Categories = wiki.NormalizeCategories(req.Categories)
+	req.Tags = wiki.NormalizeTags(req.Tags)
+	req.Organizer = callerAddress(r)
 
 	log.Printf("🏆 Creating contest: %s", req.Name)
 
-	response, err := h.blockchainService.CreateContest(&req)
+	response, err := h.chain(r).CreateContest(&req)
 	if err != nil {
 		log.Printf("❌ Error creating contest: %v", err)
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to create contest", err.Error())
+		h.respondWithServiceError(w, "Failed to create contest", err)
 		return
 	}
 
+	h.taxonomy.Index(&models.TaxonomyItem{
+		Kind:       "contest",
+		ID:         response.ID,
+		Title:      req.Name,
+		Categories: req.Categories,
+		Tags:       req.Tags,
+	})
+
 	log.Printf("✅ Contest created successfully: %+v", response)
 	h.respondWithJSON(w, http.StatusCreated, response)
 }
@@ -180,14 +333,17 @@ func (h *Handler) GetContest(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetContest(id)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get contest", err.Error())
+		h.respondWithServiceError(w, "Failed to get contest", err)
 		return
 	}
 
 	if !response.Success {
-		h.respondWithJSON(w, http.StatusNotFound, response)
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", response.Message)
 		return
 	}
+	if response.Data != nil {
+		response.Data = h.withSponsorship(response.Data)
+	}
 
 	h.respondWithJSON(w, http.StatusOK, response)
 }
@@ -198,10 +354,15 @@ func (h *Handler) ListContests(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetAllContests()
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to list contests", err.Error())
+		h.respondWithServiceError(w, "Failed to list contests", err)
 		return
 	}
 
+	if filter := taxonomyFilter(r); !filter.IsEmpty() {
+		response.Data = filterContests(response.Data, filter)
+		response.Total = len(response.Data)
+	}
+
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
@@ -210,33 +371,25 @@ func (h *Handler) ListContests(w http.ResponseWriter, r *http.Request) {
 // CreateContestant handles POST /api/v1/contestants
 func (h *Handler) CreateContestant(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateContestantRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+	if !h.decode(w, r, &req) {
 		return
 	}
 
-	// Validate required fields
-	if req.Name == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contestant name is required", "")
+	if !h.authorize(w, r, auth.RoleContestant, auth.RoleOrganizer) {
 		return
 	}
 
-	if req.Details == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contestant details are required", "")
-		return
-	}
-
-	// Set default creator if not provided
+	// Attribute to the signed-in wallet, or default creator if not provided
+	attribute(r, &req.Creator)
 	if req.Creator == "" {
 		req.Creator = "anonymous"
 	}
 
 	log.Printf("👤 Creating contestant: %s", req.Name)
 
-	response, err := h.blockchainService.CreateContestant(&req)
+	response, err := h.chain(r).CreateContestant(&req)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to create contestant", err.Error())
+		h.respondWithServiceError(w, "Failed to create contestant", err)
 		return
 	}
 
@@ -257,12 +410,12 @@ func (h *Handler) GetContestant(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetContestant(id)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get contestant", err.Error())
+		h.respondWithServiceError(w, "Failed to get contestant", err)
 		return
 	}
 
 	if !response.Success {
-		h.respondWithJSON(w, http.StatusNotFound, response)
+		h.respondWithError(w, http.StatusNotFound, "Contestant not found", response.Message)
 		return
 	}
 
@@ -275,7 +428,7 @@ func (h *Handler) ListContestants(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetAllContestants()
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to list contestants", err.Error())
+		h.respondWithServiceError(w, "Failed to list contestants", err)
 		return
 	}
 
@@ -287,28 +440,22 @@ func (h *Handler) ListContestants(w http.ResponseWriter, r *http.Request) {
 // CreateSponsor handles POST /api/v1/sponsors
 func (h *Handler) CreateSponsor(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateSponsorRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+	if !h.decode(w, r, &req) {
 		return
 	}
 
-	// Validate required fields
-	if req.Name == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Sponsor name is required", "")
+	if !h.authorize(w, r, auth.RoleSponsor) {
 		return
 	}
 
-	if req.ContactInfo == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contact info is required", "")
-		return
-	}
+	// Pledges for the sponsor must come from the signed-in wallet
+	attribute(r, &req.WalletAddress)
 
 	log.Printf("💰 Creating sponsor: %s", req.Name)
 
-	response, err := h.blockchainService.CreateSponsor(&req)
+	response, err := h.chain(r).CreateSponsor(&req)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to create sponsor", err.Error())
+		h.respondWithServiceError(w, "Failed to create sponsor", err)
 		return
 	}
 
@@ -329,12 +476,12 @@ func (h *Handler) GetSponsor(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetSponsor(id)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get sponsor", err.Error())
+		h.respondWithServiceError(w, "Failed to get sponsor", err)
 		return
 	}
 
 	if !response.Success {
-		h.respondWithJSON(w, http.StatusNotFound, response)
+		h.respondWithError(w, http.StatusNotFound, "Sponsor not found", response.Message)
 		return
 	}
 
@@ -347,7 +494,7 @@ func (h *Handler) ListSponsors(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetAllSponsors()
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to list sponsors", err.Error())
+		h.respondWithServiceError(w, "Failed to list sponsors", err)
 		return
 	}
 
@@ -367,24 +514,22 @@ func (h *Handler) RegisterContestant(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req models.RegisterContestantRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+	if !h.decode(w, r, &req) {
 		return
 	}
 
 	// Override contest ID from URL
 	req.ContestID = contestID
 
-	if req.ContestantID == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Contestant ID is required", "")
+	if !h.authorizeRegistration(w, r, &req) {
 		return
 	}
 
 	log.Printf("📝 Registering contestant %s for contest %s", req.ContestantID, req.ContestID)
 
-	response, err := h.blockchainService.RegisterContestant(&req)
+	response, err := h.chain(r).RegisterContestant(&req)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to register contestant", err.Error())
+		h.respondWithServiceError(w, "Failed to register contestant", err)
 		return
 	}
 
@@ -392,10 +537,125 @@ func (h *Handler) RegisterContestant(w http.ResponseWriter, r *http.Request) {
 		h.respondWithJSON(w, http.StatusBadRequest, response)
 		return
 	}
+	if response.Data != nil && response.Data.Status == models.RegistrationRegistered {
+		h.rosterChanged(req.ContestID, req.ContestantID, "")
+	}
 
 	h.respondWithJSON(w, http.StatusCreated, response)
 }
 
+// WithdrawContestant handles DELETE /api/v1/contests/{contestId}/register/{contestantId}.
+// Contestants withdraw themselves; the contest's organizer removes others and
+// must give a reason.
+func (h *Handler) WithdrawContestant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req models.WithdrawContestantRequest
+	if r.ContentLength != 0 && !h.decode(w, r, &req) {
+		return
+	}
+	req.ContestID = vars["contestId"]
+	req.ContestantID = vars["contestantId"]
+	req.Actor = callerAddress(r)
+
+	removed, ok := h.authorizeWithdrawal(w, r, &req)
+	if !ok {
+		return
+	}
+	req.Removed = removed
+
+	log.Printf("🚪 Withdrawing contestant %s from contest %s", req.ContestantID, req.ContestID)
+
+	response, err := h.chain(r).WithdrawContestant(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to withdraw contestant", err)
+		return
+	}
+	if response.Data != nil {
+		h.rosterChanged(req.ContestID, response.Data.Promoted, req.ContestantID)
+	}
+	if h.teams != nil {
+		h.teams.Withdrawn(req.ContestantID)
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// authorizeWithdrawal lets signed-in contestants withdraw themselves and a
+// contest's organizer remove anyone. It reports whether the caller removes
+// someone else. Anonymous callers are refused, since nobody could tell a
+// withdrawal they ask for from one the contestant asked for.
+func (h *Handler) authorizeWithdrawal(w http.ResponseWriter, r *http.Request, req *models.WithdrawContestantRequest) (bool, bool) {
+	if !h.authorize(w, r, auth.RoleOrganizer, auth.RoleContestant) {
+		return false, false
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "sign in as the contestant or the contest organizer")
+		return false, false
+	}
+	contestant, err := h.blockchainService.GetContestant(req.ContestantID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get contestant", err)
+		return false, false
+	}
+	if contestant.Success && contestant.Data != nil && sameAccount(identity.Account(), contestant.Data.Creator) {
+		return false, true
+	}
+
+	contest, err := h.blockchainService.GetContest(req.ContestID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get contest", err)
+		return false, false
+	}
+	if !contest.Success || contest.Data == nil {
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+		return false, false
+	}
+	if isOrganizer(identity, contest.Data) {
+		return true, true
+	}
+
+	h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the contest organizer can remove other contestants")
+	return false, false
+}
+
+// authorizeRegistration lets a contest's organizer register anyone and a
+// contestant register only themselves
+func (h *Handler) authorizeRegistration(w http.ResponseWriter, r *http.Request, req *models.RegisterContestantRequest) bool {
+	if !h.authorize(w, r, auth.RoleOrganizer, auth.RoleContestant) {
+		return false
+	}
+	if h.roles == nil {
+		return true
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	contest, err := h.blockchainService.GetContest(req.ContestID)
+	if err != nil || !contest.Success || contest.Data == nil {
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+		return false
+	}
+	if isOrganizer(identity, contest.Data) {
+		return true
+	}
+
+	if identity.HasRole(auth.RoleContestant) {
+		contestant, err := h.blockchainService.GetContestant(req.ContestantID)
+		if err != nil {
+			h.respondWithServiceError(w, "Failed to get contestant", err)
+			return false
+		}
+		if contestant.Success && contestant.Data != nil && sameAccount(identity.Account(), contestant.Data.Creator) {
+			return true
+		}
+	}
+
+	h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the contest organizer can register other contestants")
+	return false
+}
+
 // GetContestantsInContest handles GET /api/v1/contests/{contestId}/contestants
 func (h *Handler) GetContestantsInContest(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -410,12 +670,12 @@ func (h *Handler) GetContestantsInContest(w http.ResponseWriter, r *http.Request
 
 	response, err := h.blockchainService.GetContestantsInContest(contestID)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get contestants", err.Error())
+		h.respondWithServiceError(w, "Failed to get contestants", err)
 		return
 	}
 
 	if !response.Success {
-		h.respondWithJSON(w, http.StatusNotFound, response)
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", response.Message)
 		return
 	}
 
@@ -430,14 +690,14 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.blockchainService.GetBlockchainStats()
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to get statistics", err.Error())
+		h.respondWithServiceError(w, "Failed to get statistics", err)
 		return
 	}
 
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
-// SearchContestsHandler handles GET /api/v1/contests/search?keyword=abc
+// SearchContestsHandler handles GET /api/v1/contests/search?keyword=abc[&tag=&category=]
 func (h *Handler) SearchContestsHandler(w http.ResponseWriter, r *http.Request) {
 	keyword := r.URL.Query().Get("keyword")
 	if keyword == "" {
@@ -446,9 +706,12 @@ func (h *Handler) SearchContestsHandler(w http.ResponseWriter, r *http.Request)
 	}
 	results, err := h.blockchainService.SearchContests(keyword)
 	if err != nil {
-		h.respondWithError(w, http.StatusInternalServerError, "Search failed", err.Error())
+		h.respondWithServiceError(w, "Search failed", err)
 		return
 	}
+	if filter := taxonomyFilter(r); !filter.IsEmpty() {
+		results = filterContests(results, filter)
+	}
 	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
 		"data":    results,
@@ -456,19 +719,65 @@ func (h *Handler) SearchContestsHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
-// respondWithError sends an error response
-func (h *Handler) respondWithError(w http.ResponseWriter, code int, message, details string) {
-	response := models.ErrorResponse{
-		Success: false,
-		Error:   message,
-		Message: details,
+// decode reads and validates a JSON request body, reporting every invalid
+// field. It returns false when a response has been sent.
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := validation.Decode(w, r, v, h.maxBodyBytes)
+	if err == nil {
+		return true
+	}
+	message := "Invalid request body"
+	if fields := apperr.FieldsOf(err); len(fields) > 0 {
+		message = fields[0].Message
 	}
-	h.respondWithJSON(w, code, response)
+	log.Printf("❌ Rejected request body: %v", err)
+	h.respondWithServiceError(w, message, err)
+	return false
+}
+
+// respondWithServiceError reports a failed call to the blockchain service
+// with the status and code of its typed error, 500 if it is untyped
+func (h *Handler) respondWithServiceError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, signer.ErrReadOnly) {
+		h.respondReadOnly(w)
+		return
+	}
+	log.Printf("📤 Sending error response - Status: %d, Code: %s, Error: %s", apperr.StatusOf(err), apperr.CodeOf(err), message)
+	apperr.WriteError(w, nil, message, err)
+}
+
+// respondReadOnly rejects a write on a read-only server
+func (h *Handler) respondReadOnly(w http.ResponseWriter) {
+	h.respondWithCode(w, http.StatusServiceUnavailable, models.ErrorCodeReadOnly, "Server is read-only", "This deployment serves queries only; write endpoints are disabled")
+}
+
+// RequireWritable rejects requests with 503 when the server is read-only,
+// before the handler decodes or validates anything
+func (h *Handler) RequireWritable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions && h.blockchainService.ReadOnly() {
+			h.respondReadOnly(w)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// respondWithError sends problem details with the default code of the status
+func (h *Handler) respondWithError(w http.ResponseWriter, status int, message, details string) {
+	h.respondWithCode(w, status, "", message, details)
+}
+
+// respondWithCode sends problem details with a machine-readable code
+func (h *Handler) respondWithCode(w http.ResponseWriter, status int, code, message, details string) {
+	log.Printf("📤 Sending error response - Status: %d, Code: %s, Error: %s", status, code, message)
+	apperr.Write(w, nil, status, code, message, details)
 }
 
-// respondWithJSON sends a JSON response
+// respondWithJSON sends a JSON response. Only the payload type is logged,
+// since responses may carry API keys and session tokens
 func (h *Handler) respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	log.Printf("📤 Sending JSON response - Status: %d, Payload: %+v", code, payload)
+	log.Printf("📤 Sending JSON response - Status: %d, Payload: %T", code, payload)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"blockchain-demo/internal/ids"
+	"blockchain-demo/internal/models"
+	"net/http"
+)
+
+// PreviewID handles GET /api/v1/ids/preview?type=&creator=&name=&nonce=
+// It returns the deterministic ID a create request with the same fields and
+// nonce would get, and whether it is already taken on-chain.
+func (h *Handler) PreviewID(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entity := query.Get("type")
+	id, err := ids.Derive(entity, query.Get("creator"), query.Get("name"), query.Get("nonce"))
+	if err != nil {
+		h.respondWithCode(w, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid ID parameters", err.Error())
+		return
+	}
+
+	taken, err := h.blockchainService.IDTaken(entity, id)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to check ID", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.IDPreviewResponse{
+		Success: true,
+		Data:    &models.IDPreview{ID: id, Type: entity, Taken: taken},
+	})
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/judging"
+	"blockchain-demo/internal/models"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// WithJudging enables rubrics, judges and scoring
+func WithJudging(board *judging.Board) Option {
+	return func(h *Handler) {
+		h.judging = board
+	}
+}
+
+// organizerContest looks a contest up on behalf of its organizer. When
+// role-based access control is enabled, only the contest's organizer (or an
+// admin) gets it; otherwise it writes the error response.
+func (h *Handler) organizerContest(w http.ResponseWriter, r *http.Request, id string) (*models.Contest, bool) {
+	if !h.authorize(w, r, auth.RoleOrganizer) {
+		return nil, false
+	}
+
+	contest, ok := h.findContest(w, id)
+	if !ok {
+		return nil, false
+	}
+	if h.roles != nil && !isOrganizer(auth.IdentityFromContext(r.Context()), contest) {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the contest's organizer can manage it")
+		return nil, false
+	}
+	return contest, true
+}
+
+// findContest looks a contest up. A contest missing on-chain is a 404; any
+// other failure, such as the node being unreachable, is reported as it is.
+func (h *Handler) findContest(w http.ResponseWriter, id string) (*models.Contest, bool) {
+	contest, err := h.blockchainService.GetContest(id)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get contest", err)
+		return nil, false
+	}
+	if !contest.Success || contest.Data == nil {
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+		return nil, false
+	}
+	return contest.Data, true
+}
+
+// judgingViewer checks that the caller is signed in and judges or organizes a
+// contest whose results are not final yet. It reports whether the caller is
+// the organizer; otherwise it writes the error response.
+func (h *Handler) judgingViewer(w http.ResponseWriter, r *http.Request, contestID string) (organizer bool, ok bool) {
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil || callerAddress(r) == "" {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "sign in as a judge or the organizer of the contest")
+		return false, false
+	}
+
+	contest, found := h.findContest(w, contestID)
+	if !found {
+		return false, false
+	}
+	if isOrganizer(identity, contest) {
+		return true, true
+	}
+	if !h.judging.IsJudge(contestID, callerAddress(r)) {
+		h.respondWithCode(w, http.StatusForbidden, models.ErrorCodeNotAJudge, "Permission denied", "only the judges and the organizer see judging before the results are final")
+		return false, false
+	}
+	return false, true
+}
+
+// ============ JUDGING HANDLERS ============
+
+// SetRubric handles PUT /api/v1/contests/{id}/judging/rubric
+func (h *Handler) SetRubric(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	var req models.SetRubricRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	rubric, err := h.judging.SetRubric(contest.ID, &req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to set rubric", err)
+		return
+	}
+
+	log.Printf("📐 Rubric of contest %s set: %d criteria, %s", contest.ID, len(rubric.Criteria), rubric.Aggregation)
+
+	h.respondWithJSON(w, http.StatusOK, models.RubricResponse{
+		Success: true,
+		Message: "Rubric saved",
+		Data:    rubric,
+	})
+}
+
+// GetRubric handles GET /api/v1/contests/{id}/judging/rubric
+func (h *Handler) GetRubric(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	rubric, exists := h.judging.Rubric(mux.Vars(r)["id"])
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Rubric not found", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.RubricResponse{
+		Success: true,
+		Data:    rubric,
+	})
+}
+
+// AssignJudges handles POST /api/v1/contests/{id}/judging/judges
+func (h *Handler) AssignJudges(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	var req models.AssignJudgesRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	rubric, err := h.judging.AssignJudges(contest.ID, req.Judges)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to assign judges", err)
+		return
+	}
+
+	log.Printf("🧑‍⚖️ Contest %s now has %d judges", contest.ID, len(rubric.Judges))
+
+	h.respondWithJSON(w, http.StatusOK, models.RubricResponse{
+		Success: true,
+		Message: "Judges assigned",
+		Data:    rubric,
+	})
+}
+
+// SubmitScores handles PUT /api/v1/contests/{id}/judging/scores/{contestantId}
+func (h *Handler) SubmitScores(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	var req models.SubmitScoresRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	// Judges score as their signed-in wallet; the judge named in the body is
+	// only trusted when role-based access control is disabled
+	if h.roles != nil && auth.IdentityFromContext(r.Context()) == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "")
+		return
+	}
+	attribute(r, &req.Judge)
+	if req.Judge == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Judge is required", "")
+		return
+	}
+
+	vars := mux.Vars(r)
+	contest, ok := h.findContest(w, vars["id"])
+	if !ok {
+		return
+	}
+
+	registered, err := h.blockchainService.IsContestantRegistered(contest.ID, vars["contestantId"])
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to check registration", err)
+		return
+	}
+	if !registered {
+		h.respondWithServiceError(w, "Contestant not registered",
+			apperr.NotFound("contestant %s is not registered for contest %s", vars["contestantId"], contest.ID))
+		return
+	}
+
+	scorecard, err := h.judging.Submit(contest, vars["contestantId"], &req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to submit scores", err)
+		return
+	}
+
+	log.Printf("📝 Judge %s scored %s in contest %s", scorecard.Judge, scorecard.ContestantID, scorecard.ContestID)
+
+	h.respondWithJSON(w, http.StatusOK, models.ScorecardResponse{
+		Success: true,
+		Message: "Scores submitted",
+		Data:    scorecard,
+	})
+}
+
+// ListScorecards handles GET /api/v1/contests/{id}/judging/scores. Until the
+// results are final, judges only see their own scorecards and the organizer
+// sees every scorecard; nobody else sees any.
+func (h *Handler) ListScorecards(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	contestID := mux.Vars(r)["id"]
+	organizer := false
+	if !h.judging.Final(contestID) {
+		var ok bool
+		if organizer, ok = h.judgingViewer(w, r, contestID); !ok {
+			return
+		}
+	}
+
+	scorecards := h.judging.Scorecards(contestID, callerAddress(r), organizer)
+	h.respondWithJSON(w, http.StatusOK, models.ListScorecardsResponse{
+		Success:   true,
+		ContestID: contestID,
+		Data:      scorecards,
+		Total:     len(scorecards),
+	})
+}
+
+// GetJudgingResults handles GET /api/v1/contests/{id}/judging/results. Final
+// results are read from blockchain and public; provisional results are only
+// shown to the signed-in organizer.
+func (h *Handler) GetJudgingResults(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	contestID := mux.Vars(r)["id"]
+	final, err := h.blockchainService.GetContestResults(contestID)
+	if err == nil && final.Success {
+		h.respondWithJSON(w, http.StatusOK, final)
+		return
+	}
+	if err != nil && apperr.KindOf(err) != apperr.KindNotFound {
+		h.respondWithServiceError(w, "Failed to get results", err)
+		return
+	}
+
+	organizer, ok := h.judgingViewer(w, r, contestID)
+	if !ok {
+		return
+	}
+	if !organizer {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the contest's organizer sees provisional results")
+		return
+	}
+
+	results, exists := h.judging.Results(contestID)
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Results not found", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.ContestResultsResponse{
+		Success: true,
+		Message: "Provisional results",
+		Data:    results,
+	})
+}
+
+// FinalizeResults handles POST /api/v1/contests/{id}/judging/finalize
+func (h *Handler) FinalizeResults(w http.ResponseWriter, r *http.Request) {
+	if h.judging == nil {
+		h.respondWithError(w, http.StatusNotFound, "Judging is not enabled", "")
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	results, err := h.judging.Claim(contest, callerAddress(r))
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to finalize results", err)
+		return
+	}
+
+	log.Printf("🏆 Finalizing results of contest %s: %d entries, %d scorecards", contest.ID, len(results.Results), len(results.Scorecards))
+
+	response, err := h.chain(r).FinalizeResults(results)
+	if err != nil {
+		h.judging.Release(contest.ID)
+		h.respondWithServiceError(w, "Failed to finalize results", err)
+		return
+	}
+	if err := h.judging.Finalized(response.Data); err != nil {
+		log.Printf("⚠️ Results of contest %s are final on-chain but could not be saved: %v", contest.ID, err)
+	}
+	h.finalized(response.Data)
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/leaderboard"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/voting"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// heartbeatInterval keeps idle leaderboard streams open through proxies
+const heartbeatInterval = 15 * time.Second
+
+// WithLeaderboard enables live leaderboards
+func WithLeaderboard(hub *leaderboard.Hub) Option {
+	return func(h *Handler) {
+		h.leaderboard = hub
+	}
+}
+
+// loadLeaderboard makes sure the leaderboard of a contest is in memory,
+// building it from its registered contestants, final results and votes.
+// Judges' scores stay off the leaderboard until the results are final,
+// since scorecards are private while judging.
+func (h *Handler) loadLeaderboard(contest *models.Contest) error {
+	if h.leaderboard.Loaded(contest.ID) {
+		return nil
+	}
+
+	standings := make(map[string]*models.LeaderboardEntry)
+	entry := func(contestantID string) *models.LeaderboardEntry {
+		if standings[contestantID] == nil {
+			standings[contestantID] = &models.LeaderboardEntry{ContestantID: contestantID}
+		}
+		return standings[contestantID]
+	}
+
+	registered, err := h.blockchainService.GetContestantsInContest(contest.ID)
+	if err != nil {
+		return err
+	}
+	candidates := make([]string, 0, len(registered.Contestants))
+	for _, contestant := range registered.Contestants {
+		candidates = append(candidates, contestant.ID)
+		entry(contestant.ID)
+	}
+
+	final, err := h.blockchainService.GetContestResults(contest.ID)
+	if err != nil && apperr.KindOf(err) != apperr.KindNotFound {
+		return err
+	}
+	if err == nil && final.Success && final.Data != nil {
+		for _, result := range final.Data.Results {
+			e := entry(result.ContestantID)
+			e.Score = result.Score
+			e.Criteria = result.Criteria
+			e.Judges = result.Judges
+		}
+	}
+
+	if contest.Voting != nil {
+		votes, err := h.blockchainService.GetVotes(contest.ID)
+		if err != nil {
+			return err
+		}
+		for _, count := range voting.Tally(contest, candidates, votes, time.Now()).Results {
+			entry(count.ContestantID).Votes = count.Votes
+		}
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(standings))
+	for _, e := range standings {
+		entries = append(entries, *e)
+	}
+	h.leaderboard.Load(contest.ID, entries)
+	return nil
+}
+
+// finalized puts the final results of a contest on its leaderboard
+func (h *Handler) finalized(results *models.ContestResults) {
+	if h.leaderboard == nil || results == nil {
+		return
+	}
+	for _, result := range results.Results {
+		h.leaderboard.SetScore(results.ContestID, result)
+	}
+}
+
+// rosterChanged keeps a loaded leaderboard in step with the roster of its
+// contest: the entry that left is dropped and the entry that joined the
+// registered contestants is added with the votes it already has. Either may
+// be empty.
+func (h *Handler) rosterChanged(contestID, joined, left string) {
+	if h.leaderboard == nil || !h.leaderboard.Loaded(contestID) {
+		return
+	}
+	if left != "" {
+		h.leaderboard.Withdraw(contestID, left)
+	}
+	if joined == "" {
+		return
+	}
+
+	votes := 0
+	contest, err := h.blockchainService.GetContest(contestID)
+	if err == nil && contest.Success && contest.Data != nil && contest.Data.Voting != nil {
+		all, err := h.blockchainService.GetVotes(contestID)
+		if err != nil {
+			log.Printf("⚠️ Failed to count the votes of %s in contest %s: %v", joined, contestID, err)
+		}
+		for _, count := range voting.Tally(contest.Data, []string{joined}, all, time.Now()).Results {
+			votes = count.Votes
+		}
+	}
+	h.leaderboard.Register(contestID, joined, votes)
+}
+
+// voted moves an entry on the leaderboard after a wallet revealed a valid
+// vote for it. Votes for someone who is not registered are not counted.
+func (h *Handler) voted(vote *models.Vote) {
+	if h.leaderboard == nil || vote == nil || !vote.Verified {
+		return
+	}
+	registered, err := h.blockchainService.IsContestantRegistered(vote.ContestID, vote.ContestantID)
+	if err != nil || !registered {
+		return
+	}
+	h.leaderboard.AddVote(vote.ContestID, vote.ContestantID)
+}
+
+// leaderboardRequest reads the contest and entry limit of a leaderboard
+// request, loading the leaderboard if needed
+func (h *Handler) leaderboardRequest(w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	if h.leaderboard == nil {
+		h.respondWithError(w, http.StatusNotFound, "Leaderboard is not enabled", "")
+		return "", 0, false
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			h.respondWithServiceError(w, "Invalid limit", apperr.Fields([]models.FieldError{
+				{Field: "limit", Rule: "min", Message: "Limit must be a non-negative number"},
+			}))
+			return "", 0, false
+		}
+		limit = n
+	}
+
+	contest, ok := h.findContest(w, mux.Vars(r)["id"])
+	if !ok {
+		return "", 0, false
+	}
+	if err := h.loadLeaderboard(contest); err != nil {
+		h.respondWithServiceError(w, "Failed to load leaderboard", err)
+		return "", 0, false
+	}
+	return contest.ID, limit, true
+}
+
+// ============ LEADERBOARD HANDLERS ============
+
+// GetLeaderboard handles GET /api/v1/contests/{id}/leaderboard?limit=10
+func (h *Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	contestID, limit, ok := h.leaderboardRequest(w, r)
+	if !ok {
+		return
+	}
+
+	board, _ := h.leaderboard.Snapshot(contestID, limit)
+	h.respondWithJSON(w, http.StatusOK, models.LeaderboardResponse{
+		Success: true,
+		Data:    board,
+	})
+}
+
+// StreamLeaderboard handles GET /api/v1/contests/{id}/leaderboard/stream. It
+// sends the leaderboard as a server-sent event right away and again after
+// every change, until the client disconnects.
+func (h *Handler) StreamLeaderboard(w http.ResponseWriter, r *http.Request) {
+	contestID, limit, ok := h.leaderboardRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updates, cancel := h.leaderboard.Subscribe(contestID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	log.Printf("📡 Streaming leaderboard of contest %s", contestID)
+
+	var sent uint64
+	send := func() error {
+		board, _ := h.leaderboard.Snapshot(contestID, limit)
+		if board.Version == sent {
+			return nil
+		}
+		data, err := json.Marshal(board)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: leaderboard\ndata: %s\n\n", board.Version, data); err != nil {
+			return err
+		}
+		sent = board.Version
+		return rc.Flush()
+	}
+	if err := send(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-updates:
+			if err := send(); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ============ CONTEST LIFECYCLE HANDLERS ============
+
+// OpenContest handles POST /api/v1/contests/{id}/open
+func (h *Handler) OpenContest(w http.ResponseWriter, r *http.Request) {
+	h.transitionContest(w, r, models.ContestOpen)
+}
+
+// CloseContest handles POST /api/v1/contests/{id}/close
+func (h *Handler) CloseContest(w http.ResponseWriter, r *http.Request) {
+	h.transitionContest(w, r, models.ContestClosed)
+}
+
+// CancelContest handles POST /api/v1/contests/{id}/cancel
+func (h *Handler) CancelContest(w http.ResponseWriter, r *http.Request) {
+	h.transitionContest(w, r, models.ContestCancelled)
+}
+
+// transitionContest moves a contest to a new state on behalf of its
+// organizer. The body, with an optional reason, may be omitted.
+func (h *Handler) transitionContest(w http.ResponseWriter, r *http.Request, to string) {
+	id := mux.Vars(r)["id"]
+
+	var req models.TransitionContestRequest
+	if r.ContentLength != 0 && !h.decode(w, r, &req) {
+		return
+	}
+
+	if !h.authorize(w, r, auth.RoleOrganizer) {
+		return
+	}
+	if h.roles != nil {
+		contest, err := h.blockchainService.GetContest(id)
+		if err != nil || !contest.Success || contest.Data == nil {
+			h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+			return
+		}
+		if !isOrganizer(auth.IdentityFromContext(r.Context()), contest.Data) {
+			h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the contest's organizer can change its state")
+			return
+		}
+	}
+
+	log.Printf("🔁 Moving contest %s to %s", id, to)
+
+	response, err := h.chain(r).TransitionContest(id, to, req.Reason, callerAddress(r))
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to change contest state", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
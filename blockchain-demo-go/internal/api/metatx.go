@@ -0,0 +1,274 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/metatx"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/wiki"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+)
+
+// Prepared calls waiting to be relayed, in total and per signer. Calls expire
+// after META_TX_TTL; the caps stop callers from filling memory before then.
+const (
+	maxMetaCalls          = 10000
+	maxMetaCallsPerSigner = 10
+)
+
+var errTooManyMetaCalls = errors.New("too many prepared requests waiting to be relayed")
+
+// metaCall is a contract call prepared for a user to sign, waiting to be relayed
+type metaCall struct {
+	id       string
+	from     common.Address
+	item     *models.TaxonomyItem
+	deadline time.Time
+}
+
+// metaCalls keeps prepared calls by calldata. Only prepared calls are relayed,
+// so the server never pays gas for calls it did not build.
+type metaCalls struct {
+	mu    sync.Mutex
+	calls map[string]*metaCall
+}
+
+func (c *metaCalls) add(data string, call *metaCall) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	pending := 0
+	for key, prepared := range c.calls {
+		if now.After(prepared.deadline) {
+			delete(c.calls, key)
+			continue
+		}
+		if prepared.from == call.from {
+			pending++
+		}
+	}
+	if len(c.calls) >= maxMetaCalls || pending >= maxMetaCallsPerSigner {
+		return errTooManyMetaCalls
+	}
+	c.calls[strings.ToLower(data)] = call
+	return nil
+}
+
+func (c *metaCalls) get(data string) (*metaCall, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := strings.ToLower(data)
+	call, ok := c.calls[key]
+	if ok && time.Now().After(call.deadline) {
+		delete(c.calls, key)
+		return nil, false
+	}
+	return call, ok
+}
+
+func (c *metaCalls) remove(data string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, strings.ToLower(data))
+}
+
+// WithRelayer enables meta-transactions relayed through the forwarder
+func WithRelayer(relayer *metatx.Relayer) Option {
+	return func(h *Handler) {
+		h.relayer = relayer
+		h.metaCalls = &metaCalls{calls: make(map[string]*metaCall)}
+	}
+}
+
+// ============ META-TRANSACTION HANDLERS ============
+
+// PrepareMetaContest handles POST /api/v1/meta/contests
+// It returns the EIP-712 request the signed-in organizer signs to create the contest.
+func (h *Handler) PrepareMetaContest(w http.ResponseWriter, r *http.Request) {
+	if h.relayer == nil {
+		h.respondWithError(w, http.StatusNotFound, "Meta-transactions are not enabled", "")
+		return
+	}
+
+	var req models.PrepareMetaContestRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	address := callerAddress(r)
+	if address == "" {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "meta-transactions are signed by the wallet of the caller")
+		return
+	}
+	from := common.HexToAddress(address)
+	if req.From != "" && common.HexToAddress(req.From) != from {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "from must be the signed-in wallet")
+		return
+	}
+	if h.roles != nil && !h.roles.Has(from.Hex(), auth.RoleOrganizer) && !h.roles.Has(from.Hex(), auth.RoleAdmin) {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "requires role organizer")
+		return
+	}
+
+	contest := &req.CreateContestRequest
+	contest.Categories = wiki.NormalizeCategories(contest.Categories)
+	contest.Tags = wiki.NormalizeTags(contest.Tags)
+	contest.Organizer = from.Hex()
+
+	chainNonce, err := h.blockchainService.ForwarderNonce(from.Hex())
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to read forwarder nonce", err)
+		return
+	}
+
+	id, data, err := h.blockchainService.EncodeCreateContest(contest)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to prepare contest", err)
+		return
+	}
+
+	request := h.relayer.NewRequest(from, data, chainNonce)
+	hash, err := h.relayer.Hash(request)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to hash request", err)
+		return
+	}
+
+	err = h.metaCalls.add(request.Data, &metaCall{
+		id:   id,
+		from: from,
+		item: &models.TaxonomyItem{
+			Kind:       "contest",
+			ID:         id,
+			Title:      contest.Name,
+			Categories: contest.Categories,
+			Tags:       contest.Tags,
+		},
+		deadline: time.Unix(int64(request.Deadline), 0),
+	})
+	if err != nil {
+		h.respondWithCode(w, http.StatusTooManyRequests, models.ErrorCodeRateLimited, "Too many pending meta-transactions", err.Error())
+		return
+	}
+
+	log.Printf("✍️ Prepared contest %s for signature by %s (nonce %d)", id, from.Hex(), request.Nonce)
+
+	h.respondWithJSON(w, http.StatusOK, models.PrepareMetaTxResponse{
+		Success:   true,
+		Message:   "Sign typed_data with eth_signTypedData_v4 and POST it to /api/v1/meta/relay",
+		ID:        id,
+		Request:   request,
+		TypedData: h.relayer.TypedData(request),
+		Hash:      hexutil.Encode(hash),
+	})
+}
+
+// RelayMetaTx handles POST /api/v1/meta/relay
+func (h *Handler) RelayMetaTx(w http.ResponseWriter, r *http.Request) {
+	if h.relayer == nil {
+		h.respondWithError(w, http.StatusNotFound, "Meta-transactions are not enabled", "")
+		return
+	}
+
+	var req models.RelayMetaTxRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	signature, err := hexutil.Decode(req.Signature)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid signature encoding", err.Error())
+		return
+	}
+
+	call, ok := h.metaCalls.get(req.Request.Data)
+	if !ok {
+		h.respondWithError(w, http.StatusNotFound, "Request not found or expired", "prepare the request before relaying it")
+		return
+	}
+	if !common.IsHexAddress(req.Request.From) || common.HexToAddress(req.Request.From) != call.from || common.HexToAddress(callerAddress(r)) != call.from {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the wallet the request was prepared for can relay it")
+		return
+	}
+
+	chainNonce, err := h.blockchainService.ForwarderNonce(req.Request.From)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to read forwarder nonce", err)
+		return
+	}
+
+	signer, err := h.relayer.Claim(req.Request, signature, chainNonce)
+	if err != nil {
+		log.Printf("🔒 Rejected meta-transaction from %s: %v", req.Request.From, err)
+		h.respondWithError(w, metaTxStatus(err), "Invalid meta-transaction", err.Error())
+		return
+	}
+
+	txHash, err := h.chain(r).Relay(req.Request, signature)
+	if err != nil {
+		h.relayer.Release(req.Request)
+		h.respondWithServiceError(w, "Failed to relay request", err)
+		return
+	}
+
+	h.metaCalls.remove(req.Request.Data)
+	h.taxonomy.Index(call.item)
+
+	log.Printf("📨 Relayed %s signed by %s: %s", call.id, signer.Hex(), txHash)
+
+	h.respondWithJSON(w, http.StatusCreated, models.RelayMetaTxResponse{
+		Success: true,
+		Message: "Request relayed to blockchain",
+		ID:      call.id,
+		Signer:  signer.Hex(),
+		TxHash:  txHash,
+	})
+}
+
+// GetMetaNonce handles GET /api/v1/meta/nonce/{address}
+func (h *Handler) GetMetaNonce(w http.ResponseWriter, r *http.Request) {
+	if h.relayer == nil {
+		h.respondWithError(w, http.StatusNotFound, "Meta-transactions are not enabled", "")
+		return
+	}
+
+	address := mux.Vars(r)["address"]
+	if !common.IsHexAddress(address) {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid address", "")
+		return
+	}
+	from := common.HexToAddress(address)
+
+	chainNonce, err := h.blockchainService.ForwarderNonce(from.Hex())
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to read forwarder nonce", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.MetaNonceResponse{
+		Success: true,
+		Address: from.Hex(),
+		Nonce:   h.relayer.NextNonce(from, chainNonce),
+	})
+}
+
+// metaTxStatus maps a rejected forward request to an HTTP status
+func metaTxStatus(err error) int {
+	switch {
+	case errors.Is(err, metatx.ErrSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, metatx.ErrNonce):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
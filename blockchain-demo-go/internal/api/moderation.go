@@ -0,0 +1,220 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/moderation"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// moderate runs the automatic checks on a content submission and, when the
+// moderation queue is enabled, queues it. It returns true when a response has
+// already been written and the content must not be pushed to blockchain.
+func (h *Handler) moderate(w http.ResponseWriter, req *models.CreateContentRequest, targetID string) bool {
+	if violations := h.contentRules.Check(req.Title, req.Content); len(violations) > 0 {
+		log.Printf("🚫 Content rejected by automatic checks: %v", violations)
+
+		submission := &models.Submission{
+			Status:     models.SubmissionRejected,
+			TargetID:   targetID,
+			Request:    *req,
+			Violations: violations,
+		}
+		if h.moderationQueue != nil {
+			submission = h.moderationQueue.RecordRejected(req, targetID, violations)
+		}
+		h.respondWithJSON(w, http.StatusUnprocessableEntity, models.SubmissionResponse{
+			Success: false,
+			Message: "Content rejected by automatic checks",
+			Data:    submission,
+		})
+		return true
+	}
+
+	if h.moderationQueue == nil {
+		return false
+	}
+
+	submission := h.moderationQueue.Submit(req, targetID)
+	log.Printf("🕵️ Content queued for moderation: %s", submission.ID)
+
+	h.respondWithJSON(w, http.StatusAccepted, models.SubmissionResponse{
+		Success: true,
+		Message: "Content queued for moderation",
+		Data:    submission,
+	})
+	return true
+}
+
+// ============ MODERATION HANDLERS ============
+
+// ListSubmissions handles GET /api/v1/moderation/submissions?status=pending|approved|rejected
+func (h *Handler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	if h.moderationQueue == nil {
+		h.respondWithError(w, http.StatusNotFound, "Moderation is not enabled", "")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.SubmissionPending, models.SubmissionApproved, models.SubmissionRejected:
+	default:
+		h.respondWithError(w, http.StatusBadRequest, "Invalid status", "status must be pending, approved or rejected")
+		return
+	}
+
+	log.Printf("🕵️ Listing submissions (status=%q)", status)
+
+	submissions := h.moderationQueue.List(status)
+	h.respondWithJSON(w, http.StatusOK, models.ListSubmissionsResponse{
+		Success: true,
+		Data:    submissions,
+		Total:   len(submissions),
+	})
+}
+
+// GetSubmission handles GET /api/v1/moderation/submissions/{id}
+func (h *Handler) GetSubmission(w http.ResponseWriter, r *http.Request) {
+	if h.moderationQueue == nil {
+		h.respondWithError(w, http.StatusNotFound, "Moderation is not enabled", "")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	submission, exists := h.moderationQueue.Get(id)
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Submission not found", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.SubmissionResponse{
+		Success: true,
+		Data:    submission,
+	})
+}
+
+// ApproveSubmission handles POST /api/v1/moderation/submissions/{id}/approve
+func (h *Handler) ApproveSubmission(w http.ResponseWriter, r *http.Request) {
+	if h.moderationQueue == nil {
+		h.respondWithError(w, http.StatusNotFound, "Moderation is not enabled", "")
+		return
+	}
+
+	moderator, ok := h.authorizeModerator(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var req models.ReviewSubmissionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	submission, err := h.moderationQueue.Claim(id)
+	if err != nil {
+		h.respondWithModerationError(w, err)
+		return
+	}
+
+	log.Printf("✅ Approving submission %s by %s", id, moderator)
+
+	// Only approved content is pushed to blockchain
+	var response *models.CreateContentResponse
+	if submission.TargetID == "" {
+		response, err = h.chain(r).StoreContent(&submission.Request)
+	} else {
+		response, err = h.chain(r).UpdateContent(submission.TargetID, &submission.Request)
+	}
+	if err != nil {
+		h.moderationQueue.Release(id)
+		h.respondWithServiceError(w, "Failed to push approved content", err)
+		return
+	}
+
+	h.indexContent(&submission.Request, response)
+
+	submission, err = h.moderationQueue.Approve(id, moderator, req.Reason, response)
+	if err != nil {
+		h.respondWithModerationError(w, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.SubmissionResponse{
+		Success: true,
+		Message: "Submission approved and pushed to blockchain",
+		Data:    submission,
+	})
+}
+
+// RejectSubmission handles POST /api/v1/moderation/submissions/{id}/reject
+func (h *Handler) RejectSubmission(w http.ResponseWriter, r *http.Request) {
+	if h.moderationQueue == nil {
+		h.respondWithError(w, http.StatusNotFound, "Moderation is not enabled", "")
+		return
+	}
+
+	moderator, ok := h.authorizeModerator(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var req models.ReviewSubmissionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if req.Reason == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Reason is required", "")
+		return
+	}
+
+	log.Printf("⛔ Rejecting submission %s by %s: %s", id, moderator, req.Reason)
+
+	submission, err := h.moderationQueue.Reject(id, moderator, req.Reason)
+	if err != nil {
+		h.respondWithModerationError(w, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.SubmissionResponse{
+		Success: true,
+		Message: "Submission rejected",
+		Data:    submission,
+	})
+}
+
+// authorizeModerator requires a signed-in caller with the moderation scope or
+// the moderator role, even when authentication and RBAC are off, and returns
+// the account recorded as the reviewer
+func (h *Handler) authorizeModerator(w http.ResponseWriter, r *http.Request) (string, bool) {
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "reviewing submissions requires a moderator")
+		return "", false
+	}
+	if !identity.HasScope(auth.ScopeModeration) && !identity.HasRole(auth.RoleModerator) {
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "requires scope "+auth.ScopeModeration+" or role "+auth.RoleModerator)
+		return "", false
+	}
+	return identity.Account(), true
+}
+
+// respondWithModerationError maps moderation queue errors to HTTP statuses
+func (h *Handler) respondWithModerationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, moderation.ErrNotFound):
+		h.respondWithError(w, http.StatusNotFound, "Submission not found", "")
+	case errors.Is(err, moderation.ErrAlreadyHandled), errors.Is(err, moderation.ErrInProgress):
+		h.respondWithError(w, http.StatusConflict, "Submission cannot be reviewed", err.Error())
+	default:
+		h.respondWithServiceError(w, "Moderation failed", err)
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"blockchain-demo/internal/models"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// WithPrizePools enables the escrowed prize pool endpoints, backed by the
+// PrizeEscrow contract. They also need WithRoles: without role-based access
+// control anyone could pay out or refund a pool.
+func WithPrizePools() Option {
+	return func(h *Handler) {
+		h.prizePools = true
+	}
+}
+
+// prizePoolsEnabled writes a 404 unless prize pools are enabled along with
+// role-based access control
+func (h *Handler) prizePoolsEnabled(w http.ResponseWriter) bool {
+	if !h.prizePools {
+		h.respondWithError(w, http.StatusNotFound, "Prize pools are not enabled", "")
+		return false
+	}
+	if h.roles == nil {
+		h.respondWithError(w, http.StatusNotFound, "Prize pools are not enabled", "prize pools require role-based access control")
+		return false
+	}
+	return true
+}
+
+// ============ PRIZE POOL HANDLERS ============
+
+// OpenPrizePool handles PUT /api/v1/contests/{id}/prize-pool
+func (h *Handler) OpenPrizePool(w http.ResponseWriter, r *http.Request) {
+	if !h.prizePoolsEnabled(w) {
+		return
+	}
+
+	var req models.OpenPrizePoolRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+	req.ContestID = contest.ID
+
+	response, err := h.chain(r).OpenPrizePool(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to open prize pool", err)
+		return
+	}
+
+	log.Printf("💰 Opened prize pool of contest %s with %d places", contest.ID, len(req.SharesBps))
+
+	h.respondWithJSON(w, http.StatusCreated, response)
+}
+
+// GetPrizePool handles GET /api/v1/contests/{id}/prize-pool. Deposits are
+// public: they are read from the escrow's events.
+func (h *Handler) GetPrizePool(w http.ResponseWriter, r *http.Request) {
+	if !h.prizePoolsEnabled(w) {
+		return
+	}
+
+	response, err := h.blockchainService.GetPrizePool(mux.Vars(r)["id"])
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get prize pool", err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// PayoutPrizePool handles POST /api/v1/contests/{id}/prize-pool/payout. The
+// body is optional; it carries the payout addresses winners signed.
+func (h *Handler) PayoutPrizePool(w http.ResponseWriter, r *http.Request) {
+	if !h.prizePoolsEnabled(w) {
+		return
+	}
+
+	var req models.PrizePayoutRequest
+	if r.ContentLength != 0 && !h.decode(w, r, &req) {
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+	req.ContestID = contest.ID
+
+	response, err := h.chain(r).PayoutPrizePool(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to pay out prize pool", err)
+		return
+	}
+
+	log.Printf("🏆 Paid out prize pool of contest %s: %s", contest.ID, response.Message)
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// RefundPrizePool handles POST /api/v1/contests/{id}/prize-pool/refund, for
+// closed or cancelled contests whose pool was not paid out
+func (h *Handler) RefundPrizePool(w http.ResponseWriter, r *http.Request) {
+	if !h.prizePoolsEnabled(w) {
+		return
+	}
+
+	contest, ok := h.organizerContest(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	response, err := h.chain(r).RefundPrizePool(contest.ID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to refund prize pool", err)
+		return
+	}
+
+	log.Printf("↩️ Refunded prize pool of contest %s: %s", contest.ID, response.Message)
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/ratelimit"
+	"net/http"
+)
+
+// WithRateLimiter reports the caller's limits on GET /api/v1/me/quota
+func WithRateLimiter(limiter *ratelimit.RateLimiter) Option {
+	return func(h *Handler) {
+		h.rateLimiter = limiter
+	}
+}
+
+// GetQuota handles GET /api/v1/me/quota
+func (h *Handler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	if h.rateLimiter == nil {
+		h.respondWithError(w, http.StatusNotFound, "Rate limiting is not enabled", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.QuotaResponse{
+		Success: true,
+		Data:    h.rateLimiter.Quota(r),
+	})
+}
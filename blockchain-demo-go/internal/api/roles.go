@@ -0,0 +1,154 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+// WithRoles enables role-based access control
+func WithRoles(roles *auth.RoleStore) Option {
+	return func(h *Handler) {
+		h.roles = roles
+	}
+}
+
+// authorize checks that the caller holds one of the roles. It always passes
+// when role-based access control is disabled; otherwise it writes 401 for
+// anonymous callers and 403 for callers without any of the roles.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, roles ...string) bool {
+	if h.roles == nil {
+		return true
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "")
+		return false
+	}
+	for _, role := range roles {
+		if identity.HasRole(role) {
+			return true
+		}
+	}
+
+	h.respondWithError(w, http.StatusForbidden, "Permission denied", "requires role "+strings.Join(roles, " or "))
+	return false
+}
+
+// isOrganizer reports whether the caller organizes a contest (or is an admin)
+func isOrganizer(identity *auth.Identity, contest *models.Contest) bool {
+	if identity.HasRole(auth.RoleAdmin) {
+		return true
+	}
+	return sameAccount(identity.Account(), contest.Organizer)
+}
+
+// sameAccount compares two accounts, ignoring the case of wallet addresses
+func sameAccount(a, b string) bool {
+	return a != "" && auth.NormalizeAccount(a) == auth.NormalizeAccount(b)
+}
+
+// ============ ROLE HANDLERS ============
+
+// ListRoles handles GET /api/v1/admin/roles?account=
+func (h *Handler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	if h.roles == nil {
+		h.respondWithError(w, http.StatusNotFound, "Role-based access control is not enabled", "")
+		return
+	}
+
+	assignments := h.roles.Assignments(r.URL.Query().Get("account"))
+	h.respondWithJSON(w, http.StatusOK, models.ListRolesResponse{
+		Success: true,
+		Data:    assignments,
+		Total:   len(assignments),
+	})
+}
+
+// GrantRole handles POST /api/v1/admin/roles
+func (h *Handler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	if h.roles == nil {
+		h.respondWithError(w, http.StatusNotFound, "Role-based access control is not enabled", "")
+		return
+	}
+
+	var req models.GrantRoleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	req.Account = auth.NormalizeAccount(req.Account)
+	req.Role = strings.ToLower(strings.TrimSpace(req.Role))
+	if req.Account == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Account is required", "")
+		return
+	}
+	if !auth.IsValidRole(req.Role) {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid role", "role must be one of admin, organizer, moderator, contestant, sponsor")
+		return
+	}
+
+	// Wallet roles are mirrored on-chain first so the audit trail never misses a grant
+	var txHash string
+	if common.IsHexAddress(req.Account) {
+		var err error
+		if txHash, err = h.chain(r).GrantRole(req.Role, req.Account); err != nil {
+			h.respondWithServiceError(w, "Failed to grant role on blockchain", err)
+			return
+		}
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	assignment := h.roles.Grant(req.Account, req.Role, identity.Subject, txHash)
+
+	log.Printf("🛡️ Role %s granted to %s by %s", req.Role, req.Account, identity.Subject)
+
+	h.respondWithJSON(w, http.StatusCreated, models.RoleAssignmentResponse{
+		Success: true,
+		Message: "Role granted",
+		TxHash:  txHash,
+		Data:    assignment,
+	})
+}
+
+// RevokeRole handles DELETE /api/v1/admin/roles/{account}/{role}
+func (h *Handler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	if h.roles == nil {
+		h.respondWithError(w, http.StatusNotFound, "Role-based access control is not enabled", "")
+		return
+	}
+
+	vars := mux.Vars(r)
+	account := auth.NormalizeAccount(vars["account"])
+	role := strings.ToLower(vars["role"])
+
+	if !h.roles.Has(account, role) {
+		h.respondWithError(w, http.StatusNotFound, "Role assignment not found", "")
+		return
+	}
+
+	var txHash string
+	if common.IsHexAddress(account) {
+		var err error
+		if txHash, err = h.chain(r).RevokeRole(role, account); err != nil {
+			h.respondWithServiceError(w, "Failed to revoke role on blockchain", err)
+			return
+		}
+	}
+	h.roles.Revoke(account, role)
+
+	log.Printf("🛡️ Role %s revoked from %s", role, account)
+
+	h.respondWithJSON(w, http.StatusOK, models.RoleAssignmentResponse{
+		Success: true,
+		Message: "Role revoked",
+		TxHash:  txHash,
+		Data:    &models.RoleAssignment{Account: account, Role: role},
+	})
+}
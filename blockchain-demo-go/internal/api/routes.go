@@ -0,0 +1,205 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Middleware wraps a handler
+type Middleware func(http.HandlerFunc) http.HandlerFunc
+
+// Routes configures the guards RegisterRoutes puts on endpoints that spend gas
+type Routes struct {
+	// AuthRequired rejects anonymous callers and callers without the
+	// endpoint's scope; when off, write endpoints stay open
+	AuthRequired bool
+	// Idempotent replays retries with the same Idempotency-Key, nil disables
+	Idempotent Middleware
+	// Budget charges the caller's daily gas budget, nil disables
+	Budget Middleware
+}
+
+// RegisterRoutes mounts the API under /api/v1. Router-wide middleware
+// (logging, CORS, authentication, rate limits) is left to the caller.
+func (h *Handler) RegisterRoutes(router *mux.Router, routes Routes) {
+	idempotent := func(handler http.HandlerFunc) http.HandlerFunc {
+		if routes.Idempotent == nil {
+			return handler
+		}
+		return routes.Idempotent(handler)
+	}
+	budget := func(handler http.HandlerFunc) http.HandlerFunc {
+		if routes.Budget == nil {
+			return handler
+		}
+		return routes.Budget(handler)
+	}
+
+	// writable rejects endpoints that spend gas when the server is read-only,
+	// replays retries with the same Idempotency-Key, and charges the others
+	// to the caller's daily gas budget
+	writable := func(handler http.HandlerFunc) http.HandlerFunc {
+		return h.RequireWritable(idempotent(budget(handler)))
+	}
+
+	// write guards endpoints that spend gas; they stay open unless AUTH_REQUIRED is set
+	write := func(scope string, handler http.HandlerFunc) http.HandlerFunc {
+		if !routes.AuthRequired {
+			return writable(handler)
+		}
+		return writable(auth.RequireScope(scope)(handler))
+	}
+
+	v1 := router.PathPrefix("/api/v1").Subrouter()
+
+	// Auth endpoints
+	v1.HandleFunc("/auth/nonce", h.GetAuthNonce).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/auth/verify", h.VerifySIWE).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/auth/logout", h.Logout).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/auth/me", h.GetMe).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/me/quota", h.GetQuota).Methods("GET", "OPTIONS")
+
+	// Test endpoint
+	v1.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("✅ Test endpoint hit!")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"success":true,"message":"Test endpoint working!"}`))
+	}).Methods("GET", "OPTIONS")
+
+	// Add POST test endpoint specifically for testing contest creation
+	v1.HandleFunc("/test-post", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("🧪 Test POST endpoint called")
+
+		// Read the request body
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Printf("❌ Error reading request body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("📥 Received POST data: %s", string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"success":true,"message":"Test POST endpoint working!","txHash":"0xtest123","id":"test-id-123"}`))
+	}).Methods("POST", "OPTIONS")
+
+	// Content endpoints
+	v1.HandleFunc("/content", write(auth.ScopeContentWrite, h.CreateContent)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/content/{id}", h.GetContent).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/content/{id}", write(auth.ScopeContentWrite, h.UpdateContent)).Methods("PUT", "OPTIONS")
+	v1.HandleFunc("/contents", h.ListContents).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/content/{id}/links", h.GetContentLinks).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/content/{id}/backlinks", h.GetContentBacklinks).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/content/{id}/revisions", h.ListContentRevisions).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/content/{id}/diff", h.GetContentDiff).Methods("GET", "OPTIONS")
+
+	// Wiki graph endpoints
+	v1.HandleFunc("/wiki/graph", h.GetLinkGraph).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/wiki/maintenance/broken-links", h.GetBrokenLinksReport).Methods("GET", "OPTIONS")
+
+	// Deterministic ID preview
+	v1.HandleFunc("/ids/preview", h.PreviewID).Methods("GET", "OPTIONS")
+
+	// Category and tag endpoints
+	v1.HandleFunc("/categories", h.ListCategories).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/categories/{name:.+}", h.GetCategory).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/tags", h.GetTagCloud).Methods("GET", "OPTIONS")
+
+	// Fix Contest endpoints by using explicit subrouter for method separation
+	contests := v1.PathPrefix("/contests").Subrouter()
+	contests.HandleFunc("/search", h.SearchContestsHandler).Methods("GET", "OPTIONS")
+
+	contests.HandleFunc("", h.ListContests).Methods("GET", "OPTIONS")                                   // GET /contests
+	contests.HandleFunc("", write(auth.ScopeContestsWrite, h.CreateContest)).Methods("POST", "OPTIONS") // POST /contests
+	contests.HandleFunc("/{id}", h.GetContest).Methods("GET", "OPTIONS")                                // GET /contests/{id}
+	contests.HandleFunc("/{id}/open", write(auth.ScopeContestsWrite, h.OpenContest)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/close", write(auth.ScopeContestsWrite, h.CloseContest)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/cancel", write(auth.ScopeContestsWrite, h.CancelContest)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{contestId}/register", write(auth.ScopeContestsWrite, h.RegisterContestant)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{contestId}/register/{contestantId}", write(auth.ScopeContestsWrite, h.WithdrawContestant)).Methods("DELETE", "OPTIONS")
+	contests.HandleFunc("/{contestId}/contestants", h.GetContestantsInContest).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/teams", h.ListTeams).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/teams", write(auth.ScopeContestsWrite, h.CreateTeam)).Methods("POST", "OPTIONS")
+
+	// Judging endpoints (scores stay private to their judge until the organizer finalizes)
+	contests.HandleFunc("/{id}/judging/rubric", h.GetRubric).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/rubric", write(auth.ScopeContestsWrite, h.SetRubric)).Methods("PUT", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/judges", write(auth.ScopeContestsWrite, h.AssignJudges)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/scores", h.ListScorecards).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/scores/{contestantId}", write(auth.ScopeContestsWrite, h.SubmitScores)).Methods("PUT", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/results", h.GetJudgingResults).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/judging/finalize", write(auth.ScopeContestsWrite, h.FinalizeResults)).Methods("POST", "OPTIONS")
+
+	// Voting endpoints (wallets commit a hashed vote, then reveal it)
+	contests.HandleFunc("/{id}/votes/commit", write(auth.ScopeVote, h.CommitVote)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/votes/reveal", write(auth.ScopeVote, h.RevealVote)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/results", h.GetVoteResults).Methods("GET", "OPTIONS")
+
+	// Leaderboard endpoints (the stream sends server-sent events as scores and votes arrive)
+	contests.HandleFunc("/{id}/leaderboard", h.GetLeaderboard).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/leaderboard/stream", h.StreamLeaderboard).Methods("GET", "OPTIONS")
+
+	// Sponsorship endpoints (pledges are anchored on blockchain; visibility only applies to the API)
+	contests.HandleFunc("/{id}/sponsors", h.ListContestSponsors).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/sponsors", write(auth.ScopeContestsWrite, h.PledgeSponsor)).Methods("POST", "OPTIONS")
+
+	// Prize pool endpoints (sponsors deposit straight into the escrow; the organizer pays out or refunds)
+	contests.HandleFunc("/{id}/prize-pool", h.GetPrizePool).Methods("GET", "OPTIONS")
+	contests.HandleFunc("/{id}/prize-pool", write(auth.ScopeContestsWrite, h.OpenPrizePool)).Methods("PUT", "OPTIONS")
+	contests.HandleFunc("/{id}/prize-pool/payout", write(auth.ScopeContestsWrite, h.PayoutPrizePool)).Methods("POST", "OPTIONS")
+	contests.HandleFunc("/{id}/prize-pool/refund", write(auth.ScopeContestsWrite, h.RefundPrizePool)).Methods("POST", "OPTIONS")
+
+	// Team endpoints (members accept with a wallet signature or decline, the captain registers or disbands)
+	v1.HandleFunc("/teams/{id}", h.GetTeam).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/teams/{id}", write(auth.ScopeContestsWrite, h.DisbandTeam)).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/teams/{id}/accept", write(auth.ScopeContestsWrite, h.AcceptTeamInvite)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/teams/{id}/decline", write(auth.ScopeContestsWrite, h.DeclineTeamInvite)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/teams/{id}/register", write(auth.ScopeContestsWrite, h.RegisterTeam)).Methods("POST", "OPTIONS")
+
+	// Meta-transaction endpoints (users sign, the server relays). Requests are
+	// bound to the signed-in wallet, so they always need authentication.
+	meta := auth.RequireScope(auth.ScopeContestsWrite)
+	v1.HandleFunc("/meta/contests", h.RequireWritable(idempotent(meta(h.PrepareMetaContest)))).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/meta/relay", writable(meta(h.RelayMetaTx))).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/meta/nonce/{address}", h.GetMetaNonce).Methods("GET", "OPTIONS")
+
+	// Contestant endpoints
+	v1.HandleFunc("/contestants", write(auth.ScopeContestsWrite, h.CreateContestant)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/contestants/{id}", h.GetContestant).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/contestants", h.ListContestants).Methods("GET", "OPTIONS")
+
+	// Sponsor endpoints
+	v1.HandleFunc("/sponsors", write(auth.ScopeContestsWrite, h.CreateSponsor)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/sponsors/{id}", h.GetSponsor).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/sponsors/{id}/contests", h.ListSponsorContests).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/sponsors", h.ListSponsors).Methods("GET", "OPTIONS")
+
+	// Moderation endpoints
+	v1.HandleFunc("/moderation/submissions", h.ListSubmissions).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/moderation/submissions/{id}", h.GetSubmission).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/moderation/submissions/{id}/approve", write(auth.ScopeModeration, h.ApproveSubmission)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/moderation/submissions/{id}/reject", write(auth.ScopeModeration, h.RejectSubmission)).Methods("POST", "OPTIONS")
+
+	// Admin endpoints
+	admin := auth.RequireScope(auth.ScopeAdmin)
+	// Never idempotent: a replay would have to keep the plaintext key
+	v1.HandleFunc("/admin/api-keys", admin(h.CreateAPIKey)).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/admin/api-keys", admin(h.ListAPIKeys)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/api-keys/{id}", idempotent(admin(h.RevokeAPIKey))).Methods("DELETE", "OPTIONS")
+	v1.HandleFunc("/admin/roles", admin(h.ListRoles)).Methods("GET", "OPTIONS")
+	v1.HandleFunc("/admin/roles", writable(admin(h.GrantRole))).Methods("POST", "OPTIONS")
+	v1.HandleFunc("/admin/roles/{account}/{role}", writable(admin(h.RevokeRole))).Methods("DELETE", "OPTIONS")
+
+	// Statistics endpoint
+	v1.HandleFunc("/stats", h.GetStats).Methods("GET", "OPTIONS")
+
+	// Health check
+	v1.HandleFunc("/health", h.HealthCheck).Methods("GET", "OPTIONS")
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/sponsorship"
+	"log"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+)
+
+// ============ SPONSORSHIP HANDLERS ============
+
+// seesPledges reports whether the caller may see past the visibility of a
+// sponsor's pledges to a contest: its organizer, an admin, or the account
+// that recorded one of the pledges
+func seesPledges(identity *auth.Identity, contest *models.Contest, pledges []*models.Pledge, sponsorID string) bool {
+	if identity == nil {
+		return false
+	}
+	if isOrganizer(identity, contest) {
+		return true
+	}
+	for _, pledge := range pledges {
+		if pledge.ContestID == contest.ID && pledge.SponsorID == sponsorID && sameAccount(identity.Account(), pledge.PledgedBy) {
+			return true
+		}
+	}
+	return false
+}
+
+// sponsorWallet returns the wallet that pledges for a sponsor: the one it was
+// created with or, when the sponsor cannot be read back, the wallet of its
+// first pledge. It is empty for an unknown sponsor that never pledged.
+func (h *Handler) sponsorWallet(sponsorID string) (string, error) {
+	sponsor, err := h.blockchainService.GetSponsor(sponsorID)
+	if err != nil && apperr.KindOf(err) != apperr.KindNotFound {
+		return "", err
+	}
+	if err == nil && sponsor.Success && sponsor.Data != nil && sponsor.Data.WalletAddress != "" {
+		return sponsor.Data.WalletAddress, nil
+	}
+
+	pledges, err := h.blockchainService.GetSponsorPledges(sponsorID)
+	if err != nil {
+		return "", err
+	}
+	var first *models.Pledge
+	for _, pledge := range pledges {
+		if pledge.PledgedBy != "" && (first == nil || pledge.PledgedAt.Before(first.PledgedAt)) {
+			first = pledge
+		}
+	}
+	if first == nil {
+		return "", nil
+	}
+	return first.PledgedBy, nil
+}
+
+// PledgeSponsor handles POST /api/v1/contests/{id}/sponsors. Only the
+// sponsor's wallet pledges for it, even with role-based access control off.
+func (h *Handler) PledgeSponsor(w http.ResponseWriter, r *http.Request) {
+	var req models.PledgeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.ContestID = mux.Vars(r)["id"]
+
+	if !h.authorize(w, r, auth.RoleSponsor) {
+		return
+	}
+	identity := auth.IdentityFromContext(r.Context())
+	if identity == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "sign in with the sponsor's wallet")
+		return
+	}
+	wallet, err := h.sponsorWallet(req.SponsorID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get sponsor", err)
+		return
+	}
+	if wallet != "" && !sameAccount(identity.Account(), wallet) {
+		h.respondWithError(w, http.StatusForbidden, "Forbidden", "only the sponsor's wallet can pledge for it")
+		return
+	}
+	req.PledgedBy = identity.Account()
+
+	response, err := h.chain(r).PledgeSponsor(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to record pledge", err)
+		return
+	}
+
+	log.Printf("🤝 Sponsor %s pledged %d to contest %s", req.SponsorID, req.Amount, req.ContestID)
+
+	h.respondWithJSON(w, http.StatusCreated, response)
+}
+
+// ListContestSponsors handles GET /api/v1/contests/{id}/sponsors. The
+// summary counts every pledge; each sponsor is shown as its visibility allows.
+func (h *Handler) ListContestSponsors(w http.ResponseWriter, r *http.Request) {
+	contestID := mux.Vars(r)["id"]
+	contest, ok := h.findContest(w, contestID)
+	if !ok {
+		return
+	}
+
+	pledges, err := h.blockchainService.GetContestPledges(contestID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get pledges", err)
+		return
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	sponsors := sponsorship.Sponsors(contest, pledges)
+	for i, association := range sponsors {
+		if !seesPledges(identity, contest, pledges, association.SponsorID) {
+			sponsors[i] = sponsorship.Redact(association)
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.ListContestSponsorsResponse{
+		Success:   true,
+		ContestID: contestID,
+		Summary:   sponsorship.Summarize(contest, pledges),
+		Data:      sponsors,
+		Total:     len(sponsors),
+	})
+}
+
+// ListSponsorContests handles GET /api/v1/sponsors/{id}/contests, from the
+// latest pledge. Anonymous associations are left out for other viewers, since
+// naming the sponsor is what they hide.
+func (h *Handler) ListSponsorContests(w http.ResponseWriter, r *http.Request) {
+	sponsorID := mux.Vars(r)["id"]
+	pledges, err := h.blockchainService.GetSponsorPledges(sponsorID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get pledges", err)
+		return
+	}
+
+	identity := auth.IdentityFromContext(r.Context())
+	byContest := make(map[string][]*models.Pledge)
+	order := make([]string, 0)
+	for _, pledge := range pledges {
+		if _, seen := byContest[pledge.ContestID]; !seen {
+			order = append(order, pledge.ContestID)
+		}
+		byContest[pledge.ContestID] = append(byContest[pledge.ContestID], pledge)
+	}
+
+	contests := make([]models.ContestSponsor, 0, len(order))
+	for _, contestID := range order {
+		contest, err := h.blockchainService.GetContest(contestID)
+		if err != nil || !contest.Success || contest.Data == nil {
+			continue
+		}
+		for _, association := range sponsorship.Sponsors(contest.Data, byContest[contestID]) {
+			if !seesPledges(identity, contest.Data, byContest[contestID], sponsorID) {
+				if association.Visibility == models.VisibilityAnonymous {
+					continue
+				}
+				association = sponsorship.Redact(association)
+			}
+			contests = append(contests, association)
+		}
+	}
+	sort.SliceStable(contests, func(i, j int) bool {
+		return contests[i].PledgedAt.After(contests[j].PledgedAt)
+	})
+
+	h.respondWithJSON(w, http.StatusOK, models.ListContestSponsorsResponse{
+		Success:   true,
+		SponsorID: sponsorID,
+		Data:      contests,
+		Total:     len(contests),
+	})
+}
+
+// withSponsorship returns a copy of a contest with the totals of its pledges.
+// Contest details are still served when pledges cannot be read.
+func (h *Handler) withSponsorship(contest *models.Contest) *models.Contest {
+	pledges, err := h.blockchainService.GetContestPledges(contest.ID)
+	if err != nil {
+		log.Printf("⚠️ Failed to get pledges of contest %s: %v", contest.ID, err)
+		return contest
+	}
+
+	detailed := *contest
+	detailed.Sponsorship = sponsorship.Summarize(contest, pledges)
+	return &detailed
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/wiki"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ============ TAXONOMY HANDLERS ============
+
+// ListCategories handles GET /api/v1/categories
+func (h *Handler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	log.Printf("🗂️ Listing categories")
+
+	categories := h.taxonomy.Categories()
+	h.respondWithJSON(w, http.StatusOK, models.ListCategoriesResponse{
+		Success: true,
+		Data:    categories,
+		Total:   len(categories),
+	})
+}
+
+// GetCategory handles GET /api/v1/categories/{name}
+func (h *Handler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	if name == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Category name is required", "")
+		return
+	}
+
+	log.Printf("🗂️ Getting category: %s", name)
+
+	category, exists := h.taxonomy.Category(name)
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Category not found", fmt.Sprintf("category %s does not exist", name))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.GetCategoryResponse{
+		Success: true,
+		Data:    category,
+		Total:   len(category.Members),
+	})
+}
+
+// GetTagCloud handles GET /api/v1/tags?kind=content|contest
+func (h *Handler) GetTagCloud(w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	if kind != "" && kind != "content" && kind != "contest" {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid kind", "kind must be 'content' or 'contest'")
+		return
+	}
+
+	log.Printf("🏷️ Building tag cloud")
+
+	cloud := h.taxonomy.TagCloud(kind)
+	h.respondWithJSON(w, http.StatusOK, models.TagCloudResponse{
+		Success: true,
+		Data:    cloud,
+		Total:   len(cloud),
+	})
+}
+
+// taxonomyFilter reads the namespace, category and tag query parameters
+func taxonomyFilter(r *http.Request) wiki.Filter {
+	query := r.URL.Query()
+	return wiki.Filter{
+		Namespace: query.Get("namespace"),
+		Category:  query.Get("category"),
+		Tag:       query.Get("tag"),
+	}
+}
+
+// filterContents keeps the contents matching the filter
+func filterContents(contents []*models.Content, filter wiki.Filter) []*models.Content {
+	filtered := make([]*models.Content, 0, len(contents))
+	for _, content := range contents {
+		if filter.Match(content.Namespace, content.Categories, content.Tags) {
+			filtered = append(filtered, content)
+		}
+	}
+	return filtered
+}
+
+// filterContests keeps the contests matching the filter. Contests have no namespace.
+func filterContests(contests []*models.Contest, filter wiki.Filter) []*models.Contest {
+	filtered := make([]*models.Contest, 0, len(contests))
+	for _, contest := range contests {
+		if filter.Match(filter.Namespace, contest.Categories, contest.Tags) {
+			filtered = append(filtered, contest)
+		}
+	}
+	return filtered
+}
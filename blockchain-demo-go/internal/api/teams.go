@@ -0,0 +1,292 @@
+package api
+
+import (
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/teams"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// WithTeams enables team registration
+func WithTeams(store *teams.Store) Option {
+	return func(h *Handler) {
+		h.teams = store
+	}
+}
+
+// ============ TEAM HANDLERS ============
+
+// restoreTeams syncs the store with the teams registered on-chain for a
+// contest. The store otherwise only knows the teams formed since the server
+// started and would keep the members of teams withdrawn elsewhere locked.
+func (h *Handler) restoreTeams(contestID string) error {
+	list, err := h.blockchainService.GetContestTeams(contestID)
+	if err != nil {
+		return err
+	}
+	h.teams.Restore(contestID, list.Data)
+	return nil
+}
+
+// CreateTeam handles POST /api/v1/contests/{id}/teams
+func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	var req models.CreateTeamRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.ContestID = mux.Vars(r)["id"]
+
+	if !h.authorize(w, r, auth.RoleContestant, auth.RoleOrganizer) {
+		return
+	}
+
+	// The signed-in wallet captains the team
+	attribute(r, &req.Captain)
+
+	contest, err := h.blockchainService.GetContest(req.ContestID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get contest", err)
+		return
+	}
+	if !contest.Success || contest.Data == nil {
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+		return
+	}
+
+	// Members of teams registered before a restart are on a team already
+	if err := h.restoreTeams(req.ContestID); err != nil {
+		h.respondWithServiceError(w, "Failed to read registered teams", err)
+		return
+	}
+
+	team, err := h.teams.Create(&req, contest.Data.Registration)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to create team", err)
+		return
+	}
+
+	log.Printf("👥 Team %s (%s) formed for contest %s by %s", team.Name, team.ID, team.ContestID, team.Captain)
+
+	h.respondWithJSON(w, http.StatusCreated, models.TeamResponse{
+		Success: true,
+		Message: "Team created, waiting for members to accept",
+		Data:    team,
+	})
+}
+
+// ListTeams handles GET /api/v1/contests/{id}/teams
+func (h *Handler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	contestID := mux.Vars(r)["id"]
+	contest, err := h.blockchainService.GetContest(contestID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get contest", err)
+		return
+	}
+	if !contest.Success || contest.Data == nil {
+		h.respondWithError(w, http.StatusNotFound, "Contest not found", "")
+		return
+	}
+
+	if err := h.restoreTeams(contestID); err != nil {
+		h.respondWithServiceError(w, "Failed to read registered teams", err)
+		return
+	}
+
+	list := h.teams.List(contestID)
+	h.respondWithJSON(w, http.StatusOK, models.ListTeamsResponse{
+		Success:   true,
+		ContestID: contestID,
+		Data:      list,
+		Total:     len(list),
+	})
+}
+
+// GetTeam handles GET /api/v1/teams/{id}
+func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	team, exists := h.teams.Get(id)
+	if !exists {
+		// Teams registered before a restart are only on-chain
+		response, err := h.blockchainService.GetTeam(id)
+		if err != nil {
+			h.respondWithServiceError(w, "Failed to get team", err)
+			return
+		}
+		team = response.Data
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.TeamResponse{
+		Success: true,
+		Data:    team,
+	})
+}
+
+// AcceptTeamInvite handles POST /api/v1/teams/{id}/accept. The member signs
+// the message of their invitation with personal_sign.
+func (h *Handler) AcceptTeamInvite(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	var req models.AcceptTeamInviteRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	attribute(r, &req.Address)
+	if req.Address == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Address is required", "")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if forming, exists := h.teams.Get(id); exists {
+		if err := h.restoreTeams(forming.ContestID); err != nil {
+			h.respondWithServiceError(w, "Failed to read registered teams", err)
+			return
+		}
+	}
+	team, err := h.teams.Accept(id, req.Address, req.Signature)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to accept team invitation", err)
+		return
+	}
+
+	log.Printf("✍️ %s accepted to join team %s", req.Address, id)
+
+	h.respondWithJSON(w, http.StatusOK, models.TeamResponse{
+		Success: true,
+		Message: "Invitation accepted",
+		Data:    team,
+	})
+}
+
+// RegisterTeam handles POST /api/v1/teams/{id}/register. Only the captain
+// registers the team, once every member has accepted.
+func (h *Handler) RegisterTeam(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	if !h.authorize(w, r, auth.RoleContestant, auth.RoleOrganizer) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	team, err := h.teams.Claim(id)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to register team", err)
+		return
+	}
+
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil && !sameAccount(identity.Account(), team.Captain) {
+		h.teams.Release(id)
+		h.respondWithError(w, http.StatusForbidden, "Permission denied", "only the team captain can register the team")
+		return
+	}
+
+	log.Printf("👥 Registering team %s (%s) for contest %s", team.Name, team.ID, team.ContestID)
+
+	response, err := h.chain(r).RegisterTeam(team)
+	if err != nil {
+		h.teams.Release(id)
+		h.respondWithServiceError(w, "Failed to register team", err)
+		return
+	}
+
+	team, err = h.teams.Registered(id, response.Data.Entry, response.TxHash)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to register team", err)
+		return
+	}
+	if team.Entry == models.RegistrationRegistered {
+		h.rosterChanged(team.ContestID, team.ID, "")
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, models.TeamResponse{
+		Success: true,
+		Message: response.Message,
+		TxHash:  response.TxHash,
+		Data:    team,
+	})
+}
+
+// DeclineTeamInvite handles POST /api/v1/teams/{id}/decline. The signed-in
+// wallet leaves a team it was invited to.
+func (h *Handler) DeclineTeamInvite(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	address := callerAddress(r)
+	if address == "" {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "sign in with the invited wallet")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	team, err := h.teams.Decline(id, address)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to decline team invitation", err)
+		return
+	}
+
+	log.Printf("🙅 %s declined to join team %s", address, id)
+
+	h.respondWithJSON(w, http.StatusOK, models.TeamResponse{
+		Success: true,
+		Message: "Invitation declined",
+		Data:    team,
+	})
+}
+
+// DisbandTeam handles DELETE /api/v1/teams/{id}. Only the signed-in captain
+// disbands a team, before it registers.
+func (h *Handler) DisbandTeam(w http.ResponseWriter, r *http.Request) {
+	if h.teams == nil {
+		h.respondWithError(w, http.StatusNotFound, "Teams are not enabled", "")
+		return
+	}
+
+	address := callerAddress(r)
+	if address == "" {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "sign in with the captain's wallet")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	team, err := h.teams.Disband(id, address)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to disband team", err)
+		return
+	}
+
+	log.Printf("👋 Team %s (%s) disbanded by %s", team.Name, team.ID, address)
+
+	h.respondWithJSON(w, http.StatusOK, models.TeamResponse{
+		Success: true,
+		Message: "Team disbanded",
+		Data:    team,
+	})
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLog collects what the server logs while fn runs
+func captureLog(fn func()) string {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	fn()
+	return buf.String()
+}
+
+func TestCreatedAPIKeyIsNotLogged(t *testing.T) {
+	keys, err := auth.NewKeyStore("")
+	require.NoError(t, err)
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithAPIKeys(keys))
+
+	body, _ := json.Marshal(models.CreateAPIKeyRequest{Name: "ci", Scopes: []string{auth.ScopeContentWrite}})
+	rr := httptest.NewRecorder()
+	logs := captureLog(func() {
+		handler.CreateAPIKey(rr, httptest.NewRequest("POST", "/api/v1/admin/api-keys", bytes.NewReader(body)))
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var response models.CreateAPIKeyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Key)
+	assert.NotContains(t, logs, response.Key)
+}
+
+func TestSessionTokenIsNotLogged(t *testing.T) {
+	sessions := auth.NewSessionStore(time.Hour)
+	siwe := auth.NewSIWE("localhost:8081", 1337, auth.NewNonceStore(time.Minute), sessions)
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithSIWE(siwe, sessions))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	nonce, _ := siwe.Nonce()
+	message := fmt.Sprintf(`localhost:8081 wants you to sign in with your Ethereum account:
+%s
+
+URI: http://localhost:8081
+Version: 1
+Chain ID: 1337
+Nonce: %s
+Issued At: %s`, crypto.PubkeyToAddress(key.PublicKey).Hex(), nonce, time.Now().UTC().Format(time.RFC3339))
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(models.VerifySIWERequest{Message: message, Signature: hexutil.Encode(sig)})
+	rr := httptest.NewRecorder()
+	logs := captureLog(func() {
+		handler.VerifySIWE(rr, httptest.NewRequest("POST", "/api/v1/auth/verify", bytes.NewReader(body)))
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var response models.SessionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Token)
+	assert.NotContains(t, logs, response.Token)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
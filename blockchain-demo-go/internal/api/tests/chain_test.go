@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/config"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// artifactDir holds the committed truffle artifacts the server loads
+var artifactDir = filepath.Join("..", "..", "..", "..", "backend", "truffle", "build", "contracts")
+
+// newChainService deploys the committed contracts on a simulated chain and
+// connects the real BlockchainService to it, whose key is the contract admin
+func newChainService(t *testing.T) (*service.BlockchainService, *simulated.Backend) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner, err := bind.NewKeyedTransactorWithChainID(key, params.AllDevChainProtocolChanges.ChainID)
+	require.NoError(t, err)
+
+	ipc := filepath.Join(t.TempDir(), "sim.ipc")
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	sim := simulated.NewBackend(types.GenesisAlloc{owner.From: {Balance: balance}}, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.IPCPath = ipc
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	deploy := func(name string, args ...interface{}) common.Address {
+		data, err := os.ReadFile(filepath.Join(artifactDir, name+".json"))
+		require.NoError(t, err)
+		var artifact struct {
+			ABI      json.RawMessage `json:"abi"`
+			Bytecode string          `json:"bytecode"`
+		}
+		require.NoError(t, json.Unmarshal(data, &artifact))
+		parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+		require.NoError(t, err)
+		address, _, _, err := bind.DeployContract(owner, parsed, common.FromHex(artifact.Bytecode), sim.Client(), args...)
+		require.NoError(t, err)
+		sim.Commit()
+		return address
+	}
+	forwarder := deploy("WikiChainForwarder")
+	contract := deploy("ContentStorage", forwarder)
+
+	svc, err := service.NewBlockchainService(&config.Config{
+		NetworkURL:       ipc,
+		ChainID:          params.AllDevChainProtocolChanges.ChainID.String(),
+		PrivateKey:       hex.EncodeToString(crypto.FromECDSA(key)),
+		ContractAddress:  contract.Hex(),
+		ContractJSON:     filepath.Join(artifactDir, "ContentStorage.json"),
+		ForwarderAddress: forwarder.Hex(),
+		Signer:           "key",
+	})
+	require.NoError(t, err)
+	return svc, sim
+}
+
+// requireMined fails the test unless the transaction of a response was mined successfully
+func requireMined(t *testing.T, sim *simulated.Backend, rr *httptest.ResponseRecorder) {
+	t.Helper()
+	var sent struct {
+		TxHash string `json:"tx_hash"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &sent))
+	require.NotEmpty(t, sent.TxHash, rr.Body.String())
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(context.Background(), common.HexToHash(sent.TxHash))
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestContestantsManageTheirOwnRegistrationOnChain(t *testing.T) {
+	svc, sim := newChainService(t)
+	router := newRouter(api.NewHandler(svc, api.WithRoles(auth.NewRoleStore())))
+	organizer := &auth.Identity{Subject: organizerWallet, Address: organizerWallet, Roles: []string{auth.RoleOrganizer}}
+	player := &auth.Identity{Subject: "0x3333333333333333333333333333333333333333", Address: "0x3333333333333333333333333333333333333333", Roles: []string{auth.RoleContestant}}
+	other := &auth.Identity{Subject: "0x4444444444444444444444444444444444444444", Address: "0x4444444444444444444444444444444444444444", Roles: []string{auth.RoleContestant}}
+
+	as := func(identity *auth.Identity, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+	created := func(rr *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		requireMined(t, sim, rr)
+		var response struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.ID
+	}
+
+	contestID := created(as(organizer, "POST", "/api/v1/contests", `{"name":"On chain","description":"d","start_date":"2099-07-05T00:00:00Z","end_date":"2099-08-05T00:00:00Z","registration":{"require_verified":true}}`))
+	contestantID := created(as(player, "POST", "/api/v1/contestants", `{"name":"Player","details":"d"}`))
+
+	// The creator wallet read back from the contract registers its own contestant only
+	register := `{"contestant_id":"` + contestantID + `"}`
+	assert.Equal(t, http.StatusForbidden, as(other, "POST", "/api/v1/contests/"+contestID+"/register", register).Code)
+	created(as(player, "POST", "/api/v1/contests/"+contestID+"/register", register))
+	assert.Len(t, contestRoster(t, router, contestID).Contestants, 1)
+
+	// and withdraws it
+	path := "/api/v1/contests/" + contestID + "/register/" + contestantID
+	assert.Equal(t, http.StatusForbidden, as(other, "DELETE", path, "").Code)
+	rr := as(player, "DELETE", path, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	requireMined(t, sim, rr)
+	var response models.WithdrawContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.RegistrationWithdrawn, response.Data.Status)
+	assert.Empty(t, contestRoster(t, router, contestID).Contestants)
+}
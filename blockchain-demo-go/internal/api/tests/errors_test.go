@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemResponses(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService())
+	router := newRouter(handler)
+
+	invalidDates, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Test Contest",
+		Description: "This is a test contest",
+		StartDate:   "tomorrow",
+		EndDate:     "2025-08-05T00:00:00Z",
+	})
+	reversedDates, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Test Contest",
+		Description: "This is a test contest",
+		StartDate:   "2025-08-05T00:00:00Z",
+		EndDate:     "2025-07-05T00:00:00Z",
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   []byte
+		status int
+		code   string
+	}{
+		{"missing contest", "GET", "/api/v1/contests/nope", nil, http.StatusNotFound, models.ErrorCodeNotFound},
+		{"missing content", "GET", "/api/v1/content/nope", nil, http.StatusNotFound, models.ErrorCodeNotFound},
+		{"invalid date", "POST", "/api/v1/contests", invalidDates, http.StatusBadRequest, models.ErrorCodeValidation},
+		{"reversed dates", "POST", "/api/v1/contests", reversedDates, http.StatusBadRequest, models.ErrorCodeValidation},
+		{"malformed body", "POST", "/api/v1/contests", []byte("{"), http.StatusBadRequest, models.ErrorCodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, bytes.NewReader(tt.body)))
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, apperr.ProblemContentType, rr.Header().Get("Content-Type"))
+			var problem models.ErrorResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, "urn:wikichain:problem:"+tt.code, problem.Type)
+			assert.NotEmpty(t, problem.Title)
+		})
+	}
+}
+
+func TestValidationReportsEveryField(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithMaxBodyBytes(512))
+	router := newRouter(handler)
+
+	post := func(body string) (*httptest.ResponseRecorder, models.ErrorResponse) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", strings.NewReader(body)))
+		var problem models.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+		return rr, problem
+	}
+
+	rr, problem := post(`{"description":"d","start_date":"2025-08-05T00:00:00Z","end_date":"2025-07-05T00:00:00Z","image_url":"not a url"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeValidation, problem.Code)
+	assert.Equal(t, "Contest name is required", problem.Error)
+	fields := make(map[string]string)
+	for _, field := range problem.Errors {
+		fields[field.Field] = field.Rule
+	}
+	assert.Equal(t, map[string]string{"name": "required", "end_date": "after", "image_url": "url"}, fields)
+
+	// Unknown fields are rejected instead of silently dropped
+	rr, problem = post(`{"name":"n","description":"d","start_date":"2025-07-05T00:00:00Z","end_date":"2025-08-05T00:00:00Z","organizer":"0xabc"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "organizer", problem.Errors[0].Field)
+	assert.Equal(t, "unknown", problem.Errors[0].Rule)
+
+	rr, problem = post(`{"name":"n","description":"` + strings.Repeat("x", 600) + `"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, models.ErrorCodePayloadTooLarge, problem.Code)
+}
+
+func TestServiceValidatesLikeHandlers(t *testing.T) {
+	mock := service.NewMockBlockchainService()
+	_, err := mock.CreateContestant(&models.CreateContestantRequest{Name: strings.Repeat("x", 201)})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, apperr.StatusOf(err))
+
+	fields := apperr.FieldsOf(err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "name", fields[0].Field)
+	assert.Equal(t, "max", fields[0].Rule)
+	assert.Equal(t, "details", fields[1].Field)
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/ids"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicContestID(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService())
+	router := newRouter(handler)
+
+	preview := func() models.IDPreview {
+		query := url.Values{"type": {"contest"}, "name": {"Test Contest"}, "nonce": {"abc-1"}}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/ids/preview?"+query.Encode(), nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response models.IDPreviewResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return *response.Data
+	}
+	create := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.CreateContestRequest{
+			Name:        "  test   CONTEST ",
+			Description: "This is a test contest",
+			StartDate:   "2025-07-05T00:00:00Z",
+			EndDate:     "2025-08-05T00:00:00Z",
+			Nonce:       "abc-1",
+		})
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+		return rr
+	}
+
+	// Client tính trước ID trước khi gửi
+	expected, err := ids.Derive(ids.EntityContest, "", "Test Contest", "abc-1")
+	require.NoError(t, err)
+	before := preview()
+	assert.Equal(t, expected, before.ID)
+	assert.False(t, before.Taken)
+
+	rr := create()
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, expected, created.ID)
+	assert.True(t, preview().Taken)
+
+	// Cùng nonce lần hai bị từ chối vì trùng ID
+	rr = create()
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var errResp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResp))
+	assert.Equal(t, models.ErrorCodeIDTaken, errResp.Code)
+}
+
+func TestPreviewIDInvalidNonce(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService())
+	rr := httptest.NewRecorder()
+	handler.PreviewID(rr, httptest.NewRequest("GET", "/api/v1/ids/preview?type=contest&name=x&nonce=bad+nonce", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,259 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/judging"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	organizerWallet = "0x1111111111111111111111111111111111111111"
+	judgeWallet     = "0x2222222222222222222222222222222222222222"
+	otherJudge      = "0x3333333333333333333333333333333333333333"
+)
+
+// judgedContest creates a contest with two registered contestants and moves
+// it to judging, as the scheduler would at its end date
+func judgedContest(t *testing.T, svc service.BlockchainServiceInterface, router *mux.Router) (string, string, string) {
+	t.Helper()
+	contestID := createPolicyContest(t, router, nil)
+	alice := createContestant(t, router, "Alice")
+	bob := createContestant(t, router, "Bob")
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, alice).Code)
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, bob).Code)
+
+	for _, state := range []string{models.ContestRunning, models.ContestJudging} {
+		_, err := svc.TransitionContest(contestID, state, "", "scheduler")
+		require.NoError(t, err)
+	}
+	return contestID, alice, bob
+}
+
+func send(router *mux.Router, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return sendAs(router, nil, method, path, body)
+}
+
+// sendAs sends a request authenticated as identity, anonymous when nil
+func sendAs(router *mux.Router, identity *auth.Identity, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	r := httptest.NewRequest(method, path, bytes.NewReader(data))
+	if identity != nil {
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, r)
+	return rr
+}
+
+// wallet is a wallet signed in with SIWE
+func wallet(address string) *auth.Identity {
+	return &auth.Identity{Subject: address, Address: address, Method: auth.MethodSIWE, Scopes: auth.WalletScopes}
+}
+
+func TestJudgingFlow(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard())))
+	contestID, alice, bob := judgedContest(t, svc, router)
+	base := "/api/v1/contests/" + contestID + "/judging"
+
+	rr := send(router, "PUT", base+"/rubric", models.SetRubricRequest{
+		Criteria:    []models.CriterionRequest{{Name: "Design", MaxScore: 10}, {Name: "Fun", Weight: 2, MaxScore: 10}},
+		Aggregation: models.AggregationMedian,
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = send(router, "POST", base+"/judges", models.AssignJudgesRequest{Judges: []string{judgeWallet, otherJudge}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	score := func(judge, contestantID string, design, fun float64) *httptest.ResponseRecorder {
+		return send(router, "PUT", base+"/scores/"+contestantID, models.SubmitScoresRequest{
+			Judge:  judge,
+			Scores: map[string]float64{"Design": design, "Fun": fun},
+		})
+	}
+
+	rr = score(judgeWallet, alice, 8, 9)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var card models.ScorecardResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &card))
+	assert.Equal(t, 26.0, card.Data.Total)
+
+	require.Equal(t, http.StatusOK, score(otherJudge, alice, 6, 7).Code)
+	require.Equal(t, http.StatusOK, score(judgeWallet, bob, 5, 5).Code)
+
+	// Out of range, unknown judges and unregistered entries are refused
+	assert.Equal(t, http.StatusBadRequest, score(judgeWallet, bob, 11, 5).Code)
+	rr = score(organizerWallet, bob, 5, 5)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, models.ErrorCodeNotAJudge, problemCode(t, rr))
+	assert.Equal(t, http.StatusNotFound, score(judgeWallet, "nobody", 5, 5).Code)
+
+	// Each judge sees only their own scorecards, whoever the query names
+	rr = sendAs(router, wallet(otherJudge), "GET", base+"/scores?judge="+judgeWallet, nil)
+	var cards models.ListScorecardsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &cards))
+	require.Equal(t, 1, cards.Total)
+	assert.Equal(t, otherJudge, cards.Data[0].Judge)
+
+	// Even with role-based access control off, nobody else sees scores before
+	// the results are final
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", base+"/scores?judge="+judgeWallet, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "GET", base+"/results", nil).Code)
+	rr = sendAs(router, wallet(sponsorWallet), "GET", base+"/scores", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, models.ErrorCodeNotAJudge, problemCode(t, rr))
+	assert.Equal(t, http.StatusForbidden, sendAs(router, wallet(judgeWallet), "GET", base+"/results", nil).Code)
+
+	// The rubric is locked once scores are in
+	rr = send(router, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Luck", MaxScore: 10}}})
+	assert.Equal(t, models.ErrorCodeRubricLocked, problemCode(t, rr))
+
+	rr = send(router, "POST", base+"/finalize", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var final models.ContestResultsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &final))
+	assert.NotEmpty(t, final.TxHash)
+	require.Len(t, final.Data.Results, 2)
+	assert.Equal(t, alice, final.Data.Results[0].ContestantID)
+	assert.Equal(t, 23.0, final.Data.Results[0].Score) // median of 26 and 20
+	assert.Equal(t, 2, final.Data.Results[1].Rank)
+
+	// Final results are public, with every scorecard, and cannot change
+	rr = send(router, "GET", base+"/results", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var results models.ContestResultsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &results))
+	assert.True(t, results.Data.Final)
+	assert.Len(t, results.Data.Scorecards, 3)
+
+	rr = send(router, "GET", base+"/scores", nil)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &cards))
+	assert.Equal(t, 3, cards.Total)
+
+	assert.Equal(t, models.ErrorCodeResultsFinalized, problemCode(t, score(judgeWallet, bob, 6, 6)))
+	assert.Equal(t, models.ErrorCodeResultsFinalized, problemCode(t, send(router, "POST", base+"/finalize", nil)))
+}
+
+func TestScoringNeedsTheJudgingState(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard())))
+	contestID := createPolicyContest(t, router, nil)
+	alice := createContestant(t, router, "Alice")
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, alice).Code)
+	base := "/api/v1/contests/" + contestID + "/judging"
+
+	require.Equal(t, http.StatusOK, send(router, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}}).Code)
+	require.Equal(t, http.StatusOK, send(router, "POST", base+"/judges", models.AssignJudgesRequest{Judges: []string{judgeWallet}}).Code)
+
+	rr := send(router, "PUT", base+"/scores/"+alice, models.SubmitScoresRequest{Judge: judgeWallet, Scores: map[string]float64{"Design": 5}})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problemCode(t, rr))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problemCode(t, send(router, "POST", base+"/finalize", nil)))
+
+	rr = send(router, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design"}}})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestJudgingHidesScoresFromOtherJudges(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard()), api.WithRoles(auth.NewRoleStore())))
+	organizer := &auth.Identity{Subject: organizerWallet, Address: organizerWallet, Roles: []string{auth.RoleOrganizer}}
+	judge := &auth.Identity{Subject: judgeWallet, Address: judgeWallet}
+	other := &auth.Identity{Subject: otherJudge, Address: otherJudge}
+
+	as := func(identity *auth.Identity, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		r := httptest.NewRequest(method, path, bytes.NewReader(data))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	rr := as(organizer, "POST", "/api/v1/contests", models.CreateContestRequest{
+		Name:        "Judged Contest",
+		Description: "Scored by a panel",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var contest models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &contest))
+	base := "/api/v1/contests/" + contest.ID + "/judging"
+
+	created, err := svc.CreateContestant(&models.CreateContestantRequest{Name: "Alice", Details: "Player"})
+	require.NoError(t, err)
+	alice := created.ID
+	rr = as(organizer, "POST", "/api/v1/contests/"+contest.ID+"/register", models.RegisterContestantRequest{ContestantID: alice})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	for _, state := range []string{models.ContestRunning, models.ContestJudging} {
+		_, err := svc.TransitionContest(contest.ID, state, "", "scheduler")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, http.StatusForbidden, as(judge, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}}).Code)
+	require.Equal(t, http.StatusOK, as(organizer, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}}).Code)
+	require.Equal(t, http.StatusOK, as(organizer, "POST", base+"/judges", models.AssignJudgesRequest{Judges: []string{judgeWallet, otherJudge}}).Code)
+
+	// The body cannot score on behalf of another judge
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "PUT", base+"/scores/"+alice, models.SubmitScoresRequest{Judge: judgeWallet, Scores: map[string]float64{"Design": 9}}).Code)
+	rr = as(judge, "PUT", base+"/scores/"+alice, models.SubmitScoresRequest{Judge: otherJudge, Scores: map[string]float64{"Design": 9}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var card models.ScorecardResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &card))
+	assert.Equal(t, judgeWallet, card.Data.Judge)
+
+	var cards models.ListScorecardsResponse
+	require.NoError(t, json.Unmarshal(as(other, "GET", base+"/scores?judge="+judgeWallet, nil).Body.Bytes(), &cards))
+	assert.Equal(t, 0, cards.Total)
+	require.NoError(t, json.Unmarshal(as(judge, "GET", base+"/scores", nil).Body.Bytes(), &cards))
+	assert.Equal(t, 1, cards.Total)
+
+	// Provisional results are for the organizer only
+	assert.Equal(t, http.StatusForbidden, as(judge, "GET", base+"/results", nil).Code)
+	assert.Equal(t, http.StatusOK, as(organizer, "GET", base+"/results", nil).Code)
+
+	assert.Equal(t, http.StatusForbidden, as(judge, "POST", base+"/finalize", nil).Code)
+	require.Equal(t, http.StatusOK, as(organizer, "POST", base+"/finalize", nil).Code)
+	assert.Equal(t, http.StatusOK, as(judge, "GET", base+"/results", nil).Code)
+}
+
+// unreachableChain fails every contest lookup as a node that is down does
+type unreachableChain struct {
+	service.BlockchainServiceInterface
+}
+
+func (unreachableChain) GetContest(id string) (*models.GetContestResponse, error) {
+	return &models.GetContestResponse{Success: false, Message: "Contest not found on blockchain"},
+		apperr.New(apperr.KindChainUnavailable, "", "dial tcp: connection refused")
+}
+
+func TestJudgingReportsChainFailures(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard())))
+	contestID, _, _ := judgedContest(t, svc, router)
+	rubric := models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}}
+
+	// A missing contest is a 404, a node that is down is not
+	assert.Equal(t, http.StatusNotFound, send(router, "PUT", "/api/v1/contests/missing/judging/rubric", rubric).Code)
+	router = newRouter(api.NewHandler(unreachableChain{svc}, api.WithJudging(judging.NewBoard())))
+	assert.Equal(t, http.StatusServiceUnavailable, send(router, "PUT", "/api/v1/contests/"+contestID+"/judging/rubric", rubric).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, sendAs(router, wallet(judgeWallet), "GET", "/api/v1/contests/"+contestID+"/judging/scores", nil).Code)
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/judging"
+	"blockchain-demo/internal/leaderboard"
+	"blockchain-demo/internal/middleware"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scoredContest creates a contest being judged by judgeWallet on one
+// criterion, and returns a function that scores an entry
+func scoredContest(t *testing.T, svc service.BlockchainServiceInterface, router *mux.Router) (string, string, string, func(contestantID string, fun float64)) {
+	t.Helper()
+	contestID, alice, bob := judgedContest(t, svc, router)
+	base := "/api/v1/contests/" + contestID + "/judging"
+	rr := send(router, "PUT", base+"/rubric", models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Fun", MaxScore: 10}}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = send(router, "POST", base+"/judges", models.AssignJudgesRequest{Judges: []string{judgeWallet}})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	return contestID, alice, bob, func(contestantID string, fun float64) {
+		t.Helper()
+		rr := send(router, "PUT", base+"/scores/"+contestantID, models.SubmitScoresRequest{Judge: judgeWallet, Scores: map[string]float64{"Fun": fun}})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+func getLeaderboard(t *testing.T, router *mux.Router, path string) *models.Leaderboard {
+	t.Helper()
+	rr := send(router, "GET", path, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.LeaderboardResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.Data
+}
+
+func TestLeaderboard(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard()), api.WithLeaderboard(leaderboard.NewHub())))
+	contestID, alice, bob, score := scoredContest(t, svc, router)
+	score(bob, 6)
+	path := "/api/v1/contests/" + contestID + "/leaderboard"
+
+	// Loaded from the registered contestants; scores stay private while judging
+	board := getLeaderboard(t, router, path)
+	require.Len(t, board.Entries, 2)
+	for _, entry := range board.Entries {
+		assert.Equal(t, 0.0, entry.Score)
+		assert.Empty(t, entry.Criteria)
+		assert.Zero(t, entry.Judges)
+	}
+
+	score(alice, 9)
+	board = getLeaderboard(t, router, path)
+	assert.Equal(t, 0.0, board.Entries[0].Score)
+
+	// The final results go on the leaderboard
+	rr := send(router, "POST", "/api/v1/contests/"+contestID+"/judging/finalize", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	board = getLeaderboard(t, router, path+"?limit=1")
+	require.Len(t, board.Entries, 1)
+	assert.Equal(t, models.LeaderboardEntry{Rank: 1, ContestantID: alice, Score: 9, Criteria: map[string]float64{"Fun": 9}, Judges: 1, UpdatedAt: board.Entries[0].UpdatedAt}, board.Entries[0])
+	assert.Equal(t, 2, board.Total)
+
+	// and are read back from the chain after a restart
+	restarted := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard()), api.WithLeaderboard(leaderboard.NewHub())))
+	board = getLeaderboard(t, restarted, path)
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, alice, board.Entries[0].ContestantID)
+	assert.Equal(t, bob, board.Entries[1].ContestantID)
+	assert.Equal(t, 6.0, board.Entries[1].Score)
+
+	rr = send(router, "GET", path+"?limit=-1", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = send(router, "GET", "/api/v1/contests/nope/leaderboard", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestLeaderboardFollowsRoster(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithLeaderboard(leaderboard.NewHub())))
+	contestID := createPolicyContest(t, router, nil)
+	alice := createContestant(t, router, "Alice")
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, alice).Code)
+	path := "/api/v1/contests/" + contestID + "/leaderboard"
+	require.Len(t, getLeaderboard(t, router, path).Entries, 1)
+
+	player := wallet(sponsorWallet)
+	rr := sendAs(router, player, "POST", "/api/v1/contestants", models.CreateContestantRequest{Name: "Bob", Details: "Player"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	bob := created.ID
+
+	// A new registration joins the loaded leaderboard
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, bob).Code)
+	board := getLeaderboard(t, router, path)
+	require.Len(t, board.Entries, 2)
+	assert.Contains(t, []string{board.Entries[0].ContestantID, board.Entries[1].ContestantID}, bob)
+
+	// and a withdrawal leaves it
+	rr = sendAs(router, player, "DELETE", "/api/v1/contests/"+contestID+"/register/"+bob, models.WithdrawContestantRequest{Reason: "cannot attend"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	board = getLeaderboard(t, router, path)
+	require.Len(t, board.Entries, 1)
+	assert.Equal(t, alice, board.Entries[0].ContestantID)
+	assert.Equal(t, 1, board.Total)
+}
+
+func TestLeaderboardStream(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithJudging(judging.NewBoard()), api.WithLeaderboard(leaderboard.NewHub())))
+	contestID, alice, bob, score := scoredContest(t, svc, router)
+
+	// Served through the request logger, which must still let events flush
+	server := httptest.NewServer(middleware.Logger(router))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/contests/"+contestID+"/leaderboard/stream", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewScanner(resp.Body)
+	next := func() *models.Leaderboard {
+		t.Helper()
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				var board models.Leaderboard
+				require.NoError(t, json.Unmarshal([]byte(data), &board))
+				return &board
+			}
+		}
+		t.Fatalf("stream ended: %v", events.Err())
+		return nil
+	}
+
+	board := next()
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, 0.0, board.Entries[0].Score)
+
+	// Scores are not streamed while judging; the final results are
+	score(bob, 4)
+	score(alice, 7)
+	rr := send(router, "POST", "/api/v1/contests/"+contestID+"/judging/finalize", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	for board = next(); board.Entries[1].Score == 0; board = next() {
+	}
+	assert.Equal(t, alice, board.Entries[0].ContestantID)
+	assert.Equal(t, 7.0, board.Entries[0].Score)
+	assert.Equal(t, 4.0, board.Entries[1].Score)
+}
+
+func TestLeaderboardNotEnabled(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	contestID := createPolicyContest(t, router, nil)
+
+	rr := send(router, "GET", "/api/v1/contests/"+contestID+"/leaderboard", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createLifecycleContest(t *testing.T, router *mux.Router, draft bool) string {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Lifecycle Contest",
+		Description: "Contest moving through its states",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+		Draft:       draft,
+	})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+func contestState(t *testing.T, router *mux.Router, id string) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/contests/"+id, nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var contest models.GetContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &contest))
+	return contest.Data.State
+}
+
+func TestContestLifecycleEndpoints(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+
+	id := createLifecycleContest(t, router, true)
+	assert.Equal(t, models.ContestDraft, contestState(t, router, id))
+
+	// Opening a draft, with or without a reason
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+id+"/open", strings.NewReader(`{"reason":"rules published"}`)))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.ContestTransitionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.ContestDraft, response.Data.From)
+	assert.Equal(t, models.ContestOpen, response.Data.To)
+	assert.Equal(t, "rules published", response.Data.Reason)
+	assert.NotEmpty(t, response.Data.TxHash)
+	assert.Equal(t, models.ContestOpen, contestState(t, router, id))
+
+	// Open contests must run before they close
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+id+"/close", nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var problem models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problem.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+id+"/cancel", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.ContestCancelled, contestState(t, router, id))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+id+"/open", nil))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/missing/open", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+id+"/cancel", strings.NewReader(`{"reason":"`+strings.Repeat("x", 501)+`"}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRegistrationFollowsContestState(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+
+	body, _ := json.Marshal(models.CreateContestantRequest{Name: "Alice", Details: "Player"})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contestants", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var contestant models.CreateContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &contestant))
+
+	register := func(contestID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.RegisterContestantRequest{ContestantID: contestant.ID})
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+contestID+"/register", bytes.NewReader(body)))
+		return rr
+	}
+
+	draft := createLifecycleContest(t, router, true)
+	rr = register(draft)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var problem models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problem.Code)
+
+	open := createLifecycleContest(t, router, false)
+	assert.Equal(t, http.StatusCreated, register(open).Code)
+}
+
+func TestContestTransitionsNeedTheOrganizer(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithRoles(auth.NewRoleStore()))
+	router := newRouter(handler)
+	organizer := &auth.Identity{Subject: "0x1111111111111111111111111111111111111111", Address: "0x1111111111111111111111111111111111111111", Roles: []string{auth.RoleOrganizer}}
+	other := &auth.Identity{Subject: "0x2222222222222222222222222222222222222222", Address: "0x2222222222222222222222222222222222222222", Roles: []string{auth.RoleOrganizer}}
+
+	as := func(identity *auth.Identity, method, path string, body []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, bytes.NewReader(body))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Owned Contest",
+		Description: "Only its organizer may cancel it",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+	})
+	rr := as(organizer, "POST", "/api/v1/contests", body)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "POST", "/api/v1/contests/"+created.ID+"/cancel", nil).Code)
+	assert.Equal(t, http.StatusForbidden, as(other, "POST", "/api/v1/contests/"+created.ID+"/cancel", nil).Code)
+	assert.Equal(t, http.StatusOK, as(organizer, "POST", "/api/v1/contests/"+created.ID+"/cancel", nil).Code)
+}
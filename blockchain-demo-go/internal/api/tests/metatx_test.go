@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/metatx"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaTxIsBoundToTheSignedInWallet(t *testing.T) {
+	relayer := metatx.NewRelayer(1337, "0x5FbDB2315678afecb367f032d93F642f64180aa3", "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512", time.Minute, 500000)
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithRelayer(relayer)))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	organizer := &auth.Identity{Subject: address, Address: address, Method: auth.MethodSIWE, Scopes: []string{auth.ScopeContestsWrite}}
+	stranger := &auth.Identity{Subject: judgeWallet, Address: judgeWallet, Method: auth.MethodSIWE, Scopes: []string{auth.ScopeContestsWrite}}
+
+	as := func(identity *auth.Identity, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		r := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		if identity != nil {
+			r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+	prepare := func(identity *auth.Identity, from string) *httptest.ResponseRecorder {
+		return as(identity, "/api/v1/meta/contests", models.PrepareMetaContestRequest{
+			From: from,
+			CreateContestRequest: models.CreateContestRequest{
+				Name:        "Signed Contest",
+				Description: "Created through a meta-transaction",
+				StartDate:   "2099-07-05T00:00:00Z",
+				EndDate:     "2099-08-05T00:00:00Z",
+			},
+		})
+	}
+
+	// Authentication is required even with AUTH_REQUIRED=false, and from is the caller
+	assert.Equal(t, http.StatusUnauthorized, prepare(nil, address).Code)
+	assert.Equal(t, http.StatusForbidden, prepare(stranger, address).Code)
+
+	rr := prepare(organizer, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var prepared models.PrepareMetaTxResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &prepared))
+	assert.Equal(t, address, prepared.Request.From)
+
+	hash, err := relayer.Hash(prepared.Request)
+	require.NoError(t, err)
+	signature, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	relay := models.RelayMetaTxRequest{Request: prepared.Request, Signature: hexutil.Encode(signature)}
+
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "/api/v1/meta/relay", relay).Code)
+	assert.Equal(t, http.StatusForbidden, as(stranger, "/api/v1/meta/relay", relay).Code)
+	rr = as(organizer, "/api/v1/meta/relay", relay)
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	// Calls prepared but never relayed are capped per wallet
+	for i := 0; ; i++ {
+		rr = prepare(organizer, "")
+		if rr.Code != http.StatusOK {
+			break
+		}
+		require.Less(t, i, 100, "prepared calls are never capped")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, rr.Body.String())
+	assert.Equal(t, http.StatusOK, prepare(stranger, "").Code, "other wallets have their own cap")
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/moderation"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewingSubmissionsNeedsAModerator(t *testing.T) {
+	queue := moderation.NewQueue()
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithModerationQueue(queue)))
+
+	as := func(identity *auth.Identity, path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		r := httptest.NewRequest("POST", path, bytes.NewReader(data))
+		if identity != nil {
+			r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	first := queue.Submit(&models.CreateContentRequest{Title: "First", Content: "Body", Creator: organizerWallet}, "")
+	second := queue.Submit(&models.CreateContentRequest{Title: "Second", Content: "Body", Creator: organizerWallet}, "")
+	approve := "/api/v1/moderation/submissions/" + first.ID + "/approve"
+	reject := "/api/v1/moderation/submissions/" + second.ID + "/reject"
+
+	// RBAC is off, yet anonymous callers and wallets without the scope are refused
+	wallet := &auth.Identity{Subject: judgeWallet, Address: judgeWallet, Scopes: auth.WalletScopes}
+	assert.Equal(t, http.StatusUnauthorized, as(nil, approve, models.ReviewSubmissionRequest{}).Code)
+	assert.Equal(t, http.StatusForbidden, as(wallet, approve, models.ReviewSubmissionRequest{}).Code)
+	assert.Equal(t, http.StatusForbidden, as(wallet, reject, models.ReviewSubmissionRequest{Reason: "Spam"}).Code)
+
+	// The reviewer is recorded as the caller
+	moderatorKey := &auth.Identity{Subject: "apikey:mod", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeModeration}}
+	rr := as(moderatorKey, approve, models.ReviewSubmissionRequest{})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.SubmissionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "apikey:mod", response.Data.Moderator)
+
+	moderatorWallet := &auth.Identity{Subject: otherJudge, Address: otherJudge, Roles: []string{auth.RoleModerator}}
+	rr = as(moderatorWallet, reject, models.ReviewSubmissionRequest{Reason: "Spam"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, otherJudge, response.Data.Moderator)
+}
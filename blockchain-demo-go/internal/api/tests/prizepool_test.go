@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/escrow"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// admin manages every prize pool
+var admin = &auth.Identity{Subject: organizerWallet, Address: organizerWallet, Roles: []string{auth.RoleAdmin}}
+
+func prizePool(t *testing.T, rr *httptest.ResponseRecorder) *models.PrizePool {
+	t.Helper()
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, rr.Code, rr.Body.String())
+	var response models.PrizePoolResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.NotNil(t, response.Data)
+	return response.Data
+}
+
+// prizePoolRouter serves the prize pool endpoints, which need role-based
+// access control
+func prizePoolRouter(svc service.BlockchainServiceInterface) *mux.Router {
+	return newRouter(api.NewHandler(svc, api.WithRoles(auth.NewRoleStore()), api.WithPrizePools()))
+}
+
+// createOwnedContestant creates a contestant whose creator is owner's wallet
+func createOwnedContestant(t *testing.T, router *mux.Router, owner teamWallet, name string) string {
+	t.Helper()
+	rr := sendAs(router, wallet(owner.address), "POST", "/api/v1/contestants", models.CreateContestantRequest{Name: name, Details: "Player"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+// payoutAddress signs the wallet a contestant wants its prize sent to
+func payoutAddress(t *testing.T, signer teamWallet, contestID, contestantID, to string) models.PayoutAddress {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(escrow.PayoutMessage(contestID, contestantID, to))), signer.key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return models.PayoutAddress{Wallet: to, Signature: hexutil.Encode(sig)}
+}
+
+func TestPrizePoolPayout(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	mock := svc.(*service.MockBlockchainService)
+	setup := newRouter(api.NewHandler(svc))
+	router := prizePoolRouter(svc)
+
+	contestID := createPolicyContest(t, setup, nil)
+	aliceWallet, bobWallet := newTeamWallet(t), newTeamWallet(t)
+	alice := createOwnedContestant(t, setup, aliceWallet, "Alice")
+	bob := createOwnedContestant(t, setup, bobWallet, "Bob")
+	require.Equal(t, http.StatusCreated, registerContestant(setup, contestID, alice).Code)
+	require.Equal(t, http.StatusCreated, registerContestant(setup, contestID, bob).Code)
+	for _, state := range []string{models.ContestRunning, models.ContestJudging} {
+		_, err := svc.TransitionContest(contestID, state, "", "scheduler")
+		require.NoError(t, err)
+	}
+	path := "/api/v1/contests/" + contestID + "/prize-pool"
+
+	assert.Equal(t, http.StatusNotFound, send(router, "GET", path, nil).Code)
+
+	open := models.OpenPrizePoolRequest{SharesBps: []uint64{6000, 4000}}
+	assert.Equal(t, http.StatusUnauthorized, send(router, "PUT", path, open).Code)
+	assert.Equal(t, http.StatusForbidden, sendAs(router, wallet(otherJudge), "PUT", path, open).Code)
+
+	rr := sendAs(router, admin, "PUT", path, open)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	pool := prizePool(t, rr)
+	assert.Equal(t, models.PrizePoolFunding, pool.State)
+	assert.Equal(t, "0", pool.Total)
+	assert.NotEmpty(t, pool.Escrow)
+
+	rr = sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{SharesBps: []uint64{10000}})
+	assert.Equal(t, models.ErrorCodePrizePoolExists, problemCode(t, rr))
+
+	require.NoError(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(600)))
+	require.NoError(t, mock.DepositPrize(contestID, organizerWallet, big.NewInt(401)))
+	pool = prizePool(t, send(router, "GET", path, nil))
+	assert.Equal(t, "1001", pool.Total)
+	assert.Len(t, pool.Deposits, 2)
+
+	// Payouts wait for the final results
+	rr = sendAs(router, admin, "POST", path+"/payout", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeResultsNotFinal, problemCode(t, rr))
+
+	_, err := svc.FinalizeResults(&models.ContestResults{
+		ContestID: contestID,
+		Results:   []models.ContestantResult{{Rank: 1, ContestantID: bob}, {Rank: 2, ContestantID: alice}},
+		Final:     true,
+	})
+	require.NoError(t, err)
+
+	// A payout address must be signed by the contestant's creator
+	forged := models.PrizePayoutRequest{Addresses: map[string]models.PayoutAddress{
+		alice: payoutAddress(t, bobWallet, contestID, alice, otherJudge),
+	}}
+	rr = sendAs(router, admin, "POST", path+"/payout", forged)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidSignature, problemCode(t, rr))
+
+	// Bob is paid at his verified wallet, Alice at the wallet she signed for
+	signed := models.PrizePayoutRequest{Addresses: map[string]models.PayoutAddress{
+		alice: payoutAddress(t, aliceWallet, contestID, alice, judgeWallet),
+	}}
+	pool = prizePool(t, sendAs(router, admin, "POST", path+"/payout", signed))
+	assert.Equal(t, models.PrizePoolPaid, pool.State)
+	require.Len(t, pool.Payouts, 2)
+	assert.Equal(t, models.PrizePayout{Rank: 1, ContestantID: bob, Wallet: bobWallet.address, Amount: "601", TxHash: pool.Payouts[0].TxHash}, pool.Payouts[0])
+	assert.Equal(t, judgeWallet, pool.Payouts[1].Wallet)
+	assert.Equal(t, "400", pool.Payouts[1].Amount)
+
+	// A pool is paid out once and takes no more deposits
+	assert.Equal(t, models.ErrorCodeInvalidPoolState, problemCode(t, sendAs(router, admin, "POST", path+"/payout", nil)))
+	assert.Error(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(1)))
+}
+
+func TestPrizePoolRefund(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	mock := svc.(*service.MockBlockchainService)
+	setup := newRouter(api.NewHandler(svc))
+	router := prizePoolRouter(svc)
+	contestID := createPolicyContest(t, setup, nil)
+	path := "/api/v1/contests/" + contestID + "/prize-pool"
+
+	require.Equal(t, http.StatusCreated, sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{SharesBps: []uint64{10000}}).Code)
+	require.NoError(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(300)))
+	require.NoError(t, mock.DepositPrize(contestID, organizerWallet, big.NewInt(200)))
+	require.NoError(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(100)))
+
+	// Only closed or cancelled contests are refunded
+	rr := sendAs(router, admin, "POST", path+"/refund", nil)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problemCode(t, rr))
+	assert.Equal(t, http.StatusUnauthorized, send(router, "POST", path+"/refund", nil).Code)
+
+	require.Equal(t, http.StatusOK, send(setup, "POST", "/api/v1/contests/"+contestID+"/cancel", nil).Code)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problemCode(t, sendAs(router, admin, "POST", path+"/payout", nil)))
+
+	rr = sendAs(router, admin, "POST", path+"/refund", nil)
+	pool := prizePool(t, rr)
+	assert.Equal(t, models.PrizePoolRefunding, pool.State)
+	for _, deposit := range pool.Deposits {
+		assert.True(t, deposit.Refunded)
+	}
+	var response models.PrizePoolResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Contains(t, response.Message, "Refunded 2 depositors")
+	assert.Empty(t, pool.Payouts)
+
+	// Refunding again has nothing left to send
+	require.NoError(t, json.Unmarshal(sendAs(router, admin, "POST", path+"/refund", nil).Body.Bytes(), &response))
+	assert.Contains(t, response.Message, "Refunded 0 depositors")
+}
+
+func TestPrizePoolRefundOfClosedContest(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	mock := svc.(*service.MockBlockchainService)
+	setup := newRouter(api.NewHandler(svc))
+	router := prizePoolRouter(svc)
+	contestID, _, _ := judgedContest(t, svc, setup)
+	path := "/api/v1/contests/" + contestID + "/prize-pool"
+
+	require.Equal(t, http.StatusCreated, sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{SharesBps: []uint64{10000}}).Code)
+	require.NoError(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(300)))
+
+	// A contest that closed without its pool being paid out gives it back
+	_, err := svc.TransitionContest(contestID, models.ContestClosed, "", "scheduler")
+	require.NoError(t, err)
+	pool := prizePool(t, sendAs(router, admin, "POST", path+"/refund", nil))
+	assert.Equal(t, models.PrizePoolRefunding, pool.State)
+	require.Len(t, pool.Deposits, 1)
+	assert.True(t, pool.Deposits[0].Refunded)
+}
+
+func TestPrizePoolErrors(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	mock := svc.(*service.MockBlockchainService)
+	setup := newRouter(api.NewHandler(svc))
+	router := prizePoolRouter(svc)
+	contestID, alice, bob := judgedContest(t, svc, setup)
+	path := "/api/v1/contests/" + contestID + "/prize-pool"
+
+	rr := sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{SharesBps: []uint64{6000, 3000}})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{Token: "not-an-address", SharesBps: []uint64{10000}})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, http.StatusNotFound, sendAs(router, admin, "PUT", "/api/v1/contests/missing/prize-pool", models.OpenPrizePoolRequest{SharesBps: []uint64{10000}}).Code)
+	assert.Equal(t, http.StatusNotFound, sendAs(router, admin, "POST", path+"/payout", nil).Code)
+
+	// Contestants created without a wallet have nowhere to be paid
+	require.Equal(t, http.StatusCreated, sendAs(router, admin, "PUT", path, models.OpenPrizePoolRequest{SharesBps: []uint64{10000}}).Code)
+	require.NoError(t, mock.DepositPrize(contestID, sponsorWallet, big.NewInt(100)))
+	_, err := svc.FinalizeResults(&models.ContestResults{
+		ContestID: contestID,
+		Results:   []models.ContestantResult{{Rank: 1, ContestantID: bob}, {Rank: 2, ContestantID: alice}},
+		Final:     true,
+	})
+	require.NoError(t, err)
+	rr = sendAs(router, admin, "POST", path+"/payout", nil)
+	assert.Equal(t, models.ErrorCodeNoPayoutWallet, problemCode(t, rr))
+
+	// The endpoints are off unless an escrow is configured, and so is
+	// role-based access control
+	for _, handler := range []*api.Handler{api.NewHandler(svc), api.NewHandler(svc, api.WithPrizePools())} {
+		rr = send(newRouter(handler), "GET", path, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Contains(t, rr.Body.String(), "Prize pools are not enabled")
+	}
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	handler := api.NewHandler(service.NewReadOnlyMockBlockchainService())
+
+	router := newRouter(handler)
+
+	// Health báo chế độ chỉ đọc
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/health", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var health map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &health))
+	assert.Equal(t, "read-only", health["mode"])
+
+	// Truy vấn vẫn hoạt động
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/contests", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Ghi bị từ chối với mã lỗi máy đọc được
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:      "Test Contest",
+		StartDate: "2025-07-05T00:00:00Z",
+		EndDate:   "2025-08-05T00:00:00Z",
+	})
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var errResp models.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResp))
+	assert.Equal(t, models.ErrorCodeReadOnly, errResp.Code)
+}
+
+func TestReadWriteHealth(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService())
+
+	rr := httptest.NewRecorder()
+	handler.HealthCheck(rr, httptest.NewRequest("GET", "/api/v1/health", nil))
+	var health map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &health))
+	assert.Equal(t, "read-write", health["mode"])
+}
@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createPolicyContest(t *testing.T, router *mux.Router, policy *models.RegistrationPolicyRequest) string {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:         "Limited Contest",
+		Description:  "Contest with registration rules",
+		StartDate:    "2099-07-05T00:00:00Z",
+		EndDate:      "2099-08-05T00:00:00Z",
+		Registration: policy,
+	})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+func createContestant(t *testing.T, router *mux.Router, name string) string {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateContestantRequest{Name: name, Details: "Player"})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contestants", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+func registerContestant(router *mux.Router, contestID, contestantID string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(models.RegisterContestantRequest{ContestantID: contestantID})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+contestID+"/register", bytes.NewReader(body)))
+	return rr
+}
+
+func problemCode(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var problem models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	return problem.Code
+}
+
+func TestRegistrationCapacityAndWaitlist(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxContestants: 1, Waitlist: true})
+	alice := createContestant(t, router, "Alice")
+	bob := createContestant(t, router, "Bob")
+
+	rr := registerContestant(router, contestID, alice)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var registered models.RegisterContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &registered))
+	assert.Equal(t, models.RegistrationRegistered, registered.Data.Status)
+
+	rr = registerContestant(router, contestID, bob)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var waitlisted models.RegisterContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &waitlisted))
+	assert.Equal(t, models.RegistrationWaitlisted, waitlisted.Data.Status)
+	assert.Equal(t, 1, waitlisted.Data.Position)
+
+	rr = registerContestant(router, contestID, alice)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeAlreadyRegistered, problemCode(t, rr))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/contests/"+contestID+"/contestants", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list models.ListContestantsInContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	require.Len(t, list.Waitlist, 1)
+	assert.Equal(t, bob, list.Waitlist[0].ID)
+
+	// Without a waitlist a full contest refuses
+	full := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxContestants: 1})
+	require.Equal(t, http.StatusCreated, registerContestant(router, full, alice).Code)
+	rr = registerContestant(router, full, bob)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeContestFull, problemCode(t, rr))
+}
+
+func TestRegistrationWindow(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	alice := createContestant(t, router, "Alice")
+
+	notYet := createPolicyContest(t, router, &models.RegistrationPolicyRequest{OpensAt: "2099-01-01T00:00:00Z"})
+	rr := registerContestant(router, notYet, alice)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeRegistrationNotOpen, problemCode(t, rr))
+
+	closed := createPolicyContest(t, router, &models.RegistrationPolicyRequest{OpensAt: "2020-01-01T00:00:00Z", ClosesAt: "2020-02-01T00:00:00Z"})
+	rr = registerContestant(router, closed, alice)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeRegistrationClosed, problemCode(t, rr))
+
+	// The window must close after it opens
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:         "Backwards",
+		Description:  "Window closes before it opens",
+		StartDate:    "2099-07-05T00:00:00Z",
+		EndDate:      "2099-08-05T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{OpensAt: "2099-02-01T00:00:00Z", ClosesAt: "2099-01-01T00:00:00Z", MaxContestants: -1},
+	})
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var problem models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	fields := make(map[string]string)
+	for _, field := range problem.Errors {
+		fields[field.Field] = field.Rule
+	}
+	assert.Equal(t, map[string]string{"registration.closes_at": "after", "registration.max_contestants": "range"}, fields)
+}
+
+func contestRoster(t *testing.T, router *mux.Router, contestID string) models.ListContestantsInContestResponse {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/contests/"+contestID+"/contestants", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list models.ListContestantsInContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	return list
+}
+
+func TestWithdrawalPromotesTheWaitlist(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxContestants: 1, Waitlist: true})
+	aliceWallet, bobWallet := wallet(judgeWallet), wallet(otherJudge)
+	createOwned := func(owner *auth.Identity, name string) string {
+		rr := sendAs(router, owner, "POST", "/api/v1/contestants", models.CreateContestantRequest{Name: name, Details: "Player"})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var created models.CreateContestantResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		return created.ID
+	}
+	alice := createOwned(aliceWallet, "Alice")
+	bob := createOwned(bobWallet, "Bob")
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, alice).Code)
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, bob).Code)
+	path := "/api/v1/contests/" + contestID + "/register/" + alice
+
+	// Only the contestant may withdraw, even with RBAC off; nobody else's
+	// request is written on-chain as the contestant's own withdrawal
+	assert.Equal(t, http.StatusUnauthorized, send(router, "DELETE", path, models.WithdrawContestantRequest{Reason: "cannot attend"}).Code)
+	rr := sendAs(router, bobWallet, "DELETE", path, models.WithdrawContestantRequest{Reason: "cannot attend"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = sendAs(router, aliceWallet, "DELETE", path, models.WithdrawContestantRequest{Reason: "cannot attend"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.WithdrawContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.RegistrationWithdrawn, response.Data.Status)
+	assert.Equal(t, "cannot attend", response.Data.Reason)
+	assert.Equal(t, aliceWallet.Address, response.Data.Actor)
+	assert.Equal(t, bob, response.Data.Promoted)
+	assert.NotEmpty(t, response.TxHash)
+
+	list := contestRoster(t, router, contestID)
+	require.Len(t, list.Contestants, 1)
+	assert.Equal(t, bob, list.Contestants[0].ID)
+	assert.Empty(t, list.Waitlist)
+
+	// Withdrawing twice finds nothing to withdraw
+	assert.Equal(t, http.StatusNotFound, sendAs(router, aliceWallet, "DELETE", path, nil).Code)
+
+	// A withdrawn contestant may register again, behind those already in
+	rr = registerContestant(router, contestID, alice)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var again models.RegisterContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &again))
+	assert.Equal(t, models.RegistrationWaitlisted, again.Data.Status)
+}
+
+func TestOrganizerRemovesContestant(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithRoles(auth.NewRoleStore()))
+	router := newRouter(handler)
+	organizer := &auth.Identity{Subject: "0x1111111111111111111111111111111111111111", Address: "0x1111111111111111111111111111111111111111", Roles: []string{auth.RoleOrganizer}}
+	player := &auth.Identity{Subject: "0x3333333333333333333333333333333333333333", Address: "0x3333333333333333333333333333333333333333", Roles: []string{auth.RoleContestant}}
+	other := &auth.Identity{Subject: "0x4444444444444444444444444444444444444444", Address: "0x4444444444444444444444444444444444444444", Roles: []string{auth.RoleContestant}}
+
+	as := func(identity *auth.Identity, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+	decodeID := func(rr *httptest.ResponseRecorder) string {
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var created struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		return created.ID
+	}
+
+	contestID := decodeID(as(organizer, "POST", "/api/v1/contests", `{"name":"Owned","description":"d","start_date":"2099-07-05T00:00:00Z","end_date":"2099-08-05T00:00:00Z"}`))
+	contestantID := decodeID(as(player, "POST", "/api/v1/contestants", `{"name":"Player","details":"d"}`))
+	require.Equal(t, http.StatusCreated, as(player, "POST", "/api/v1/contests/"+contestID+"/register", `{"contestant_id":"`+contestantID+`"}`).Code)
+
+	path := "/api/v1/contests/" + contestID + "/register/" + contestantID
+	assert.Equal(t, http.StatusForbidden, as(other, "DELETE", path, "").Code)
+
+	rr := as(organizer, "DELETE", path, "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeValidation, problemCode(t, rr))
+
+	rr = as(organizer, "DELETE", path, `{"reason":"broke the rules"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.WithdrawContestantResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, models.RegistrationRemoved, response.Data.Status)
+	assert.Equal(t, organizer.Address, response.Data.Actor)
+	assert.Empty(t, contestRoster(t, router, contestID).Contestants)
+}
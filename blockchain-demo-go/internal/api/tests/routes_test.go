@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/idempotency"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRouter mounts the routes of the server, with write endpoints open to
+// anonymous callers as with AUTH_REQUIRED=false
+func newRouter(handler *api.Handler) *mux.Router {
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router, api.Routes{})
+	return router
+}
+
+func TestRoutesGuardWriteEndpoints(t *testing.T) {
+	store, err := idempotency.NewStore("", time.Hour)
+	require.NoError(t, err)
+	defer store.Close()
+	router := mux.NewRouter()
+	api.NewHandler(service.NewMockBlockchainService()).RegisterRoutes(router, api.Routes{
+		AuthRequired: true,
+		Idempotent:   idempotency.NewMiddleware(store, func(r *http.Request) string { return "test" }).Wrap,
+	})
+
+	as := func(identity *auth.Identity, method, path, key string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		r := httptest.NewRequest(method, path, bytes.NewReader(data))
+		if identity != nil {
+			r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		}
+		if key != "" {
+			r.Header.Set(idempotency.Header, key)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	contest := models.CreateContestRequest{
+		Name:        "Guarded Contest",
+		Description: "Created through the server routes",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+	}
+	wallet := &auth.Identity{Subject: organizerWallet, Address: organizerWallet, Scopes: auth.WalletScopes}
+
+	// Anonymous callers and callers without the scope never reach the handler
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "POST", "/api/v1/contests", "", contest).Code)
+	assert.Equal(t, http.StatusForbidden, as(wallet, "POST", "/api/v1/moderation/submissions/sub-1/approve", "", models.ReviewSubmissionRequest{}).Code)
+	contestsKey := &auth.Identity{Subject: "apikey:contests", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeContestsWrite}}
+	assert.Equal(t, http.StatusForbidden, as(contestsKey, "POST", "/api/v1/contests/c1/votes/commit", "", models.CommitVoteRequest{}).Code)
+
+	// Retries with the same key are replayed instead of creating a second contest
+	first := as(wallet, "POST", "/api/v1/contests", "create-1", contest)
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	retry := as(wallet, "POST", "/api/v1/contests", "create-1", contest)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	// Reads stay open
+	assert.Equal(t, http.StatusOK, as(nil, "GET", "/api/v1/contests", "", nil).Code)
+}
@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sponsorWallet = "0x4444444444444444444444444444444444444444"
+
+// createSponsor creates a sponsor whose pledges come from wallet
+func createSponsor(t *testing.T, svc service.BlockchainServiceInterface, name, wallet string) string {
+	t.Helper()
+	created, err := svc.CreateSponsor(&models.CreateSponsorRequest{Name: name, ContactInfo: name + "@example.com", WalletAddress: wallet})
+	require.NoError(t, err)
+	return created.ID
+}
+
+func contestSponsors(t *testing.T, rr *httptest.ResponseRecorder) models.ListContestSponsorsResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.ListContestSponsorsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func TestSponsorPledgesAndTiers(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc))
+	rr := send(router, "POST", "/api/v1/contests", models.CreateContestRequest{
+		Name:         "Sponsored Contest",
+		Description:  "Contest with sponsor tiers",
+		StartDate:    "2099-07-05T00:00:00Z",
+		EndDate:      "2099-08-05T00:00:00Z",
+		SponsorTiers: &models.SponsorTiersRequest{Gold: 300, Silver: 200, Bronze: 100},
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	path := "/api/v1/contests/" + created.ID + "/sponsors"
+
+	acme := createSponsor(t, svc, "Acme", sponsorWallet)
+	globex := createSponsor(t, svc, "Globex", judgeWallet)
+	initech := createSponsor(t, svc, "Initech", otherJudge)
+	wallets := map[string]string{acme: sponsorWallet, globex: judgeWallet, initech: otherJudge}
+	for _, pledge := range []models.PledgeRequest{
+		{SponsorID: acme, Amount: 150},
+		{SponsorID: globex, Amount: 120, Visibility: models.VisibilityHideAmount},
+		{SponsorID: acme, Amount: 200, Message: "Top up"},
+		{SponsorID: initech, Amount: 50, Visibility: models.VisibilityAnonymous},
+	} {
+		rr := sendAs(router, wallet(wallets[pledge.SponsorID]), "POST", path, pledge)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	// Pledges add up per sponsor; hidden fields are left out
+	sponsors := contestSponsors(t, send(router, "GET", path, nil))
+	require.Len(t, sponsors.Data, 3)
+	assert.Equal(t, "Acme", sponsors.Data[0].SponsorName)
+	assert.Equal(t, uint64(350), *sponsors.Data[0].Amount)
+	assert.Equal(t, models.TierGold, sponsors.Data[0].Tier)
+	assert.Equal(t, 2, sponsors.Data[0].Pledges)
+	assert.Equal(t, globex, sponsors.Data[1].SponsorID)
+	assert.Nil(t, sponsors.Data[1].Amount)
+	assert.Equal(t, models.TierBronze, sponsors.Data[1].Tier)
+	assert.Empty(t, sponsors.Data[2].SponsorID)
+	assert.Equal(t, uint64(50), *sponsors.Data[2].Amount)
+	assert.Equal(t, models.TierSupporter, sponsors.Data[2].Tier)
+	// The total leaves out the hidden amount
+	assert.Equal(t, &models.SponsorshipSummary{Total: 400, Sponsors: 3, Pledges: 4,
+		Tiers: map[string]int{models.TierGold: 1, models.TierBronze: 1, models.TierSupporter: 1}}, sponsors.Summary)
+
+	// Totals show up in the contest details
+	rr = send(router, "GET", "/api/v1/contests/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var contest models.GetContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &contest))
+	require.NotNil(t, contest.Data.Sponsorship)
+	assert.Equal(t, uint64(400), contest.Data.Sponsorship.Total)
+
+	// A sponsor's contests hide anonymous associations from others
+	contests := contestSponsors(t, send(router, "GET", "/api/v1/sponsors/"+acme+"/contests", nil))
+	require.Len(t, contests.Data, 1)
+	assert.Equal(t, "Sponsored Contest", contests.Data[0].ContestName)
+	assert.Equal(t, models.TierGold, contests.Data[0].Tier)
+	contests = contestSponsors(t, send(router, "GET", "/api/v1/sponsors/"+initech+"/contests", nil))
+	assert.Empty(t, contests.Data)
+}
+
+func TestSponsorPledgeErrors(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc))
+	contestID := createLifecycleContest(t, router, false)
+	path := "/api/v1/contests/" + contestID + "/sponsors"
+	acme := createSponsor(t, svc, "Acme", sponsorWallet)
+	owner := wallet(sponsorWallet)
+
+	rr := sendAs(router, owner, "POST", path, models.PledgeRequest{SponsorID: "nope", Amount: 10})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = sendAs(router, owner, "POST", path, models.PledgeRequest{SponsorID: acme})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = sendAs(router, owner, "POST", path, models.PledgeRequest{SponsorID: acme, Amount: 10, Visibility: "secret"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = sendAs(router, owner, "POST", "/api/v1/contests/nope/sponsors", models.PledgeRequest{SponsorID: acme, Amount: 10})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = send(router, "GET", "/api/v1/contests/nope/sponsors", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Only the sponsor's wallet pledges for it, even with RBAC off
+	assert.Equal(t, http.StatusUnauthorized, send(router, "POST", path, models.PledgeRequest{SponsorID: acme, Amount: 10}).Code)
+	assert.Equal(t, http.StatusForbidden, sendAs(router, wallet(otherJudge), "POST", path, models.PledgeRequest{SponsorID: acme, Amount: 10}).Code)
+
+	// A sponsor created through the API belongs to the wallet that created it
+	rr = sendAs(router, wallet(judgeWallet), "POST", "/api/v1/sponsors", models.CreateSponsorRequest{Name: "Globex", ContactInfo: "globex@example.com"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var globex models.CreateSponsorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &globex))
+	assert.Equal(t, http.StatusForbidden, sendAs(router, owner, "POST", path, models.PledgeRequest{SponsorID: globex.ID, Amount: 10}).Code)
+	assert.Equal(t, http.StatusCreated, sendAs(router, wallet(judgeWallet), "POST", path, models.PledgeRequest{SponsorID: globex.ID, Amount: 10}).Code)
+
+	// Chain failures are not reported as a missing contest
+	unreachable := newRouter(api.NewHandler(unreachableChain{svc}))
+	assert.Equal(t, http.StatusServiceUnavailable, send(unreachable, "GET", path, nil).Code)
+
+	// Cancelled contests take no more pledges
+	require.Equal(t, http.StatusOK, send(router, "POST", "/api/v1/contests/"+contestID+"/cancel", nil).Code)
+	rr = sendAs(router, owner, "POST", path, models.PledgeRequest{SponsorID: acme, Amount: 10})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, problemCode(t, rr))
+
+	// Thresholds must decrease from gold to bronze
+	rr = send(router, "POST", "/api/v1/contests", models.CreateContestRequest{
+		Name:         "Bad Tiers",
+		Description:  "Silver above gold",
+		StartDate:    "2099-07-05T00:00:00Z",
+		EndDate:      "2099-08-05T00:00:00Z",
+		SponsorTiers: &models.SponsorTiersRequest{Gold: 100, Silver: 200, Bronze: 50},
+	})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSponsorPledgeVisibility(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc, api.WithRoles(auth.NewRoleStore())))
+	organizer := &auth.Identity{Subject: organizerWallet, Address: organizerWallet, Roles: []string{auth.RoleOrganizer}}
+	sponsor := &auth.Identity{Subject: sponsorWallet, Address: sponsorWallet, Roles: []string{auth.RoleSponsor}}
+	other := &auth.Identity{Subject: otherJudge, Address: otherJudge, Roles: []string{auth.RoleContestant}}
+
+	as := func(identity *auth.Identity, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		r := httptest.NewRequest(method, path, bytes.NewReader(data))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	rr := as(organizer, "POST", "/api/v1/contests", models.CreateContestRequest{
+		Name:        "Private Sponsors",
+		Description: "Sponsors that keep to themselves",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	path := "/api/v1/contests/" + created.ID + "/sponsors"
+	acme := createSponsor(t, svc, "Acme", sponsorWallet)
+
+	// Only sponsors pledge
+	pledge := models.PledgeRequest{SponsorID: acme, Amount: 5000, Visibility: models.VisibilityAnonymous}
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "POST", path, pledge).Code)
+	assert.Equal(t, http.StatusForbidden, as(other, "POST", path, pledge).Code)
+	rr = as(sponsor, "POST", path, pledge)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var pledged models.PledgeResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &pledged))
+	assert.True(t, sameAddress(sponsorWallet, pledged.Data.PledgedBy))
+
+	// The organizer and the pledging wallet see who sponsors; others do not
+	assert.Empty(t, contestSponsors(t, as(other, "GET", path, nil)).Data[0].SponsorID)
+	assert.Equal(t, acme, contestSponsors(t, as(organizer, "GET", path, nil)).Data[0].SponsorID)
+	assert.Equal(t, acme, contestSponsors(t, as(sponsor, "GET", path, nil)).Data[0].SponsorID)
+
+	assert.Empty(t, contestSponsors(t, as(other, "GET", "/api/v1/sponsors/"+acme+"/contests", nil)).Data)
+	assert.Len(t, contestSponsors(t, as(sponsor, "GET", "/api/v1/sponsors/"+acme+"/contests", nil)).Data, 1)
+}
@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"blockchain-demo/internal/teams"
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type teamWallet struct {
+	key     *ecdsa.PrivateKey
+	address string
+}
+
+func newTeamWallet(t *testing.T) teamWallet {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return teamWallet{key: key, address: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+}
+
+func (tw teamWallet) accept(t *testing.T, router *mux.Router, team *models.Team) *httptest.ResponseRecorder {
+	t.Helper()
+	message := teams.AcceptMessage(team, tw.address)
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), tw.key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+
+	body, _ := json.Marshal(models.AcceptTeamInviteRequest{Address: tw.address, Signature: hexutil.Encode(sig)})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/teams/"+team.ID+"/accept", bytes.NewReader(body)))
+	return rr
+}
+
+func TestTeamRegistrationFlow(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithTeams(teams.NewStore())))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MinTeamSize: 2, MaxTeamSize: 3})
+	captain, member := newTeamWallet(t), newTeamWallet(t)
+
+	body, _ := json.Marshal(models.CreateTeamRequest{Name: "Rockets", Captain: captain.address, Members: []string{member.address}})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+contestID+"/teams", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.TeamResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	team := created.Data
+	require.Len(t, team.Members, 2)
+
+	register := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/teams/"+team.ID+"/register", nil))
+		return rr
+	}
+
+	// Not every member has signed yet
+	require.Equal(t, http.StatusOK, captain.accept(t, router, team).Code)
+	rr = register()
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeTeamNotReady, problemCode(t, rr))
+
+	// A signature by the wrong wallet is refused
+	impostor := teamWallet{key: captain.key, address: member.address}
+	rr = impostor.accept(t, router, team)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidSignature, problemCode(t, rr))
+
+	require.Equal(t, http.StatusOK, member.accept(t, router, team).Code)
+	rr = register()
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var registered models.TeamResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &registered))
+	assert.Equal(t, models.TeamRegistered, registered.Data.Status)
+	assert.Equal(t, models.RegistrationRegistered, registered.Data.Entry)
+	assert.NotEmpty(t, registered.TxHash)
+
+	assert.Equal(t, models.ErrorCodeAlreadyRegistered, problemCode(t, register()))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/contests/"+contestID+"/teams", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list models.ListTeamsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, team.ID, list.Data[0].ID)
+	assert.Equal(t, models.TeamRegistered, list.Data[0].Status)
+}
+
+func TestTeamSizeLimits(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithTeams(teams.NewStore())))
+	captain := newTeamWallet(t)
+
+	create := func(contestID string, members ...string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.CreateTeamRequest{Name: "Rockets", Captain: captain.address, Members: members})
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests/"+contestID+"/teams", bytes.NewReader(body)))
+		return rr
+	}
+
+	solo := createPolicyContest(t, router, nil)
+	rr := create(solo)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeTeamsNotAllowed, problemCode(t, rr))
+
+	pairs := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MinTeamSize: 2, MaxTeamSize: 2})
+	rr = create(pairs, newTeamWallet(t).address, newTeamWallet(t).address)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidTeamSize, problemCode(t, rr))
+	assert.Equal(t, models.ErrorCodeInvalidTeamSize, problemCode(t, create(pairs)))
+
+	rr = create("missing")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTeamsNeedTheCaptain(t *testing.T) {
+	handler := api.NewHandler(service.NewMockBlockchainService(), api.WithTeams(teams.NewStore()), api.WithRoles(auth.NewRoleStore()))
+	router := newRouter(handler)
+	captain, member := newTeamWallet(t), newTeamWallet(t)
+	organizer := &auth.Identity{Subject: captain.address, Address: captain.address, Roles: []string{auth.RoleOrganizer, auth.RoleContestant}}
+	other := &auth.Identity{Subject: member.address, Address: member.address, Roles: []string{auth.RoleContestant}}
+
+	as := func(identity *auth.Identity, method, path string, body []byte) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, bytes.NewReader(body))
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:         "Team Contest",
+		Description:  "Pairs only",
+		StartDate:    "2099-07-05T00:00:00Z",
+		EndDate:      "2099-08-05T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{MaxTeamSize: 2},
+	})
+	rr := as(organizer, "POST", "/api/v1/contests", body)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var contest models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &contest))
+
+	// The signed-in wallet captains the team whatever the body says
+	body, _ = json.Marshal(models.CreateTeamRequest{Name: "Rockets", Captain: member.address, Members: []string{member.address}})
+	assert.Equal(t, http.StatusUnauthorized, as(nil, "POST", "/api/v1/contests/"+contest.ID+"/teams", body).Code)
+	rr = as(organizer, "POST", "/api/v1/contests/"+contest.ID+"/teams", body)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.TeamResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.True(t, sameAddress(captain.address, created.Data.Captain))
+
+	require.Equal(t, http.StatusOK, captain.accept(t, router, created.Data).Code)
+	require.Equal(t, http.StatusOK, member.accept(t, router, created.Data).Code)
+
+	assert.Equal(t, http.StatusForbidden, as(other, "POST", "/api/v1/teams/"+created.Data.ID+"/register", nil).Code)
+	assert.Equal(t, http.StatusCreated, as(organizer, "POST", "/api/v1/teams/"+created.Data.ID+"/register", nil).Code)
+}
+
+func TestTeamInvitationsDoNotHoldWallets(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithTeams(teams.NewStore())))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxTeamSize: 3})
+	captain, rival, member := newTeamWallet(t), newTeamWallet(t), newTeamWallet(t)
+
+	create := func(captain teamWallet, members ...string) *models.Team {
+		rr := send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Team", Captain: captain.address, Members: members})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var created models.TeamResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		return created.Data
+	}
+
+	// Anyone may be invited by several teams, but joins only one
+	first := create(captain, member.address)
+	second := create(rival, member.address)
+	require.Equal(t, http.StatusOK, member.accept(t, router, first).Code)
+	rr := member.accept(t, router, second)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeAlreadyOnTeam, problemCode(t, rr))
+
+	// Declining takes the signed-in wallet off the team
+	assert.Equal(t, http.StatusUnauthorized, send(router, "POST", "/api/v1/teams/"+first.ID+"/decline", nil).Code)
+	rr = sendAs(router, wallet(member.address), "POST", "/api/v1/teams/"+first.ID+"/decline", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, http.StatusOK, member.accept(t, router, second).Code)
+
+	// Only the captain disbands a team
+	path := "/api/v1/teams/" + first.ID
+	assert.Equal(t, http.StatusUnauthorized, send(router, "DELETE", path, nil).Code)
+	assert.Equal(t, http.StatusForbidden, sendAs(router, wallet(rival.address), "DELETE", path, nil).Code)
+	require.Equal(t, http.StatusOK, sendAs(router, wallet(captain.address), "DELETE", path, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send(router, "GET", path, nil).Code)
+}
+
+func TestVerifiedContestsCheckEveryMember(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService(), api.WithTeams(teams.NewStore())))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxTeamSize: 2, RequireVerified: true})
+	captain, member := newTeamWallet(t), newTeamWallet(t)
+	createOwned := func(owner teamWallet) {
+		rr := sendAs(router, wallet(owner.address), "POST", "/api/v1/contestants", models.CreateContestantRequest{Name: "Player", Details: "Verified player"})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	rr := send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Rockets", Captain: captain.address, Members: []string{member.address}})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.TeamResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	require.Equal(t, http.StatusOK, captain.accept(t, router, created.Data).Code)
+	require.Equal(t, http.StatusOK, member.accept(t, router, created.Data).Code)
+	register := func() *httptest.ResponseRecorder {
+		return send(router, "POST", "/api/v1/teams/"+created.Data.ID+"/register", nil)
+	}
+
+	// The captain alone being verified is not enough
+	createOwned(captain)
+	rr = register()
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, models.ErrorCodeNotEligible, problemCode(t, rr))
+
+	createOwned(member)
+	rr = register()
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+}
+
+func TestRegisteredTeamsSurviveRestart(t *testing.T) {
+	chain := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(chain, api.WithTeams(teams.NewStore())))
+	contestID := createPolicyContest(t, router, &models.RegistrationPolicyRequest{MaxTeamSize: 2})
+	captain, member := newTeamWallet(t), newTeamWallet(t)
+
+	rr := send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Rockets", Captain: captain.address, Members: []string{member.address}})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.TeamResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	require.Equal(t, http.StatusOK, captain.accept(t, router, created.Data).Code)
+	require.Equal(t, http.StatusOK, member.accept(t, router, created.Data).Code)
+	rr = send(router, "POST", "/api/v1/teams/"+created.Data.ID+"/register", nil)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	// A new server starts with an empty store and reads the team from the chain
+	router = newRouter(api.NewHandler(chain, api.WithTeams(teams.NewStore())))
+	rr = send(router, "GET", "/api/v1/teams/"+created.Data.ID, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = send(router, "GET", "/api/v1/contests/"+contestID+"/teams", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list models.ListTeamsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, models.TeamRegistered, list.Data[0].Status)
+
+	// Its members cannot form another team or be put on one
+	rr = send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Comets", Captain: member.address})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeAlreadyOnTeam, problemCode(t, rr))
+	rr = send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Meteors", Captain: newTeamWallet(t).address, Members: []string{captain.address}})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Once the team withdraws, through any server, its members are free again
+	_, err := chain.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: contestID, ContestantID: created.Data.ID, Actor: captain.address})
+	require.NoError(t, err)
+	rr = send(router, "POST", "/api/v1/contests/"+contestID+"/teams", models.CreateTeamRequest{Name: "Comets", Captain: member.address})
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = send(router, "GET", "/api/v1/teams/"+created.Data.ID, nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTeamsNotEnabled(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v1/teams/abc", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func sameAddress(a, b string) bool {
+	return auth.NormalizeAccount(a) == auth.NormalizeAccount(b)
+}
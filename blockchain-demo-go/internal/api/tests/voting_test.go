@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"blockchain-demo/internal/voting"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// votingContest creates a contest whose commit phase ends at commitEndsAt
+// and reveal phase at revealEndsAt
+func votingContest(t *testing.T, router *mux.Router, commitEndsAt, revealEndsAt time.Time) string {
+	t.Helper()
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Audience Award",
+		Description: "Contest decided by vote",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+		Voting: &models.VotingPolicyRequest{
+			CommitEndsAt: commitEndsAt.Format(time.RFC3339Nano),
+			RevealEndsAt: revealEndsAt.Format(time.RFC3339Nano),
+		},
+	})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContestResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+// commitVote commits the wallet's vote for contestantID, hidden behind salt
+func (tw teamWallet) commitVote(t *testing.T, router *mux.Router, contestID, contestantID string, salt common.Hash) *httptest.ResponseRecorder {
+	t.Helper()
+	commitment := voting.Commitment(contestID, contestantID, salt, common.HexToAddress(tw.address)).Hex()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(voting.CommitMessage(contestID, commitment))), tw.key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return send(router, "POST", "/api/v1/contests/"+contestID+"/votes/commit", models.CommitVoteRequest{
+		Voter:      tw.address,
+		Commitment: commitment,
+		Signature:  hexutil.Encode(sig),
+	})
+}
+
+func (tw teamWallet) revealVote(router *mux.Router, contestID, contestantID string, salt common.Hash) *httptest.ResponseRecorder {
+	return send(router, "POST", "/api/v1/contests/"+contestID+"/votes/reveal", models.RevealVoteRequest{
+		Voter:        tw.address,
+		ContestantID: contestantID,
+		Salt:         salt.Hex(),
+	})
+}
+
+func voteTally(t *testing.T, router *mux.Router, contestID string) *models.VoteTally {
+	t.Helper()
+	rr := send(router, "GET", "/api/v1/contests/"+contestID+"/results", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response models.VoteTallyResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.Data
+}
+
+func TestCommitRevealVoting(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	router := newRouter(api.NewHandler(svc))
+	commitEndsAt := time.Now().Add(500 * time.Millisecond)
+	revealEndsAt := commitEndsAt.Add(500 * time.Millisecond)
+	contestID := votingContest(t, router, commitEndsAt, revealEndsAt)
+	alice := createContestant(t, router, "Alice")
+	bob := createContestant(t, router, "Bob")
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, alice).Code)
+	require.Equal(t, http.StatusCreated, registerContestant(router, contestID, bob).Code)
+
+	voters := []teamWallet{newTeamWallet(t), newTeamWallet(t), newTeamWallet(t)}
+	choices := []string{alice, bob, alice}
+	salts := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")}
+	for i, voter := range voters {
+		rr := voter.commitVote(t, router, contestID, choices[i], salts[i])
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	// One vote per wallet, and nothing to reveal until the commit phase ends
+	rr := voters[0].commitVote(t, router, contestID, bob, salts[0])
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeAlreadyVoted, problemCode(t, rr))
+	rr = voters[0].revealVote(router, contestID, alice, salts[0])
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeWrongVotingPhase, problemCode(t, rr))
+
+	tally := voteTally(t, router, contestID)
+	assert.Equal(t, models.VotingCommit, tally.Phase)
+	assert.Equal(t, 3, tally.Committed)
+	assert.Equal(t, 0, tally.Revealed)
+	for _, vote := range tally.Votes {
+		assert.Empty(t, vote.ContestantID)
+	}
+
+	time.Sleep(time.Until(commitEndsAt))
+
+	rr = voters[0].revealVote(router, contestID, bob, salts[0])
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, models.ErrorCodeInvalidReveal, problemCode(t, rr))
+	rr = voters[0].revealVote(router, contestID, alice, salts[0])
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var revealed models.VoteResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &revealed))
+	assert.True(t, revealed.Data.Verified)
+	require.Equal(t, http.StatusOK, voters[1].revealVote(router, contestID, bob, salts[1]).Code)
+	require.Equal(t, http.StatusOK, voters[2].revealVote(router, contestID, alice, salts[2]).Code)
+	rr = voters[2].revealVote(router, contestID, alice, salts[2])
+	assert.Equal(t, models.ErrorCodeAlreadyVoted, problemCode(t, rr))
+
+	time.Sleep(time.Until(revealEndsAt))
+
+	tally = voteTally(t, router, contestID)
+	assert.True(t, tally.Final)
+	assert.Equal(t, 3, tally.Revealed)
+	assert.Equal(t, 0, tally.Invalid)
+	assert.Equal(t, []models.VoteCount{{Rank: 1, ContestantID: alice, Votes: 2}, {Rank: 2, ContestantID: bob, Votes: 1}}, tally.Results)
+	for _, vote := range tally.Votes {
+		assert.True(t, voting.Verify(vote))
+	}
+
+	// The roster as of the end of the reveal phase decides, not today's
+	_, err := svc.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: contestID, ContestantID: alice, Actor: "organizer", Reason: "Broke the rules", Removed: true})
+	require.NoError(t, err)
+	tally = voteTally(t, router, contestID)
+	assert.Equal(t, 0, tally.Invalid)
+	assert.Equal(t, []models.VoteCount{{Rank: 1, ContestantID: alice, Votes: 2}, {Rank: 2, ContestantID: bob, Votes: 1}}, tally.Results)
+}
+
+func TestVotingNeedsAVotingContest(t *testing.T) {
+	router := newRouter(api.NewHandler(service.NewMockBlockchainService()))
+	contestID := createPolicyContest(t, router, nil)
+
+	rr := newTeamWallet(t).commitVote(t, router, contestID, "alice", common.HexToHash("0x01"))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, models.ErrorCodeVotingNotEnabled, problemCode(t, rr))
+	rr = send(router, "GET", "/api/v1/contests/"+contestID+"/results", nil)
+	assert.Equal(t, models.ErrorCodeVotingNotEnabled, problemCode(t, rr))
+
+	// The reveal phase must end after the commit phase
+	body, _ := json.Marshal(models.CreateContestRequest{
+		Name:        "Audience Award",
+		Description: "Contest decided by vote",
+		StartDate:   "2099-07-05T00:00:00Z",
+		EndDate:     "2099-08-05T00:00:00Z",
+		Voting:      &models.VotingPolicyRequest{CommitEndsAt: "2099-07-10T00:00:00Z", RevealEndsAt: "2099-07-09T00:00:00Z"},
+	})
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v1/contests", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
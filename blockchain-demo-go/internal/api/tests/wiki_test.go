@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"blockchain-demo/internal/api"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/service"
+	"blockchain-demo/internal/wiki"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArticle(t *testing.T, router *mux.Router, title, content string, categories ...string) string {
+	t.Helper()
+	rr := send(router, "POST", "/api/v1/content", models.CreateContentRequest{
+		Title:      title,
+		Content:    content,
+		Creator:    organizerWallet,
+		Categories: categories,
+	})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created models.CreateContentResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	return created.ID
+}
+
+func TestWikiIndexesSurviveRestart(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	path := filepath.Join(t.TempDir(), "wiki")
+
+	store, err := wiki.OpenRevisionStore(path)
+	require.NoError(t, err)
+	router := newRouter(api.NewHandler(svc, api.WithRevisionStore(store)))
+	alpha := createArticle(t, router, "Alpha", "The first article", "Games")
+	createArticle(t, router, "Beta", "Links back to [[Alpha]]")
+	require.NoError(t, store.Close())
+
+	store, err = wiki.OpenRevisionStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	handler := api.NewHandler(svc, api.WithRevisionStore(store))
+	require.NoError(t, handler.Reindex())
+	router = newRouter(handler)
+
+	var backlinks models.BacklinksResponse
+	rr := send(router, "GET", "/api/v1/content/"+alpha+"/backlinks", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &backlinks))
+	assert.Equal(t, 1, backlinks.Total)
+
+	var category models.GetCategoryResponse
+	rr = send(router, "GET", "/api/v1/categories/Games", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &category))
+	assert.Equal(t, 1, category.Total)
+
+	// Editing continues the stored history
+	rr = send(router, "PUT", "/api/v1/content/"+alpha, models.CreateContentRequest{Title: "Alpha", Content: "Edited", Creator: organizerWallet})
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var revisions models.ListRevisionsResponse
+	require.NoError(t, json.Unmarshal(send(router, "GET", "/api/v1/content/"+alpha+"/revisions", nil).Body.Bytes(), &revisions))
+	assert.Equal(t, 2, revisions.Total)
+}
+
+func TestUpdateContentWithoutStoredRevisions(t *testing.T) {
+	svc := service.NewMockBlockchainService()
+	alpha := createArticle(t, newRouter(api.NewHandler(svc)), "Alpha", "The first article")
+
+	// Articles from before revisions were stored can still be edited
+	router := newRouter(api.NewHandler(svc))
+	rr := send(router, "PUT", "/api/v1/content/"+alpha, models.CreateContentRequest{Title: "Alpha", Content: "Edited", Creator: organizerWallet})
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, http.StatusNotFound, send(router, "PUT", "/api/v1/content/missing", models.CreateContentRequest{Title: "Alpha", Content: "Edited", Creator: organizerWallet}).Code)
+}
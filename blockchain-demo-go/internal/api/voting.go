@@ -0,0 +1,132 @@
+package api
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/voting"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ============ VOTING HANDLERS ============
+
+// voter fills in the wallet casting a vote. Wallets vote as themselves; the
+// voter named in the body is only trusted when role-based access control is
+// disabled.
+func (h *Handler) voter(w http.ResponseWriter, r *http.Request, voter *string) bool {
+	if h.roles != nil && auth.IdentityFromContext(r.Context()) == nil {
+		h.respondWithError(w, http.StatusUnauthorized, "Authentication required", "")
+		return false
+	}
+	attribute(r, voter)
+	if *voter == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Voter is required", "")
+		return false
+	}
+	return true
+}
+
+// CommitVote handles POST /api/v1/contests/{id}/votes/commit. The voter
+// signs the commit message of its commitment with personal_sign.
+func (h *Handler) CommitVote(w http.ResponseWriter, r *http.Request) {
+	var req models.CommitVoteRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.ContestID = mux.Vars(r)["id"]
+	if !h.voter(w, r, &req.Voter) {
+		return
+	}
+
+	response, err := h.chain(r).CommitVote(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to commit vote", err)
+		return
+	}
+
+	log.Printf("🗳️ %s committed a vote in contest %s", req.Voter, req.ContestID)
+
+	h.respondWithJSON(w, http.StatusCreated, response)
+}
+
+// RevealVote handles POST /api/v1/contests/{id}/votes/reveal
+func (h *Handler) RevealVote(w http.ResponseWriter, r *http.Request) {
+	var req models.RevealVoteRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	req.ContestID = mux.Vars(r)["id"]
+	if !h.voter(w, r, &req.Voter) {
+		return
+	}
+
+	response, err := h.chain(r).RevealVote(&req)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to reveal vote", err)
+		return
+	}
+
+	log.Printf("🔓 %s revealed its vote in contest %s", req.Voter, req.ContestID)
+	h.voted(response.Data)
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// GetVoteResults handles GET /api/v1/contests/{id}/results. The tally is
+// recomputed from the votes on blockchain, checking every reveal against its
+// commitment; only registered contestants can receive votes. Once the reveal
+// phase is over, the roster is the one registered when it ended, so later
+// withdrawals and registrations do not change the result.
+func (h *Handler) GetVoteResults(w http.ResponseWriter, r *http.Request) {
+	contestID := mux.Vars(r)["id"]
+	contest, ok := h.findContest(w, contestID)
+	if !ok {
+		return
+	}
+	if contest.Voting == nil {
+		h.respondWithServiceError(w, "Voting is not enabled",
+			apperr.New(apperr.KindConflict, models.ErrorCodeVotingNotEnabled, "contest "+contestID+" is not decided by vote"))
+		return
+	}
+
+	now := time.Now()
+	var candidates []string
+	if voting.PhaseOf(contest, now) == models.VotingEnded {
+		roster, err := h.blockchainService.GetRosterAt(contestID, contest.Voting.RevealEndsAt)
+		if err != nil {
+			h.respondWithServiceError(w, "Failed to read the roster", err)
+			return
+		}
+		candidates = roster.Registered
+	} else {
+		registered, err := h.blockchainService.GetContestantsInContest(contestID)
+		if err != nil {
+			h.respondWithServiceError(w, "Failed to list contestants", err)
+			return
+		}
+		for _, contestant := range registered.Contestants {
+			candidates = append(candidates, contestant.ID)
+		}
+	}
+
+	votes, err := h.blockchainService.GetVotes(contestID)
+	if err != nil {
+		h.respondWithServiceError(w, "Failed to get votes", err)
+		return
+	}
+
+	tally := voting.Tally(contest, candidates, votes, now)
+	message := "Provisional tally"
+	if tally.Final {
+		message = "Final tally"
+	}
+	h.respondWithJSON(w, http.StatusOK, models.VoteTallyResponse{
+		Success: true,
+		Message: message,
+		Data:    tally,
+	})
+}
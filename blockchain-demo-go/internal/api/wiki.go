@@ -0,0 +1,265 @@
+package api
+
+import (
+	"blockchain-demo/internal/diff"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/wiki"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// diffContextLines is the number of unchanged lines around each unified diff hunk
+const diffContextLines = 3
+
+// WithRevisionStore keeps article revisions in store instead of memory, so
+// they survive restarts
+func WithRevisionStore(store *wiki.RevisionStore) Option {
+	return func(h *Handler) {
+		h.revisions = store
+	}
+}
+
+// indexContent records a new revision of an article, with the transaction
+// that anchored it, and re-indexes its links, categories and tags
+func (h *Handler) indexContent(req *models.CreateContentRequest, anchored *models.CreateContentResponse) {
+	id := anchored.ID
+	rev := &models.Revision{
+		ContentID:  id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Namespace:  req.Namespace,
+		Categories: req.Categories,
+		Tags:       req.Tags,
+		Creator:    req.Creator,
+		TxHash:     anchored.TxHash,
+		RecordHash: anchored.RecordHash,
+		Timestamp:  time.Now(),
+	}
+	if _, err := h.revisions.Add(rev); err != nil {
+		log.Printf("⚠️ Failed to persist revision of content %s: %v", id, err)
+	}
+	h.indexRevision(rev)
+}
+
+// indexRevision indexes the links, categories and tags of an article's
+// latest revision
+func (h *Handler) indexRevision(rev *models.Revision) {
+	h.links.Index(rev.ContentID, rev.Title, rev.Content)
+	h.taxonomy.Index(&models.TaxonomyItem{
+		Kind:       "content",
+		ID:         rev.ContentID,
+		Title:      rev.Title,
+		Namespace:  rev.Namespace,
+		Categories: rev.Categories,
+		Tags:       rev.Tags,
+	})
+}
+
+// Reindex rebuilds the link graph and the taxonomy after a restart, from the
+// stored revisions and the contests on blockchain
+func (h *Handler) Reindex() error {
+	articles := h.revisions.Articles()
+	for _, rev := range articles {
+		h.indexRevision(rev)
+	}
+
+	contests, err := h.blockchainService.GetAllContests()
+	if err != nil {
+		return fmt.Errorf("failed to list contests: %w", err)
+	}
+	for _, contest := range contests.Data {
+		h.taxonomy.Index(&models.TaxonomyItem{
+			Kind:       "contest",
+			ID:         contest.ID,
+			Title:      contest.Name,
+			Categories: contest.Categories,
+			Tags:       contest.Tags,
+		})
+	}
+
+	log.Printf("📚 Indexed %d articles and %d contests", len(articles), len(contests.Data))
+	return nil
+}
+
+// ============ WIKI LINK HANDLERS ============
+
+// GetContentLinks handles GET /api/v1/content/{id}/links
+func (h *Handler) GetContentLinks(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if id == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Content ID is required", "")
+		return
+	}
+
+	log.Printf("🔗 Getting links of content: %s", id)
+
+	links, exists := h.links.Links(id)
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Content not found", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.ContentLinksResponse{
+		Success:   true,
+		ContentID: id,
+		Links:     links,
+		Total:     len(links),
+	})
+}
+
+// GetContentBacklinks handles GET /api/v1/content/{id}/backlinks
+func (h *Handler) GetContentBacklinks(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if id == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Content ID is required", "")
+		return
+	}
+
+	log.Printf("🔗 Getting backlinks of content: %s", id)
+
+	backlinks, exists := h.links.Backlinks(id)
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "Content not found", "")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, models.BacklinksResponse{
+		Success:   true,
+		ContentID: id,
+		Backlinks: backlinks,
+		Total:     len(backlinks),
+	})
+}
+
+// GetLinkGraph handles GET /api/v1/wiki/graph
+func (h *Handler) GetLinkGraph(w http.ResponseWriter, r *http.Request) {
+	log.Printf("🌐 Exporting wiki link graph")
+
+	h.respondWithJSON(w, http.StatusOK, models.LinkGraphResponse{
+		Success: true,
+		Data:    h.links.Graph(),
+	})
+}
+
+// GetBrokenLinksReport handles GET /api/v1/wiki/maintenance/broken-links
+func (h *Handler) GetBrokenLinksReport(w http.ResponseWriter, r *http.Request) {
+	log.Printf("🧹 Building broken links report")
+
+	broken := h.links.BrokenLinks()
+	h.respondWithJSON(w, http.StatusOK, models.BrokenLinksReportResponse{
+		Success: true,
+		Data:    broken,
+		Total:   len(broken),
+	})
+}
+
+// ============ REVISION HANDLERS ============
+
+// ListContentRevisions handles GET /api/v1/content/{id}/revisions
+func (h *Handler) ListContentRevisions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if id == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Content ID is required", "")
+		return
+	}
+
+	log.Printf("🕘 Listing revisions of content: %s", id)
+
+	if !h.revisions.Has(id) {
+		h.respondWithError(w, http.StatusNotFound, "Content not found", "")
+		return
+	}
+
+	revisions := h.revisions.List(id)
+	h.respondWithJSON(w, http.StatusOK, models.ListRevisionsResponse{
+		Success:   true,
+		ContentID: id,
+		Data:      revisions,
+		Total:     len(revisions),
+	})
+}
+
+// GetContentDiff handles GET /api/v1/content/{id}/diff?from=&to=
+// Both parameters are revision numbers; "to" defaults to the latest revision
+// and "from" to the revision before "to".
+func (h *Handler) GetContentDiff(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if id == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Content ID is required", "")
+		return
+	}
+
+	latest := h.revisions.Latest(id)
+	if latest == 0 {
+		h.respondWithError(w, http.StatusNotFound, "Content not found", "")
+		return
+	}
+
+	to, err := revisionParam(r, "to", latest)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid 'to' revision", err.Error())
+		return
+	}
+	from, err := revisionParam(r, "from", to-1)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid 'from' revision", err.Error())
+		return
+	}
+
+	fromRev, ok := h.revisions.Get(id, from)
+	if !ok {
+		h.respondWithError(w, http.StatusNotFound, "Revision not found", fmt.Sprintf("revision %d does not exist", from))
+		return
+	}
+	toRev, ok := h.revisions.Get(id, to)
+	if !ok {
+		h.respondWithError(w, http.StatusNotFound, "Revision not found", fmt.Sprintf("revision %d does not exist", to))
+		return
+	}
+
+	log.Printf("🔀 Diffing content %s: r%d..r%d", id, from, to)
+
+	lines, stats := diff.Lines(fromRev.Content, toRev.Content)
+	unified := diff.Unified(
+		fmt.Sprintf("a/%s@r%d %s", fromRev.Title, fromRev.Number, fromRev.TxHash),
+		fmt.Sprintf("b/%s@r%d %s", toRev.Title, toRev.Number, toRev.TxHash),
+		lines, diffContextLines,
+	)
+
+	h.respondWithJSON(w, http.StatusOK, models.ContentDiffResponse{
+		Success: true,
+		Data: &models.ContentDiff{
+			ContentID: id,
+			From:      wiki.RefOf(fromRev),
+			To:        wiki.RefOf(toRev),
+			Lines:     lines,
+			Stats:     stats,
+			Unified:   unified,
+		},
+	})
+}
+
+// revisionParam parses a revision number query parameter
+func revisionParam(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		if defaultValue < 1 {
+			return 0, fmt.Errorf("article has a single revision, nothing to compare")
+		}
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%s must be a positive revision number", name)
+	}
+	return number, nil
+}
@@ -0,0 +1,288 @@
+// Package apperr defines the typed errors returned by the service layer and
+// writes errors as RFC 9457 problem details with a stable code, so clients
+// never have to match error messages.
+package apperr
+
+import (
+	"blockchain-demo/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ProblemContentType is the media type of problem details
+const ProblemContentType = "application/problem+json"
+
+// Kind classifies an error; each kind has an HTTP status and a default code
+type Kind int
+
+// Error kinds
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindTooLarge
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindRateLimited
+	KindReverted
+	KindInsufficientFunds
+	KindChainUnavailable
+	KindUnavailable
+)
+
+var kindStatus = map[Kind]int{
+	KindInternal:          http.StatusInternalServerError,
+	KindBadRequest:        http.StatusBadRequest,
+	KindTooLarge:          http.StatusRequestEntityTooLarge,
+	KindValidation:        http.StatusBadRequest,
+	KindNotFound:          http.StatusNotFound,
+	KindConflict:          http.StatusConflict,
+	KindUnauthorized:      http.StatusUnauthorized,
+	KindForbidden:         http.StatusForbidden,
+	KindRateLimited:       http.StatusTooManyRequests,
+	KindReverted:          http.StatusUnprocessableEntity,
+	KindInsufficientFunds: http.StatusServiceUnavailable,
+	KindChainUnavailable:  http.StatusServiceUnavailable,
+	KindUnavailable:       http.StatusServiceUnavailable,
+}
+
+var kindCode = map[Kind]string{
+	KindInternal:          models.ErrorCodeInternal,
+	KindBadRequest:        models.ErrorCodeBadRequest,
+	KindTooLarge:          models.ErrorCodePayloadTooLarge,
+	KindValidation:        models.ErrorCodeValidation,
+	KindNotFound:          models.ErrorCodeNotFound,
+	KindConflict:          models.ErrorCodeConflict,
+	KindUnauthorized:      models.ErrorCodeUnauthorized,
+	KindForbidden:         models.ErrorCodeForbidden,
+	KindRateLimited:       models.ErrorCodeRateLimited,
+	KindReverted:          models.ErrorCodeReverted,
+	KindInsufficientFunds: models.ErrorCodeInsufficientFunds,
+	KindChainUnavailable:  models.ErrorCodeChainUnavailable,
+	KindUnavailable:       models.ErrorCodeServiceUnavailable,
+}
+
+// Error is a typed error. Code overrides the default code of the kind.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error               // Cause, if any
+	Fields  []models.FieldError // Invalid fields of a validation error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of a kind with a specific code, empty for the default
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap creates an error of a kind caused by err
+func Wrap(kind Kind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// NotFound reports a missing entity
+func NotFound(format string, args ...interface{}) *Error {
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validation reports invalid input
+func Validation(format string, args ...interface{}) *Error {
+	return &Error{Kind: KindValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports input clashing with the current state
+func Conflict(format string, args ...interface{}) *Error {
+	return &Error{Kind: KindConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Fields reports invalid fields of a request
+func Fields(fields []models.FieldError) *Error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+	return &Error{Kind: KindValidation, Message: strings.Join(messages, "; "), Fields: fields}
+}
+
+// FieldsOf returns the invalid fields of a validation error
+func FieldsOf(err error) []models.FieldError {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Fields
+	}
+	return nil
+}
+
+// KindOf returns the kind of an error, KindInternal if it is untyped
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+// Is reports whether an error is of a kind
+func Is(err error, kind Kind) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Kind == kind
+}
+
+// StatusOf returns the HTTP status of an error
+func StatusOf(err error) int {
+	return kindStatus[KindOf(err)]
+}
+
+// CodeOf returns the machine-readable code of an error
+func CodeOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) && e.Code != "" {
+		return e.Code
+	}
+	return kindCode[KindOf(err)]
+}
+
+// CodeForStatus returns the default code of an HTTP error status
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return models.ErrorCodeBadRequest
+	case http.StatusUnprocessableEntity:
+		return models.ErrorCodeValidation
+	}
+	for kind, s := range kindStatus {
+		if s == status && kind != KindInsufficientFunds && kind != KindChainUnavailable && kind != KindReverted && kind != KindValidation {
+			return kindCode[kind]
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		return models.ErrorCodeInternal
+	}
+	return models.ErrorCodeBadRequest
+}
+
+// FromChain classifies an error of the Ethereum client: reverts, missing
+// funds and unreachable nodes get their own kind, anything else is internal.
+// Typed errors are returned unchanged.
+func FromChain(err error) error {
+	if err == nil {
+		return nil
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return err
+	}
+
+	message := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(message, "insufficient funds"):
+		return Wrap(KindInsufficientFunds, "the server wallet cannot pay for the transaction", err)
+	case strings.Contains(message, "execution reverted") || strings.Contains(message, "revert"):
+		if reason := revertReason(err); reason != "" {
+			return Wrap(KindReverted, "transaction reverted: "+reason, err)
+		}
+		return Wrap(KindReverted, "transaction reverted", err)
+	case isUnreachable(err, message):
+		return Wrap(KindChainUnavailable, "blockchain node is unavailable", err)
+	}
+	return Wrap(KindInternal, "blockchain call failed", err)
+}
+
+// revertReason decodes the Error(string) reason of a reverted call
+func revertReason(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return ""
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return ""
+	}
+	raw, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil {
+		return ""
+	}
+	reason, unpackErr := abi.UnpackRevert(raw)
+	if unpackErr != nil {
+		return ""
+	}
+	return reason
+}
+
+// isUnreachable reports whether an error means the node could not be reached
+func isUnreachable(err error, message string) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	for _, hint := range []string{"connection refused", "no such host", "dial tcp", "i/o timeout", "too many requests", "429", "502 bad gateway", "503 service unavailable"} {
+		if strings.Contains(message, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// Problem builds the problem details of an error response
+func Problem(r *http.Request, status int, code, title, detail string) models.ErrorResponse {
+	problem := models.ErrorResponse{
+		Type:    "urn:wikichain:problem:" + code,
+		Title:   title,
+		Status:  status,
+		Detail:  detail,
+		Code:    code,
+		Success: false,
+		Error:   title,
+		Message: detail,
+	}
+	if r != nil {
+		problem.Instance = r.URL.Path
+	}
+	return problem
+}
+
+// Write sends problem details. An empty code is derived from the status.
+func Write(w http.ResponseWriter, r *http.Request, status int, code, title, detail string) {
+	if code == "" {
+		code = CodeForStatus(status)
+	}
+	writeProblem(w, Problem(r, status, code, title, detail))
+}
+
+// WriteError sends the problem details of an error, with its status, code
+// and invalid fields
+func WriteError(w http.ResponseWriter, r *http.Request, title string, err error) {
+	problem := Problem(r, StatusOf(err), CodeOf(err), title, err.Error())
+	problem.Errors = FieldsOf(err)
+	writeProblem(w, problem)
+}
+
+func writeProblem(w http.ResponseWriter, problem models.ErrorResponse) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
@@ -0,0 +1,101 @@
+package apperr
+
+import (
+	"blockchain-demo/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError mimics the JSON-RPC error of a reverted eth_call
+type revertError struct{ data string }
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+func TestStatusAndCode(t *testing.T) {
+	err := fmt.Errorf("loading contest: %w", NotFound("contest %s not found", "c1"))
+	assert.Equal(t, http.StatusNotFound, StatusOf(err))
+	assert.Equal(t, models.ErrorCodeNotFound, CodeOf(err))
+	assert.True(t, Is(err, KindNotFound))
+
+	// A specific code overrides the default code of the kind
+	taken := New(KindConflict, models.ErrorCodeIDTaken, "id is already taken")
+	assert.Equal(t, http.StatusConflict, StatusOf(taken))
+	assert.Equal(t, models.ErrorCodeIDTaken, CodeOf(taken))
+	assert.ErrorIs(t, fmt.Errorf("contest x: %w", taken), taken)
+
+	// Untyped errors are internal
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(errors.New("boom")))
+	assert.Equal(t, models.ErrorCodeInternal, CodeOf(errors.New("boom")))
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, models.ErrorCodeBadRequest, CodeForStatus(http.StatusBadRequest))
+	assert.Equal(t, models.ErrorCodeUnauthorized, CodeForStatus(http.StatusUnauthorized))
+	assert.Equal(t, models.ErrorCodeForbidden, CodeForStatus(http.StatusForbidden))
+	assert.Equal(t, models.ErrorCodeNotFound, CodeForStatus(http.StatusNotFound))
+	assert.Equal(t, models.ErrorCodeConflict, CodeForStatus(http.StatusConflict))
+	assert.Equal(t, models.ErrorCodeRateLimited, CodeForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, models.ErrorCodeServiceUnavailable, CodeForStatus(http.StatusServiceUnavailable))
+	assert.Equal(t, models.ErrorCodeInternal, CodeForStatus(http.StatusBadGateway))
+}
+
+func TestFromChain(t *testing.T) {
+	assert.Nil(t, FromChain(nil))
+
+	funds := FromChain(errors.New("insufficient funds for gas * price + value"))
+	assert.Equal(t, KindInsufficientFunds, KindOf(funds))
+
+	refused := FromChain(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED})
+	assert.Equal(t, KindChainUnavailable, KindOf(refused))
+	assert.Equal(t, http.StatusServiceUnavailable, StatusOf(refused))
+	assert.Equal(t, KindChainUnavailable, KindOf(FromChain(errors.New("429 Too Many Requests"))))
+
+	// The Error(string) reason of a revert is decoded
+	stringType, _ := abi.NewType("string", "", nil)
+	packed, err := abi.Arguments{{Type: stringType}}.Pack("contest is closed")
+	require.NoError(t, err)
+	data := hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...))
+	reverted := FromChain(revertError{data: data})
+	assert.Equal(t, KindReverted, KindOf(reverted))
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusOf(reverted))
+	assert.Contains(t, reverted.Error(), "contest is closed")
+
+	// Typed errors are kept, anything else is internal
+	notFound := NotFound("missing")
+	assert.Same(t, notFound, FromChain(notFound))
+	assert.Equal(t, KindInternal, KindOf(FromChain(errors.New("abi: cannot unmarshal"))))
+}
+
+func TestWrite(t *testing.T) {
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/contests/c1", nil)
+	WriteError(rr, r, "Failed to get contest", NotFound("contest c1 not found"))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, ProblemContentType, rr.Header().Get("Content-Type"))
+	var problem models.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+	assert.Equal(t, "urn:wikichain:problem:not_found", problem.Type)
+	assert.Equal(t, "Failed to get contest", problem.Title)
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "contest c1 not found", problem.Detail)
+	assert.Equal(t, "/api/v1/contests/c1", problem.Instance)
+	assert.Equal(t, models.ErrorCodeNotFound, problem.Code)
+
+	// Legacy fields stay for existing clients
+	assert.False(t, problem.Success)
+	assert.Equal(t, problem.Title, problem.Error)
+	assert.Equal(t, problem.Detail, problem.Message)
+}
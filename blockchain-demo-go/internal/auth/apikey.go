@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"blockchain-demo/internal/models"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiKeyPrefix starts every API key so leaked keys are easy to recognize
+const apiKeyPrefix = "wk_"
+
+// Errors returned by the key store
+var (
+	ErrKeyNotFound = fmt.Errorf("api key not found")
+	ErrInvalidKey  = fmt.Errorf("invalid, expired or revoked api key")
+
+	errInvalidSession = fmt.Errorf("invalid or expired session token")
+)
+
+// KeyStore keeps hashed API keys, optionally persisted to a JSON file
+type KeyStore struct {
+	mu   sync.RWMutex
+	path string
+	keys map[string]*models.APIKey // id -> key
+}
+
+// NewKeyStore creates a key store. When path is not empty keys are loaded from
+// and saved to that file.
+func NewKeyStore(path string) (*KeyStore, error) {
+	store := &KeyStore{
+		path: path,
+		keys: make(map[string]*models.APIKey),
+	}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys: %v", err)
+	}
+
+	var keys []*models.APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys: %v", err)
+	}
+	for _, key := range keys {
+		store.keys[key.ID] = key
+	}
+	return store, nil
+}
+
+// Create generates a new API key and returns its plaintext value with its record
+func (s *KeyStore) Create(name string, scopes []string, ttl time.Duration) (string, *models.APIKey, error) {
+	id := randomHex(4)
+	plaintext := apiKeyPrefix + id + "_" + randomHex(24)
+
+	key := &models.APIKey{
+		ID:        id,
+		Name:      name,
+		Scopes:    scopes,
+		Hash:      hashKey(plaintext),
+		CreatedAt: time.Now(),
+	}
+	if ttl > 0 {
+		expiresAt := key.CreatedAt.Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[id] = key
+	if err := s.save(); err != nil {
+		delete(s.keys, id)
+		return "", nil, err
+	}
+	return plaintext, redact(key), nil
+}
+
+// Import registers a key whose plaintext is provided by configuration, e.g. the
+// bootstrap admin key. Importing the same id again replaces the key.
+func (s *KeyStore) Import(id, name, plaintext string, scopes []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[id] = &models.APIKey{
+		ID:        id,
+		Name:      name,
+		Scopes:    scopes,
+		Hash:      hashKey(plaintext),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Authenticate returns the key matching a plaintext key if it is usable
+func (s *KeyStore) Authenticate(plaintext string) (*models.APIKey, error) {
+	hash := hashKey(plaintext)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.lookup(plaintext)
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, ErrInvalidKey
+	}
+	key.LastUsedAt = &now
+	return redact(key), nil
+}
+
+// lookup finds the candidate key of a plaintext key by its embedded id
+func (s *KeyStore) lookup(plaintext string) *models.APIKey {
+	if rest, ok := strings.CutPrefix(plaintext, apiKeyPrefix); ok {
+		if id, _, found := strings.Cut(rest, "_"); found {
+			return s.keys[id]
+		}
+	}
+	// Imported keys have arbitrary formats
+	hash := hashKey(plaintext)
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash)) == 1 {
+			return key
+		}
+	}
+	return nil
+}
+
+// Revoke revokes a key by id
+func (s *KeyStore) Revoke(id string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, exists := s.keys[id]
+	if !exists {
+		return nil, ErrKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := s.save(); err != nil {
+			key.RevokedAt = nil
+			return nil, err
+		}
+	}
+	return redact(key), nil
+}
+
+// List returns all keys without their hashes, oldest first
+func (s *KeyStore) List() []*models.APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, redact(key))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys
+}
+
+// save writes the keys to the backing file atomically. Callers hold the lock.
+func (s *KeyStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	keys := make([]*models.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".api-keys-*")
+	if err != nil {
+		return fmt.Errorf("failed to save api keys: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save api keys: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save api keys: %v", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// hashKey returns the hex SHA-256 of a plaintext key
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// redact returns a copy of a key without its hash
+func redact(key *models.APIKey) *models.APIKey {
+	copied := *key
+	copied.Hash = ""
+	return &copied
+}
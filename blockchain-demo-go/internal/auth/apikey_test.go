@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStoreLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := NewKeyStore(path)
+	require.NoError(t, err)
+
+	plaintext, key, err := store.Create("ci", []string{ScopeContentWrite}, 0)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plaintext, apiKeyPrefix+key.ID+"_"))
+	assert.Empty(t, key.Hash)
+
+	// Only the hash is written to disk
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), plaintext)
+	assert.Contains(t, string(data), hashKey(plaintext))
+
+	got, err := store.Authenticate(plaintext)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, got.ID)
+	assert.NotNil(t, got.LastUsedAt)
+
+	_, err = store.Authenticate(plaintext + "x")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	// Keys survive a restart and revocation is persisted
+	reloaded, err := NewKeyStore(path)
+	require.NoError(t, err)
+	_, err = reloaded.Revoke(key.ID)
+	require.NoError(t, err)
+	_, err = reloaded.Authenticate(plaintext)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	reloaded, err = NewKeyStore(path)
+	require.NoError(t, err)
+	_, err = reloaded.Authenticate(plaintext)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = reloaded.Revoke("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestKeyStoreExpiryAndImport(t *testing.T) {
+	store, err := NewKeyStore("")
+	require.NoError(t, err)
+
+	plaintext, _, err := store.Create("short", []string{ScopeContentWrite}, time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = store.Authenticate(plaintext)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	store.Import("admin", "bootstrap", "super-secret", []string{ScopeAdmin})
+	key, err := store.Authenticate("super-secret")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", key.ID)
+}
+
+func TestMiddlewareScopes(t *testing.T) {
+	store, err := NewKeyStore("")
+	require.NoError(t, err)
+	writer, _, err := store.Create("writer", []string{ScopeContentWrite}, 0)
+	require.NoError(t, err)
+
+	a := NewAuthenticator(NewSessionStore(time.Hour), WithAPIKeys(store))
+	handler := a.Middleware(RequireScope(ScopeContentWrite)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	admin := a.Middleware(RequireScope(ScopeAdmin)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	call := func(h http.Handler, header, value string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call(handler, "", ""))
+	assert.Equal(t, http.StatusUnauthorized, call(handler, APIKeyHeader, "wk_bad_key"))
+	assert.Equal(t, http.StatusNoContent, call(handler, APIKeyHeader, writer))
+	assert.Equal(t, http.StatusNoContent, call(handler, "Authorization", "ApiKey "+writer))
+	assert.Equal(t, http.StatusForbidden, call(admin, APIKeyHeader, writer))
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// jwtLeeway tolerates small clock differences with the token issuer
+const jwtLeeway = 30 * time.Second
+
+// JWTClaims are the registered and custom claims read from a bearer token
+type JWTClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+	Scope     string   `json:"scope"`   // Space separated (RFC 8693)
+	Scopes    []string `json:"scopes"`  // Alternative array form
+	Address   string   `json:"address"` // Wallet address of the subject, if any
+}
+
+// AllScopes merges the space separated and array forms of the scope claims
+func (c *JWTClaims) AllScopes() []string {
+	return append(strings.Fields(c.Scope), c.Scopes...)
+}
+
+// audience accepts both the string and the array form of the "aud" claim
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("invalid audience claim")
+	}
+	*a = multiple
+	return nil
+}
+
+// JWTVerifier verifies HS256 or RS256 signed JSON Web Tokens
+type JWTVerifier struct {
+	hmacSecret []byte
+	rsaKey     *rsa.PublicKey
+	issuer     string
+	audience   string
+}
+
+// NewJWTVerifier creates a verifier. At least one of secret (HS256) or
+// publicKeyPEM (RS256) must be set; issuer and audience are checked when not empty.
+func NewJWTVerifier(secret string, publicKeyPEM []byte, issuer, aud string) (*JWTVerifier, error) {
+	v := &JWTVerifier{issuer: issuer, audience: aud}
+	if secret != "" {
+		v.hmacSecret = []byte(secret)
+	}
+	if len(publicKeyPEM) > 0 {
+		key, err := parseRSAPublicKey(publicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKey = key
+	}
+	if v.hmacSecret == nil && v.rsaKey == nil {
+		return nil, fmt.Errorf("jwt verifier needs an HS256 secret or an RS256 public key")
+	}
+	return v, nil
+}
+
+// parseRSAPublicKey parses a PKIX or PKCS#1 PEM encoded RSA public key
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM public key")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an RSA key")
+	}
+	return key, nil
+}
+
+// LooksLikeJWT reports whether a bearer token has the three part JWT shape
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Verify checks the signature and the claims of a token
+func (v *JWTVerifier) Verify(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %v", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature encoding")
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+
+	// The algorithm must match a configured key, "none" is never accepted
+	switch {
+	case header.Alg == "HS256" && v.hmacSecret != nil:
+		mac := hmac.New(sha256.New, v.hmacSecret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, fmt.Errorf("invalid token signature")
+		}
+	case header.Alg == "RS256" && v.rsaKey != nil:
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(v.rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, fmt.Errorf("invalid token signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported token algorithm: %q", header.Alg)
+	}
+
+	var claims JWTClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %v", err)
+	}
+	if err := v.validate(&claims, time.Now()); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// validate checks the time based claims, issuer and audience
+func (v *JWTVerifier) validate(claims *JWTClaims, now time.Time) error {
+	if claims.Subject == "" {
+		return fmt.Errorf("token has no subject")
+	}
+	if claims.ExpiresAt == 0 {
+		return fmt.Errorf("token has no expiration")
+	}
+	if now.Add(-jwtLeeway).Unix() >= claims.ExpiresAt {
+		return fmt.Errorf("token has expired")
+	}
+	if claims.NotBefore != 0 && now.Add(jwtLeeway).Unix() < claims.NotBefore {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("token issuer %q is not accepted", claims.Issuer)
+	}
+	if v.audience != "" {
+		for _, aud := range claims.Audience {
+			if aud == v.audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("token is not intended for audience %q", v.audience)
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
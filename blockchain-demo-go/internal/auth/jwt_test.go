@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func hs256Token(t *testing.T, secret string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example",
+		"aud":   []string{"wiki-chain"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "content:write moderation",
+	}
+}
+
+func TestJWTVerifierHS256(t *testing.T) {
+	v, err := NewJWTVerifier("secret", nil, "https://issuer.example", "wiki-chain")
+	require.NoError(t, err)
+
+	claims, err := v.Verify(hs256Token(t, "secret", validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, []string{ScopeContentWrite, ScopeModeration}, claims.AllScopes())
+
+	_, err = v.Verify(hs256Token(t, "other", validClaims()))
+	assert.Error(t, err)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = v.Verify(hs256Token(t, "secret", expired))
+	assert.ErrorContains(t, err, "expired")
+
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "https://evil.example"
+	_, err = v.Verify(hs256Token(t, "secret", wrongIssuer))
+	assert.ErrorContains(t, err, "issuer")
+
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "another-app"
+	_, err = v.Verify(hs256Token(t, "secret", wrongAudience))
+	assert.ErrorContains(t, err, "audience")
+
+	// Unsigned tokens are rejected
+	none := encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+	_, err = v.Verify(none)
+	assert.ErrorContains(t, err, "unsupported")
+}
+
+func TestJWTVerifierRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	v, err := NewJWTVerifier("", publicPEM, "", "")
+	require.NoError(t, err)
+
+	signed := encodeSegment(t, map[string]string{"alg": "RS256"}) + "." + encodeSegment(t, validClaims())
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	claims, err := v.Verify(signed + "." + base64.RawURLEncoding.EncodeToString(sig))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+
+	// An HS256 token must not be accepted by an RS256-only verifier
+	_, err = v.Verify(hs256Token(t, string(publicPEM), validClaims()))
+	assert.Error(t, err)
+}
+
+func TestMiddlewareJWT(t *testing.T) {
+	v, err := NewJWTVerifier("secret", nil, "", "")
+	require.NoError(t, err)
+	a := NewAuthenticator(NewSessionStore(time.Hour), WithJWT(v))
+
+	var identity *Identity
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = IdentityFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+hs256Token(t, "secret", validClaims()))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, identity)
+	assert.Equal(t, MethodJWT, identity.Method)
+	assert.True(t, identity.HasScope(ScopeModeration))
+	assert.False(t, identity.HasScope(ScopeAdmin))
+}
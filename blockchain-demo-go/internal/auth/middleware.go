@@ -0,0 +1,256 @@
+// Package auth authenticates API callers and attaches their identity to the
+// request context.
+package auth
+
+import (
+	"blockchain-demo/internal/apperr"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Authentication methods
+const (
+	MethodSIWE   = "siwe"
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// Scopes granted to callers. ScopeAdmin implies every other scope.
+const (
+	ScopeAdmin         = "admin"
+	ScopeContentWrite  = "content:write"
+	ScopeContestsWrite = "contests:write"
+	ScopeModeration    = "moderation"
+	ScopeVote          = "votes:write"
+)
+
+// WalletScopes are granted to allowlisted wallets signed in with SIWE. Other
+// wallets only get the scopes of their roles, so signing in alone does not
+// let a stranger spend the server wallet's gas.
+var WalletScopes = []string{ScopeContentWrite, ScopeContestsWrite, ScopeVote}
+
+// APIKeyHeader carries API keys. "Authorization: ApiKey <key>" is accepted too.
+const APIKeyHeader = "X-API-Key"
+
+// Identity is an authenticated caller
+type Identity struct {
+	Subject string // Stable identifier of the caller
+	Address string // Wallet address, empty if the caller has none
+	Method  string
+	Scopes  []string
+	Roles   []string
+}
+
+// HasScope reports whether the identity was granted a scope
+func (i *Identity) HasScope(scope string) bool {
+	if i == nil {
+		return false
+	}
+	for _, s := range i.Scopes {
+		if s == scope || s == ScopeAdmin || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the identity holds a role. Admins hold every role.
+func (i *Identity) HasRole(role string) bool {
+	if i == nil {
+		return false
+	}
+	if i.HasScope(ScopeAdmin) {
+		return true
+	}
+	for _, r := range i.Roles {
+		if r == role || r == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Account returns the account roles are granted to: the wallet address when
+// the caller has one, the subject otherwise
+func (i *Identity) Account() string {
+	if i.Address != "" {
+		return i.Address
+	}
+	return i.Subject
+}
+
+type contextKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the identity
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// IdentityFromContext returns the identity of the caller, nil if anonymous
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(contextKey{}).(*Identity)
+	return identity
+}
+
+// Authenticator resolves the identity of incoming requests
+type Authenticator struct {
+	sessions *SessionStore
+	keys     *KeyStore
+	jwt      *JWTVerifier
+	roles    *RoleStore
+	wallets  map[string]bool // Allowlisted wallets granted WalletScopes
+}
+
+// Option configures an Authenticator
+type Option func(*Authenticator)
+
+// WithAPIKeys accepts API keys from the given store
+func WithAPIKeys(keys *KeyStore) Option {
+	return func(a *Authenticator) {
+		a.keys = keys
+	}
+}
+
+// WithJWT accepts bearer JSON Web Tokens checked by the given verifier
+func WithJWT(verifier *JWTVerifier) Option {
+	return func(a *Authenticator) {
+		a.jwt = verifier
+	}
+}
+
+// WithRoles attaches the roles of each caller and the scopes they imply
+func WithRoles(roles *RoleStore) Option {
+	return func(a *Authenticator) {
+		a.roles = roles
+	}
+}
+
+// WithWallets grants WalletScopes to SIWE sessions of the given wallets
+func WithWallets(addresses []string) Option {
+	return func(a *Authenticator) {
+		a.wallets = make(map[string]bool, len(addresses))
+		for _, address := range addresses {
+			a.wallets[NormalizeAccount(address)] = true
+		}
+	}
+}
+
+// NewAuthenticator creates an authenticator backed by SIWE sessions
+func NewAuthenticator(sessions *SessionStore, opts ...Option) *Authenticator {
+	a := &Authenticator{sessions: sessions}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Middleware attaches the caller identity to the request context. Requests
+// without credentials pass through anonymously; invalid credentials get 401.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := a.authenticate(r)
+		if err != nil {
+			log.Printf("🔒 Authentication failed for %s %s: %v", r.Method, r.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, "Invalid or expired credentials", err.Error())
+			return
+		}
+		if identity == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if a.roles != nil {
+			identity.Roles = a.roles.Roles(identity.Account())
+			for _, role := range identity.Roles {
+				identity.Scopes = append(identity.Scopes, roleScopes[role]...)
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), identity)))
+	})
+}
+
+// authenticate resolves the identity of a request, nil if it has no credentials
+func (a *Authenticator) authenticate(r *http.Request) (*Identity, error) {
+	if key := APIKey(r); key != "" {
+		if a.keys == nil {
+			return nil, ErrInvalidKey
+		}
+		apiKey, err := a.keys.Authenticate(key)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Subject: "apikey:" + apiKey.ID, Method: MethodAPIKey, Scopes: append([]string(nil), apiKey.Scopes...)}, nil
+	}
+
+	token := BearerToken(r)
+	if token == "" {
+		return nil, nil
+	}
+
+	if a.jwt != nil && LooksLikeJWT(token) {
+		claims, err := a.jwt.Verify(token)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Subject: claims.Subject, Address: claims.Address, Method: MethodJWT, Scopes: claims.AllScopes()}, nil
+	}
+
+	session, ok := a.sessions.Lookup(token)
+	if !ok {
+		return nil, errInvalidSession
+	}
+	identity := &Identity{Subject: session.Address, Address: session.Address, Method: MethodSIWE}
+	if a.wallets[NormalizeAccount(session.Address)] {
+		identity.Scopes = append([]string(nil), WalletScopes...)
+	}
+	return identity, nil
+}
+
+// RequireScope rejects requests whose caller lacks a scope: anonymous callers
+// get 401, authenticated callers without the scope get 403.
+func RequireScope(scope string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			identity := IdentityFromContext(r.Context())
+			if identity == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				writeError(w, http.StatusUnauthorized, "Authentication required", "")
+				return
+			}
+			if !identity.HasScope(scope) {
+				writeError(w, http.StatusForbidden, "Insufficient scope", "requires scope "+scope)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// BearerToken extracts the token of an "Authorization: Bearer" header
+func BearerToken(r *http.Request) string {
+	return authorization(r, "Bearer")
+}
+
+// APIKey extracts an API key from the X-API-Key or "Authorization: ApiKey" header
+func APIKey(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		return key
+	}
+	return authorization(r, "ApiKey")
+}
+
+// authorization returns the credentials of an Authorization header with the given scheme
+func authorization(r *http.Request, scheme string) string {
+	got, credentials, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(got, scheme) {
+		return ""
+	}
+	return strings.TrimSpace(credentials)
+}
+
+// writeError writes problem details in the API's error format
+func writeError(w http.ResponseWriter, status int, message, details string) {
+	apperr.Write(w, nil, status, "", message, details)
+}
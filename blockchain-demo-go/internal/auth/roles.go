@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"blockchain-demo/internal/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Roles that can be granted to wallets or users
+const (
+	RoleAdmin      = "admin"
+	RoleOrganizer  = "organizer"
+	RoleModerator  = "moderator"
+	RoleContestant = "contestant"
+	RoleSponsor    = "sponsor"
+)
+
+// roleScopes are the scopes implied by each role
+var roleScopes = map[string][]string{
+	RoleAdmin:      {ScopeAdmin},
+	RoleOrganizer:  {ScopeContestsWrite},
+	RoleModerator:  {ScopeModeration},
+	RoleContestant: {ScopeContestsWrite},
+	RoleSponsor:    {ScopeContestsWrite},
+}
+
+// IsValidRole reports whether a role is known
+func IsValidRole(role string) bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+// NormalizeAccount returns the canonical form of an account: checksummed for
+// wallet addresses, unchanged for other subjects such as "apikey:<id>"
+func NormalizeAccount(account string) string {
+	account = strings.TrimSpace(account)
+	if common.IsHexAddress(account) {
+		return common.HexToAddress(account).Hex()
+	}
+	return account
+}
+
+// RoleStore keeps the roles granted to each account
+type RoleStore struct {
+	mu    sync.RWMutex
+	roles map[string]map[string]*models.RoleAssignment // account -> role -> assignment
+}
+
+// NewRoleStore creates an empty role store
+func NewRoleStore() *RoleStore {
+	return &RoleStore{
+		roles: make(map[string]map[string]*models.RoleAssignment),
+	}
+}
+
+// Grant records a role for an account and returns the assignment
+func (s *RoleStore) Grant(account, role, grantedBy, txHash string) *models.RoleAssignment {
+	account = NormalizeAccount(account)
+	assignment := &models.RoleAssignment{
+		Account:   account,
+		Role:      role,
+		GrantedBy: grantedBy,
+		GrantedAt: time.Now(),
+		TxHash:    txHash,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.roles[account] == nil {
+		s.roles[account] = make(map[string]*models.RoleAssignment)
+	}
+	s.roles[account][role] = assignment
+	return assignment
+}
+
+// Revoke removes a role from an account, reporting whether it was granted
+func (s *RoleStore) Revoke(account, role string) bool {
+	account = NormalizeAccount(account)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.roles[account][role]; !ok {
+		return false
+	}
+	delete(s.roles[account], role)
+	if len(s.roles[account]) == 0 {
+		delete(s.roles, account)
+	}
+	return true
+}
+
+// Has reports whether an account holds a role
+func (s *RoleStore) Has(account, role string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.roles[NormalizeAccount(account)][role]
+	return ok
+}
+
+// Roles returns the sorted roles of an account
+func (s *RoleStore) Roles(account string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	roles := make([]string, 0, len(s.roles[NormalizeAccount(account)]))
+	for role := range s.roles[NormalizeAccount(account)] {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Assignments returns the assignments of an account, or of every account when
+// account is empty, sorted by account then role
+func (s *RoleStore) Assignments(account string) []*models.RoleAssignment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	assignments := make([]*models.RoleAssignment, 0)
+	for acc, roles := range s.roles {
+		if account != "" && acc != NormalizeAccount(account) {
+			continue
+		}
+		for _, assignment := range roles {
+			assignments = append(assignments, assignment)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		if assignments[i].Account != assignments[j].Account {
+			return assignments[i].Account < assignments[j].Account
+		}
+		return assignments[i].Role < assignments[j].Role
+	})
+	return assignments
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wallet = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+
+func TestRoleStore(t *testing.T) {
+	store := NewRoleStore()
+
+	store.Grant(strings.ToLower(wallet), RoleOrganizer, "admin", "0xabc")
+	store.Grant(wallet, RoleModerator, "admin", "")
+	store.Grant("apikey:1234", RoleSponsor, "admin", "")
+
+	assert.True(t, store.Has(wallet, RoleOrganizer), "addresses are compared case-insensitively")
+	assert.Equal(t, []string{RoleModerator, RoleOrganizer}, store.Roles(strings.ToUpper("0x"+wallet[2:])))
+	assert.Len(t, store.Assignments(""), 3)
+	assert.Len(t, store.Assignments(wallet), 2)
+
+	assert.True(t, store.Revoke(wallet, RoleOrganizer))
+	assert.False(t, store.Revoke(wallet, RoleOrganizer))
+	assert.Equal(t, []string{RoleModerator}, store.Roles(wallet))
+
+	assert.True(t, IsValidRole(RoleContestant))
+	assert.False(t, IsValidRole("owner"))
+}
+
+func TestIdentityHasRole(t *testing.T) {
+	organizer := &Identity{Roles: []string{RoleOrganizer}}
+	assert.True(t, organizer.HasRole(RoleOrganizer))
+	assert.False(t, organizer.HasRole(RoleModerator))
+
+	admin := &Identity{Roles: []string{RoleAdmin}}
+	assert.True(t, admin.HasRole(RoleModerator))
+
+	adminKey := &Identity{Scopes: []string{ScopeAdmin}}
+	assert.True(t, adminKey.HasRole(RoleSponsor))
+
+	var anonymous *Identity
+	assert.False(t, anonymous.HasRole(RoleContestant))
+}
+
+func TestMiddlewareAttachesRoles(t *testing.T) {
+	sessions := NewSessionStore(time.Hour)
+	session := sessions.Open(wallet)
+
+	roles := NewRoleStore()
+	roles.Grant(wallet, RoleModerator, "admin", "")
+	a := NewAuthenticator(sessions, WithRoles(roles))
+
+	var identity *Identity
+	handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = IdentityFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+session.Token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, identity)
+	assert.Equal(t, []string{RoleModerator}, identity.Roles)
+	assert.True(t, identity.HasScope(ScopeModeration), "roles grant their scopes")
+	assert.False(t, identity.HasRole(RoleOrganizer))
+}
+
+func TestWalletScopesNeedAllowlistOrRole(t *testing.T) {
+	const stranger = "0x8ba1f109551bD432803012645Ac136ddd64DBA72"
+	sessions := NewSessionStore(time.Hour)
+	roles := NewRoleStore()
+	roles.Grant(stranger, RoleOrganizer, "admin", "")
+	a := NewAuthenticator(sessions, WithRoles(roles), WithWallets([]string{strings.ToLower(wallet)}))
+
+	identityOf := func(address string) *Identity {
+		var identity *Identity
+		handler := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity = IdentityFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+sessions.Open(address).Token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		require.NotNil(t, identity)
+		return identity
+	}
+
+	// Allowlisted wallets get every wallet scope
+	allowed := identityOf(wallet)
+	for _, scope := range WalletScopes {
+		assert.True(t, allowed.HasScope(scope), scope)
+	}
+
+	// Other wallets only get the scopes of their roles
+	organizer := identityOf(stranger)
+	assert.True(t, organizer.HasScope(ScopeContestsWrite))
+	assert.False(t, organizer.HasScope(ScopeContentWrite))
+	assert.False(t, organizer.HasScope(ScopeVote))
+
+	newcomer := identityOf("0x0000000000000000000000000000000000000042")
+	assert.Empty(t, newcomer.Scopes, "signing in alone grants no write scope")
+}
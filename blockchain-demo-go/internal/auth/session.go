@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// nonceAlphabet is the alphanumeric alphabet required by EIP-4361 nonces
+const nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// NonceStore issues single-use nonces that expire after a TTL
+type NonceStore struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	nonces map[string]time.Time // nonce -> expiry
+}
+
+// NewNonceStore creates a nonce store
+func NewNonceStore(ttl time.Duration) *NonceStore {
+	return &NonceStore{
+		ttl:    ttl,
+		nonces: make(map[string]time.Time),
+	}
+}
+
+// Issue creates a new nonce and returns it with its expiry
+func (s *NonceStore) Issue() (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for nonce, expiry := range s.nonces {
+		if now.After(expiry) {
+			delete(s.nonces, nonce)
+		}
+	}
+
+	nonce := randomString(nonceAlphabet, 17)
+	expiry := now.Add(s.ttl)
+	s.nonces[nonce] = expiry
+	return nonce, expiry
+}
+
+// Consume removes a nonce, reporting whether it was valid
+func (s *NonceStore) Consume(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiry, exists := s.nonces[nonce]
+	if !exists {
+		return false
+	}
+	delete(s.nonces, nonce)
+	return time.Now().Before(expiry)
+}
+
+// Session is an authenticated wallet session
+type Session struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SessionStore keeps opaque session tokens in memory
+type SessionStore struct {
+	mu       sync.RWMutex
+	ttl      time.Duration
+	sessions map[string]*Session
+}
+
+// NewSessionStore creates a session store
+func NewSessionStore(ttl time.Duration) *SessionStore {
+	return &SessionStore{
+		ttl:      ttl,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Open creates a session for an address
+func (s *SessionStore) Open(address string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := &Session{
+		Token:     randomHex(32),
+		Address:   address,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	s.sessions[session.Token] = session
+	copied := *session
+	return &copied
+}
+
+// Lookup returns the session of a token if it exists and has not expired
+func (s *SessionStore) Lookup(token string) (*Session, bool) {
+	s.mu.RLock()
+	session, exists := s.sessions[token]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	if time.Now().After(session.ExpiresAt) {
+		s.Close(token)
+		return nil, false
+	}
+	copied := *session
+	return &copied, true
+}
+
+// Close ends a session
+func (s *SessionStore) Close(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+// randomHex returns n random bytes hex encoded
+func randomHex(n int) string {
+	bytes := make([]byte, n)
+	if _, err := rand.Read(bytes); err != nil {
+		panic("auth: crypto/rand unavailable: " + err.Error())
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// randomString returns a random string of the given length from the alphabet
+func randomString(alphabet string, length int) string {
+	out := make([]byte, length)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic("auth: crypto/rand unavailable: " + err.Error())
+		}
+		out[i] = alphabet[n.Int64()]
+	}
+	return string(out)
+}
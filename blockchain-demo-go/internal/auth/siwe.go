@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// siweHeaderSuffix ends the first line of an EIP-4361 message
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// maxClockSkew tolerates small clock differences between wallet and server
+const maxClockSkew = 5 * time.Minute
+
+// SIWEMessage is a parsed Sign-In with Ethereum (EIP-4361) message
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseSIWEMessage parses the plain text of an EIP-4361 message
+func ParseSIWEMessage(message string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+	if len(lines) < 2 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("invalid SIWE message header")
+	}
+
+	msg := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siweHeaderSuffix)}
+	if i := strings.Index(msg.Domain, "://"); i >= 0 {
+		msg.Domain = msg.Domain[i+3:]
+	}
+
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("invalid SIWE address")
+	}
+	msg.Address = common.HexToAddress(lines[1])
+
+	// The statement is optional and surrounded by blank lines
+	i := 2
+	for i < len(lines) && lines[i] == "" {
+		i++
+	}
+	if i < len(lines) && !strings.HasPrefix(lines[i], "URI: ") {
+		msg.Statement = lines[i]
+		i++
+		for i < len(lines) && lines[i] == "" {
+			i++
+		}
+	}
+
+	var err error
+	inResources := false
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		if inResources && strings.HasPrefix(line, "- ") {
+			msg.Resources = append(msg.Resources, strings.TrimPrefix(line, "- "))
+			continue
+		}
+		inResources = false
+
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			if line == "Resources:" {
+				inResources = true
+				continue
+			}
+			return nil, fmt.Errorf("invalid SIWE field: %q", line)
+		}
+
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			if msg.ChainID, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid SIWE chain ID: %v", err)
+			}
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			if msg.IssuedAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid SIWE issued-at: %v", err)
+			}
+		case "Expiration Time":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SIWE expiration time: %v", err)
+			}
+			msg.ExpirationTime = &t
+		case "Not Before":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SIWE not-before: %v", err)
+			}
+			msg.NotBefore = &t
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("unknown SIWE field: %q", key)
+		}
+	}
+
+	switch {
+	case msg.URI == "":
+		return nil, fmt.Errorf("SIWE message is missing URI")
+	case msg.Version != "1":
+		return nil, fmt.Errorf("unsupported SIWE version: %q", msg.Version)
+	case len(msg.Nonce) < 8:
+		return nil, fmt.Errorf("SIWE nonce must be at least 8 characters")
+	case msg.IssuedAt.IsZero():
+		return nil, fmt.Errorf("SIWE message is missing issued-at")
+	}
+	return msg, nil
+}
+
+// RecoverAddress recovers the address that signed a message with personal_sign
+func RecoverAddress(message []byte, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature encoding: %v", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+
+	// Wallets return V as 27/28, go-ethereum expects 0/1
+	sig = append([]byte(nil), sig...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %v", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SIWE verifies Sign-In with Ethereum messages and opens sessions
+type SIWE struct {
+	domain   string
+	chainID  int64
+	nonces   *NonceStore
+	sessions *SessionStore
+}
+
+// NewSIWE creates a SIWE verifier accepting messages for the given domain and chain
+func NewSIWE(domain string, chainID int64, nonces *NonceStore, sessions *SessionStore) *SIWE {
+	return &SIWE{
+		domain:   domain,
+		chainID:  chainID,
+		nonces:   nonces,
+		sessions: sessions,
+	}
+}
+
+// Nonce issues a single-use nonce to embed in the next SIWE message
+func (s *SIWE) Nonce() (string, time.Time) {
+	return s.nonces.Issue()
+}
+
+// Verify checks a signed SIWE message and opens a session for its signer
+func (s *SIWE) Verify(message, signature string) (*Session, error) {
+	msg, err := ParseSIWEMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.domain != "" && !strings.EqualFold(msg.Domain, s.domain) {
+		return nil, fmt.Errorf("SIWE domain %q does not match %q", msg.Domain, s.domain)
+	}
+	if s.chainID != 0 && msg.ChainID != s.chainID {
+		return nil, fmt.Errorf("SIWE chain ID %d does not match %d", msg.ChainID, s.chainID)
+	}
+
+	now := time.Now()
+	if msg.IssuedAt.After(now.Add(maxClockSkew)) {
+		return nil, fmt.Errorf("SIWE message is issued in the future")
+	}
+	if msg.ExpirationTime != nil && now.After(*msg.ExpirationTime) {
+		return nil, fmt.Errorf("SIWE message has expired")
+	}
+	if msg.NotBefore != nil && now.Add(maxClockSkew).Before(*msg.NotBefore) {
+		return nil, fmt.Errorf("SIWE message is not valid yet")
+	}
+
+	signer, err := RecoverAddress([]byte(message), signature)
+	if err != nil {
+		return nil, err
+	}
+	if signer != msg.Address {
+		return nil, fmt.Errorf("signature does not match SIWE address")
+	}
+
+	// Consume the nonce last so a bad signature cannot burn someone else's nonce
+	if !s.nonces.Consume(msg.Nonce) {
+		return nil, fmt.Errorf("SIWE nonce is unknown, expired or already used")
+	}
+
+	return s.sessions.Open(signer.Hex()), nil
+}
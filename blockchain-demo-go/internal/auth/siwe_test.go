@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func siweMessage(address, nonce string, issuedAt time.Time) string {
+	return fmt.Sprintf(`localhost:8081 wants you to sign in with your Ethereum account:
+%s
+
+Sign in to Wiki Chain
+
+URI: http://localhost:8081
+Version: 1
+Chain ID: 1337
+Nonce: %s
+Issued At: %s
+Resources:
+- https://example.com/terms`, address, nonce, issuedAt.UTC().Format(time.RFC3339))
+}
+
+func sign(t *testing.T, message string) (string, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27 // as returned by wallets
+	return crypto.PubkeyToAddress(key.PublicKey).Hex(), hexutil.Encode(sig)
+}
+
+func TestParseSIWEMessage(t *testing.T) {
+	msg, err := ParseSIWEMessage(siweMessage("0x742d35cc6641c7b2b85ce462af7c9bb7a5db8b7a", "abcdefgh12", time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8081", msg.Domain)
+	assert.Equal(t, "Sign in to Wiki Chain", msg.Statement)
+	assert.Equal(t, int64(1337), msg.ChainID)
+	assert.Equal(t, []string{"https://example.com/terms"}, msg.Resources)
+
+	_, err = ParseSIWEMessage("hello")
+	assert.Error(t, err)
+}
+
+func TestSIWEVerify(t *testing.T) {
+	sessions := NewSessionStore(time.Hour)
+	siwe := NewSIWE("localhost:8081", 1337, NewNonceStore(time.Minute), sessions)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	nonce, _ := siwe.Nonce()
+	message := siweMessage(address, nonce, time.Now())
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+
+	session, err := siwe.Verify(message, hexutil.Encode(sig))
+	require.NoError(t, err)
+	assert.Equal(t, address, session.Address)
+
+	// Nonces are single use
+	_, err = siwe.Verify(message, hexutil.Encode(sig))
+	assert.Error(t, err)
+
+	// Signature of another wallet is rejected
+	nonce, _ = siwe.Nonce()
+	message = siweMessage(address, nonce, time.Now())
+	_, otherSig := sign(t, message)
+	_, err = siwe.Verify(message, otherSig)
+	assert.Error(t, err)
+
+	// The nonce was not burnt by the bad signature
+	sig, _ = crypto.Sign(accounts.TextHash([]byte(message)), key)
+	_, err = siwe.Verify(message, hexutil.Encode(sig))
+	assert.NoError(t, err)
+
+	// Unknown nonce
+	message = siweMessage(address, "unknownnonce", time.Now())
+	sig, _ = crypto.Sign(accounts.TextHash([]byte(message)), key)
+	_, err = siwe.Verify(message, hexutil.Encode(sig))
+	assert.Error(t, err)
+}
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	sessions := NewSessionStore(time.Hour)
+	session := sessions.Open("0x742d35Cc6641C7b2B85ce462Af7c9bB7A5db8b7A")
+	handler := NewAuthenticator(sessions).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if identity := IdentityFromContext(r.Context()); identity != nil {
+			w.Write([]byte(identity.Address))
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer "+session.Token)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, session.Address, rr.Body.String())
+
+	req.Header.Set("Authorization", "Bearer nope")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -4,6 +4,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,8 +21,85 @@ type Config struct {
 	NetworkURL      string
 	ChainID         string
 	ContractAddress string
-	PrivateKey      string
+	PrivateKey      string // Deprecated: prefer KeystorePath or ClefURL
 	ContractJSON    string
+
+	// Signer configuration
+	Signer               string // auto, keystore, clef, key or readonly
+	KeystorePath         string // Encrypted go-ethereum keystore file
+	KeystorePasswordFile string // Empty prompts on the terminal
+	ClefURL              string // Remote signer endpoint (Clef external API)
+	SignerAddress        string // Account to use on the remote signer
+	ReadOnly             bool   // Serve queries only; refuses to start with a key
+
+	// Moderation configuration
+	ModerationEnabled     bool
+	ModerationBannedWords []string
+	ModerationMaxLinks    int    // 0 disables the check
+	ModerationMaxLength   int    // 0 disables the check
+	ModerationDB          string // LevelDB directory, empty keeps the queue in memory
+
+	// Authentication configuration
+	SIWEDomain string // Domain expected in Sign-In with Ethereum messages
+	SessionTTL time.Duration
+
+	// AuthRequired rejects anonymous calls to endpoints that spend gas. On by
+	// default; turning it off lets anyone spend the server wallet's gas.
+	AuthRequired bool
+	AdminAPIKey  string // Bootstrap key with the admin scope, empty to disable
+	APIKeysFile  string // Hashed API keys are persisted here, empty keeps them in memory
+
+	JWTSecret        string // HS256 shared secret
+	JWTPublicKeyFile string // PEM encoded RS256 public key
+	JWTIssuer        string
+	JWTAudience      string
+
+	// Meta-transactions (EIP-712 requests relayed through an ERC-2771 forwarder)
+	ForwarderAddress string // Empty disables meta-transactions
+	MetaTxTTL        time.Duration
+	MetaTxGas        uint64 // Gas forwarded to the contract call
+
+	// Role-based access control
+	RBACEnabled  bool
+	AdminWallets []string // Wallets granted the admin role at startup
+
+	// Wallets whose SIWE sessions get the write scopes without holding a role
+	WalletAllowlist []string
+
+	// Rate limiting, per API key, wallet or IP (0 disables a limit)
+	RateLimitRead  int      // Read requests per minute
+	RateLimitWrite int      // Write requests per minute
+	GasBudgetDaily uint64   // Gas of the transactions each client may send per UTC day
+	TrustedProxies []string // IPs or CIDRs of reverse proxies whose X-Forwarded-For entries are trusted
+
+	// CORS policy
+	CORSAllowedOrigins   []string // Exact origins, "*" or wildcard subdomains like https://*.example.com
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSExposedHeaders   []string
+	CORSAllowCredentials bool // Requires explicit origins
+	CORSMaxAge           time.Duration
+
+	// Idempotency keys
+	IdempotencyDB  string // LevelDB directory, empty keeps records in memory
+	IdempotencyTTL time.Duration
+
+	// Wiki article revisions, from which links and taxonomy are rebuilt on boot
+	WikiDB string // LevelDB directory, empty keeps revisions in memory
+
+	// Judging rubrics, judges and scorecards until results are final
+	JudgingDB string // LevelDB directory, empty keeps the judging board in memory
+
+	// Request bodies larger than this are rejected with 413
+	MaxBodyBytes int64
+
+	// How often contests are moved by their dates, 0 disables the scheduler
+	ContestSchedulerInterval time.Duration
+
+	// Prize pools held by the PrizeEscrow contract
+	EscrowAddress    string // Empty disables prize pools
+	EscrowJSON       string // Compiled PrizeEscrow artifact the ABI is read from
+	EscrowStartBlock uint64 // Block the escrow was deployed at, where event scans start
 }
 
 // Load loads configuration from environment variables
@@ -45,6 +125,67 @@ func Load() (*Config, error) {
 		ContractAddress: getEnv("CONTRACT_ADDRESS", ""),
 		PrivateKey:      getEnv("PRIVATE_KEY", ""),
 		ContractJSON:    getEnv("CONTRACT_JSON", defaultContractPath),
+
+		Signer:               getEnv("SIGNER", "auto"),
+		KeystorePath:         getEnv("KEYSTORE_PATH", ""),
+		KeystorePasswordFile: getEnv("KEYSTORE_PASSWORD_FILE", ""),
+		ClefURL:              getEnv("CLEF_URL", ""),
+		SignerAddress:        getEnv("SIGNER_ADDRESS", ""),
+		ReadOnly:             getEnvBool("READ_ONLY", false),
+
+		ModerationEnabled:     getEnvBool("MODERATION_ENABLED", false),
+		ModerationBannedWords: getEnvList("MODERATION_BANNED_WORDS"),
+		ModerationMaxLinks:    getEnvInt("MODERATION_MAX_LINKS", 0),
+		ModerationMaxLength:   getEnvInt("MODERATION_MAX_LENGTH", 0),
+		ModerationDB:          getEnv("MODERATION_DB", "./data/moderation"),
+
+		SIWEDomain: getEnv("SIWE_DOMAIN", getEnv("HOST", "localhost")+":"+getEnv("PORT", "8081")),
+		SessionTTL: getEnvDuration("SESSION_TTL", 24*time.Hour),
+
+		AuthRequired: getEnvBool("AUTH_REQUIRED", true),
+		AdminAPIKey:  getEnv("ADMIN_API_KEY", ""),
+		APIKeysFile:  getEnv("API_KEYS_FILE", ""),
+
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
+
+		ForwarderAddress: getEnv("FORWARDER_ADDRESS", ""),
+		MetaTxTTL:        getEnvDuration("META_TX_TTL", 10*time.Minute),
+		MetaTxGas:        uint64(getEnvInt("META_TX_GAS", 1000000)),
+
+		RBACEnabled:  getEnvBool("RBAC_ENABLED", false),
+		AdminWallets: getEnvList("ADMIN_WALLETS"),
+
+		WalletAllowlist: getEnvList("WALLET_ALLOWLIST"),
+
+		RateLimitRead:  getEnvInt("RATE_LIMIT_READ", 300),
+		RateLimitWrite: getEnvInt("RATE_LIMIT_WRITE", 30),
+		GasBudgetDaily: uint64(getEnvInt("GAS_BUDGET_DAILY", 5000000)),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		CORSAllowedOrigins:   getEnvListDefault("CORS_ALLOWED_ORIGINS", "*"),
+		CORSAllowedMethods:   getEnvListDefault("CORS_ALLOWED_METHODS", "GET", "POST", "PUT", "DELETE", "OPTIONS"),
+		CORSAllowedHeaders:   getEnvListDefault("CORS_ALLOWED_HEADERS", "Content-Type", "Authorization", "X-API-Key", "Idempotency-Key"),
+		CORSExposedHeaders:   getEnvListDefault("CORS_EXPOSED_HEADERS", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "Idempotent-Replayed"),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAge:           getEnvDuration("CORS_MAX_AGE", time.Hour),
+
+		IdempotencyDB:  getEnv("IDEMPOTENCY_DB", ""),
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		WikiDB: getEnv("WIKI_DB", "./data/wiki"),
+
+		JudgingDB: getEnv("JUDGING_DB", "./data/judging"),
+
+		MaxBodyBytes: int64(getEnvInt("MAX_BODY_BYTES", 1<<20)),
+
+		ContestSchedulerInterval: getEnvDuration("CONTEST_SCHEDULER_INTERVAL", time.Minute),
+
+		EscrowAddress:    getEnv("ESCROW_ADDRESS", ""),
+		EscrowJSON:       getEnv("ESCROW_JSON", filepath.Join(filepath.Dir(defaultContractPath), "PrizeEscrow.json")),
+		EscrowStartBlock: uint64(getEnvInt("ESCROW_START_BLOCK", 0)),
 	}
 
 	return config, nil
@@ -57,3 +198,47 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvBool gets a boolean environment variable with default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt gets an integer environment variable with default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration gets a duration environment variable (e.g. "30m") with default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList gets a comma-separated environment variable as a list
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvListDefault gets a comma-separated environment variable as a list,
+// the default values when it is empty
+func getEnvListDefault(key string, defaultValues ...string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValues
+}
@@ -47,4 +47,16 @@ func TestGetEnv(t *testing.T) {
     // Test with non-existing env var
     value = getEnv("NON_EXISTING_VAR", "default")
     assert.Equal(t, "default", value)
-}
\ No newline at end of file
+}
+func TestLoadRequiresAuthByDefault(t *testing.T) {
+    t.Setenv("AUTH_REQUIRED", "")
+    cfg, err := Load()
+    assert.NoError(t, err)
+    assert.True(t, cfg.AuthRequired)
+
+    // Operators have to opt out explicitly
+    t.Setenv("AUTH_REQUIRED", "false")
+    cfg, err = Load()
+    assert.NoError(t, err)
+    assert.False(t, cfg.AuthRequired)
+}
@@ -0,0 +1,386 @@
+// Package diff computes line and word level differences between article bodies.
+package diff
+
+import (
+	"blockchain-demo/internal/models"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Operations of an edit script
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Edit is one step of an edit script turning a into b
+type Edit struct {
+	Op   string
+	Text string
+}
+
+// Compute returns the shortest edit script turning a into b, using the linear
+// space variant of Myers' algorithm: the middle snake of the optimal path
+// splits the problem in two, so memory stays O(len(a)+len(b)).
+func Compute(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+
+	// Each half of a split has at most half the edits, so the arrays sized
+	// for the whole problem are reused at every level of the recursion
+	size := (n+m+1)/2 + 1
+	c := &differ{
+		a:      a,
+		b:      b,
+		edits:  make([]Edit, 0, n+m),
+		fwd:    make([]int, 2*size+1),
+		bwd:    make([]int, 2*size+1),
+		offset: size,
+	}
+	c.compare(0, n, 0, m)
+	return c.edits
+}
+
+// differ holds the inputs and the furthest reaching paths of the forward
+// and backward searches, indexed by diagonal plus offset
+type differ struct {
+	a, b     []string
+	edits    []Edit
+	fwd, bwd []int
+	offset   int
+}
+
+// compare appends the edit script turning a[aLo:aHi] into b[bLo:bHi]
+func (c *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && c.a[aLo] == c.b[bLo] {
+		c.edits = append(c.edits, Edit{Op: OpEqual, Text: c.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && c.a[aHi-suffix-1] == c.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for _, text := range c.b[bLo:bHi] {
+			c.edits = append(c.edits, Edit{Op: OpInsert, Text: text})
+		}
+	case bLo == bHi:
+		for _, text := range c.a[aLo:aHi] {
+			c.edits = append(c.edits, Edit{Op: OpDelete, Text: text})
+		}
+	default:
+		x, y, u, v := c.middleSnake(aLo, aHi, bLo, bHi)
+		c.compare(aLo, x, bLo, y)
+		for _, text := range c.a[x:u] {
+			c.edits = append(c.edits, Edit{Op: OpEqual, Text: text})
+		}
+		c.compare(u, aHi, v, bHi)
+	}
+
+	for _, text := range c.a[aHi : aHi+suffix] {
+		c.edits = append(c.edits, Edit{Op: OpEqual, Text: text})
+	}
+}
+
+// middleSnake runs the forward and backward searches until their paths
+// overlap and returns the snake (x, y) to (u, v) where they meet
+func (c *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	fwd, bwd, off := c.fwd, c.bwd, c.offset
+	fwd[off+1], bwd[off+1] = 0, 0
+
+	for d := 0; d <= (n+m+1)/2; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && fwd[off+k-1] < fwd[off+k+1]) {
+				x = fwd[off+k+1]
+			} else {
+				x = fwd[off+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && c.a[aLo+u] == c.b[bLo+v] {
+				u++
+				v++
+			}
+			fwd[off+k] = u
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && u+bwd[off+delta-k] >= n {
+				return aLo + x, bLo + y, aLo + u, bLo + v
+			}
+		}
+
+		// The backward search runs on the reversed inputs
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && bwd[off+k-1] < bwd[off+k+1]) {
+				x = bwd[off+k+1]
+			} else {
+				x = bwd[off+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && c.a[aHi-1-u] == c.b[bHi-1-v] {
+				u++
+				v++
+			}
+			bwd[off+k] = u
+			if !odd && delta-k >= -d && delta-k <= d && u+fwd[off+delta-k] >= n {
+				return aHi - u, bHi - v, aHi - x, bHi - y
+			}
+		}
+	}
+	panic("diff: no middle snake")
+}
+
+// SplitLines splits a body into lines, ignoring a trailing newline
+func SplitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, "\n")
+}
+
+// SplitWords splits text into words, whitespace runs and single punctuation marks.
+// Text is NFC normalized first and combining marks stay attached to their letter,
+// so Vietnamese words like "Nguyễn" are never split in the middle.
+func SplitWords(s string) []string {
+	var tokens []string
+	var current []rune
+	currentClass := -1
+
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range norm.NFC.String(s) {
+		class := runeClass(r)
+		if class != currentClass || class == classPunct {
+			flush()
+			currentClass = class
+		}
+		current = append(current, r)
+	}
+	flush()
+	return tokens
+}
+
+const (
+	classWord = iota
+	classSpace
+	classPunct
+)
+
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r):
+		return classWord
+	case unicode.IsSpace(r):
+		return classSpace
+	default:
+		return classPunct
+	}
+}
+
+// Lines computes a line diff of two bodies. Lines replaced within the same
+// change block are paired and annotated with a word level diff.
+func Lines(oldBody, newBody string) ([]*models.DiffLine, *models.DiffStats) {
+	edits := Compute(SplitLines(oldBody), SplitLines(newBody))
+	lines := make([]*models.DiffLine, 0, len(edits))
+	stats := &models.DiffStats{}
+
+	oldNumber, newNumber := 0, 0
+	for i := 0; i < len(edits); {
+		if edits[i].Op == OpEqual {
+			oldNumber++
+			newNumber++
+			lines = append(lines, &models.DiffLine{Op: OpEqual, OldNumber: oldNumber, NewNumber: newNumber, Text: edits[i].Text})
+			i++
+			continue
+		}
+
+		// Collect a block of consecutive changes
+		var deleted, inserted []*models.DiffLine
+		for ; i < len(edits) && edits[i].Op != OpEqual; i++ {
+			if edits[i].Op == OpDelete {
+				oldNumber++
+				deleted = append(deleted, &models.DiffLine{Op: OpDelete, OldNumber: oldNumber, Text: edits[i].Text})
+			} else {
+				newNumber++
+				inserted = append(inserted, &models.DiffLine{Op: OpInsert, NewNumber: newNumber, Text: edits[i].Text})
+			}
+		}
+		stats.LinesRemoved += len(deleted)
+		stats.LinesAdded += len(inserted)
+
+		for j := 0; j < len(deleted) && j < len(inserted); j++ {
+			deleted[j].Segments, inserted[j].Segments = Words(deleted[j].Text, inserted[j].Text)
+		}
+		for _, line := range deleted {
+			stats.WordsRemoved += countWords(line, OpDelete)
+		}
+		for _, line := range inserted {
+			stats.WordsAdded += countWords(line, OpInsert)
+		}
+
+		lines = append(lines, deleted...)
+		lines = append(lines, inserted...)
+	}
+	return lines, stats
+}
+
+// Words computes a word diff of two lines, returning the segments of the old
+// line (equal and delete) and of the new line (equal and insert).
+func Words(oldLine, newLine string) (oldSegments, newSegments []*models.DiffSegment) {
+	for _, edit := range Compute(SplitWords(oldLine), SplitWords(newLine)) {
+		if edit.Op != OpInsert {
+			oldSegments = appendSegment(oldSegments, edit)
+		}
+		if edit.Op != OpDelete {
+			newSegments = appendSegment(newSegments, edit)
+		}
+	}
+	return oldSegments, newSegments
+}
+
+// appendSegment merges consecutive tokens with the same operation
+func appendSegment(segments []*models.DiffSegment, edit Edit) []*models.DiffSegment {
+	if last := len(segments) - 1; last >= 0 && segments[last].Op == edit.Op {
+		segments[last].Text += edit.Text
+		return segments
+	}
+	return append(segments, &models.DiffSegment{Op: edit.Op, Text: edit.Text})
+}
+
+// countWords counts the changed words of a line
+func countWords(line *models.DiffLine, op string) int {
+	if line.Segments == nil {
+		return countTokens(line.Text)
+	}
+	count := 0
+	for _, segment := range line.Segments {
+		if segment.Op == op {
+			count += countTokens(segment.Text)
+		}
+	}
+	return count
+}
+
+// countTokens counts the words of a text, ignoring whitespace and punctuation
+func countTokens(text string) int {
+	count := 0
+	for _, token := range SplitWords(text) {
+		if runeClass([]rune(token)[0]) == classWord {
+			count++
+		}
+	}
+	return count
+}
+
+// Unified renders a line diff in unified diff format with the given context size
+func Unified(oldName, newName string, lines []*models.DiffLine, context int) string {
+	var changed []int
+	for i, line := range lines {
+		if line.Op != OpEqual {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(changed); {
+		end := start
+		for end+1 < len(changed) && changed[end+1]-changed[end] <= 2*context+1 {
+			end++
+		}
+
+		from := changed[start] - context
+		if from < 0 {
+			from = 0
+		}
+		to := changed[end] + context + 1
+		if to > len(lines) {
+			to = len(lines)
+		}
+		writeHunk(&sb, lines, from, to)
+		start = end + 1
+	}
+	return sb.String()
+}
+
+// writeHunk writes lines[from:to] as a unified diff hunk
+func writeHunk(sb *strings.Builder, lines []*models.DiffLine, from, to int) {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+
+	// Line numbers preceding the hunk, used when a side has no lines
+	for i := from - 1; i >= 0; i-- {
+		if oldStart == 0 && lines[i].OldNumber > 0 {
+			oldStart = lines[i].OldNumber
+		}
+		if newStart == 0 && lines[i].NewNumber > 0 {
+			newStart = lines[i].NewNumber
+		}
+	}
+
+	firstOld, firstNew := 0, 0
+	for _, line := range lines[from:to] {
+		if line.Op != OpInsert {
+			oldCount++
+			if firstOld == 0 {
+				firstOld = line.OldNumber
+			}
+		}
+		if line.Op != OpDelete {
+			newCount++
+			if firstNew == 0 {
+				firstNew = line.NewNumber
+			}
+		}
+	}
+	if firstOld > 0 {
+		oldStart = firstOld
+	}
+	if firstNew > 0 {
+		newStart = firstNew
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, line := range lines[from:to] {
+		switch line.Op {
+		case OpInsert:
+			sb.WriteString("+")
+		case OpDelete:
+			sb.WriteString("-")
+		default:
+			sb.WriteString(" ")
+		}
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
+)
+
+func apply(edits []Edit) (a, b []string) {
+	for _, e := range edits {
+		if e.Op != OpInsert {
+			a = append(a, e.Text)
+		}
+		if e.Op != OpDelete {
+			b = append(b, e.Text)
+		}
+	}
+	return a, b
+}
+
+func TestCompute(t *testing.T) {
+	a := strings.Split("ABCABBA", "")
+	b := strings.Split("CBABAC", "")
+	edits := Compute(a, b)
+
+	gotA, gotB := apply(edits)
+	assert.Equal(t, a, gotA)
+	assert.Equal(t, b, gotB)
+
+	changes := 0
+	for _, e := range edits {
+		if e.Op != OpEqual {
+			changes++
+		}
+	}
+	assert.Equal(t, 5, changes, "Myers' shortest edit script for ABCABBA -> CBABAC has 5 edits")
+
+	assert.Nil(t, Compute(nil, nil))
+}
+
+// lcs is the length of the longest common subsequence, by dynamic programming
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestComputeIsShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		s := make([]string, rng.Intn(12))
+		for i := range s {
+			s[i] = string(rune('A' + rng.Intn(3)))
+		}
+		return s
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := random(), random()
+		edits := Compute(a, b)
+		gotA, gotB := apply(edits)
+		assert.Equal(t, strings.Join(a, ""), strings.Join(gotA, ""))
+		assert.Equal(t, strings.Join(b, ""), strings.Join(gotB, ""))
+
+		changes := 0
+		for _, e := range edits {
+			if e.Op != OpEqual {
+				changes++
+			}
+		}
+		assert.Equal(t, len(a)+len(b)-2*lcs(a, b), changes, "%v -> %v", a, b)
+	}
+}
+
+func TestComputeMemoryIsLinear(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, fmt.Sprintf("old line %d", i))
+		b = append(b, fmt.Sprintf("new line %d", i))
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := Compute(a, b)
+	runtime.ReadMemStats(&after)
+
+	assert.Len(t, edits, 6000)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(4<<20))
+}
+
+func TestSplitWordsVietnamese(t *testing.T) {
+	// "Nguyễn" written with combining marks (NFD) must stay one word
+	nfd := norm.NFD.String("Nguyễn Văn A, 20 tuổi.")
+	assert.Equal(t, []string{"Nguyễn", " ", "Văn", " ", "A", ",", " ", "20", " ", "tuổi", "."}, SplitWords(nfd))
+}
+
+func TestLines(t *testing.T) {
+	oldBody := "Tiêu đề\nCuộc thi board game\nKết thúc\n"
+	newBody := "Tiêu đề\nCuộc thi board game Việt Nam\nKết thúc\nThêm dòng"
+
+	lines, stats := Lines(oldBody, newBody)
+	assert.Equal(t, 1, stats.LinesRemoved)
+	assert.Equal(t, 2, stats.LinesAdded)
+	assert.Equal(t, 4, stats.WordsAdded)
+	assert.Equal(t, 0, stats.WordsRemoved)
+
+	assert.Equal(t, OpDelete, lines[1].Op)
+	assert.Equal(t, OpInsert, lines[2].Op)
+	assert.Equal(t, " Việt Nam", lines[2].Segments[1].Text)
+	assert.Equal(t, OpInsert, lines[2].Segments[1].Op)
+
+	unified := Unified("a/old", "b/new", lines, 3)
+	assert.Equal(t, "--- a/old\n+++ b/new\n@@ -1,3 +1,4 @@\n Tiêu đề\n-Cuộc thi board game\n+Cuộc thi board game Việt Nam\n Kết thúc\n+Thêm dòng\n", unified)
+}
+
+func TestUnifiedHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 1; i <= 20; i++ {
+		line := strings.Repeat("x", i)
+		oldLines = append(oldLines, line)
+		if i != 2 && i != 18 {
+			newLines = append(newLines, line)
+		}
+	}
+	lines, _ := Lines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	unified := Unified("a", "b", lines, 3)
+	assert.Contains(t, unified, "@@ -1,5 +1,4 @@")
+	assert.Contains(t, unified, "@@ -15,6 +14,5 @@")
+
+	assert.Empty(t, Unified("a", "b", lines[:1], 3))
+}
@@ -0,0 +1,237 @@
+package escrow
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// poolStates maps the contract's State enum
+var poolStates = map[uint8]string{1: models.PrizePoolFunding, 2: models.PrizePoolPaid, 3: models.PrizePoolRefunding}
+
+// winnerTuple is the Go form of the contract's Winner struct
+type winnerTuple struct {
+	Rank         *big.Int
+	ContestantId string
+	Wallet       common.Address
+}
+
+// Client reads and drives the PrizeEscrow contract. Transactions are signed
+// by the contract's owner.
+type Client struct {
+	address   common.Address
+	abi       abi.ABI
+	contract  *bind.BoundContract
+	backend   bind.ContractBackend
+	transact  func() (*bind.TransactOpts, error)
+	fromBlock uint64 // Block the escrow was deployed at, where event scans start
+}
+
+// NewClient binds the escrow contract at address with the ABI of its
+// compiled artifact
+func NewClient(address string, contractABI abi.ABI, fromBlock uint64, backend bind.ContractBackend, transact func() (*bind.TransactOpts, error)) (*Client, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid escrow address %q", address)
+	}
+	escrow := common.HexToAddress(address)
+	return &Client{
+		address:   escrow,
+		abi:       contractABI,
+		contract:  bind.NewBoundContract(escrow, contractABI, backend, backend, backend),
+		backend:   backend,
+		transact:  transact,
+		fromBlock: fromBlock,
+	}, nil
+}
+
+// Address returns the address of the escrow contract
+func (c *Client) Address() string {
+	return c.address.Hex()
+}
+
+// Pool reads the prize pool of a contest with its deposits and payouts, nil
+// if the contest has none
+func (c *Client) Pool(contestID string) (*models.PrizePool, error) {
+	var result []interface{}
+	if err := c.contract.Call(&bind.CallOpts{}, &result, "getPool", contestID); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	if len(result) != 4 {
+		return nil, fmt.Errorf("unexpected getPool result length %d", len(result))
+	}
+	token, _ := result[0].(common.Address)
+	state, _ := result[1].(uint8)
+	total, _ := result[2].(*big.Int)
+	shares, _ := result[3].([]uint16)
+	if state == 0 {
+		return nil, nil
+	}
+
+	sharesBps := make([]uint64, 0, len(shares))
+	for _, share := range shares {
+		sharesBps = append(sharesBps, uint64(share))
+	}
+	pool := &models.PrizePool{
+		ContestID: contestID,
+		Escrow:    c.address.Hex(),
+		State:     poolStates[state],
+		Total:     total.String(),
+		Shares:    Shares(sharesBps),
+	}
+	if token != (common.Address{}) {
+		pool.Token = token.Hex()
+	}
+
+	var err error
+	if pool.Deposits, err = c.deposits(contestID); err != nil {
+		return nil, err
+	}
+	if pool.Payouts, err = c.payouts(contestID); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Open opens the prize pool of a contest in token, the native token when
+// empty
+func (c *Client) Open(contestID, token string, sharesBps []uint64) (string, error) {
+	shares := make([]uint16, 0, len(sharesBps))
+	for _, share := range sharesBps {
+		shares = append(shares, uint16(share))
+	}
+	return c.send("openPool", contestID, common.HexToAddress(token), shares)
+}
+
+// Payout pays the whole pool out to the winners of a plan in one
+// transaction. The contract computes the amounts from the pool's total and
+// distribution table, as Plan does; the amounts of the plan are not sent.
+func (c *Client) Payout(contestID string, payouts []models.PrizePayout) (string, error) {
+	winners := make([]winnerTuple, 0, len(payouts))
+	for _, payout := range payouts {
+		winners = append(winners, winnerTuple{
+			Rank:         big.NewInt(int64(payout.Rank)),
+			ContestantId: payout.ContestantID,
+			Wallet:       common.HexToAddress(payout.Wallet),
+		})
+	}
+	return c.send("payout", contestID, winners)
+}
+
+// Cancel stops the deposits of a pool so they can be refunded
+func (c *Client) Cancel(contestID string) (string, error) {
+	return c.send("cancelPool", contestID)
+}
+
+// Refund returns everything a depositor sent to a cancelled pool
+func (c *Client) Refund(contestID, depositor string) (string, error) {
+	return c.send("refund", contestID, common.HexToAddress(depositor))
+}
+
+// send signs and sends a transaction to the escrow
+func (c *Client) send(method string, args ...interface{}) (string, error) {
+	auth, err := c.transact()
+	if err != nil {
+		return "", fmt.Errorf("failed to create transactor: %w", err)
+	}
+	tx, err := c.contract.Transact(auth, method, args...)
+	if err != nil {
+		return "", apperr.FromChain(err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// deposits reads the deposits to a pool from the Deposited events, marking
+// those of depositors that were refunded
+func (c *Client) deposits(contestID string) ([]models.PrizeDeposit, error) {
+	logs, err := c.logs("Refunded", contestID)
+	if err != nil {
+		return nil, err
+	}
+	refunded := make(map[common.Address]bool)
+	for _, log := range logs {
+		refunded[common.BytesToAddress(log.Topics[2].Bytes())] = true
+	}
+
+	if logs, err = c.logs("Deposited", contestID); err != nil {
+		return nil, err
+	}
+	deposits := make([]models.PrizeDeposit, 0, len(logs))
+	for _, log := range logs {
+		values, err := c.abi.Unpack("Deposited", log.Data)
+		if err != nil || len(values) != 2 {
+			return nil, fmt.Errorf("malformed Deposited event in tx %s", log.TxHash.Hex())
+		}
+		depositor := common.BytesToAddress(log.Topics[2].Bytes())
+		deposits = append(deposits, models.PrizeDeposit{
+			Depositor: depositor.Hex(),
+			Amount:    values[0].(*big.Int).String(),
+			Refunded:  refunded[depositor],
+			Block:     log.BlockNumber,
+			TxHash:    log.TxHash.Hex(),
+		})
+	}
+	return deposits, nil
+}
+
+// payouts reads the prizes paid from a pool from the PaidOut and PrizeHeld
+// events, in the order of the places. Held prizes stay held until their
+// winner claims them.
+func (c *Client) payouts(contestID string) ([]models.PrizePayout, error) {
+	logs, err := c.logs("PrizeClaimed", contestID)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make(map[common.Address]bool)
+	for _, log := range logs {
+		claimed[common.BytesToAddress(log.Topics[2].Bytes())] = true
+	}
+
+	payouts := make([]models.PrizePayout, 0)
+	for _, event := range []string{"PaidOut", "PrizeHeld"} {
+		logs, err := c.logs(event, contestID)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			values, err := c.abi.Unpack(event, log.Data)
+			if err != nil || len(values) != 3 {
+				return nil, fmt.Errorf("malformed %s event in tx %s", event, log.TxHash.Hex())
+			}
+			winner := common.BytesToAddress(log.Topics[2].Bytes())
+			payouts = append(payouts, models.PrizePayout{
+				Rank:         int(values[0].(*big.Int).Int64()),
+				ContestantID: values[1].(string),
+				Wallet:       winner.Hex(),
+				Amount:       values[2].(*big.Int).String(),
+				Held:         event == "PrizeHeld" && !claimed[winner],
+				TxHash:       log.TxHash.Hex(),
+			})
+		}
+	}
+	sort.SliceStable(payouts, func(i, j int) bool { return payouts[i].Rank < payouts[j].Rank })
+	return payouts, nil
+}
+
+// logs returns the events of a contest's pool, oldest first. Indexed strings
+// are stored as their keccak256 hash.
+func (c *Client) logs(event, contestID string) ([]types.Log, error) {
+	logs, err := c.backend.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(c.fromBlock),
+		Addresses: []common.Address{c.address},
+		Topics:    [][]common.Hash{{c.abi.Events[event].ID}, {crypto.Keccak256Hash([]byte(contestID))}},
+	})
+	if err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	return logs, nil
+}
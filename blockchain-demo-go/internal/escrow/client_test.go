@@ -0,0 +1,308 @@
+package escrow
+
+import (
+	"blockchain-demo/internal/models"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// artifactDir holds the contracts compiled by truffle, committed with their
+// sources
+var artifactDir = filepath.Join("..", "..", "..", "backend", "truffle", "build", "contracts")
+
+// artifact is a compiled contract
+type artifact struct {
+	abi      abi.ABI
+	bytecode []byte
+}
+
+func loadArtifact(t *testing.T, name string) artifact {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(artifactDir, name+".json"))
+	require.NoError(t, err)
+
+	var compiled struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode string          `json:"bytecode"`
+	}
+	require.NoError(t, json.Unmarshal(data, &compiled))
+	parsed, err := abi.JSON(strings.NewReader(string(compiled.ABI)))
+	require.NoError(t, err)
+	return artifact{abi: parsed, bytecode: common.FromHex(compiled.Bytecode)}
+}
+
+// chain is a simulated chain with the escrow deployed by owner
+type chain struct {
+	t       *testing.T
+	sim     *simulated.Backend
+	owner   *bind.TransactOpts
+	escrow  *Client
+	address common.Address
+}
+
+func newAccount(t *testing.T) (*ecdsa.PrivateKey, *bind.TransactOpts) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, params.AllDevChainProtocolChanges.ChainID)
+	require.NoError(t, err)
+	return key, opts
+}
+
+func newChain(t *testing.T, funded ...*bind.TransactOpts) *chain {
+	t.Helper()
+	escrowArtifact := loadArtifact(t, "PrizeEscrow")
+
+	_, owner := newAccount(t)
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	alloc := types.GenesisAlloc{owner.From: {Balance: balance}}
+	for _, account := range funded {
+		alloc[account.From] = types.Account{Balance: balance}
+	}
+	sim := simulated.NewBackend(alloc)
+	t.Cleanup(func() { sim.Close() })
+
+	address, _, _, err := bind.DeployContract(owner, escrowArtifact.abi, escrowArtifact.bytecode, sim.Client())
+	require.NoError(t, err)
+	sim.Commit()
+
+	client, err := NewClient(address.Hex(), escrowArtifact.abi, 0, sim.Client(), func() (*bind.TransactOpts, error) { return owner, nil })
+	require.NoError(t, err)
+	return &chain{t: t, sim: sim, owner: owner, escrow: client, address: address}
+}
+
+// mine commits the pending transactions and fails the test if one reverted
+func (c *chain) mine(txHash string, err error) {
+	c.t.Helper()
+	require.NoError(c.t, err)
+	c.sim.Commit()
+	receipt, err := c.sim.Client().TransactionReceipt(context.Background(), common.HexToHash(txHash))
+	require.NoError(c.t, err)
+	require.Equal(c.t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+// deposit sends native tokens to a pool from a sponsor's wallet
+func (c *chain) deposit(from *bind.TransactOpts, contestID string, amount int64) {
+	c.t.Helper()
+	opts := *from
+	opts.Value = big.NewInt(amount)
+	tx, err := c.escrow.contract.Transact(&opts, "deposit", contestID)
+	if err != nil {
+		c.mine("", err)
+	}
+	c.mine(tx.Hash().Hex(), nil)
+}
+
+func (c *chain) balance(account common.Address) *big.Int {
+	c.t.Helper()
+	balance, err := c.sim.Client().BalanceAt(context.Background(), account, nil)
+	require.NoError(c.t, err)
+	return balance
+}
+
+// deploy deploys a test contract compiled with the escrow
+func (c *chain) deploy(name string) (common.Address, *bind.BoundContract) {
+	c.t.Helper()
+	compiled := loadArtifact(c.t, name)
+	address, tx, contract, err := bind.DeployContract(c.owner, compiled.abi, compiled.bytecode, c.sim.Client())
+	require.NoError(c.t, err)
+	c.mine(tx.Hash().Hex(), nil)
+	return address, contract
+}
+
+// transact sends a transaction to a test contract and mines it
+func (c *chain) transact(contract *bind.BoundContract, from *bind.TransactOpts, method string, args ...interface{}) {
+	c.t.Helper()
+	tx, err := contract.Transact(from, method, args...)
+	require.NoError(c.t, err)
+	c.mine(tx.Hash().Hex(), nil)
+}
+
+func (c *chain) pool(contestID string) *models.PrizePool {
+	c.t.Helper()
+	pool, err := c.escrow.Pool(contestID)
+	require.NoError(c.t, err)
+	require.NotNil(c.t, pool)
+	return pool
+}
+
+func TestClientPaysOutNativePool(t *testing.T) {
+	_, acme := newAccount(t)
+	_, globex := newAccount(t)
+	c := newChain(t, acme, globex)
+	winner, runnerUp := common.HexToAddress(alice), common.HexToAddress(bob)
+
+	pool, err := c.escrow.Pool("c1")
+	require.NoError(t, err)
+	assert.Nil(t, pool)
+
+	c.mine(c.escrow.Open("c1", "", []uint64{7000, 3000}))
+	c.deposit(acme, "c1", 700)
+	c.deposit(globex, "c1", 300)
+	c.deposit(acme, "c1", 1)
+
+	pool = c.pool("c1")
+	assert.Equal(t, models.PrizePoolFunding, pool.State)
+	assert.Empty(t, pool.Token)
+	assert.Equal(t, "1001", pool.Total)
+	assert.Equal(t, []models.PrizeShare{{Rank: 1, ShareBps: 7000}, {Rank: 2, ShareBps: 3000}}, pool.Shares)
+	require.Len(t, pool.Deposits, 3)
+	assert.Equal(t, acme.From.Hex(), pool.Deposits[0].Depositor)
+	assert.Equal(t, "300", pool.Deposits[1].Amount)
+
+	payouts, err := Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}}, wallet)
+	require.NoError(t, err)
+
+	// Only the owner pays out, and only in the order of the places
+	stranger, err := NewClient(c.address.Hex(), c.escrow.abi, 0, c.sim.Client(), func() (*bind.TransactOpts, error) { return acme, nil })
+	require.NoError(t, err)
+	_, err = stranger.Payout("c1", payouts)
+	assert.Error(t, err)
+	_, err = c.escrow.Payout("c1", []models.PrizePayout{{Rank: 2, ContestantID: "b", Wallet: bob}, {Rank: 1, ContestantID: "a", Wallet: alice}})
+	assert.Error(t, err)
+
+	// A deposit landing before the payout cannot block it: the contract
+	// splits whatever the pool holds by its own table
+	c.deposit(globex, "c1", 1)
+	c.mine(c.escrow.Payout("c1", payouts))
+
+	assert.Equal(t, big.NewInt(702), c.balance(winner))
+	assert.Equal(t, big.NewInt(300), c.balance(runnerUp))
+	assert.Zero(t, c.balance(c.address).Sign())
+
+	pool = c.pool("c1")
+	assert.Equal(t, models.PrizePoolPaid, pool.State)
+	require.Len(t, pool.Payouts, 2)
+	assert.Equal(t, "702", pool.Payouts[0].Amount)
+	assert.Equal(t, models.PrizePayout{Rank: 2, ContestantID: "b", Wallet: runnerUp.Hex(), Amount: "300", TxHash: pool.Payouts[1].TxHash}, pool.Payouts[1])
+
+	// A pool is paid out once and takes no more deposits
+	_, err = c.escrow.Payout("c1", payouts)
+	assert.Error(t, err)
+	opts := *acme
+	opts.Value = big.NewInt(1)
+	_, err = c.escrow.contract.Transact(&opts, "deposit", "c1")
+	assert.Error(t, err)
+}
+
+func TestClientHoldsRefusedPrizes(t *testing.T) {
+	_, acme := newAccount(t)
+	c := newChain(t, acme)
+	rejecting, wallet := c.deploy("RejectingWallet")
+	refusing := map[string]string{"a": alice, "b": rejecting.Hex()}
+
+	c.mine(c.escrow.Open("c1", "", []uint64{5000, 5000}))
+	c.deposit(acme, "c1", 1000)
+	payouts, err := Plan(c.pool("c1"), []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}}, func(contestantID string) (string, error) {
+		return refusing[contestantID], nil
+	})
+	require.NoError(t, err)
+
+	// A wallet refusing its prize does not stop the others from being paid
+	c.mine(c.escrow.Payout("c1", payouts))
+	assert.Equal(t, big.NewInt(500), c.balance(common.HexToAddress(alice)))
+	pool := c.pool("c1")
+	require.Len(t, pool.Payouts, 2)
+	assert.False(t, pool.Payouts[0].Held)
+	assert.True(t, pool.Payouts[1].Held)
+	assert.Equal(t, big.NewInt(500), c.balance(c.address))
+
+	// The winner claims it to another wallet
+	c.transact(wallet, c.owner, "claim", c.address, "c1", common.HexToAddress(carol))
+	assert.Equal(t, big.NewInt(500), c.balance(common.HexToAddress(carol)))
+	assert.False(t, c.pool("c1").Payouts[1].Held)
+	_, err = wallet.Transact(c.owner, "claim", c.address, "c1", common.HexToAddress(carol))
+	assert.Error(t, err)
+}
+
+func TestClientRefundsCancelledPool(t *testing.T) {
+	_, acme := newAccount(t)
+	_, globex := newAccount(t)
+	c := newChain(t, acme, globex)
+
+	c.mine(c.escrow.Open("c1", "", []uint64{10000}))
+	c.deposit(acme, "c1", 500)
+	c.deposit(globex, "c1", 250)
+	c.deposit(acme, "c1", 500)
+	acmeBefore := c.balance(acme.From)
+
+	// Refunds wait for the pool to be cancelled
+	_, err := c.escrow.Refund("c1", acme.From.Hex())
+	assert.Error(t, err)
+
+	c.mine(c.escrow.Cancel("c1"))
+	pool := c.pool("c1")
+	assert.Equal(t, models.PrizePoolRefunding, pool.State)
+	refunds := Refundable(pool)
+	require.Len(t, refunds, 2)
+	assert.Equal(t, "1000", refunds[0].Amount)
+
+	c.mine(c.escrow.Refund("c1", acme.From.Hex()))
+	assert.Equal(t, new(big.Int).Add(acmeBefore, big.NewInt(1000)), c.balance(acme.From))
+
+	// Deposits of refunded wallets are marked; the others are still owed
+	pool = c.pool("c1")
+	refunds = Refundable(pool)
+	require.Len(t, refunds, 1)
+	assert.Equal(t, globex.From.Hex(), refunds[0].Depositor)
+	assert.True(t, pool.Deposits[0].Refunded)
+
+	c.mine(c.escrow.Refund("c1", globex.From.Hex()))
+	assert.Empty(t, Refundable(c.pool("c1")))
+	assert.Zero(t, c.balance(c.address).Sign())
+
+	// Deposits stop once the pool is cancelled
+	opts := *acme
+	opts.Value = big.NewInt(1)
+	_, err = c.escrow.contract.Transact(&opts, "deposit", "c1")
+	assert.Error(t, err)
+}
+
+func TestClientPaysOutTokenPool(t *testing.T) {
+	_, acme := newAccount(t)
+	c := newChain(t, acme)
+	tokenAddress, token := c.deploy("TestToken")
+	c.transact(token, c.owner, "mint", acme.From, big.NewInt(1000))
+
+	c.mine(c.escrow.Open("c1", tokenAddress.Hex(), []uint64{6000, 4000}))
+
+	// Native deposits are refused by token pools
+	opts := *acme
+	opts.Value = big.NewInt(1)
+	_, err := c.escrow.contract.Transact(&opts, "deposit", "c1")
+	assert.Error(t, err)
+
+	c.transact(token, acme, "approve", c.address, big.NewInt(1000))
+	c.transact(c.escrow.contract, acme, "depositToken", "c1", big.NewInt(1000))
+
+	pool := c.pool("c1")
+	assert.Equal(t, tokenAddress.Hex(), pool.Token)
+	assert.Equal(t, "1000", pool.Total)
+
+	// Tied winners split first and second place
+	payouts, err := Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 1, ContestantID: "b"}}, wallet)
+	require.NoError(t, err)
+	c.mine(c.escrow.Payout("c1", payouts))
+
+	for _, winner := range []string{alice, bob} {
+		var result []interface{}
+		require.NoError(t, token.Call(&bind.CallOpts{}, &result, "balanceOf", common.HexToAddress(winner)))
+		assert.Equal(t, big.NewInt(500), result[0])
+	}
+}
@@ -0,0 +1,211 @@
+// Package escrow manages the prize pools held by the PrizeEscrow contract.
+//
+// Sponsors deposit the chain's native token or an ERC-20 token into the pool
+// of a contest straight from their wallets; deposits are read back from the
+// contract's events. Once the results are final the pool is paid out to the
+// winners by a distribution table in basis points, which the contract applies
+// itself. A cancelled contest, or a closed one whose pool could not be paid
+// out, returns every deposit to the wallet it came from.
+//
+// Prizes only go to wallets the winners proved they own: a payout address the
+// wallet that created the contestant signed, or that wallet itself once the
+// contestant is verified.
+package escrow
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/models"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TotalBps is the sum of the shares of a distribution table
+const TotalBps = 10000
+
+// CheckShares returns an error unless every place gets a share and the
+// shares add up to the whole pool
+func CheckShares(sharesBps []uint64) error {
+	var sum uint64
+	for i, share := range sharesBps {
+		if share == 0 || share > TotalBps {
+			return apperr.Fields([]models.FieldError{{Field: fmt.Sprintf("shares_bps[%d]", i), Rule: "range", Message: "Share must be between 1 and 10000"}})
+		}
+		sum += share
+	}
+	if sum != TotalBps {
+		return apperr.Fields([]models.FieldError{{Field: "shares_bps", Rule: "sum", Message: fmt.Sprintf("Shares must add up to %d basis points, not %d", TotalBps, sum)}})
+	}
+	return nil
+}
+
+// Shares numbers the places of a distribution table from first place
+func Shares(sharesBps []uint64) []models.PrizeShare {
+	shares := make([]models.PrizeShare, 0, len(sharesBps))
+	for i, share := range sharesBps {
+		shares = append(shares, models.PrizeShare{Rank: i + 1, ShareBps: share})
+	}
+	return shares
+}
+
+// CheckOpen returns an error unless the prize pool of a contest can be
+// opened. existing is the contest's pool, nil if it has none.
+func CheckOpen(contest *models.Contest, existing *models.PrizePool, req *models.OpenPrizePoolRequest) error {
+	if existing != nil {
+		return apperr.New(apperr.KindConflict, models.ErrorCodePrizePoolExists, "contest "+contest.ID+" already has a prize pool")
+	}
+	if state := lifecycle.StateOf(contest); lifecycle.Final(state) {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is "+state)
+	}
+	return CheckShares(req.SharesBps)
+}
+
+// CheckPayout returns an error unless a pool can be paid out with the
+// results of its contest
+func CheckPayout(contest *models.Contest, pool *models.PrizePool, results *models.ContestResults) error {
+	if err := requireState(pool, models.PrizePoolFunding); err != nil {
+		return err
+	}
+	if lifecycle.StateOf(contest) == models.ContestCancelled {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is cancelled, refund the prize pool instead")
+	}
+	if results == nil || !results.Final {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeResultsNotFinal, "contest "+contest.ID+" has no final results")
+	}
+	return nil
+}
+
+// CheckRefund returns an error unless a pool can be returned to its
+// depositors: its contest was cancelled, or closed without the pool being
+// paid out, for instance because a winner has no wallet. A pool already
+// refunding can be refunded again, for deposits whose refund failed.
+func CheckRefund(contest *models.Contest, pool *models.PrizePool) error {
+	if pool.State != models.PrizePoolRefunding {
+		if err := requireState(pool, models.PrizePoolFunding); err != nil {
+			return err
+		}
+	}
+	if state := lifecycle.StateOf(contest); !lifecycle.Final(state) {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "only closed or cancelled contests are refunded, contest is "+state)
+	}
+	return nil
+}
+
+// PayoutMessage returns the message a winner signs with the wallet that
+// created its contestant to have its prize sent to wallet
+func PayoutMessage(contestID, contestantID, wallet string) string {
+	return fmt.Sprintf("Send my prize in contest %s as contestant %s to wallet %s",
+		contestID, contestantID, strings.ToLower(wallet))
+}
+
+// PayoutWallet returns the wallet a winner proved it owns: the payout
+// address it signed, if any, or else the wallet that created the contestant
+// once the contestant is verified. It is empty when the winner proved none.
+func PayoutWallet(contestID string, contestant *models.Contestant, signed *models.PayoutAddress) (string, error) {
+	if contestant == nil {
+		return "", nil
+	}
+	if signed != nil {
+		signer, err := auth.RecoverAddress([]byte(PayoutMessage(contestID, contestant.ID, signed.Wallet)), signed.Signature)
+		if err != nil || !common.IsHexAddress(signed.Wallet) || !strings.EqualFold(signer.Hex(), contestant.Creator) {
+			return "", apperr.New(apperr.KindValidation, models.ErrorCodeInvalidSignature,
+				"payout address of contestant "+contestant.ID+" is not signed by the wallet that created it")
+		}
+		return signed.Wallet, nil
+	}
+	if contestant.Verified && common.IsHexAddress(contestant.Creator) {
+		return contestant.Creator, nil
+	}
+	return "", nil
+}
+
+// Plan splits a pool between the winners of the final results the way the
+// escrow contract does. Entries tied on a rank share the prizes of the places
+// they take together; the prizes of places nobody takes and the rounding
+// remainder go to first place, so the whole pool is paid out. wallet returns
+// the wallet a contestant proved it owns.
+func Plan(pool *models.PrizePool, results []models.ContestantResult, wallet func(contestantID string) (string, error)) ([]models.PrizePayout, error) {
+	total, ok := new(big.Int).SetString(pool.Total, 10)
+	if !ok || total.Sign() <= 0 {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodePrizePoolEmpty, "prize pool of contest "+pool.ContestID+" is empty")
+	}
+	if len(results) == 0 {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeResultsNotFinal, "contest "+pool.ContestID+" has no ranked entries")
+	}
+
+	payouts := make([]models.PrizePayout, 0, len(pool.Shares))
+	paid := new(big.Int)
+	for place := 0; place < len(results) && place < len(pool.Shares); {
+		end := place + 1
+		for end < len(results) && results[end].Rank == results[place].Rank {
+			end++
+		}
+		var bps uint64
+		for p := place; p < end && p < len(pool.Shares); p++ {
+			bps += pool.Shares[p].ShareBps
+		}
+		each := new(big.Int).Mul(total, new(big.Int).SetUint64(bps))
+		each.Quo(each, big.NewInt(TotalBps*int64(end-place)))
+
+		for _, result := range results[place:end] {
+			address, err := wallet(result.ContestantID)
+			if err != nil {
+				return nil, err
+			}
+			if !common.IsHexAddress(address) {
+				return nil, apperr.New(apperr.KindConflict, models.ErrorCodeNoPayoutWallet, "contestant "+result.ContestantID+" has no verified or signed wallet to receive its prize")
+			}
+			payouts = append(payouts, models.PrizePayout{
+				Rank:         result.Rank,
+				ContestantID: result.ContestantID,
+				Wallet:       common.HexToAddress(address).Hex(),
+				Amount:       each.String(),
+			})
+			paid.Add(paid, each)
+		}
+		place = end
+	}
+
+	first, _ := new(big.Int).SetString(payouts[0].Amount, 10)
+	payouts[0].Amount = first.Add(first, new(big.Int).Sub(total, paid)).String()
+	return payouts, nil
+}
+
+// Refundable returns the depositors owed a refund with the sum of what each
+// deposited, in the order of their first deposit
+func Refundable(pool *models.PrizePool) []models.PrizeDeposit {
+	owed := make(map[string]*big.Int)
+	order := make([]string, 0)
+	for _, deposit := range pool.Deposits {
+		if deposit.Refunded {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(deposit.Amount, 10)
+		if !ok {
+			continue
+		}
+		if _, exists := owed[deposit.Depositor]; !exists {
+			owed[deposit.Depositor] = new(big.Int)
+			order = append(order, deposit.Depositor)
+		}
+		owed[deposit.Depositor].Add(owed[deposit.Depositor], amount)
+	}
+
+	refunds := make([]models.PrizeDeposit, 0, len(order))
+	for _, depositor := range order {
+		refunds = append(refunds, models.PrizeDeposit{Depositor: depositor, Amount: owed[depositor].String()})
+	}
+	return refunds
+}
+
+// requireState returns an error unless the pool is in state
+func requireState(pool *models.PrizePool, state string) error {
+	if pool.State != state {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidPoolState, fmt.Sprintf("prize pool is %s, not %s", pool.State, state))
+	}
+	return nil
+}
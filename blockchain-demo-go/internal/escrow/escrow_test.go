@@ -0,0 +1,166 @@
+package escrow
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alice = "0x1111111111111111111111111111111111111111"
+	bob   = "0x2222222222222222222222222222222222222222"
+	carol = "0x3333333333333333333333333333333333333333"
+)
+
+var wallets = map[string]string{"a": alice, "b": bob, "c": carol}
+
+func wallet(contestantID string) (string, error) {
+	return wallets[contestantID], nil
+}
+
+func amounts(payouts []models.PrizePayout) []string {
+	out := make([]string, 0, len(payouts))
+	for _, payout := range payouts {
+		out = append(out, payout.Amount)
+	}
+	return out
+}
+
+func TestShares(t *testing.T) {
+	assert.NoError(t, CheckShares([]uint64{5000, 3000, 2000}))
+
+	err := CheckShares([]uint64{5000, 3000})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, apperr.StatusOf(err))
+	assert.Equal(t, "shares_bps", apperr.FieldsOf(err)[0].Field)
+	assert.Equal(t, "shares_bps[1]", apperr.FieldsOf(CheckShares([]uint64{10000, 0}))[0].Field)
+
+	shares := Shares([]uint64{7000, 3000})
+	assert.Equal(t, []models.PrizeShare{{Rank: 1, ShareBps: 7000}, {Rank: 2, ShareBps: 3000}}, shares)
+}
+
+func TestChecks(t *testing.T) {
+	contest := &models.Contest{ID: "c1", State: models.ContestOpen, Active: true}
+	req := &models.OpenPrizePoolRequest{SharesBps: []uint64{10000}}
+	assert.NoError(t, CheckOpen(contest, nil, req))
+
+	pool := &models.PrizePool{ContestID: "c1", State: models.PrizePoolFunding, Total: "100"}
+	assert.Equal(t, models.ErrorCodePrizePoolExists, apperr.CodeOf(CheckOpen(contest, pool, req)))
+
+	// Payouts need final results, refunds a cancelled contest
+	assert.Equal(t, models.ErrorCodeResultsNotFinal, apperr.CodeOf(CheckPayout(contest, pool, nil)))
+	assert.NoError(t, CheckPayout(contest, pool, &models.ContestResults{Final: true}))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(CheckRefund(contest, pool)))
+
+	contest.State = models.ContestCancelled
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(CheckOpen(contest, nil, req)))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(CheckPayout(contest, pool, &models.ContestResults{Final: true})))
+	assert.NoError(t, CheckRefund(contest, pool))
+
+	pool.State = models.PrizePoolRefunding
+	assert.NoError(t, CheckRefund(contest, pool))
+
+	// A closed contest whose pool could not be paid out is refunded too
+	contest.State = models.ContestClosed
+	pool.State = models.PrizePoolFunding
+	assert.NoError(t, CheckRefund(contest, pool))
+	pool.State = models.PrizePoolPaid
+	assert.Equal(t, models.ErrorCodeInvalidPoolState, apperr.CodeOf(CheckRefund(contest, pool)))
+	assert.Equal(t, models.ErrorCodeInvalidPoolState, apperr.CodeOf(CheckPayout(contest, pool, &models.ContestResults{Final: true})))
+}
+
+func TestPayoutWallet(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	creator := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	sign := func(message string) string {
+		sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+		require.NoError(t, err)
+		sig[crypto.RecoveryIDOffset] += 27
+		return hexutil.Encode(sig)
+	}
+	contestant := &models.Contestant{ID: "a", Creator: creator}
+
+	// Unverified contestants need a signed payout address
+	address, err := PayoutWallet("c1", contestant, nil)
+	require.NoError(t, err)
+	assert.Empty(t, address)
+	contestant.Verified = true
+	address, err = PayoutWallet("c1", contestant, nil)
+	require.NoError(t, err)
+	assert.Equal(t, creator, address)
+
+	signed := &models.PayoutAddress{Wallet: bob, Signature: sign(PayoutMessage("c1", "a", bob))}
+	address, err = PayoutWallet("c1", contestant, signed)
+	require.NoError(t, err)
+	assert.Equal(t, bob, address)
+
+	// Signed for another contest, or by another wallet, it does not count
+	_, err = PayoutWallet("c2", contestant, signed)
+	assert.Equal(t, models.ErrorCodeInvalidSignature, apperr.CodeOf(err))
+	contestant.Creator = alice
+	_, err = PayoutWallet("c1", contestant, signed)
+	assert.Equal(t, models.ErrorCodeInvalidSignature, apperr.CodeOf(err))
+
+	address, err = PayoutWallet("c1", nil, signed)
+	require.NoError(t, err)
+	assert.Empty(t, address)
+}
+
+func TestPlan(t *testing.T) {
+	pool := &models.PrizePool{ContestID: "c1", Total: "1000", Shares: Shares([]uint64{5000, 3000, 2000})}
+
+	payouts, err := Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}, {Rank: 3, ContestantID: "c"}}, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"500", "300", "200"}, amounts(payouts))
+	assert.Equal(t, bob, payouts[1].Wallet)
+	assert.Equal(t, 2, payouts[1].Rank)
+
+	// Tied entries split the places they take together
+	payouts, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}, {Rank: 2, ContestantID: "c"}}, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"500", "250", "250"}, amounts(payouts))
+
+	// Unclaimed places and the rounding remainder go to first place
+	payouts, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}}, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1000"}, amounts(payouts))
+
+	pool = &models.PrizePool{ContestID: "c1", Total: "10", Shares: Shares([]uint64{3334, 3333, 3333})}
+	payouts, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}, {Rank: 3, ContestantID: "c"}}, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "3", "3"}, amounts(payouts))
+
+	// Entries past the last paid place get nothing
+	pool = &models.PrizePool{ContestID: "c1", Total: "100", Shares: Shares([]uint64{10000})}
+	payouts, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}, {Rank: 2, ContestantID: "b"}}, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"100"}, amounts(payouts))
+
+	_, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "nobody"}}, wallet)
+	assert.Equal(t, models.ErrorCodeNoPayoutWallet, apperr.CodeOf(err))
+	_, err = Plan(pool, nil, wallet)
+	assert.Equal(t, models.ErrorCodeResultsNotFinal, apperr.CodeOf(err))
+
+	pool.Total = "0"
+	_, err = Plan(pool, []models.ContestantResult{{Rank: 1, ContestantID: "a"}}, wallet)
+	assert.Equal(t, models.ErrorCodePrizePoolEmpty, apperr.CodeOf(err))
+}
+
+func TestRefundable(t *testing.T) {
+	pool := &models.PrizePool{Deposits: []models.PrizeDeposit{
+		{Depositor: bob, Amount: "5"},
+		{Depositor: alice, Amount: "7"},
+		{Depositor: bob, Amount: "10"},
+		{Depositor: carol, Amount: "3", Refunded: true},
+	}}
+
+	assert.Equal(t, []models.PrizeDeposit{{Depositor: bob, Amount: "15"}, {Depositor: alice, Amount: "7"}}, Refundable(pool))
+}
@@ -0,0 +1,158 @@
+package idempotency
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T, path string) *Store {
+	store, err := NewStore(path, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStore(t *testing.T) {
+	store := newTestStore(t, "")
+
+	record, err := store.Begin("k", "fp")
+	require.NoError(t, err)
+	assert.Nil(t, record, "the first request runs")
+
+	_, err = store.Begin("k", "fp")
+	assert.ErrorIs(t, err, ErrInProgress)
+	_, err = store.Begin("k", "other")
+	assert.ErrorIs(t, err, ErrConflict)
+
+	require.NoError(t, store.Finish("k", http.StatusCreated, map[string]string{"Content-Type": "application/json"}, []byte(`{"id":"1"}`)))
+	record, err = store.Begin("k", "fp")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, http.StatusCreated, record.Status)
+	assert.Equal(t, `{"id":"1"}`, string(record.Body))
+
+	// Aborted keys can be used again
+	_, err = store.Begin("aborted", "fp")
+	require.NoError(t, err)
+	require.NoError(t, store.Abort("aborted"))
+	_, err = store.Begin("aborted", "other")
+	assert.NoError(t, err)
+}
+
+func TestStoreExpiry(t *testing.T) {
+	store := newTestStore(t, "")
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Begin("k", "fp")
+	require.NoError(t, err)
+	require.NoError(t, store.Finish("k", http.StatusOK, nil, nil))
+
+	now = now.Add(2 * time.Hour)
+	record, err := store.Begin("k", "other")
+	assert.NoError(t, err, "expired keys can be reused")
+	assert.Nil(t, record)
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency")
+	store, err := NewStore(path, time.Hour)
+	require.NoError(t, err)
+	_, err = store.Begin("k", "fp")
+	require.NoError(t, err)
+	require.NoError(t, store.Finish("k", http.StatusCreated, nil, []byte("done")))
+	require.NoError(t, store.Close())
+
+	record, err := newTestStore(t, path).Begin("k", "fp")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "done", string(record.Body))
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	status := http.StatusCreated
+	handler := NewMiddleware(newTestStore(t, ""), func(r *http.Request) string { return r.RemoteAddr }).Wrap(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"id":"%d"}`, calls)
+		})
+
+	post := func(key, body, client string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/api/v1/contests", strings.NewReader(body))
+		r.RemoteAddr = client
+		if key != "" {
+			r.Header.Set(Header, key)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, r)
+		return rr
+	}
+
+	first := post("abc", `{"name":"a"}`, "1.1.1.1:1")
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replay := post("abc", `{"name":"a"}`, "1.1.1.1:1")
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get(ReplayedHeader))
+	assert.Equal(t, "application/json", replay.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls, "the retry did not run the handler")
+
+	conflict := post("abc", `{"name":"b"}`, "1.1.1.1:1")
+	assert.Equal(t, http.StatusUnprocessableEntity, conflict.Code)
+	assert.Contains(t, conflict.Body.String(), `"code":"idempotency_key_reused"`)
+
+	// Keys are scoped per client
+	assert.Equal(t, http.StatusCreated, post("abc", `{"name":"b"}`, "2.2.2.2:1").Code)
+	assert.Equal(t, 2, calls)
+
+	// Server errors are not stored, so the retry runs again
+	status = http.StatusInternalServerError
+	post("retry", `{}`, "1.1.1.1:1")
+	status = http.StatusCreated
+	assert.Equal(t, http.StatusCreated, post("retry", `{}`, "1.1.1.1:1").Code)
+	assert.Equal(t, 4, calls)
+
+	// Without a key every request runs
+	post("", `{}`, "1.1.1.1:1")
+	post("", `{}`, "1.1.1.1:1")
+	assert.Equal(t, 6, calls)
+
+	assert.Equal(t, http.StatusBadRequest, post(strings.Repeat("k", 256), `{}`, "1.1.1.1:1").Code)
+}
+
+func TestMiddlewareNeverStoresSecrets(t *testing.T) {
+	store := newTestStore(t, "")
+	calls := 0
+	handler := NewMiddleware(store, func(r *http.Request) string { return r.RemoteAddr }).Wrap(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"key":"secret-%d"}`, calls)
+		})
+
+	post := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/api/v1/admin/api-keys", strings.NewReader(`{}`))
+		r.Header.Set(Header, "abc")
+		rr := httptest.NewRecorder()
+		handler(rr, r)
+		return rr
+	}
+
+	assert.Contains(t, post().Body.String(), "secret-1")
+	rr := post()
+	assert.Contains(t, rr.Body.String(), "secret-2", "the secret was not kept for a replay")
+	assert.Empty(t, rr.Header().Get(ReplayedHeader))
+}
@@ -0,0 +1,144 @@
+package idempotency
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+)
+
+// Header carries the client-chosen idempotency key
+const Header = "Idempotency-Key"
+
+// ReplayedHeader is set on responses replayed from the store
+const ReplayedHeader = "Idempotent-Replayed"
+
+// maxKeyLength bounds the keys clients may send
+const maxKeyLength = 255
+
+// Middleware makes writes idempotent for requests carrying an Idempotency-Key
+type Middleware struct {
+	store *Store
+	scope func(*http.Request) string
+}
+
+// NewMiddleware creates the middleware. scope identifies the client, so two
+// clients choosing the same key do not see each other's results.
+func NewMiddleware(store *Store, scope func(*http.Request) string) *Middleware {
+	return &Middleware{store: store, scope: scope}
+}
+
+// Wrap runs the handler once per key and replays its response for retries
+// with the same body. Responses that may change on retry (server errors,
+// 401, 403, 409 and 429) are not stored.
+func (m *Middleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(Header)
+		if idempotencyKey == "" || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+		if len(idempotencyKey) > maxKeyLength {
+			writeError(w, http.StatusBadRequest, models.ErrorCodeInvalidIdempotencyKey, "Invalid Idempotency-Key", "keys are at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeError(w, status, "", "Invalid request body", err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := m.scope(r) + "\x00" + idempotencyKey
+		record, err := m.store.Begin(key, fingerprint(r, body))
+		switch {
+		case errors.Is(err, ErrConflict):
+			writeError(w, http.StatusUnprocessableEntity, models.ErrorCodeIdempotencyKeyReused, "Idempotency-Key reused with a different request", err.Error())
+			return
+		case errors.Is(err, ErrInProgress):
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusConflict, models.ErrorCodeIdempotencyInProgress, "Request in progress", err.Error())
+			return
+		case err != nil:
+			log.Printf("❌ Idempotency store error: %v", err)
+			writeError(w, http.StatusInternalServerError, "", "Idempotency store unavailable", err.Error())
+			return
+		case record != nil:
+			log.Printf("🔁 Replaying %s %s for idempotency key %q", r.Method, r.URL.Path, idempotencyKey)
+			for name, value := range record.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(ReplayedHeader, "true")
+			w.WriteHeader(record.Status)
+			w.Write(record.Body)
+			return
+		}
+
+		recorder := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r)
+
+		if !storable(recorder.status) || w.Header().Get("Cache-Control") == "no-store" {
+			err = m.store.Abort(key)
+		} else {
+			header := map[string]string{}
+			if contentType := w.Header().Get("Content-Type"); contentType != "" {
+				header["Content-Type"] = contentType
+			}
+			err = m.store.Finish(key, recorder.status, header, recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("❌ Failed to save idempotency record: %v", err)
+		}
+	}
+}
+
+// storable reports whether a response is final and can be replayed. Responses
+// marked Cache-Control: no-store hold secrets and are never stored either.
+func storable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// fingerprint identifies a request by its method, path and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder copies the response of a handler
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// writeError writes problem details in the API's error format
+func writeError(w http.ResponseWriter, status int, code, message, details string) {
+	apperr.Write(w, nil, status, code, message, details)
+}
@@ -0,0 +1,174 @@
+// Package idempotency replays the first result of a write retried with the
+// same Idempotency-Key, so retries never send a second transaction.
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// sweepEvery is how many requests pass between removals of expired records
+const sweepEvery = 256
+
+// Errors returned by Begin
+var (
+	ErrConflict   = errors.New("idempotency key was used with a different request")
+	ErrInProgress = errors.New("a request with this idempotency key is in progress")
+)
+
+// Record is the stored outcome of a request
+type Record struct {
+	Fingerprint string            `json:"fingerprint"` // Hash of the method, path and body
+	Done        bool              `json:"done"`        // False while the first request runs
+	Status      int               `json:"status,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// Store keeps records in an embedded LevelDB database. Records expire after
+// the TTL and are removed lazily.
+type Store struct {
+	mu    sync.Mutex
+	db    *leveldb.DB
+	ttl   time.Duration
+	calls int
+	now   func() time.Time
+}
+
+// NewStore opens the store at path, or an in-memory store if path is empty
+func NewStore(path string, ttl time.Duration) (*Store, error) {
+	var db *leveldb.DB
+	var err error
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open idempotency store: %v", err)
+	}
+
+	s := &Store{db: db, ttl: ttl, now: time.Now}
+	s.Sweep()
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Begin claims a key for a request. It returns the finished record to replay
+// if the request already ran, ErrConflict if the key was used for another
+// request and ErrInProgress if the first request is still running. A nil
+// record and error means the caller runs the request and must call Finish
+// or Abort.
+func (s *Store) Begin(key, fingerprint string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep()
+	}
+
+	record, err := s.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		switch {
+		case record.Fingerprint != fingerprint:
+			return nil, ErrConflict
+		case !record.Done:
+			return nil, ErrInProgress
+		default:
+			return record, nil
+		}
+	}
+
+	now := s.now()
+	return nil, s.put(key, &Record{Fingerprint: fingerprint, CreatedAt: now, ExpiresAt: now.Add(s.ttl)})
+}
+
+// Finish stores the outcome of a request claimed with Begin
+func (s *Store) Finish(key string, status int, header map[string]string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, err := s.get(key)
+	if err != nil || record == nil {
+		return err
+	}
+	record.Done = true
+	record.Status = status
+	record.Header = header
+	record.Body = body
+	return s.put(key, record)
+}
+
+// Abort releases a key claimed with Begin so the request can be retried
+func (s *Store) Abort(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Delete([]byte(key), nil)
+}
+
+// Sweep removes expired records
+func (s *Store) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep()
+}
+
+func (s *Store) sweep() {
+	now := s.now()
+	batch := new(leveldb.Batch)
+	iter := s.db.NewIterator(nil, nil)
+	for iter.Next() {
+		var record Record
+		if json.Unmarshal(iter.Value(), &record) != nil || now.After(record.ExpiresAt) {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+	}
+	iter.Release()
+	if batch.Len() > 0 {
+		s.db.Write(batch, nil)
+	}
+}
+
+// get returns the record of a key, nil if it does not exist or expired
+func (s *Store) get(key string) (*Record, error) {
+	data, err := s.db.Get([]byte(key), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if s.now().After(record.ExpiresAt) {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *Store) put(key string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(key), data, nil)
+}
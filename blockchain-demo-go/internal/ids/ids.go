@@ -0,0 +1,81 @@
+// Package ids generates entity IDs. Random IDs are used by default; when the
+// client sends a nonce the ID is derived from the request so the client can
+// compute it before the transaction is mined:
+//
+//	id = hex(keccak256("wikichain:v1" \n entity \n lower(creator) \n normalize(name) \n nonce))[:32]
+//
+// where normalize applies Unicode NFC, lower-cases, trims and collapses runs
+// of whitespace into a single space.
+package ids
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Entity types IDs are derived for
+const (
+	EntityContent    = "content"
+	EntityContest    = "contest"
+	EntityContestant = "contestant"
+)
+
+// scheme versions the derivation so it can change without colliding
+const scheme = "wikichain:v1"
+
+// idBytes is the length of IDs in bytes; IDs are hex encoded
+const idBytes = 16
+
+// Errors returned when deriving IDs
+var (
+	ErrInvalidNonce = apperr.New(apperr.KindValidation, models.ErrorCodeInvalidID, "nonce must be 1-64 characters of letters, digits, '-' or '_'")
+	ErrInvalidType  = apperr.New(apperr.KindValidation, models.ErrorCodeInvalidID, "unknown entity type: use content, contest or contestant")
+	ErrTaken        = apperr.New(apperr.KindConflict, models.ErrorCodeIDTaken, "id is already taken")
+)
+
+var nonceRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// New returns a random ID when nonce is empty and the derived ID otherwise
+func New(entity, creator, name, nonce string) (string, error) {
+	if nonce == "" {
+		return Random()
+	}
+	return Derive(entity, creator, name, nonce)
+}
+
+// Random returns a random ID
+func Random() (string, error) {
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Derive computes the deterministic ID of an entity
+func Derive(entity, creator, name, nonce string) (string, error) {
+	switch entity {
+	case EntityContent, EntityContest, EntityContestant:
+	default:
+		return "", ErrInvalidType
+	}
+	if !nonceRegex.MatchString(nonce) {
+		return "", ErrInvalidNonce
+	}
+
+	preimage := strings.Join([]string{scheme, entity, strings.ToLower(strings.TrimSpace(creator)), Normalize(name), nonce}, "\n")
+	return hex.EncodeToString(crypto.Keccak256([]byte(preimage))[:idBytes]), nil
+}
+
+// Normalize canonicalizes a name so that spelling variants derive the same ID
+func Normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(norm.NFC.String(name))), " ")
+}
@@ -0,0 +1,63 @@
+package ids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerive(t *testing.T) {
+	id, err := Derive(EntityContest, "0xAbC", "  Hackathon   2025 ", "n-1")
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+
+	// The same request always gives the same ID, whatever the spelling variant
+	same, err := Derive(EntityContest, "0xabc", "hackathon 2025", "n-1")
+	require.NoError(t, err)
+	assert.Equal(t, id, same)
+
+	// Precomposed and decomposed accents are the same name
+	composed, _ := Derive(EntityContent, "", "Cà phê", "x")
+	decomposed, _ := Derive(EntityContent, "", "Cà phê", "x")
+	assert.Equal(t, composed, decomposed)
+
+	// Every input changes the ID
+	for _, other := range [][4]string{
+		{EntityContent, "0xabc", "hackathon 2025", "n-1"},
+		{EntityContest, "0xdef", "hackathon 2025", "n-1"},
+		{EntityContest, "0xabc", "hackathon 2026", "n-1"},
+		{EntityContest, "0xabc", "hackathon 2025", "n-2"},
+	} {
+		different, err := Derive(other[0], other[1], other[2], other[3])
+		require.NoError(t, err)
+		assert.NotEqual(t, id, different, other)
+	}
+}
+
+func TestDeriveKnownVector(t *testing.T) {
+	// Clients reimplementing the scheme can check against this vector:
+	// keccak256("wikichain:v1\ncontest\n0xabc\nhackathon 2025\nn-1")[:16 bytes]
+	id, err := Derive(EntityContest, "0xABC", "Hackathon 2025", "n-1")
+	require.NoError(t, err)
+	assert.Equal(t, "6f1bc1d4c019bc6c277c3ee1ebae9f76", id)
+}
+
+func TestDeriveRejectsBadInput(t *testing.T) {
+	_, err := Derive("sponsor", "", "name", "n")
+	assert.ErrorIs(t, err, ErrInvalidType)
+
+	for _, nonce := range []string{"", "has space", "new\nline", string(make([]byte, 65))} {
+		_, err := Derive(EntityContest, "", "name", nonce)
+		assert.ErrorIs(t, err, ErrInvalidNonce, nonce)
+	}
+}
+
+func TestNewWithoutNonceIsRandom(t *testing.T) {
+	a, err := New(EntityContest, "", "name", "")
+	require.NoError(t, err)
+	b, err := New(EntityContest, "", "name", "")
+	require.NoError(t, err)
+	assert.Len(t, a, 32)
+	assert.NotEqual(t, a, b)
+}
@@ -0,0 +1,397 @@
+package judging
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/models"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// Errors returned by the board
+var (
+	ErrInProgress = apperr.New(apperr.KindConflict, "", "results are being finalized")
+)
+
+// Board keeps rubrics, judges and scorecards until the results of a contest
+// are finalized on-chain. Every change is written to an embedded LevelDB
+// database and loaded back on start, so judging survives restarts.
+type Board struct {
+	mu     sync.RWMutex
+	db     *leveldb.DB
+	panels map[string]*panel
+}
+
+// panel is the judging state of one contest
+type panel struct {
+	rubric     models.Rubric
+	scorecards map[string]*models.Scorecard // judge + "/" + entry -> scorecard
+	results    *models.ContestResults       // Set once finalized
+	finalizing bool
+}
+
+// record is how the panel of a contest is stored. A finalization that was
+// in flight is not: its results are read back from the chain.
+type record struct {
+	Rubric     models.Rubric          `json:"rubric"`
+	Scorecards []*models.Scorecard    `json:"scorecards"`
+	Results    *models.ContestResults `json:"results,omitempty"`
+}
+
+// NewBoard creates an empty in-memory judging board
+func NewBoard() *Board {
+	b, _ := OpenBoard("")
+	return b
+}
+
+// OpenBoard opens the judging board stored at path, or an in-memory board if
+// path is empty
+func OpenBoard(path string) (*Board, error) {
+	var db *leveldb.DB
+	var err error
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open judging board: %v", err)
+	}
+
+	b := &Board{db: db, panels: make(map[string]*panel)}
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		var rec record
+		if err := json.Unmarshal(iter.Value(), &rec); err != nil {
+			return nil, fmt.Errorf("corrupt judging panel %q: %v", iter.Key(), err)
+		}
+		p := b.panel(string(iter.Key()))
+		p.rubric = rec.Rubric
+		p.results = rec.Results
+		for _, card := range rec.Scorecards {
+			p.scorecards[card.Judge+"/"+card.ContestantID] = card
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to load judging panels: %v", err)
+	}
+	return b, nil
+}
+
+// Close closes the database
+func (b *Board) Close() error {
+	return b.db.Close()
+}
+
+// save writes the panel of a contest. The change is kept in memory even if
+// it could not be written to disk. The caller must hold the write lock.
+func (b *Board) save(contestID string) error {
+	p := b.panels[contestID]
+	data, err := json.Marshal(&record{Rubric: p.rubric, Scorecards: p.all(), Results: p.results})
+	if err != nil {
+		return err
+	}
+	return b.db.Put([]byte(contestID), data, nil)
+}
+
+// SetRubric defines the criteria and aggregation of a contest. The rubric is
+// locked once a judge has submitted scores.
+func (b *Board) SetRubric(contestID string, req *models.SetRubricRequest) (*models.Rubric, error) {
+	aggregation := req.Aggregation
+	if aggregation == "" {
+		aggregation = models.AggregationMean
+	}
+	if !ValidAggregation(aggregation) {
+		return nil, apperr.Fields([]models.FieldError{{Field: "aggregation", Rule: "oneof", Message: "Aggregation must be mean, trimmed_mean or median"}})
+	}
+	criteria, err := Criteria(req.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.panel(contestID)
+	if err := p.writable(); err != nil {
+		return nil, err
+	}
+	if len(p.scorecards) > 0 {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeRubricLocked, "rubric cannot change once judges have submitted scores")
+	}
+
+	p.rubric.Criteria = criteria
+	p.rubric.Aggregation = aggregation
+	p.rubric.UpdatedAt = time.Now()
+	return copyRubric(&p.rubric), b.save(contestID)
+}
+
+// Rubric returns a copy of the rubric of a contest
+func (b *Board) Rubric(contestID string) (*models.Rubric, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, exists := b.panels[contestID]
+	if !exists {
+		return nil, false
+	}
+	return copyRubric(&p.rubric), true
+}
+
+// AssignJudges adds judges to a contest
+func (b *Board) AssignJudges(contestID string, judges []string) (*models.Rubric, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.panel(contestID)
+	if err := p.writable(); err != nil {
+		return nil, err
+	}
+	for _, judge := range judges {
+		if judge = strings.ToLower(judge); !p.isJudge(judge) {
+			p.rubric.Judges = append(p.rubric.Judges, judge)
+		}
+	}
+	p.rubric.UpdatedAt = time.Now()
+	return copyRubric(&p.rubric), b.save(contestID)
+}
+
+// IsJudge reports whether a wallet judges a contest
+func (b *Board) IsJudge(contestID, address string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, exists := b.panels[contestID]
+	return exists && p.isJudge(strings.ToLower(address))
+}
+
+// Submit records a judge's scorecard for an entry of a contest being judged.
+// Submitting again replaces the previous scorecard.
+func (b *Board) Submit(contest *models.Contest, contestantID string, req *models.SubmitScoresRequest) (*models.Scorecard, error) {
+	if state := lifecycle.StateOf(contest); !lifecycle.AllowsJudging(state) {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState,
+			"entries can only be scored while the contest is judging, not "+state)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, exists := b.panels[contest.ID]
+	if !exists || len(p.rubric.Criteria) == 0 {
+		return nil, apperr.NotFound("contest %s has no rubric", contest.ID)
+	}
+	if err := p.writable(); err != nil {
+		return nil, err
+	}
+	judge := strings.ToLower(req.Judge)
+	if !p.isJudge(judge) {
+		return nil, apperr.New(apperr.KindForbidden, models.ErrorCodeNotAJudge, req.Judge+" does not judge this contest")
+	}
+
+	total, err := Score(&p.rubric, req.Scores)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &models.Scorecard{
+		ContestID:    contest.ID,
+		ContestantID: contestantID,
+		Judge:        judge,
+		Scores:       req.Scores,
+		Total:        round(total),
+		Comment:      req.Comment,
+		SubmittedAt:  time.Now(),
+	}
+	p.scorecards[judge+"/"+contestantID] = card
+	return copyScorecard(card), b.save(contest.ID)
+}
+
+// Scorecards returns the scorecards of a contest a wallet may see: its own
+// until the results are final, every scorecard afterwards. The organizer
+// sees every scorecard all along.
+func (b *Board) Scorecards(contestID, viewer string, organizer bool) []*models.Scorecard {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cards := make([]*models.Scorecard, 0)
+	p, exists := b.panels[contestID]
+	if !exists {
+		return cards
+	}
+	viewer = strings.ToLower(viewer)
+	for _, card := range p.scorecards {
+		if organizer || p.results != nil || (viewer != "" && card.Judge == viewer) {
+			cards = append(cards, copyScorecard(card))
+		}
+	}
+	sortScorecards(cards)
+	return cards
+}
+
+// Final reports whether the results of a contest are final
+func (b *Board) Final(contestID string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, exists := b.panels[contestID]
+	return exists && p.results != nil
+}
+
+// Results returns the final results of a contest, or provisional ones
+// computed from the scorecards so far
+func (b *Board) Results(contestID string) (*models.ContestResults, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	p, exists := b.panels[contestID]
+	if !exists {
+		return nil, false
+	}
+	if p.results != nil {
+		results := *p.results
+		return &results, true
+	}
+	return &models.ContestResults{
+		ContestID:   contestID,
+		Aggregation: p.rubric.Aggregation,
+		Results:     Compute(&p.rubric, p.all()),
+	}, true
+}
+
+// Claim computes the final results of a contest being judged so they are
+// anchored only once. The caller must call either Finalized or Release
+// afterwards.
+func (b *Board) Claim(contest *models.Contest, actor string) (*models.ContestResults, error) {
+	if state := lifecycle.StateOf(contest); !lifecycle.AllowsJudging(state) {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState,
+			"results can only be finalized while the contest is judging, not "+state)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, exists := b.panels[contest.ID]
+	if !exists || len(p.rubric.Criteria) == 0 {
+		return nil, apperr.NotFound("contest %s has no rubric", contest.ID)
+	}
+	if err := p.writable(); err != nil {
+		return nil, err
+	}
+	p.finalizing = true
+
+	now := time.Now()
+	scorecards := p.all()
+	return &models.ContestResults{
+		ContestID:   contest.ID,
+		Aggregation: p.rubric.Aggregation,
+		Results:     Compute(&p.rubric, scorecards),
+		Scorecards:  scorecards,
+		Final:       true,
+		FinalizedBy: actor,
+		FinalizedAt: &now,
+	}, nil
+}
+
+// Release gives back claimed results, e.g. when anchoring failed
+func (b *Board) Release(contestID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p, exists := b.panels[contestID]; exists {
+		p.finalizing = false
+	}
+}
+
+// Finalized records the results anchored for a claimed contest. Scores are
+// read-only from now on.
+func (b *Board) Finalized(results *models.ContestResults) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p := b.panel(results.ContestID)
+	p.finalizing = false
+	p.results = results
+	return b.save(results.ContestID)
+}
+
+// panel returns the panel of a contest, creating it if needed. The caller
+// must hold the write lock.
+func (b *Board) panel(contestID string) *panel {
+	p, exists := b.panels[contestID]
+	if !exists {
+		p = &panel{
+			rubric: models.Rubric{
+				ContestID:   contestID,
+				Criteria:    []models.Criterion{},
+				Aggregation: models.AggregationMean,
+				Judges:      []string{},
+			},
+			scorecards: make(map[string]*models.Scorecard),
+		}
+		b.panels[contestID] = p
+	}
+	return p
+}
+
+// writable returns an error once the results are final or being finalized
+func (p *panel) writable() error {
+	if p.results != nil {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeResultsFinalized, "results are final")
+	}
+	if p.finalizing {
+		return ErrInProgress
+	}
+	return nil
+}
+
+func (p *panel) isJudge(address string) bool {
+	for _, judge := range p.rubric.Judges {
+		if judge == address {
+			return true
+		}
+	}
+	return false
+}
+
+// all returns copies of every scorecard in a stable order
+func (p *panel) all() []*models.Scorecard {
+	cards := make([]*models.Scorecard, 0, len(p.scorecards))
+	for _, card := range p.scorecards {
+		cards = append(cards, copyScorecard(card))
+	}
+	sortScorecards(cards)
+	return cards
+}
+
+func sortScorecards(cards []*models.Scorecard) {
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].ContestantID != cards[j].ContestantID {
+			return cards[i].ContestantID < cards[j].ContestantID
+		}
+		return cards[i].Judge < cards[j].Judge
+	})
+}
+
+func copyRubric(rubric *models.Rubric) *models.Rubric {
+	copied := *rubric
+	copied.Criteria = append([]models.Criterion{}, rubric.Criteria...)
+	copied.Judges = append([]string{}, rubric.Judges...)
+	return &copied
+}
+
+func copyScorecard(card *models.Scorecard) *models.Scorecard {
+	copied := *card
+	copied.Scores = make(map[string]float64, len(card.Scores))
+	for name, score := range card.Scores {
+		copied.Scores[name] = score
+	}
+	return &copied
+}
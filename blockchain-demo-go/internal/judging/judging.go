@@ -0,0 +1,181 @@
+// Package judging scores contest entries against a rubric.
+//
+// Organizers define the criteria of a contest and assign judges. While the
+// contest is being judged, each judge submits one scorecard per registered
+// entry; a scorecard is only visible to its judge and the organizer. When
+// the organizer finalizes, the scorecards are aggregated per entry (mean,
+// trimmed mean or median), ranked, and anchored on-chain together, after
+// which every scorecard is public and nothing can change.
+package judging
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidAggregation reports whether method is a known aggregation
+func ValidAggregation(method string) bool {
+	switch method {
+	case models.AggregationMean, models.AggregationTrimmedMean, models.AggregationMedian:
+		return true
+	}
+	return false
+}
+
+// Aggregate combines scores with method. The trimmed mean drops the lowest
+// and highest score when there are at least three.
+func Aggregate(method string, scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+
+	switch method {
+	case models.AggregationMedian:
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 1 {
+			return sorted[middle]
+		}
+		return (sorted[middle-1] + sorted[middle]) / 2
+	case models.AggregationTrimmedMean:
+		if len(sorted) >= 3 {
+			sorted = sorted[1 : len(sorted)-1]
+		}
+	}
+
+	sum := 0.0
+	for _, score := range sorted {
+		sum += score
+	}
+	return sum / float64(len(sorted))
+}
+
+// Criteria converts and checks the criteria of a rubric request. Names must
+// be unique, ignoring case; weights default to 1.
+func Criteria(req []models.CriterionRequest) ([]models.Criterion, error) {
+	criteria := make([]models.Criterion, 0, len(req))
+	seen := make(map[string]bool)
+	var fields []models.FieldError
+	for i, c := range req {
+		name := strings.TrimSpace(c.Name)
+		if seen[strings.ToLower(name)] {
+			fields = append(fields, models.FieldError{
+				Field:   fmt.Sprintf("criteria[%d].name", i),
+				Rule:    "unique",
+				Message: "Criterion " + name + " is listed twice",
+			})
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		weight := c.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		criteria = append(criteria, models.Criterion{
+			Name:        name,
+			Description: c.Description,
+			Weight:      weight,
+			MaxScore:    c.MaxScore,
+		})
+	}
+	if len(fields) > 0 {
+		return nil, apperr.Fields(fields)
+	}
+	return criteria, nil
+}
+
+// Score checks the scores of a scorecard against a rubric and returns their
+// weighted sum. Every criterion must be scored between 0 and its maximum.
+func Score(rubric *models.Rubric, scores map[string]float64) (float64, error) {
+	var fields []models.FieldError
+	total := 0.0
+	for _, criterion := range rubric.Criteria {
+		score, ok := scores[criterion.Name]
+		switch {
+		case !ok:
+			fields = append(fields, models.FieldError{Field: "scores." + criterion.Name, Rule: "required", Message: criterion.Name + " is required"})
+		case score < 0 || score > criterion.MaxScore || math.IsNaN(score):
+			fields = append(fields, models.FieldError{Field: "scores." + criterion.Name, Rule: "range",
+				Message: fmt.Sprintf("%s must be between 0 and %g", criterion.Name, criterion.MaxScore)})
+		default:
+			total += criterion.Weight * score
+		}
+	}
+	for name := range scores {
+		if !hasCriterion(rubric, name) {
+			fields = append(fields, models.FieldError{Field: "scores." + name, Rule: "unknown", Message: name + " is not a criterion of the rubric"})
+		}
+	}
+	if len(fields) > 0 {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return 0, apperr.Fields(fields)
+	}
+	return total, nil
+}
+
+// Compute aggregates the scorecards of a contest per entry and ranks the
+// entries by score. Entries with the same score share a rank.
+func Compute(rubric *models.Rubric, scorecards []*models.Scorecard) []models.ContestantResult {
+	byEntry := make(map[string][]*models.Scorecard)
+	for _, card := range scorecards {
+		byEntry[card.ContestantID] = append(byEntry[card.ContestantID], card)
+	}
+
+	results := make([]models.ContestantResult, 0, len(byEntry))
+	for entry, cards := range byEntry {
+		totals := make([]float64, 0, len(cards))
+		perCriterion := make(map[string][]float64)
+		for _, card := range cards {
+			totals = append(totals, card.Total)
+			for name, score := range card.Scores {
+				perCriterion[name] = append(perCriterion[name], score)
+			}
+		}
+
+		result := models.ContestantResult{
+			ContestantID: entry,
+			Score:        round(Aggregate(rubric.Aggregation, totals)),
+			Criteria:     make(map[string]float64, len(perCriterion)),
+			Judges:       len(cards),
+		}
+		for name, scores := range perCriterion {
+			result.Criteria[name] = round(Aggregate(rubric.Aggregation, scores))
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ContestantID < results[j].ContestantID
+	})
+	for i := range results {
+		if i > 0 && results[i].Score == results[i-1].Score {
+			results[i].Rank = results[i-1].Rank
+		} else {
+			results[i].Rank = i + 1
+		}
+	}
+	return results
+}
+
+func hasCriterion(rubric *models.Rubric, name string) bool {
+	for _, criterion := range rubric.Criteria {
+		if criterion.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// round keeps four decimals so aggregates read the same everywhere
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,170 @@
+package judging
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	judgeA = "0x1111111111111111111111111111111111111111"
+	judgeB = "0x2222222222222222222222222222222222222222"
+	judgeC = "0x3333333333333333333333333333333333333333"
+)
+
+func TestAggregate(t *testing.T) {
+	scores := []float64{9, 1, 7, 8}
+	assert.Equal(t, 6.25, Aggregate(models.AggregationMean, scores))
+	assert.Equal(t, 7.5, Aggregate(models.AggregationTrimmedMean, scores))
+	assert.Equal(t, 7.5, Aggregate(models.AggregationMedian, scores))
+	assert.Equal(t, 7.0, Aggregate(models.AggregationMedian, []float64{9, 1, 7}))
+
+	// Too few scores to trim
+	assert.Equal(t, 5.0, Aggregate(models.AggregationTrimmedMean, []float64{9, 1}))
+	assert.Equal(t, 0.0, Aggregate(models.AggregationMean, nil))
+	assert.Equal(t, []float64{9, 1, 7, 8}, scores)
+}
+
+func TestCriteria(t *testing.T) {
+	criteria, err := Criteria([]models.CriterionRequest{{Name: " Design ", MaxScore: 10}, {Name: "Fun", Weight: 2, MaxScore: 5}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Criterion{{Name: "Design", Weight: 1, MaxScore: 10}, {Name: "Fun", Weight: 2, MaxScore: 5}}, criteria)
+
+	_, err = Criteria([]models.CriterionRequest{{Name: "Fun", MaxScore: 10}, {Name: "fun", MaxScore: 5}})
+	assert.Equal(t, "criteria[1].name", apperr.FieldsOf(err)[0].Field)
+}
+
+func TestScore(t *testing.T) {
+	rubric := &models.Rubric{Criteria: []models.Criterion{{Name: "Design", Weight: 1, MaxScore: 10}, {Name: "Fun", Weight: 2, MaxScore: 5}}}
+
+	total, err := Score(rubric, map[string]float64{"Design": 8, "Fun": 4})
+	require.NoError(t, err)
+	assert.Equal(t, 16.0, total)
+
+	_, err = Score(rubric, map[string]float64{"Design": 11, "Luck": 1})
+	fields := apperr.FieldsOf(err)
+	require.Len(t, fields, 3)
+	assert.Equal(t, "scores.Design", fields[0].Field)
+	assert.Equal(t, "range", fields[0].Rule)
+	assert.Equal(t, "scores.Fun", fields[1].Field)
+	assert.Equal(t, "required", fields[1].Rule)
+	assert.Equal(t, "scores.Luck", fields[2].Field)
+}
+
+func TestCompute(t *testing.T) {
+	rubric := &models.Rubric{Aggregation: models.AggregationMedian}
+	results := Compute(rubric, []*models.Scorecard{
+		{ContestantID: "b", Judge: judgeA, Total: 8, Scores: map[string]float64{"Fun": 8}},
+		{ContestantID: "a", Judge: judgeA, Total: 4, Scores: map[string]float64{"Fun": 4}},
+		{ContestantID: "a", Judge: judgeB, Total: 8, Scores: map[string]float64{"Fun": 8}},
+		{ContestantID: "a", Judge: judgeC, Total: 9, Scores: map[string]float64{"Fun": 9}},
+		{ContestantID: "c", Judge: judgeA, Total: 5, Scores: map[string]float64{"Fun": 5}},
+	})
+	require.Len(t, results, 3)
+
+	// a and b tie on the median and share first place
+	assert.Equal(t, models.ContestantResult{Rank: 1, ContestantID: "a", Score: 8, Criteria: map[string]float64{"Fun": 8}, Judges: 3}, results[0])
+	assert.Equal(t, 1, results[1].Rank)
+	assert.Equal(t, "b", results[1].ContestantID)
+	assert.Equal(t, 3, results[2].Rank)
+}
+
+func TestBoard(t *testing.T) {
+	board := NewBoard()
+	contest := &models.Contest{ID: "c1", State: models.ContestRunning}
+	scores := &models.SubmitScoresRequest{Judge: judgeA, Scores: map[string]float64{"Design": 7}}
+
+	_, err := board.SetRubric("c1", &models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}, Aggregation: "best"})
+	assert.Equal(t, "aggregation", apperr.FieldsOf(err)[0].Field)
+
+	rubric, err := board.SetRubric("c1", &models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}})
+	require.NoError(t, err)
+	assert.Equal(t, models.AggregationMean, rubric.Aggregation)
+
+	rubric, err = board.AssignJudges("c1", []string{judgeA, judgeB, judgeA})
+	require.NoError(t, err)
+	assert.Equal(t, []string{judgeA, judgeB}, rubric.Judges)
+	assert.True(t, board.IsJudge("c1", judgeB))
+
+	// Scores are only accepted while the contest is judging, from its judges
+	_, err = board.Submit(contest, "alice", scores)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(err))
+	contest.State = models.ContestJudging
+	_, err = board.Submit(contest, "alice", &models.SubmitScoresRequest{Judge: judgeC, Scores: scores.Scores})
+	assert.Equal(t, models.ErrorCodeNotAJudge, apperr.CodeOf(err))
+	_, err = board.Submit(&models.Contest{ID: "c2", State: models.ContestJudging}, "alice", scores)
+	assert.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+
+	_, err = board.Submit(contest, "alice", scores)
+	require.NoError(t, err)
+	_, err = board.Submit(contest, "alice", &models.SubmitScoresRequest{Judge: judgeB, Scores: map[string]float64{"Design": 9}})
+	require.NoError(t, err)
+
+	// Judges only see their own scorecards until the results are final
+	mine := board.Scorecards("c1", judgeA, false)
+	require.Len(t, mine, 1)
+	assert.Equal(t, 7.0, mine[0].Total)
+	assert.Empty(t, board.Scorecards("c1", "", false))
+	assert.Len(t, board.Scorecards("c1", "", true), 2)
+
+	_, err = board.SetRubric("c1", &models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Fun", MaxScore: 10}}})
+	assert.Equal(t, models.ErrorCodeRubricLocked, apperr.CodeOf(err))
+
+	provisional, exists := board.Results("c1")
+	require.True(t, exists)
+	assert.False(t, provisional.Final)
+	assert.Empty(t, provisional.Scorecards)
+	assert.Equal(t, 8.0, provisional.Results[0].Score)
+
+	results, err := board.Claim(contest, judgeA)
+	require.NoError(t, err)
+	assert.True(t, results.Final)
+	assert.Len(t, results.Scorecards, 2)
+	_, err = board.Claim(contest, judgeA)
+	assert.Equal(t, ErrInProgress, err)
+	_, err = board.Submit(contest, "alice", scores)
+	assert.Equal(t, ErrInProgress, err)
+
+	assert.False(t, board.Final("c1"))
+	require.NoError(t, board.Finalized(results))
+	assert.True(t, board.Final("c1"))
+	assert.Len(t, board.Scorecards("c1", "", false), 2)
+	_, err = board.Submit(contest, "alice", scores)
+	assert.Equal(t, models.ErrorCodeResultsFinalized, apperr.CodeOf(err))
+	final, _ := board.Results("c1")
+	assert.True(t, final.Final)
+}
+
+func TestBoardSurvivesRestart(t *testing.T) {
+	path := t.TempDir()
+	board, err := OpenBoard(path)
+	require.NoError(t, err)
+	contest := &models.Contest{ID: "c1", State: models.ContestJudging}
+
+	_, err = board.SetRubric("c1", &models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Design", MaxScore: 10}}, Aggregation: models.AggregationMedian})
+	require.NoError(t, err)
+	_, err = board.AssignJudges("c1", []string{judgeA})
+	require.NoError(t, err)
+	_, err = board.Submit(contest, "alice", &models.SubmitScoresRequest{Judge: judgeA, Scores: map[string]float64{"Design": 7}})
+	require.NoError(t, err)
+	require.NoError(t, board.Close())
+
+	board, err = OpenBoard(path)
+	require.NoError(t, err)
+	defer board.Close()
+	rubric, exists := board.Rubric("c1")
+	require.True(t, exists)
+	assert.Equal(t, models.AggregationMedian, rubric.Aggregation)
+	assert.True(t, board.IsJudge("c1", judgeA))
+	mine := board.Scorecards("c1", judgeA, false)
+	require.Len(t, mine, 1)
+	assert.Equal(t, 7.0, mine[0].Total)
+
+	// The rubric stays locked by the scorecard read back
+	_, err = board.SetRubric("c1", &models.SetRubricRequest{Criteria: []models.CriterionRequest{{Name: "Fun", MaxScore: 10}}})
+	assert.Equal(t, models.ErrorCodeRubricLocked, apperr.CodeOf(err))
+}
@@ -0,0 +1,274 @@
+// Package leaderboard ranks the contestants of each contest live, as wallets
+// reveal their votes, contestants join or leave and the judges' results are
+// finalized, and tells subscribers when a ranking changes.
+//
+// Entries are ordered by score (highest first), then verified votes (most
+// first), then by who reached their current score and votes first, then by
+// contestant ID. Each contest keeps its entries sorted, so an update moves
+// one entry with two binary searches instead of sorting the whole board.
+package leaderboard
+
+import (
+	"blockchain-demo/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// tieBreak lists the rules entries are ordered by
+var tieBreak = []string{models.TieBreakScore, models.TieBreakVotes, models.TieBreakEarlier, models.TieBreakID}
+
+// Hub keeps the leaderboards of contests in memory. A leaderboard is loaded
+// from the scores and votes of its contest on first use; updates for a
+// contest that is not loaded yet are dropped, since loading reads them anyway.
+type Hub struct {
+	mu     sync.RWMutex
+	boards map[string]*board
+}
+
+// board is the ranking of one contest
+type board struct {
+	entries     []*models.LeaderboardEntry // Sorted by less
+	index       map[string]*models.LeaderboardEntry
+	version     uint64
+	updatedAt   time.Time
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewHub creates an empty leaderboard hub
+func NewHub() *Hub {
+	return &Hub{boards: make(map[string]*board)}
+}
+
+// Loaded reports whether the leaderboard of a contest is in memory
+func (h *Hub) Loaded(contestID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	b, exists := h.boards[contestID]
+	return exists && b.index != nil
+}
+
+// Load fills the leaderboard of a contest unless it is already loaded
+func (h *Hub) Load(contestID string, entries []models.LeaderboardEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b := h.board(contestID)
+	if b.index != nil {
+		return
+	}
+	now := time.Now()
+	b.index = make(map[string]*models.LeaderboardEntry, len(entries))
+	for i := range entries {
+		entry := entries[i]
+		if entry.UpdatedAt.IsZero() {
+			entry.UpdatedAt = now
+		}
+		b.index[entry.ContestantID] = &entry
+		b.entries = append(b.entries, &entry)
+	}
+	sort.Slice(b.entries, func(i, j int) bool { return less(b.entries[i], b.entries[j]) })
+	b.changed(now)
+}
+
+// SetScore records the aggregate of an entry's scorecards
+func (h *Hub) SetScore(contestID string, result models.ContestantResult) {
+	h.update(contestID, result.ContestantID, func(entry *models.LeaderboardEntry) bool {
+		if entry.Score == result.Score && entry.Judges == result.Judges && sameCriteria(entry.Criteria, result.Criteria) {
+			return false
+		}
+		entry.Score = result.Score
+		entry.Criteria = result.Criteria
+		entry.Judges = result.Judges
+		return true
+	})
+}
+
+// AddVote counts a verified vote for an entry
+func (h *Hub) AddVote(contestID, contestantID string) {
+	h.update(contestID, contestantID, func(entry *models.LeaderboardEntry) bool {
+		entry.Votes++
+		return true
+	})
+}
+
+// Register puts an entry that joined the roster on the leaderboard, with the
+// votes it already has
+func (h *Hub) Register(contestID, contestantID string, votes int) {
+	h.update(contestID, contestantID, func(entry *models.LeaderboardEntry) bool {
+		changed := entry.Votes != votes
+		entry.Votes = votes
+		return changed
+	})
+}
+
+// Withdraw takes an entry that left the roster off the leaderboard
+func (h *Hub) Withdraw(contestID, contestantID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, exists := h.boards[contestID]
+	if !exists || b.index == nil {
+		return
+	}
+	entry, exists := b.index[contestantID]
+	if !exists {
+		return
+	}
+	b.remove(entry)
+	delete(b.index, contestantID)
+	b.changed(time.Now())
+}
+
+// Snapshot returns the leaderboard of a contest with at most limit entries,
+// every entry if limit is 0
+func (h *Hub) Snapshot(contestID string, limit int) (*models.Leaderboard, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	b, exists := h.boards[contestID]
+	if !exists || b.index == nil {
+		return nil, false
+	}
+	count := len(b.entries)
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	leaderboard := &models.Leaderboard{
+		ContestID: contestID,
+		Version:   b.version,
+		TieBreak:  append([]string{}, tieBreak...),
+		Entries:   make([]models.LeaderboardEntry, count),
+		Total:     len(b.entries),
+		UpdatedAt: b.updatedAt,
+	}
+	for i := 0; i < count; i++ {
+		entry := *b.entries[i]
+		entry.Rank = i + 1
+		entry.Tied = i > 0 && entry.Score == b.entries[i-1].Score && entry.Votes == b.entries[i-1].Votes
+		entry.Criteria = copyCriteria(entry.Criteria)
+		leaderboard.Entries[i] = entry
+	}
+	return leaderboard, true
+}
+
+// Subscribe returns a channel that receives a value whenever the leaderboard
+// of a contest changes, and a function that cancels the subscription.
+// Changes made while the subscriber is busy are coalesced into one.
+func (h *Hub) Subscribe(contestID string) (<-chan struct{}, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make(chan struct{}, 1)
+	h.board(contestID).subscribers[updates] = struct{}{}
+	return updates, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.boards[contestID].subscribers, updates)
+	}
+}
+
+// update applies change to an entry of a loaded leaderboard, adding the
+// entry if needed, and moves it to its new place
+func (h *Hub) update(contestID, contestantID string, change func(entry *models.LeaderboardEntry) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, exists := h.boards[contestID]
+	if !exists || b.index == nil {
+		return
+	}
+	entry, exists := b.index[contestantID]
+	if exists {
+		b.remove(entry)
+	} else {
+		entry = &models.LeaderboardEntry{ContestantID: contestantID}
+		b.index[contestantID] = entry
+	}
+	if !change(entry) && exists {
+		b.insert(entry)
+		return
+	}
+	now := time.Now()
+	entry.UpdatedAt = now
+	b.insert(entry)
+	b.changed(now)
+}
+
+// board returns the board of a contest, creating it if needed. The caller
+// must hold the write lock.
+func (h *Hub) board(contestID string) *board {
+	b, exists := h.boards[contestID]
+	if !exists {
+		b = &board{subscribers: make(map[chan struct{}]struct{})}
+		h.boards[contestID] = b
+	}
+	return b
+}
+
+// remove takes an entry out of the sorted entries
+func (b *board) remove(entry *models.LeaderboardEntry) {
+	i := sort.Search(len(b.entries), func(i int) bool { return !less(b.entries[i], entry) })
+	if i < len(b.entries) && b.entries[i] == entry {
+		b.entries = append(b.entries[:i], b.entries[i+1:]...)
+	}
+}
+
+// insert puts an entry at its place in the sorted entries
+func (b *board) insert(entry *models.LeaderboardEntry) {
+	i := sort.Search(len(b.entries), func(i int) bool { return less(entry, b.entries[i]) })
+	b.entries = append(b.entries, nil)
+	copy(b.entries[i+1:], b.entries[i:])
+	b.entries[i] = entry
+}
+
+// changed bumps the version and wakes the subscribers
+func (b *board) changed(now time.Time) {
+	b.version++
+	b.updatedAt = now
+	for updates := range b.subscribers {
+		select {
+		case updates <- struct{}{}:
+		default: // Already notified
+		}
+	}
+}
+
+// less reports whether a ranks above b
+func less(a, b *models.LeaderboardEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Votes != b.Votes {
+		return a.Votes > b.Votes
+	}
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	}
+	return a.ContestantID < b.ContestantID
+}
+
+func sameCriteria(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, score := range a {
+		if other, exists := b[name]; !exists || other != score {
+			return false
+		}
+	}
+	return true
+}
+
+func copyCriteria(criteria map[string]float64) map[string]float64 {
+	if criteria == nil {
+		return nil
+	}
+	copied := make(map[string]float64, len(criteria))
+	for name, score := range criteria {
+		copied[name] = score
+	}
+	return copied
+}
@@ -0,0 +1,142 @@
+package leaderboard
+
+import (
+	"blockchain-demo/internal/models"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(board *models.Leaderboard) []string {
+	ids := make([]string, 0, len(board.Entries))
+	for _, entry := range board.Entries {
+		ids = append(ids, entry.ContestantID)
+	}
+	return ids
+}
+
+func TestRankingAndTieBreaks(t *testing.T) {
+	hub := NewHub()
+	hub.SetScore("c1", models.ContestantResult{ContestantID: "ignored", Score: 9, Judges: 1})
+	assert.False(t, hub.Loaded("c1"))
+
+	start := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	hub.Load("c1", []models.LeaderboardEntry{
+		{ContestantID: "carol", UpdatedAt: start},
+		{ContestantID: "bob", Score: 7, Judges: 1, UpdatedAt: start},
+		{ContestantID: "alice", Score: 7, Judges: 1, UpdatedAt: start},
+		{ContestantID: "dave", Votes: 2, UpdatedAt: start},
+	})
+	require.True(t, hub.Loaded("c1"))
+
+	board, exists := hub.Snapshot("c1", 0)
+	require.True(t, exists)
+	assert.Equal(t, []string{"alice", "bob", "dave", "carol"}, ids(board))
+	assert.Equal(t, []string{"score", "votes", "earliest", "contestant_id"}, board.TieBreak)
+	assert.True(t, board.Entries[1].Tied)
+	assert.Equal(t, 2, board.Entries[1].Rank)
+	assert.False(t, board.Entries[2].Tied)
+
+	// Votes break equal scores; reaching a standing first breaks the rest
+	hub.AddVote("c1", "bob")
+	board, _ = hub.Snapshot("c1", 0)
+	assert.Equal(t, []string{"bob", "alice", "dave", "carol"}, ids(board))
+	hub.AddVote("c1", "alice")
+	board, _ = hub.Snapshot("c1", 0)
+	assert.Equal(t, []string{"bob", "alice", "dave", "carol"}, ids(board))
+	assert.True(t, board.Entries[1].Tied)
+
+	hub.SetScore("c1", models.ContestantResult{ContestantID: "carol", Score: 8.5, Criteria: map[string]float64{"Fun": 8.5}, Judges: 2})
+	hub.SetScore("c1", models.ContestantResult{ContestantID: "erin", Score: 1, Judges: 1})
+	board, _ = hub.Snapshot("c1", 2)
+	assert.Equal(t, []string{"carol", "bob"}, ids(board))
+	assert.Equal(t, 5, board.Total)
+	assert.Equal(t, map[string]float64{"Fun": 8.5}, board.Entries[0].Criteria)
+
+	// Loading again keeps the live ranking
+	hub.Load("c1", nil)
+	again, _ := hub.Snapshot("c1", 0)
+	assert.Equal(t, 5, again.Total)
+}
+
+func TestRosterChanges(t *testing.T) {
+	hub := NewHub()
+	hub.Register("c1", "ignored", 1)
+	hub.Load("c1", []models.LeaderboardEntry{{ContestantID: "alice", Votes: 1}, {ContestantID: "bob"}})
+
+	// Entries that join come with their votes; entries that leave are dropped
+	hub.Register("c1", "carol", 2)
+	hub.Withdraw("c1", "alice")
+	hub.Withdraw("c1", "nobody")
+	board, _ := hub.Snapshot("c1", 0)
+	assert.Equal(t, []string{"carol", "bob"}, ids(board))
+	assert.Equal(t, 2, board.Entries[0].Votes)
+	assert.Equal(t, uint64(3), board.Version)
+
+	// Registering an entry already on the board only resyncs its votes
+	hub.Register("c1", "carol", 2)
+	again, _ := hub.Snapshot("c1", 0)
+	assert.Equal(t, board.Version, again.Version)
+}
+
+func TestSortedAfterManyUpdates(t *testing.T) {
+	hub := NewHub()
+	hub.Load("c1", nil)
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		id := fmt.Sprintf("entry-%d", random.Intn(40))
+		if random.Intn(2) == 0 {
+			hub.AddVote("c1", id)
+		} else {
+			hub.SetScore("c1", models.ContestantResult{ContestantID: id, Score: float64(random.Intn(10)), Judges: 1})
+		}
+	}
+
+	board, _ := hub.Snapshot("c1", 0)
+	require.Equal(t, 40, board.Total)
+	assert.True(t, sort.SliceIsSorted(board.Entries, func(i, j int) bool {
+		return less(&board.Entries[i], &board.Entries[j])
+	}))
+}
+
+func TestSubscribe(t *testing.T) {
+	hub := NewHub()
+	updates, cancel := hub.Subscribe("c1")
+
+	hub.Load("c1", []models.LeaderboardEntry{{ContestantID: "alice"}})
+	hub.AddVote("c1", "alice")
+	hub.AddVote("c1", "alice")
+
+	// Changes are coalesced while the subscriber is busy
+	<-updates
+	select {
+	case <-updates:
+		t.Fatal("expected one coalesced notification")
+	default:
+	}
+	board, _ := hub.Snapshot("c1", 0)
+	assert.Equal(t, uint64(3), board.Version)
+
+	// Scores that did not change do not wake anyone
+	hub.SetScore("c1", models.ContestantResult{ContestantID: "alice", Score: 5, Judges: 1})
+	<-updates
+	hub.SetScore("c1", models.ContestantResult{ContestantID: "alice", Score: 5, Judges: 1})
+	select {
+	case <-updates:
+		t.Fatal("unchanged score should not notify")
+	default:
+	}
+
+	cancel()
+	hub.AddVote("c1", "alice")
+	select {
+	case <-updates:
+		t.Fatal("cancelled subscription should not be notified")
+	default:
+	}
+}
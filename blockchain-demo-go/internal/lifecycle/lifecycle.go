@@ -0,0 +1,144 @@
+// Package lifecycle defines the states a contest goes through and the moves
+// allowed between them:
+//
+//	draft    -> open, cancelled
+//	open     -> running, cancelled
+//	running  -> judging, closed, cancelled
+//	judging  -> closed, cancelled
+//
+// Organizers open drafts, close and cancel contests. The scheduler moves open
+// contests to running at their start date and running contests to judging at
+// their end date. Closed and cancelled are final.
+package lifecycle
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"time"
+)
+
+// transitions lists the states each state can move to
+var transitions = map[string][]string{
+	models.ContestDraft:   {models.ContestOpen, models.ContestCancelled},
+	models.ContestOpen:    {models.ContestRunning, models.ContestCancelled},
+	models.ContestRunning: {models.ContestJudging, models.ContestClosed, models.ContestCancelled},
+	models.ContestJudging: {models.ContestClosed, models.ContestCancelled},
+}
+
+// Valid reports whether state is a known lifecycle state
+func Valid(state string) bool {
+	switch state {
+	case models.ContestDraft, models.ContestOpen, models.ContestRunning,
+		models.ContestJudging, models.ContestClosed, models.ContestCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransition reports whether a contest may move from one state to another
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Final reports whether no transition leaves state
+func Final(state string) bool {
+	return state == models.ContestClosed || state == models.ContestCancelled
+}
+
+// Active reports whether a contest in state is under way, which is what the
+// legacy active flag means
+func Active(state string) bool {
+	return state == models.ContestOpen || state == models.ContestRunning || state == models.ContestJudging
+}
+
+// AllowsRegistration reports whether contestants may register in state
+func AllowsRegistration(state string) bool {
+	return state == models.ContestOpen || state == models.ContestRunning
+}
+
+// AllowsWithdrawal reports whether contestants may leave, or be removed
+// from, a contest in state
+func AllowsWithdrawal(state string) bool {
+	return Active(state)
+}
+
+// AllowsJudging reports whether judges may score entries in state
+func AllowsJudging(state string) bool {
+	return state == models.ContestJudging
+}
+
+// StateOf returns the state of a contest. Contests stored before lifecycle
+// states existed have none and are treated by their active flag.
+func StateOf(contest *models.Contest) string {
+	if contest.State != "" {
+		return contest.State
+	}
+	if contest.Active {
+		return models.ContestOpen
+	}
+	return models.ContestClosed
+}
+
+// Transition moves a contest to a new state, or returns a conflict error with
+// code invalid_contest_state if the move is not allowed
+func Transition(contest *models.Contest, to, reason, actor string, now time.Time) (*models.ContestTransition, error) {
+	from := StateOf(contest)
+	if err := Check(from, to); err != nil {
+		return nil, err
+	}
+
+	contest.State = to
+	contest.Active = Active(to)
+	return &models.ContestTransition{
+		ContestID: contest.ID,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		Actor:     actor,
+		At:        now,
+	}, nil
+}
+
+// Check returns a conflict error with code invalid_contest_state unless a
+// contest may move from one state to another
+func Check(from, to string) error {
+	if !Valid(to) {
+		return apperr.Validation("unknown contest state %q", to)
+	}
+	if !CanTransition(from, to) {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState,
+			"contest cannot move from "+from+" to "+to)
+	}
+	return nil
+}
+
+// RequireRegistration returns a conflict error unless contestants may
+// register in state
+func RequireRegistration(state string) error {
+	if !AllowsRegistration(state) {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState,
+			"registration is closed while the contest is "+state)
+	}
+	return nil
+}
+
+// Due returns the state the scheduler should move a contest to at now, empty
+// if none. A contest past both dates moves one state per call.
+func Due(contest *models.Contest, now time.Time) string {
+	switch StateOf(contest) {
+	case models.ContestOpen:
+		if !contest.StartDate.IsZero() && !now.Before(contest.StartDate) {
+			return models.ContestRunning
+		}
+	case models.ContestRunning:
+		if !contest.EndDate.IsZero() && !now.Before(contest.EndDate) {
+			return models.ContestJudging
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransition(t *testing.T) {
+	now := time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)
+	contest := &models.Contest{ID: "c1", State: models.ContestDraft}
+
+	transition, err := Transition(contest, models.ContestOpen, "ready", "0xabc", now)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ContestTransition{ContestID: "c1", From: models.ContestDraft, To: models.ContestOpen, Reason: "ready", Actor: "0xabc", At: now}, transition)
+	assert.Equal(t, models.ContestOpen, contest.State)
+	assert.True(t, contest.Active)
+
+	// Skipping states is not allowed
+	_, err = Transition(contest, models.ContestJudging, "", "", now)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, apperr.StatusOf(err))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(err))
+	assert.Equal(t, models.ContestOpen, contest.State)
+
+	_, err = Transition(contest, models.ContestCancelled, "", "", now)
+	require.NoError(t, err)
+	assert.False(t, contest.Active)
+
+	// Final states never move
+	for _, to := range []string{models.ContestOpen, models.ContestClosed, models.ContestCancelled} {
+		_, err = Transition(contest, to, "", "", now)
+		assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(err), to)
+	}
+
+	_, err = Transition(&models.Contest{State: models.ContestOpen}, "paused", "", "", now)
+	assert.Equal(t, apperr.KindValidation, apperr.KindOf(err))
+}
+
+func TestStateOf(t *testing.T) {
+	assert.Equal(t, models.ContestOpen, StateOf(&models.Contest{Active: true}))
+	assert.Equal(t, models.ContestClosed, StateOf(&models.Contest{}))
+	assert.Equal(t, models.ContestJudging, StateOf(&models.Contest{State: models.ContestJudging}))
+
+	assert.True(t, AllowsRegistration(models.ContestOpen))
+	assert.True(t, AllowsRegistration(models.ContestRunning))
+	assert.False(t, AllowsRegistration(models.ContestDraft))
+	assert.False(t, AllowsRegistration(models.ContestJudging))
+	assert.NoError(t, RequireRegistration(models.ContestRunning))
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(RequireRegistration(models.ContestClosed)))
+}
+
+func TestDue(t *testing.T) {
+	start := time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)
+	contest := &models.Contest{State: models.ContestOpen, StartDate: start, EndDate: start.Add(24 * time.Hour)}
+
+	assert.Empty(t, Due(contest, start.Add(-time.Second)))
+	assert.Equal(t, models.ContestRunning, Due(contest, start))
+
+	contest.State = models.ContestRunning
+	assert.Empty(t, Due(contest, start.Add(time.Hour)))
+	assert.Equal(t, models.ContestJudging, Due(contest, contest.EndDate))
+
+	// Judging ends, and drafts open, only when an organizer says so
+	contest.State = models.ContestJudging
+	assert.Empty(t, Due(contest, contest.EndDate.Add(time.Hour)))
+	contest.State = models.ContestDraft
+	assert.Empty(t, Due(contest, contest.EndDate.Add(time.Hour)))
+}
+
+// fakeContests stores contests in memory like the mock service
+type fakeContests struct {
+	contests map[string]*models.Contest
+	fail     string
+	actors   []string
+}
+
+func (f *fakeContests) GetAllContests() (*models.ListContestsResponse, error) {
+	response := &models.ListContestsResponse{Success: true}
+	for _, contest := range f.contests {
+		copied := *contest
+		response.Data = append(response.Data, &copied)
+	}
+	return response, nil
+}
+
+func (f *fakeContests) TransitionContest(id, to, reason, actor string) (*models.ContestTransitionResponse, error) {
+	if id == f.fail {
+		return nil, errors.New("node down")
+	}
+	transition, err := Transition(f.contests[id], to, reason, actor, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	f.actors = append(f.actors, actor)
+	return &models.ContestTransitionResponse{Success: true, Data: transition}, nil
+}
+
+func TestSchedulerTick(t *testing.T) {
+	now := time.Date(2025, 7, 5, 12, 0, 0, 0, time.UTC)
+	fake := &fakeContests{contests: map[string]*models.Contest{
+		"started": {ID: "started", State: models.ContestOpen, StartDate: now.Add(-time.Hour), EndDate: now.Add(time.Hour)},
+		"ended":   {ID: "ended", State: models.ContestOpen, StartDate: now.Add(-48 * time.Hour), EndDate: now.Add(-24 * time.Hour)},
+		"future":  {ID: "future", State: models.ContestOpen, StartDate: now.Add(time.Hour), EndDate: now.Add(2 * time.Hour)},
+		"draft":   {ID: "draft", State: models.ContestDraft, StartDate: now.Add(-time.Hour), EndDate: now.Add(time.Hour)},
+		"broken":  {ID: "broken", State: models.ContestOpen, StartDate: now.Add(-time.Hour), EndDate: now.Add(time.Hour)},
+	}, fail: "broken"}
+
+	scheduler := NewScheduler(fake, time.Minute)
+	scheduler.now = func() time.Time { return now }
+
+	assert.Equal(t, 3, scheduler.Tick())
+	assert.Equal(t, models.ContestRunning, fake.contests["started"].State)
+	assert.Equal(t, models.ContestJudging, fake.contests["ended"].State)
+	assert.Equal(t, models.ContestOpen, fake.contests["future"].State)
+	assert.Equal(t, models.ContestDraft, fake.contests["draft"].State)
+	assert.Equal(t, models.ContestOpen, fake.contests["broken"].State)
+	assert.Equal(t, []string{SchedulerActor, SchedulerActor, SchedulerActor}, fake.actors)
+
+	// Nothing is left to do until the clock moves
+	fake.fail = ""
+	fake.contests["broken"].StartDate = now.Add(time.Hour)
+	assert.Equal(t, 0, scheduler.Tick())
+}
@@ -0,0 +1,70 @@
+package lifecycle
+
+import (
+	"blockchain-demo/internal/models"
+	"context"
+	"log"
+	"time"
+)
+
+// SchedulerActor is recorded as the actor of automatic transitions
+const SchedulerActor = "scheduler"
+
+// Contests is the part of the blockchain service the scheduler needs
+type Contests interface {
+	GetAllContests() (*models.ListContestsResponse, error)
+	TransitionContest(id, to, reason, actor string) (*models.ContestTransitionResponse, error)
+}
+
+// Scheduler applies the transitions driven by contest dates
+type Scheduler struct {
+	contests Contests
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewScheduler creates a scheduler checking contests every interval
+func NewScheduler(contests Contests, interval time.Duration) *Scheduler {
+	return &Scheduler{contests: contests, interval: interval, now: time.Now}
+}
+
+// Run checks contests until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.Tick()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// Tick applies every due transition once and returns how many were applied.
+// A contest past both its dates goes through running to judging in one tick.
+func (s *Scheduler) Tick() int {
+	response, err := s.contests.GetAllContests()
+	if err != nil {
+		log.Printf("❌ Scheduler failed to list contests: %v", err)
+		return 0
+	}
+
+	now := s.now()
+	applied := 0
+	for _, contest := range response.Data {
+		for to := Due(contest, now); to != ""; to = Due(contest, now) {
+			if _, err := s.contests.TransitionContest(contest.ID, to, "scheduled", SchedulerActor); err != nil {
+				log.Printf("❌ Scheduler failed to move contest %s to %s: %v", contest.ID, to, err)
+				break
+			}
+			log.Printf("⏰ Contest %s is now %s", contest.ID, to)
+			contest.State = to
+			applied++
+		}
+	}
+	return applied
+}
@@ -0,0 +1,206 @@
+// Package metatx verifies EIP-712 signed forward requests that the server
+// relays through an ERC-2771 forwarder, so the contract sees the signer
+// instead of the server wallet.
+package metatx
+
+import (
+	"blockchain-demo/internal/models"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain of the forwarder contract (contracts/WikiChainForwarder.sol)
+const (
+	DomainName    = "WikiChainForwarder"
+	DomainVersion = "1"
+)
+
+// Errors returned when a forward request is rejected
+var (
+	ErrBadTarget = errors.New("request does not target the contract")
+	ErrValue     = errors.New("relayed requests cannot transfer value")
+	ErrExpired   = errors.New("request deadline has passed")
+	ErrNonce     = errors.New("request nonce is not the next nonce of the signer")
+	ErrSignature = errors.New("signature does not match request")
+)
+
+// forwardRequestType mirrors the FORWARD_REQUEST_TYPEHASH of the forwarder
+var forwardRequestType = []apitypes.Type{
+	{Name: "from", Type: "address"},
+	{Name: "to", Type: "address"},
+	{Name: "value", Type: "uint256"},
+	{Name: "gas", Type: "uint256"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "deadline", Type: "uint256"},
+	{Name: "data", Type: "bytes"},
+}
+
+// Relayer builds, verifies and tracks the nonces of forward requests
+type Relayer struct {
+	chainID   *big.Int
+	forwarder common.Address
+	target    common.Address
+	ttl       time.Duration
+	gas       uint64
+
+	mu   sync.Mutex
+	next map[common.Address]uint64 // signer -> next nonce claimed by the server
+}
+
+// NewRelayer creates a relayer for requests sent through forwarder to target.
+// Requests expire ttl after they are prepared and get gas for the inner call.
+func NewRelayer(chainID int64, forwarder, target string, ttl time.Duration, gas uint64) *Relayer {
+	return &Relayer{
+		chainID:   big.NewInt(chainID),
+		forwarder: common.HexToAddress(forwarder),
+		target:    common.HexToAddress(target),
+		ttl:       ttl,
+		gas:       gas,
+		next:      make(map[common.Address]uint64),
+	}
+}
+
+// NextNonce returns the nonce the next request of a signer must use, given
+// the nonce currently stored in the forwarder
+func (r *Relayer) NextNonce(from common.Address, chainNonce uint64) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.nextNonce(from, chainNonce)
+}
+
+func (r *Relayer) nextNonce(from common.Address, chainNonce uint64) uint64 {
+	if next := r.next[from]; next > chainNonce {
+		return next
+	}
+	return chainNonce
+}
+
+// NewRequest builds an unsigned request calling the target with data
+func (r *Relayer) NewRequest(from common.Address, data []byte, chainNonce uint64) *models.ForwardRequest {
+	return &models.ForwardRequest{
+		From:     from.Hex(),
+		To:       r.target.Hex(),
+		Gas:      r.gas,
+		Nonce:    r.NextNonce(from, chainNonce),
+		Deadline: uint64(time.Now().Add(r.ttl).Unix()),
+		Data:     hexutil.Encode(data),
+	}
+}
+
+// TypedData returns the EIP-712 typed data of a request, as passed to eth_signTypedData_v4
+func (r *Relayer) TypedData(req *models.ForwardRequest) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"ForwardRequest": forwardRequestType,
+		},
+		PrimaryType: "ForwardRequest",
+		Domain: apitypes.TypedDataDomain{
+			Name:              DomainName,
+			Version:           DomainVersion,
+			ChainId:           (*math.HexOrDecimal256)(r.chainID),
+			VerifyingContract: r.forwarder.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     req.From,
+			"to":       req.To,
+			"value":    strconv.FormatUint(req.Value, 10),
+			"gas":      strconv.FormatUint(req.Gas, 10),
+			"nonce":    strconv.FormatUint(req.Nonce, 10),
+			"deadline": strconv.FormatUint(req.Deadline, 10),
+			"data":     req.Data,
+		},
+	}
+}
+
+// Hash returns the EIP-712 digest signed for a request
+func (r *Relayer) Hash(req *models.ForwardRequest) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(r.TypedData(req))
+	return hash, err
+}
+
+// Claim verifies a signed request and reserves its nonce so it cannot be
+// relayed twice. Call Release if relaying fails afterwards.
+func (r *Relayer) Claim(req *models.ForwardRequest, signature []byte, chainNonce uint64) (common.Address, error) {
+	if !common.IsHexAddress(req.From) {
+		return common.Address{}, fmt.Errorf("invalid signer address: %q", req.From)
+	}
+	from := common.HexToAddress(req.From)
+
+	if !common.IsHexAddress(req.To) || common.HexToAddress(req.To) != r.target {
+		return common.Address{}, ErrBadTarget
+	}
+	if req.Value != 0 {
+		return common.Address{}, ErrValue
+	}
+	if time.Now().Unix() > int64(req.Deadline) {
+		return common.Address{}, ErrExpired
+	}
+
+	signer, err := r.recover(req, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if signer != from {
+		return common.Address{}, ErrSignature
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.Nonce != r.nextNonce(from, chainNonce) {
+		return common.Address{}, ErrNonce
+	}
+	r.next[from] = req.Nonce + 1
+	return signer, nil
+}
+
+// Release gives back the nonce of a claimed request that could not be relayed
+func (r *Relayer) Release(req *models.ForwardRequest) {
+	from := common.HexToAddress(req.From)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next[from] == req.Nonce+1 {
+		r.next[from] = req.Nonce
+	}
+}
+
+// recover returns the address that signed a request
+func (r *Relayer) recover(req *models.ForwardRequest, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+	hash, err := r.Hash(req)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid request: %v", err)
+	}
+
+	// Wallets return V as 27/28, go-ethereum expects 0/1
+	sig := append([]byte(nil), signature...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, ErrSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
@@ -0,0 +1,122 @@
+package metatx
+
+import (
+	"blockchain-demo/internal/models"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	forwarderAddr = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	targetAddr    = "0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512"
+)
+
+func newRelayer() *Relayer {
+	return NewRelayer(1337, forwarderAddr, targetAddr, time.Minute, 500000)
+}
+
+func TestHashMatchesForwarderContract(t *testing.T) {
+	r := newRelayer()
+	from := common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	req := r.NewRequest(from, []byte{0xde, 0xad, 0xbe, 0xef}, 3)
+	req.Deadline = 1700000000
+
+	got, err := r.Hash(req)
+	require.NoError(t, err)
+
+	// Same computation as WikiChainForwarder.verify
+	word := func(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+	domainSeparator := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte(DomainName)),
+		crypto.Keccak256([]byte(DomainVersion)),
+		word(big.NewInt(1337)),
+		common.LeftPadBytes(common.HexToAddress(forwarderAddr).Bytes(), 32),
+	)
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("ForwardRequest(address from,address to,uint256 value,uint256 gas,uint256 nonce,uint256 deadline,bytes data)")),
+		common.LeftPadBytes(from.Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(targetAddr).Bytes(), 32),
+		word(big.NewInt(0)),
+		word(big.NewInt(500000)),
+		word(big.NewInt(3)),
+		word(big.NewInt(1700000000)),
+		crypto.Keccak256([]byte{0xde, 0xad, 0xbe, 0xef}),
+	)
+	want := crypto.Keccak256([]byte("\x19\x01"), domainSeparator, structHash)
+
+	assert.Equal(t, hexutil.Encode(want), hexutil.Encode(got))
+}
+
+func TestClaim(t *testing.T) {
+	r := newRelayer()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	req := r.NewRequest(from, []byte{0x01}, 0)
+	hash, err := r.Hash(req)
+	require.NoError(t, err)
+	signature, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	signature[crypto.RecoveryIDOffset] += 27 // as returned by wallets
+
+	signer, err := r.Claim(req, signature, 0)
+	require.NoError(t, err)
+	assert.Equal(t, from, signer)
+	assert.Equal(t, uint64(1), r.NextNonce(from, 0))
+
+	// The same request cannot be relayed twice
+	_, err = r.Claim(req, signature, 0)
+	assert.ErrorIs(t, err, ErrNonce)
+
+	// Releasing a failed relay frees the nonce again
+	r.Release(req)
+	_, err = r.Claim(req, signature, 0)
+	assert.NoError(t, err)
+
+	// The forwarder nonce wins when it is ahead of the server
+	assert.Equal(t, uint64(5), r.NextNonce(from, 5))
+}
+
+func TestClaimRejects(t *testing.T) {
+	r := newRelayer()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	signed := func(mutate func(req *models.ForwardRequest)) (*models.ForwardRequest, []byte) {
+		req := r.NewRequest(from, []byte{0x01}, 0)
+		mutate(req)
+		hash, err := r.Hash(req)
+		require.NoError(t, err)
+		signature, err := crypto.Sign(hash, key)
+		require.NoError(t, err)
+		return req, signature
+	}
+
+	req, signature := signed(func(req *models.ForwardRequest) { req.Deadline = uint64(time.Now().Add(-time.Second).Unix()) })
+	_, err = r.Claim(req, signature, 0)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	req, signature = signed(func(req *models.ForwardRequest) { req.To = forwarderAddr })
+	_, err = r.Claim(req, signature, 0)
+	assert.ErrorIs(t, err, ErrBadTarget)
+
+	req, signature = signed(func(req *models.ForwardRequest) { req.Nonce = 7 })
+	_, err = r.Claim(req, signature, 0)
+	assert.ErrorIs(t, err, ErrNonce)
+
+	// Tampering with the signed data invalidates the signature
+	req, signature = signed(func(req *models.ForwardRequest) {})
+	req.Data = "0x02"
+	_, err = r.Claim(req, signature, 0)
+	assert.ErrorIs(t, err, ErrSignature)
+}
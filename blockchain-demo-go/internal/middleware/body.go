@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"blockchain-demo/internal/apperr"
+	"fmt"
+	"net/http"
+)
+
+// MaxBodyBytes caps the size of request bodies, so middleware that buffers
+// them never reads unbounded input. Bodies declared larger than the limit are
+// rejected with 413 before anything reads them.
+func MaxBodyBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				apperr.Write(w, r, http.StatusRequestEntityTooLarge, "", "Request body too large", fmt.Sprintf("request body must not be larger than %d bytes", limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package middleware holds the HTTP middleware shared by every route.
+package middleware
+
+import (
+	"blockchain-demo/internal/config"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS applies a cross-origin resource sharing policy. Requests from other
+// origins get CORS headers only when the origin is allowed, and preflights
+// for disallowed origins, methods or headers are rejected with 403.
+type CORS struct {
+	allowAll         bool
+	origins          map[string]bool // Exact origins, lower case
+	wildcards        []wildcardOrigin
+	methods          map[string]bool
+	headers          map[string]bool // Canonical header names
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// wildcardOrigin matches subdomains of a host, e.g. https://*.example.com
+type wildcardOrigin struct {
+	scheme string
+	suffix string // ".example.com" plus the port if any
+}
+
+// NewCORS creates the CORS policy of the configuration
+func NewCORS(cfg *config.Config) (*CORS, error) {
+	c := &CORS{
+		origins:          make(map[string]bool),
+		methods:          make(map[string]bool),
+		headers:          make(map[string]bool),
+		allowCredentials: cfg.CORSAllowCredentials,
+		maxAge:           strconv.Itoa(int(cfg.CORSMaxAge / time.Second)),
+	}
+
+	for _, origin := range cfg.CORSAllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		switch {
+		case origin == "*":
+			c.allowAll = true
+		case strings.Contains(origin, "*"):
+			scheme, host, ok := strings.Cut(origin, "://*.")
+			if !ok || strings.Contains(host, "*") || host == "" {
+				return nil, fmt.Errorf("invalid CORS origin %q: wildcards must look like https://*.example.com", origin)
+			}
+			c.wildcards = append(c.wildcards, wildcardOrigin{scheme: scheme, suffix: "." + host})
+		default:
+			if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+				return nil, fmt.Errorf("invalid CORS origin %q: expected scheme://host[:port]", origin)
+			}
+			c.origins[origin] = true
+		}
+	}
+	if c.allowAll && c.allowCredentials {
+		return nil, fmt.Errorf("CORS credentials require explicit origins, not \"*\"")
+	}
+
+	methods := make([]string, 0, len(cfg.CORSAllowedMethods))
+	for _, method := range cfg.CORSAllowedMethods {
+		method = strings.ToUpper(method)
+		c.methods[method] = true
+		methods = append(methods, method)
+	}
+	headers := make([]string, 0, len(cfg.CORSAllowedHeaders))
+	for _, header := range cfg.CORSAllowedHeaders {
+		header = http.CanonicalHeaderKey(header)
+		c.headers[header] = true
+		headers = append(headers, header)
+	}
+	c.allowedMethods = strings.Join(methods, ", ")
+	c.allowedHeaders = strings.Join(headers, ", ")
+	c.exposedHeaders = strings.Join(cfg.CORSExposedHeaders, ", ")
+	return c, nil
+}
+
+// Middleware applies the policy. OPTIONS requests, preflight or not, are
+// answered here and never reach the routes.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		// The response depends on the origin, so caches must key on it
+		w.Header().Add("Vary", "Origin")
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if preflight && origin != "" {
+			c.preflight(w, r, origin)
+			return
+		}
+
+		if origin != "" && c.AllowOrigin(origin) {
+			c.setOrigin(w, origin)
+			if c.exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", c.exposedHeaders)
+			}
+		}
+
+		// Routes accept OPTIONS only for CORS; other OPTIONS requests end here
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Allow", c.allowedMethods)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers an OPTIONS request asking whether a cross-origin request is allowed
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	switch {
+	case !c.AllowOrigin(origin):
+		c.reject(w, r, "origin %s is not allowed", origin)
+		return
+	case !c.methods[method]:
+		c.reject(w, r, "method %s is not allowed", method)
+		return
+	}
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !c.headers[http.CanonicalHeaderKey(header)] {
+			c.reject(w, r, "header %s is not allowed", header)
+			return
+		}
+	}
+
+	c.setOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", c.allowedMethods)
+	if c.allowedHeaders != "" {
+		w.Header().Set("Access-Control-Allow-Headers", c.allowedHeaders)
+	}
+	w.Header().Set("Access-Control-Max-Age", c.maxAge)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reject refuses a preflight without any CORS header, so the browser blocks the request
+func (c *CORS) reject(w http.ResponseWriter, r *http.Request, format string, args ...interface{}) {
+	log.Printf("🚫 CORS preflight for %s rejected: %s", r.URL.Path, fmt.Sprintf(format, args...))
+	w.WriteHeader(http.StatusForbidden)
+}
+
+func (c *CORS) setOrigin(w http.ResponseWriter, origin string) {
+	if c.allowAll {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if c.allowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// AllowOrigin reports whether the policy allows an origin
+func (c *CORS) AllowOrigin(origin string) bool {
+	if c.allowAll {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if c.origins[origin] {
+		return true
+	}
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok {
+		return false
+	}
+	for _, wildcard := range c.wildcards {
+		// The subdomain must not be empty: *.example.com does not match example.com
+		if scheme == wildcard.scheme && len(host) > len(wildcard.suffix) && strings.HasSuffix(host, wildcard.suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"blockchain-demo/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCORS(t *testing.T, origins ...string) http.Handler {
+	cors, err := NewCORS(&config.Config{
+		CORSAllowedOrigins:   origins,
+		CORSAllowedMethods:   []string{"GET", "POST"},
+		CORSAllowedHeaders:   []string{"Content-Type", "Authorization"},
+		CORSExposedHeaders:   []string{"Retry-After"},
+		CORSAllowCredentials: true,
+		CORSMaxAge:           time.Hour,
+	})
+	require.NoError(t, err)
+	return cors.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+}
+
+func serve(handler http.Handler, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/v1/contests", nil)
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+	return rr
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	handler := testCORS(t, "https://app.example.com", "https://*.wiki.dev")
+
+	rr := serve(handler, "GET", "https://app.example.com", nil)
+	assert.Equal(t, http.StatusTeapot, rr.Code)
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Retry-After", rr.Header().Get("Access-Control-Expose-Headers"))
+	assert.Contains(t, rr.Header().Values("Vary"), "Origin")
+
+	rr = serve(handler, "GET", "https://a.b.wiki.dev", nil)
+	assert.Equal(t, "https://a.b.wiki.dev", rr.Header().Get("Access-Control-Allow-Origin"))
+
+	// Disallowed origins still reach the handler, without CORS headers
+	for _, origin := range []string{"https://evil.com", "https://wiki.dev", "http://app.wiki.dev", "https://evilwiki.dev"} {
+		rr = serve(handler, "GET", origin, nil)
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"), origin)
+		assert.Contains(t, rr.Header().Values("Vary"), "Origin", "caches must not reuse the response for allowed origins")
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := testCORS(t, "https://app.example.com")
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		return serve(handler, "OPTIONS", origin, map[string]string{
+			"Access-Control-Request-Method":  method,
+			"Access-Control-Request-Headers": headers,
+		})
+	}
+
+	rr := preflight("https://app.example.com", "POST", "content-type, authorization")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, Authorization", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "3600", rr.Header().Get("Access-Control-Max-Age"))
+
+	for name, rr := range map[string]*httptest.ResponseRecorder{
+		"origin": preflight("https://evil.com", "POST", ""),
+		"method": preflight("https://app.example.com", "DELETE", ""),
+		"header": preflight("https://app.example.com", "POST", "X-Secret"),
+	} {
+		assert.Equal(t, http.StatusForbidden, rr.Code, name)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"), name)
+	}
+
+	// OPTIONS without a preflight never reaches the routes
+	assert.Equal(t, http.StatusNoContent, serve(handler, "OPTIONS", "", nil).Code)
+}
+
+func TestCORSConfig(t *testing.T) {
+	_, err := NewCORS(&config.Config{CORSAllowedOrigins: []string{"*"}, CORSAllowCredentials: true})
+	assert.Error(t, err, "credentials cannot be shared with every origin")
+
+	_, err = NewCORS(&config.Config{CORSAllowedOrigins: []string{"https://app.*.com"}})
+	assert.Error(t, err)
+
+	cors, err := NewCORS(&config.Config{CORSAllowedOrigins: []string{"*"}})
+	require.NoError(t, err)
+	rr := serve(cors.Middleware(http.NotFoundHandler()), "GET", "https://anything.io", nil)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+}
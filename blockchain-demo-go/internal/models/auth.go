@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ AUTH REQUEST/RESPONSE STRUCTS ============
+
+// NonceResponse represents the response carrying a Sign-In with Ethereum nonce
+type NonceResponse struct {
+	Success   bool      `json:"success"`
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// VerifySIWERequest represents a signed EIP-4361 message
+type VerifySIWERequest struct {
+	Message   string `json:"message" validate:"required,max=4096"`
+	Signature string `json:"signature" validate:"required,max=200"`
+}
+
+// SessionResponse represents the response after a successful sign-in
+type SessionResponse struct {
+	Success   bool      `json:"success"`
+	Message   string    `json:"message,omitempty"`
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MeResponse describes the authenticated caller
+type MeResponse struct {
+	Success       bool     `json:"success"`
+	Authenticated bool     `json:"authenticated"`
+	Subject       string   `json:"subject,omitempty"`
+	Address       string   `json:"address,omitempty"`
+	Method        string   `json:"method,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+}
+
+// ============ API KEY STRUCTS ============
+
+// APIKey represents an API key. The secret itself is never stored, only its hash.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Hash       string     `json:"hash,omitempty"` // SHA-256 of the full key, hidden from API responses
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAPIKeyRequest represents the payload to create an API key
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,max=10"`
+	ExpiresIn string   `json:"expires_in,omitempty" validate:"max=32"` // Go duration, e.g. "720h"
+}
+
+// CreateAPIKeyResponse represents the response after creating an API key.
+// Key is only returned once.
+type CreateAPIKeyResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message,omitempty"`
+	Key     string  `json:"key,omitempty"`
+	Data    *APIKey `json:"data,omitempty"`
+}
+
+// ListAPIKeysResponse represents the response when listing API keys
+type ListAPIKeysResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message,omitempty"`
+	Data    []*APIKey `json:"data"`
+	Total   int       `json:"total"`
+}
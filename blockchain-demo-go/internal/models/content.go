@@ -6,13 +6,17 @@ import (
 
 // Content represents the data structure for general content stored on blockchain
 type Content struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	Creator   string    `json:"creator"`
-	Timestamp time.Time `json:"timestamp"`
-	TxHash    string    `json:"tx_hash,omitempty"`
-	Verified  bool      `json:"verified"`
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	Creator    string    `json:"creator"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Categories []string  `json:"categories,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Links      []string  `json:"links,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	TxHash     string    `json:"tx_hash,omitempty"`
+	Verified   bool      `json:"verified"`
 }
 
 // Contest represents a contest/event structure
@@ -23,10 +27,19 @@ type Contest struct {
 	StartDate   time.Time `json:"start_date"`
 	EndDate     time.Time `json:"end_date"`
 	Organizer   string    `json:"organizer"`
-	Active      bool      `json:"active"`
+	Active      bool      `json:"active"` // True while the contest is open, running or judging
+	State       string    `json:"state"`  // See ContestDraft and friends
 	ImageURL    string    `json:"image_url,omitempty"`
-	TxHash      string    `json:"tx_hash,omitempty"`
-	Timestamp   time.Time `json:"timestamp"`
+
+	Registration *RegistrationPolicy `json:"registration,omitempty"`
+	Voting       *VotingPolicy       `json:"voting,omitempty"` // Set when the contest is decided by audience vote
+	SponsorTiers *SponsorTiers       `json:"sponsor_tiers,omitempty"`
+	Sponsorship  *SponsorshipSummary `json:"sponsorship,omitempty"` // Pledge totals, filled in contest details
+
+	Categories []string  `json:"categories,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	TxHash     string    `json:"tx_hash,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
 }
 
 // Contestant represents a participant in contests
@@ -55,6 +68,8 @@ type Sponsor struct {
 type ContestRegistration struct {
 	ContestID    string    `json:"contest_id"`
 	ContestantID string    `json:"contestant_id"`
+	Status       string    `json:"status"`             // RegistrationRegistered or RegistrationWaitlisted
+	Position     int       `json:"position,omitempty"` // 1-based place on the waitlist
 	RegisteredAt time.Time `json:"registered_at"`
 	TxHash       string    `json:"tx_hash,omitempty"`
 }
@@ -63,48 +78,64 @@ type ContestRegistration struct {
 
 // CreateContentRequest represents the request payload for creating content
 type CreateContentRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	Creator string `json:"creator,omitempty"`
+	Title      string   `json:"title" validate:"required,max=200" label:"Title"`
+	Content    string   `json:"content" validate:"required,max=100000" label:"Content"`
+	Creator    string   `json:"creator,omitempty" validate:"max=100"`
+	Namespace  string   `json:"namespace,omitempty" validate:"max=64"`
+	Categories []string `json:"categories,omitempty" validate:"max=20,each=max=100"` // Hierarchical, e.g. "Board Games/Strategy"
+	Tags       []string `json:"tags,omitempty" validate:"max=20,each=max=50"`
+	Nonce      string   `json:"nonce,omitempty" validate:"max=64"` // Derives a deterministic ID, see package ids
 }
 
 // CreateContestRequest represents the request payload for creating a contest
 type CreateContestRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description" binding:"required"`
-	StartDate   string `json:"start_date" binding:"required"` // Format: "2006-01-02T15:04:05Z"
-	EndDate     string `json:"end_date" binding:"required"`
-	ImageURL    string `json:"image_url,omitempty"`
+	Name        string   `json:"name" validate:"required,max=200" label:"Contest name"`
+	Description string   `json:"description" validate:"required,max=5000" label:"Contest description"`
+	StartDate   string   `json:"start_date" validate:"required,rfc3339" label:"Start date"` // Format: "2006-01-02T15:04:05Z"
+	EndDate     string   `json:"end_date" validate:"required,rfc3339,after=StartDate" label:"End date"`
+	ImageURL    string   `json:"image_url,omitempty" validate:"url,max=2048"`
+	Categories  []string `json:"categories,omitempty" validate:"max=20,each=max=100"`
+	Tags        []string `json:"tags,omitempty" validate:"max=20,each=max=50"`
+	Organizer   string   `json:"-"`                                 // Set from the authenticated wallet, never from the body
+	Nonce       string   `json:"nonce,omitempty" validate:"max=64"` // Derives a deterministic ID, see package ids
+	Draft       bool     `json:"draft,omitempty"`                   // Create in the draft state instead of open
+
+	Registration *RegistrationPolicyRequest `json:"registration,omitempty"`
+	Voting       *VotingPolicyRequest       `json:"voting,omitempty"`
+	SponsorTiers *SponsorTiersRequest       `json:"sponsor_tiers,omitempty"`
 }
 
 // CreateContestantRequest represents the request payload for creating a contestant
 type CreateContestantRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Details string `json:"details" binding:"required"`
-	Creator string `json:"creator,omitempty"`
+	Name    string `json:"name" validate:"required,max=200" label:"Contestant name"`
+	Details string `json:"details" validate:"required,max=5000" label:"Contestant details"`
+	Creator string `json:"creator,omitempty" validate:"max=100"`
+	Nonce   string `json:"nonce,omitempty" validate:"max=64"` // Derives a deterministic ID, see package ids
 }
 
 // CreateSponsorRequest represents the request payload for creating a sponsor
 type CreateSponsorRequest struct {
-	Name              string `json:"name" binding:"required"`
-	ContactInfo       string `json:"contact_info" binding:"required"`
+	Name              string `json:"name" validate:"required,max=200" label:"Sponsor name"`
+	ContactInfo       string `json:"contact_info" validate:"required,max=500" label:"Contact info"`
 	SponsorshipAmount uint64 `json:"sponsorship_amount"`
+	WalletAddress     string `json:"wallet_address,omitempty" validate:"max=100"` // Pledges for the sponsor come from this wallet
 }
 
 // RegisterContestantRequest represents the request for registering contestant to contest
 type RegisterContestantRequest struct {
-	ContestID    string `json:"contest_id" binding:"required"`
-	ContestantID string `json:"contestant_id" binding:"required"`
+	ContestID    string `json:"contest_id" validate:"max=64"` // Taken from the URL
+	ContestantID string `json:"contestant_id" validate:"required,max=64" label:"Contestant ID"`
 }
 
 // ============ RESPONSE STRUCTS ============
 
 // CreateContentResponse represents the response after creating content
 type CreateContentResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	TxHash  string `json:"tx_hash,omitempty"`
-	ID      string `json:"id,omitempty"`
+	Success    bool   `json:"success"`
+	Message    string `json:"message"`
+	TxHash     string `json:"tx_hash,omitempty"`
+	RecordHash string `json:"record_hash,omitempty"` // keccak256 of the anchored record
+	ID         string `json:"id,omitempty"`
 }
 
 // CreateContestResponse represents the response after creating contest
@@ -133,9 +164,10 @@ type CreateSponsorResponse struct {
 
 // RegisterContestantResponse represents the response after registering contestant
 type RegisterContestantResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	TxHash  string `json:"tx_hash,omitempty"`
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	TxHash  string               `json:"tx_hash,omitempty"`
+	Data    *ContestRegistration `json:"data,omitempty"`
 }
 
 // GetContentResponse represents the response when getting content
@@ -205,15 +237,81 @@ type ListContestantsInContestResponse struct {
 	ContestID   string        `json:"contest_id"`
 	Contestants []*Contestant `json:"contestants,omitempty"`
 	Total       int           `json:"total"`
+	Waitlist    []*Contestant `json:"waitlist,omitempty"` // In promotion order
 }
 
-// ErrorResponse represents an error response
+// ErrorResponse is an RFC 9457 problem details object, sent with the
+// application/problem+json content type. Success, Error and Message repeat
+// the problem for clients of the original error format.
 type ErrorResponse struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`             // Stable machine-readable code, see ErrorCode*
+	Errors   []FieldError `json:"errors,omitempty"` // Every invalid field of a rejected request
+
 	Success bool   `json:"success"`
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
 }
 
+// FieldError describes an invalid field of a request
+type FieldError struct {
+	Field   string `json:"field"` // JSON path, e.g. "request.from"
+	Rule    string `json:"rule"`  // Failed rule, e.g. "required", "max"
+	Message string `json:"message"`
+}
+
+// Machine-readable error codes. Codes are stable; titles and details are not.
+const (
+	ErrorCodeBadRequest         = "bad_request"
+	ErrorCodeValidation         = "validation_failed"
+	ErrorCodeUnauthorized       = "unauthorized"
+	ErrorCodeForbidden          = "forbidden"
+	ErrorCodeNotFound           = "not_found"
+	ErrorCodeConflict           = "conflict"
+	ErrorCodeReverted           = "reverted"
+	ErrorCodeInsufficientFunds  = "insufficient_funds"
+	ErrorCodeChainUnavailable   = "chain_unavailable"
+	ErrorCodeInternal           = "internal_error"
+	ErrorCodeServiceUnavailable = "service_unavailable"
+	ErrorCodePayloadTooLarge    = "payload_too_large"
+
+	ErrorCodeReadOnly              = "read_only"
+	ErrorCodeRateLimited           = "rate_limited"
+	ErrorCodeGasBudgetExceeded     = "gas_budget_exceeded"
+	ErrorCodeInvalidIdempotencyKey = "invalid_idempotency_key"
+	ErrorCodeIdempotencyKeyReused  = "idempotency_key_reused"
+	ErrorCodeIdempotencyInProgress = "idempotency_in_progress"
+	ErrorCodeIDTaken               = "id_taken"
+	ErrorCodeInvalidID             = "invalid_id"
+	ErrorCodeInvalidContestState   = "invalid_contest_state"
+	ErrorCodeRegistrationNotOpen   = "registration_not_open"
+	ErrorCodeRegistrationClosed    = "registration_closed"
+	ErrorCodeContestFull           = "contest_full"
+	ErrorCodeAlreadyRegistered     = "already_registered"
+	ErrorCodeNotEligible           = "not_eligible"
+	ErrorCodeTeamsNotAllowed       = "teams_not_allowed"
+	ErrorCodeInvalidTeamSize       = "invalid_team_size"
+	ErrorCodeTeamNotReady          = "team_not_ready"
+	ErrorCodeAlreadyOnTeam         = "already_on_team"
+	ErrorCodeInvalidSignature      = "invalid_signature"
+	ErrorCodeNotAJudge             = "not_a_judge"
+	ErrorCodeRubricLocked          = "rubric_locked"
+	ErrorCodeResultsFinalized      = "results_finalized"
+	ErrorCodeVotingNotEnabled      = "voting_not_enabled"
+	ErrorCodeWrongVotingPhase      = "wrong_voting_phase"
+	ErrorCodeAlreadyVoted          = "already_voted"
+	ErrorCodeInvalidReveal         = "invalid_reveal"
+	ErrorCodePrizePoolExists       = "prize_pool_exists"
+	ErrorCodeInvalidPoolState      = "invalid_pool_state"
+	ErrorCodePrizePoolEmpty        = "prize_pool_empty"
+	ErrorCodeResultsNotFinal       = "results_not_final"
+	ErrorCodeNoPayoutWallet        = "no_payout_wallet"
+)
+
 // ============ UTILITY STRUCTS ============
 
 // BlockchainStats represents general statistics from blockchain
@@ -231,3 +329,16 @@ type BlockchainStatsResponse struct {
 	Message string           `json:"message,omitempty"`
 	Data    *BlockchainStats `json:"data,omitempty"`
 }
+
+// IDPreview is the ID an entity would get, computed before it is submitted
+type IDPreview struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Taken bool   `json:"taken"` // An entity with this ID already exists on-chain
+}
+
+// IDPreviewResponse represents the response of GET /api/v1/ids/preview
+type IDPreviewResponse struct {
+	Success bool       `json:"success"`
+	Data    *IDPreview `json:"data"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+)
+
+// Ways to combine the scores judges give an entry
+const (
+	AggregationMean        = "mean"
+	AggregationTrimmedMean = "trimmed_mean" // Drops the lowest and highest score
+	AggregationMedian      = "median"
+)
+
+// Criterion is one line of a scoring rubric
+type Criterion struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Weight      float64 `json:"weight"`
+	MaxScore    float64 `json:"max_score"`
+}
+
+// Rubric defines how the entries of a contest are scored and who scores them
+type Rubric struct {
+	ContestID   string      `json:"contest_id"`
+	Criteria    []Criterion `json:"criteria"`
+	Aggregation string      `json:"aggregation"`
+	Judges      []string    `json:"judges"` // Wallet addresses
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// Scorecard holds the scores one judge gives one entry. Scorecards stay
+// private to their judge until the results are finalized.
+type Scorecard struct {
+	ContestID    string             `json:"contest_id"`
+	ContestantID string             `json:"contestant_id"` // Contestant or team
+	Judge        string             `json:"judge"`
+	Scores       map[string]float64 `json:"scores"` // Criterion name -> score
+	Total        float64            `json:"total"`  // Weighted sum of the scores
+	Comment      string             `json:"comment,omitempty"`
+	SubmittedAt  time.Time          `json:"submitted_at"`
+}
+
+// ContestantResult is the aggregated score of one entry
+type ContestantResult struct {
+	Rank         int                `json:"rank"`
+	ContestantID string             `json:"contestant_id"`
+	Score        float64            `json:"score"`    // Aggregate of the judges' totals
+	Criteria     map[string]float64 `json:"criteria"` // Aggregate per criterion
+	Judges       int                `json:"judges"`   // Number of scorecards
+}
+
+// ContestResults ranks the entries of a contest. Final results are anchored
+// on-chain together with every scorecard.
+type ContestResults struct {
+	ContestID   string             `json:"contest_id"`
+	Aggregation string             `json:"aggregation"`
+	Results     []ContestantResult `json:"results"`
+	Scorecards  []*Scorecard       `json:"scorecards,omitempty"` // Only once final
+	Final       bool               `json:"final"`
+	FinalizedBy string             `json:"finalized_by,omitempty"`
+	FinalizedAt *time.Time         `json:"finalized_at,omitempty"`
+	TxHash      string             `json:"tx_hash,omitempty"`
+}
+
+// ============ JUDGING REQUEST/RESPONSE STRUCTS ============
+
+// CriterionRequest represents one criterion of a rubric
+type CriterionRequest struct {
+	Name        string  `json:"name" validate:"required,max=100" label:"Criterion name"`
+	Description string  `json:"description,omitempty" validate:"max=500"`
+	Weight      float64 `json:"weight,omitempty" validate:"range=0:100" label:"Weight"` // Defaults to 1
+	MaxScore    float64 `json:"max_score" validate:"required,range=1:1000" label:"Maximum score"`
+}
+
+// SetRubricRequest represents the payload to define the rubric of a contest
+type SetRubricRequest struct {
+	Criteria    []CriterionRequest `json:"criteria" validate:"required,max=20" label:"Criteria"`
+	Aggregation string             `json:"aggregation,omitempty"` // mean (default), trimmed_mean or median
+}
+
+// AssignJudgesRequest represents the payload to add judges to a contest
+type AssignJudgesRequest struct {
+	Judges []string `json:"judges" validate:"required,max=50,each=address" label:"Judges"`
+}
+
+// SubmitScoresRequest represents a judge's scores for one entry
+type SubmitScoresRequest struct {
+	Judge   string             `json:"judge,omitempty" validate:"address" label:"Judge"` // Defaults to the signed-in wallet
+	Scores  map[string]float64 `json:"scores" validate:"required" label:"Scores"`
+	Comment string             `json:"comment,omitempty" validate:"max=1000"`
+}
+
+// RubricResponse represents the response for the rubric of a contest
+type RubricResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message,omitempty"`
+	Data    *Rubric `json:"data,omitempty"`
+}
+
+// ScorecardResponse represents the response after a judge submits scores
+type ScorecardResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	Data    *Scorecard `json:"data,omitempty"`
+}
+
+// ListScorecardsResponse represents the scorecards visible to the caller
+type ListScorecardsResponse struct {
+	Success   bool         `json:"success"`
+	Message   string       `json:"message,omitempty"`
+	ContestID string       `json:"contest_id"`
+	Data      []*Scorecard `json:"data"`
+	Total     int          `json:"total"`
+}
+
+// ContestResultsResponse represents the response for the results of a contest
+type ContestResultsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	TxHash  string          `json:"tx_hash,omitempty"`
+	Data    *ContestResults `json:"data,omitempty"`
+}
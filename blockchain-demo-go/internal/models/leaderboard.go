@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Rules that order a leaderboard, in the order they apply
+const (
+	TieBreakScore   = "score"    // Aggregate of the judges' scorecards, highest first
+	TieBreakVotes   = "votes"    // Verified revealed votes, most first
+	TieBreakEarlier = "earliest" // Reached the current score and votes first
+	TieBreakID      = "contestant_id"
+)
+
+// LeaderboardEntry is the standing of one contestant (or team) in a contest
+type LeaderboardEntry struct {
+	Rank         int                `json:"rank"`
+	ContestantID string             `json:"contestant_id"`
+	Score        float64            `json:"score"`
+	Criteria     map[string]float64 `json:"criteria,omitempty"` // Score breakdown per rubric criterion
+	Judges       int                `json:"judges"`
+	Votes        int                `json:"votes"`
+	Tied         bool               `json:"tied,omitempty"` // Same score and votes as the entry above, ranked below on time
+	UpdatedAt    time.Time          `json:"updated_at"`     // When the entry reached its current score and votes
+}
+
+// Leaderboard ranks the contestants of a contest. Version increases with
+// every change so clients can drop stale updates.
+type Leaderboard struct {
+	ContestID string             `json:"contest_id"`
+	Version   uint64             `json:"version"`
+	TieBreak  []string           `json:"tie_break"`
+	Entries   []LeaderboardEntry `json:"entries"`
+	Total     int                `json:"total"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// LeaderboardResponse represents the response for the leaderboard of a contest
+type LeaderboardResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    *Leaderboard `json:"data,omitempty"`
+}
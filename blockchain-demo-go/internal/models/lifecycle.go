@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Contest lifecycle states
+const (
+	ContestDraft     = "draft"
+	ContestOpen      = "open"
+	ContestRunning   = "running"
+	ContestJudging   = "judging"
+	ContestClosed    = "closed"
+	ContestCancelled = "cancelled"
+)
+
+// ContestTransition records a change of the lifecycle state of a contest
+type ContestTransition struct {
+	ContestID string    `json:"contest_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	Actor     string    `json:"actor,omitempty"` // Wallet of the organizer, or "scheduler"
+	At        time.Time `json:"at"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+}
+
+// ============ LIFECYCLE REQUEST/RESPONSE STRUCTS ============
+
+// TransitionContestRequest represents the optional payload to open, close or cancel a contest
+type TransitionContestRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// ContestTransitionResponse represents the response after a state change
+type ContestTransitionResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message,omitempty"`
+	Data    *ContestTransition `json:"data,omitempty"`
+}
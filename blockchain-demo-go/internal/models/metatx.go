@@ -0,0 +1,54 @@
+package models
+
+// ============ META-TRANSACTION STRUCTS ============
+
+// ForwardRequest is the EIP-712 message a user signs so the server can relay
+// a call through the ERC-2771 forwarder on their behalf
+type ForwardRequest struct {
+	From     string `json:"from" validate:"required,address"`
+	To       string `json:"to" validate:"required,address"`
+	Value    uint64 `json:"value"`
+	Gas      uint64 `json:"gas"`
+	Nonce    uint64 `json:"nonce"`
+	Deadline uint64 `json:"deadline"`                 // Unix timestamp
+	Data     string `json:"data" validate:"required"` // Hex encoded contract call
+}
+
+// PrepareMetaContestRequest represents the payload to prepare a contest
+// creation signed by the organizer's wallet
+type PrepareMetaContestRequest struct {
+	From string `json:"from,omitempty" validate:"address" label:"Signer address"` // Must be the signed-in wallet, which it defaults to
+	CreateContestRequest
+}
+
+// PrepareMetaTxResponse returns the request to sign with eth_signTypedData_v4
+type PrepareMetaTxResponse struct {
+	Success   bool            `json:"success"`
+	Message   string          `json:"message,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Request   *ForwardRequest `json:"request,omitempty"`
+	TypedData interface{}     `json:"typed_data,omitempty"`
+	Hash      string          `json:"hash,omitempty"` // EIP-712 digest, for debugging clients
+}
+
+// RelayMetaTxRequest represents a signed forward request to relay
+type RelayMetaTxRequest struct {
+	Request   *ForwardRequest `json:"request" validate:"required"`
+	Signature string          `json:"signature" validate:"required,max=200"`
+}
+
+// RelayMetaTxResponse represents the response after relaying a forward request
+type RelayMetaTxResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Signer  string `json:"signer,omitempty"`
+	TxHash  string `json:"tx_hash,omitempty"`
+}
+
+// MetaNonceResponse returns the next forwarder nonce of a wallet
+type MetaNonceResponse struct {
+	Success bool   `json:"success"`
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// Submission statuses
+const (
+	SubmissionPending  = "pending"
+	SubmissionApproved = "approved"
+	SubmissionRejected = "rejected"
+)
+
+// Submission represents content waiting for (or after) moderation. Only approved
+// submissions are pushed to blockchain; rejections are kept off-chain.
+type Submission struct {
+	ID          string               `json:"id"`
+	Status      string               `json:"status"`
+	TargetID    string               `json:"target_id,omitempty"` // Set when the submission edits existing content
+	Request     CreateContentRequest `json:"request"`
+	SubmittedAt time.Time            `json:"submitted_at"`
+	ReviewedAt  *time.Time           `json:"reviewed_at,omitempty"`
+	Moderator   string               `json:"moderator,omitempty"`
+	Reason      string               `json:"reason,omitempty"`
+	Violations  []string             `json:"violations,omitempty"`
+	ContentID   string               `json:"content_id,omitempty"`
+	TxHash      string               `json:"tx_hash,omitempty"`     // Transaction that anchored the approved content
+	RecordHash  string               `json:"record_hash,omitempty"` // Hash of the anchored record, see RevisionRef
+}
+
+// ============ MODERATION REQUEST/RESPONSE STRUCTS ============
+
+// ReviewSubmissionRequest represents the payload to approve or reject a submission
+type ReviewSubmissionRequest struct {
+	Reason string `json:"reason" validate:"max=1000"` // The reviewer is the signed-in moderator
+}
+
+// SubmissionResponse represents the response for a single submission
+type SubmissionResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    *Submission `json:"data,omitempty"`
+}
+
+// ListSubmissionsResponse represents the response when listing submissions
+type ListSubmissionsResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Data    []*Submission `json:"data"`
+	Total   int           `json:"total"`
+}
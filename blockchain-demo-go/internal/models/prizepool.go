@@ -0,0 +1,76 @@
+package models
+
+// Prize pool states, mirrored from the escrow contract
+const (
+	PrizePoolFunding   = "funding"
+	PrizePoolPaid      = "paid"
+	PrizePoolRefunding = "refunding" // Cancelled; deposits go back to their depositors
+)
+
+// PrizeShare is the share of the pool won by a place, in basis points
+type PrizeShare struct {
+	Rank     int    `json:"rank"`
+	ShareBps uint64 `json:"share_bps"`
+}
+
+// PrizeDeposit is an amount sent to the escrow by a wallet, read from the
+// contract's events. Amounts are decimal strings in the token's smallest unit.
+type PrizeDeposit struct {
+	Depositor string `json:"depositor"`
+	Amount    string `json:"amount"`
+	Refunded  bool   `json:"refunded"`
+	Block     uint64 `json:"block,omitempty"`
+	TxHash    string `json:"tx_hash,omitempty"`
+}
+
+// PrizePayout is the prize sent to a winner's wallet
+type PrizePayout struct {
+	Rank         int    `json:"rank"`
+	ContestantID string `json:"contestant_id"`
+	Wallet       string `json:"wallet"`
+	Amount       string `json:"amount"`
+	Held         bool   `json:"held,omitempty"` // The wallet refused the prize; it waits in the escrow until claimed
+	TxHash       string `json:"tx_hash,omitempty"`
+}
+
+// PrizePool is the escrowed prize money of a contest
+type PrizePool struct {
+	ContestID string         `json:"contest_id"`
+	Escrow    string         `json:"escrow"`          // Address of the escrow contract
+	Token     string         `json:"token,omitempty"` // ERC-20 token, empty for the chain's native token
+	State     string         `json:"state"`
+	Total     string         `json:"total"` // Sum of the deposits
+	Shares    []PrizeShare   `json:"shares"`
+	Deposits  []PrizeDeposit `json:"deposits"`
+	Payouts   []PrizePayout  `json:"payouts,omitempty"`
+}
+
+// ============ PRIZE POOL REQUEST/RESPONSE STRUCTS ============
+
+// OpenPrizePoolRequest represents the payload to open the prize pool of a contest
+type OpenPrizePoolRequest struct {
+	ContestID string   `json:"-"`                                                                       // Taken from the URL
+	Token     string   `json:"token,omitempty" validate:"address" label:"Token"`                        // Empty for the native token
+	SharesBps []uint64 `json:"shares_bps" validate:"required,max=50,each=range=1:10000" label:"Shares"` // From first place, adding up to 10000
+}
+
+// PayoutAddress is a wallet a winner wants its prize sent to, signed with
+// personal_sign by the wallet that created the contestant
+type PayoutAddress struct {
+	Wallet    string `json:"wallet"`
+	Signature string `json:"signature"`
+}
+
+// PrizePayoutRequest represents the payload to pay the prize pool out to the winners
+type PrizePayoutRequest struct {
+	ContestID string                   `json:"-"`                   // Taken from the URL
+	Addresses map[string]PayoutAddress `json:"addresses,omitempty"` // Contestant ID -> signed payout address, defaults to the creator wallet of verified contestants
+}
+
+// PrizePoolResponse represents a prize pool, or the result of an operation on it
+type PrizePoolResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	TxHash  string     `json:"tx_hash,omitempty"`
+	Data    *PrizePool `json:"data,omitempty"`
+}
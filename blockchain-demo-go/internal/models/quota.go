@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// ============ QUOTA STRUCTS ============
+
+// RateQuota is the state of a client's request bucket. A limit of -1 means
+// the requests are not limited.
+type RateQuota struct {
+	Limit     int `json:"limit"` // Requests per minute
+	Remaining int `json:"remaining"`
+}
+
+// GasQuota is a client's daily gas budget
+type GasQuota struct {
+	Limit     uint64     `json:"limit"` // 0 means no budget
+	Used      uint64     `json:"used"`
+	Remaining uint64     `json:"remaining"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+}
+
+// Quota is what a client may still do
+type Quota struct {
+	Client string    `json:"client"` // Key the limits are tracked by
+	Read   RateQuota `json:"read"`
+	Write  RateQuota `json:"write"`
+	Gas    GasQuota  `json:"gas"`
+}
+
+// QuotaResponse represents the response of GET /api/v1/me/quota
+type QuotaResponse struct {
+	Success bool   `json:"success"`
+	Data    *Quota `json:"data"`
+}
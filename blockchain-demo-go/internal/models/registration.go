@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+// Registration statuses of a contestant in a contest
+const (
+	RegistrationRegistered = "registered"
+	RegistrationWaitlisted = "waitlisted"
+	RegistrationWithdrawn  = "withdrawn" // Left on their own
+	RegistrationRemoved    = "removed"   // Removed by the organizer
+)
+
+// RegistrationPolicy controls who may register for a contest and when. The
+// zero policy accepts any contestant while the contest is open or running.
+type RegistrationPolicy struct {
+	OpensAt         *time.Time `json:"opens_at,omitempty"`
+	ClosesAt        *time.Time `json:"closes_at,omitempty"`
+	MaxContestants  int        `json:"max_contestants,omitempty"`  // 0 means unlimited
+	Waitlist        bool       `json:"waitlist,omitempty"`         // Queue contestants once the contest is full
+	RequireVerified bool       `json:"require_verified,omitempty"` // Only verified contestants may register
+	MinTeamSize     int        `json:"min_team_size,omitempty"`
+	MaxTeamSize     int        `json:"max_team_size,omitempty"` // 0 means the contest does not accept teams
+}
+
+// RegistrationPolicyRequest represents the registration rules sent when creating a contest
+type RegistrationPolicyRequest struct {
+	OpensAt         string `json:"opens_at,omitempty" validate:"rfc3339" label:"Registration opening"`
+	ClosesAt        string `json:"closes_at,omitempty" validate:"rfc3339,after=OpensAt" label:"Registration closing"`
+	MaxContestants  int    `json:"max_contestants,omitempty" validate:"range=0:100000" label:"Maximum contestants"`
+	Waitlist        bool   `json:"waitlist,omitempty"`
+	RequireVerified bool   `json:"require_verified,omitempty"`
+	MinTeamSize     int    `json:"min_team_size,omitempty" validate:"range=0:100" label:"Minimum team size"`
+	MaxTeamSize     int    `json:"max_team_size,omitempty" validate:"range=0:100" label:"Maximum team size"`
+}
+
+// ContestWithdrawal records a contestant leaving a contest. Withdrawals are
+// tombstones: the registration stays in the on-chain history.
+type ContestWithdrawal struct {
+	ContestID    string    `json:"contest_id"`
+	ContestantID string    `json:"contestant_id"`
+	Status       string    `json:"status"` // RegistrationWithdrawn or RegistrationRemoved
+	Reason       string    `json:"reason,omitempty"`
+	Actor        string    `json:"actor,omitempty"`
+	Promoted     string    `json:"promoted,omitempty"` // Contestant moved up from the waitlist
+	At           time.Time `json:"at"`
+	TxHash       string    `json:"tx_hash,omitempty"`
+}
+
+// ============ WITHDRAWAL REQUEST/RESPONSE STRUCTS ============
+
+// WithdrawContestantRequest represents the request to take a contestant off a contest
+type WithdrawContestantRequest struct {
+	ContestID    string `json:"-"` // Taken from the URL
+	ContestantID string `json:"-"` // Taken from the URL
+	Reason       string `json:"reason" validate:"max=500"`
+	Actor        string `json:"-"` // Set from the authenticated wallet
+	Removed      bool   `json:"-"` // The organizer removes someone else
+}
+
+// WithdrawContestantResponse represents the response after a withdrawal or removal
+type WithdrawContestantResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	TxHash  string             `json:"tx_hash,omitempty"`
+	Data    *ContestWithdrawal `json:"data,omitempty"`
+}
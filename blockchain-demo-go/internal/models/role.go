@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ============ ROLE STRUCTS ============
+
+// RoleAssignment records a role granted to a wallet or user
+type RoleAssignment struct {
+	Account   string    `json:"account"`
+	Role      string    `json:"role"`
+	GrantedBy string    `json:"granted_by,omitempty"`
+	GrantedAt time.Time `json:"granted_at"`
+	TxHash    string    `json:"tx_hash,omitempty"` // Mirror transaction on the contract's access-control mapping
+}
+
+// GrantRoleRequest represents the payload to grant a role
+type GrantRoleRequest struct {
+	Account string `json:"account" validate:"required,max=100" label:"Account"`
+	Role    string `json:"role" validate:"required"`
+}
+
+// RoleAssignmentResponse represents the response after granting or revoking a role
+type RoleAssignmentResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	TxHash  string          `json:"tx_hash,omitempty"`
+	Data    *RoleAssignment `json:"data,omitempty"`
+}
+
+// ListRolesResponse represents the response when listing role assignments
+type ListRolesResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message,omitempty"`
+	Data    []*RoleAssignment `json:"data"`
+	Total   int               `json:"total"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+)
+
+// Sponsorship tiers, from the highest
+const (
+	TierGold      = "gold"
+	TierSilver    = "silver"
+	TierBronze    = "bronze"
+	TierSupporter = "supporter" // Below the bronze threshold
+)
+
+// Who may see a sponsor's pledges to a contest. Pledges are anchored
+// on-chain, so visibility only applies to what the API shows.
+const (
+	VisibilityPublic     = "public"      // Sponsor, amount and tier are shown
+	VisibilityHideAmount = "hide_amount" // Sponsor and tier are shown, not the amount
+	VisibilityAnonymous  = "anonymous"   // Amount and tier are shown, not the sponsor
+)
+
+// SponsorTiers sets the smallest total pledge of each tier in a contest
+type SponsorTiers struct {
+	Gold   uint64 `json:"gold"`
+	Silver uint64 `json:"silver"`
+	Bronze uint64 `json:"bronze"`
+}
+
+// SponsorTiersRequest represents the tier thresholds sent when creating a contest
+type SponsorTiersRequest struct {
+	Gold   uint64 `json:"gold" validate:"required,min=1" label:"Gold threshold"`
+	Silver uint64 `json:"silver" validate:"required,min=1" label:"Silver threshold"`
+	Bronze uint64 `json:"bronze" validate:"required,min=1" label:"Bronze threshold"`
+}
+
+// Pledge is an amount a sponsor commits to a contest
+type Pledge struct {
+	ID          string    `json:"id"`
+	ContestID   string    `json:"contest_id"`
+	SponsorID   string    `json:"sponsor_id"`
+	SponsorName string    `json:"sponsor_name,omitempty"`
+	Amount      uint64    `json:"amount"`
+	Visibility  string    `json:"visibility"`
+	Message     string    `json:"message,omitempty"`
+	PledgedBy   string    `json:"pledged_by,omitempty"` // Wallet or account that recorded the pledge
+	PledgedAt   time.Time `json:"pledged_at"`
+	TxHash      string    `json:"tx_hash,omitempty"`
+}
+
+// ContestSponsor is a sponsor's association with a contest: the sum of its
+// pledges and the tier it reaches. Hidden fields are left empty.
+type ContestSponsor struct {
+	ContestID   string    `json:"contest_id"`
+	ContestName string    `json:"contest_name,omitempty"`
+	SponsorID   string    `json:"sponsor_id,omitempty"`
+	SponsorName string    `json:"sponsor_name,omitempty"`
+	Amount      *uint64   `json:"amount,omitempty"`
+	Tier        string    `json:"tier"`
+	Visibility  string    `json:"visibility"`
+	Pledges     int       `json:"pledges"`
+	PledgedAt   time.Time `json:"pledged_at"` // Latest pledge
+}
+
+// SponsorshipSummary totals the pledges to a contest
+type SponsorshipSummary struct {
+	Total    uint64         `json:"total"` // Leaves out sponsors hiding their amount
+	Sponsors int            `json:"sponsors"`
+	Pledges  int            `json:"pledges"`
+	Tiers    map[string]int `json:"tiers"` // Tier -> number of sponsors
+}
+
+// ============ SPONSORSHIP REQUEST/RESPONSE STRUCTS ============
+
+// PledgeRequest represents a sponsor pledging an amount to a contest
+type PledgeRequest struct {
+	ContestID  string `json:"-"` // Taken from the URL
+	SponsorID  string `json:"sponsor_id" validate:"required,max=64" label:"Sponsor ID"`
+	Amount     uint64 `json:"amount" validate:"required,min=1" label:"Amount"`
+	Visibility string `json:"visibility,omitempty" validate:"max=20" label:"Visibility"` // Defaults to public
+	Message    string `json:"message,omitempty" validate:"max=500" label:"Message"`
+	PledgedBy  string `json:"-"` // Set from the caller
+}
+
+// PledgeResponse represents the response after pledging
+type PledgeResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message,omitempty"`
+	TxHash  string  `json:"tx_hash,omitempty"`
+	Data    *Pledge `json:"data,omitempty"`
+}
+
+// ListContestSponsorsResponse represents the sponsors of a contest, or the
+// contests of a sponsor
+type ListContestSponsorsResponse struct {
+	Success   bool                `json:"success"`
+	Message   string              `json:"message,omitempty"`
+	ContestID string              `json:"contest_id,omitempty"`
+	SponsorID string              `json:"sponsor_id,omitempty"`
+	Summary   *SponsorshipSummary `json:"summary,omitempty"`
+	Data      []ContestSponsor    `json:"data"`
+	Total     int                 `json:"total"`
+}
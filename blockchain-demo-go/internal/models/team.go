@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// Team statuses
+const (
+	TeamForming    = "forming"    // Waiting for members to accept
+	TeamRegistered = "registered" // Anchored on-chain, see Team.Entry
+)
+
+// Team member statuses
+const (
+	MemberInvited  = "invited"
+	MemberAccepted = "accepted"
+)
+
+// Team represents a group of wallets entering a contest together
+type Team struct {
+	ID           string       `json:"id"`
+	ContestID    string       `json:"contest_id"`
+	Name         string       `json:"name"`
+	Captain      string       `json:"captain"`
+	Members      []TeamMember `json:"members"` // The captain comes first
+	Status       string       `json:"status"`
+	Entry        string       `json:"entry,omitempty"` // RegistrationRegistered or RegistrationWaitlisted once registered
+	CreatedAt    time.Time    `json:"created_at"`
+	RegisteredAt *time.Time   `json:"registered_at,omitempty"`
+	TxHash       string       `json:"tx_hash,omitempty"`
+}
+
+// TeamMember is a wallet invited to a team. Members accept by signing Message
+// with personal_sign; the signature is anchored with the team.
+type TeamMember struct {
+	Address    string     `json:"address"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message"`
+	Signature  string     `json:"signature,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+// ============ TEAM REQUEST/RESPONSE STRUCTS ============
+
+// CreateTeamRequest represents the payload to form a team for a contest
+type CreateTeamRequest struct {
+	ContestID string   `json:"-"` // Taken from the URL
+	Name      string   `json:"name" validate:"required,max=100" label:"Team name"`
+	Captain   string   `json:"captain,omitempty" validate:"address"` // Defaults to the signed-in wallet
+	Members   []string `json:"members,omitempty" validate:"max=50,each=address"`
+}
+
+// AcceptTeamInviteRequest represents a member's signed acceptance
+type AcceptTeamInviteRequest struct {
+	Address   string `json:"address,omitempty" validate:"address" label:"Address"`    // Defaults to the signed-in wallet
+	Signature string `json:"signature" validate:"required,max=200" label:"Signature"` // personal_sign of the member's message
+}
+
+// TeamResponse represents the response for a single team
+type TeamResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	TxHash  string `json:"tx_hash,omitempty"`
+	Data    *Team  `json:"data,omitempty"`
+}
+
+// ListTeamsResponse represents the response when listing the teams of a contest
+type ListTeamsResponse struct {
+	Success   bool    `json:"success"`
+	Message   string  `json:"message,omitempty"`
+	ContestID string  `json:"contest_id"`
+	Data      []*Team `json:"data"`
+	Total     int     `json:"total"`
+}
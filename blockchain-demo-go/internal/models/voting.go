@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// Voting phases of a contest decided by audience vote
+const (
+	VotingCommit = "commit" // Wallets submit the hash of their vote
+	VotingReveal = "reveal" // Wallets reveal the vote behind their hash
+	VotingEnded  = "ended"  // The tally is final
+)
+
+// VotingPolicy sets the phases of a commit-reveal vote. Commits are accepted
+// while the contest is active until CommitEndsAt, reveals until RevealEndsAt.
+type VotingPolicy struct {
+	CommitEndsAt time.Time `json:"commit_ends_at"`
+	RevealEndsAt time.Time `json:"reveal_ends_at"`
+}
+
+// VotingPolicyRequest represents the voting phases sent when creating a contest
+type VotingPolicyRequest struct {
+	CommitEndsAt string `json:"commit_ends_at" validate:"required,rfc3339" label:"Commit end"`
+	RevealEndsAt string `json:"reveal_ends_at" validate:"required,rfc3339,after=CommitEndsAt" label:"Reveal end"`
+}
+
+// Vote is one wallet's vote in a contest. The commitment is
+// keccak256(abi.encodePacked(contestId, contestantId, salt, voter)), so
+// anyone can check a revealed vote against it.
+type Vote struct {
+	ContestID    string     `json:"contest_id"`
+	Voter        string     `json:"voter"`
+	Commitment   string     `json:"commitment"`
+	Signature    string     `json:"signature,omitempty"`     // personal_sign of the commit message by the voter
+	ContestantID string     `json:"contestant_id,omitempty"` // Set once revealed
+	Salt         string     `json:"salt,omitempty"`          // Set once revealed
+	Verified     bool       `json:"verified"`                // The reveal matches the commitment
+	CommittedAt  time.Time  `json:"committed_at"`
+	RevealedAt   *time.Time `json:"revealed_at,omitempty"`
+	TxHash       string     `json:"tx_hash,omitempty"`
+}
+
+// VoteCount is the number of verified votes for one contestant
+type VoteCount struct {
+	Rank         int    `json:"rank"`
+	ContestantID string `json:"contestant_id"`
+	Votes        int    `json:"votes"`
+}
+
+// VoteTally counts the revealed votes of a contest. Votes lists every
+// commitment and reveal so the tally can be checked independently.
+type VoteTally struct {
+	ContestID    string      `json:"contest_id"`
+	Phase        string      `json:"phase"`
+	Final        bool        `json:"final"`
+	CommitEndsAt time.Time   `json:"commit_ends_at"`
+	RevealEndsAt time.Time   `json:"reveal_ends_at"`
+	Committed    int         `json:"committed"`
+	Revealed     int         `json:"revealed"`
+	Invalid      int         `json:"invalid"` // Revealed for someone who is not a registered contestant
+	Results      []VoteCount `json:"results"`
+	Votes        []*Vote     `json:"votes"`
+}
+
+// ============ VOTING REQUEST/RESPONSE STRUCTS ============
+
+// CommitVoteRequest represents a wallet committing to a hidden vote
+type CommitVoteRequest struct {
+	ContestID  string `json:"-"`                                                // Taken from the URL
+	Voter      string `json:"voter,omitempty" validate:"address" label:"Voter"` // Defaults to the signed-in wallet
+	Commitment string `json:"commitment" validate:"required,max=66" label:"Commitment"`
+	Signature  string `json:"signature" validate:"required,max=200" label:"Signature"` // personal_sign of the commit message
+}
+
+// RevealVoteRequest represents a wallet revealing the vote behind its commitment
+type RevealVoteRequest struct {
+	ContestID    string `json:"-"`                                                // Taken from the URL
+	Voter        string `json:"voter,omitempty" validate:"address" label:"Voter"` // Defaults to the signed-in wallet
+	ContestantID string `json:"contestant_id" validate:"required,max=100" label:"Contestant ID"`
+	Salt         string `json:"salt" validate:"required,max=66" label:"Salt"` // 32 bytes, hex
+}
+
+// VoteResponse represents the response after a commit or a reveal
+type VoteResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	TxHash  string `json:"tx_hash,omitempty"`
+	Data    *Vote  `json:"data,omitempty"`
+}
+
+// VoteTallyResponse represents the response for the results of a vote
+type VoteTallyResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	Data    *VoteTally `json:"data,omitempty"`
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"time"
+)
+
+// ============ WIKI LINK STRUCTS ============
+
+// WikiLink represents a [[Title]] link found in a content body
+type WikiLink struct {
+	Target   string `json:"target"`
+	TargetID string `json:"target_id,omitempty"`
+	Broken   bool   `json:"broken"`
+}
+
+// ContentRef is a lightweight reference to a piece of content
+type ContentRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// BrokenLink represents a link pointing to an article that does not exist
+type BrokenLink struct {
+	SourceID    string `json:"source_id"`
+	SourceTitle string `json:"source_title"`
+	Target      string `json:"target"`
+}
+
+// GraphNode represents an article in the link graph
+type GraphNode struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Missing   bool   `json:"missing"`
+	InDegree  int    `json:"in_degree"`
+	OutDegree int    `json:"out_degree"`
+}
+
+// GraphEdge represents a link between two articles in the link graph
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// LinkGraph is the full article graph used by the globe canvas
+type LinkGraph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+}
+
+// ============ WIKI RESPONSE STRUCTS ============
+
+// ContentLinksResponse represents the response when getting forward links of content
+type ContentLinksResponse struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	ContentID string      `json:"content_id"`
+	Links     []*WikiLink `json:"links"`
+	Total     int         `json:"total"`
+}
+
+// BacklinksResponse represents the response when getting backlinks of content
+type BacklinksResponse struct {
+	Success   bool          `json:"success"`
+	Message   string        `json:"message,omitempty"`
+	ContentID string        `json:"content_id"`
+	Backlinks []*ContentRef `json:"backlinks"`
+	Total     int           `json:"total"`
+}
+
+// LinkGraphResponse represents the response for the link graph export
+type LinkGraphResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	Data    *LinkGraph `json:"data,omitempty"`
+}
+
+// BrokenLinksReportResponse represents the maintenance report of broken links
+type BrokenLinksReportResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Data    []*BrokenLink `json:"data"`
+	Total   int           `json:"total"`
+}
+
+// ============ TAXONOMY STRUCTS ============
+
+// TaxonomyItem is an entity (content or contest) classified by namespace, categories and tags
+type TaxonomyItem struct {
+	Kind       string   `json:"kind"` // "content" or "contest"
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// CategoryMember is an entity listed under a category
+type CategoryMember struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"` // The (sub)category the entity was filed under
+}
+
+// Category represents a node in the category hierarchy
+type Category struct {
+	Name          string            `json:"name"`
+	Parent        string            `json:"parent,omitempty"`
+	Subcategories []string          `json:"subcategories"`
+	Members       []*CategoryMember `json:"members"`
+}
+
+// TagCount represents a tag and its usage count in a tag cloud
+type TagCount struct {
+	Tag    string `json:"tag"`
+	Count  int    `json:"count"`
+	Weight int    `json:"weight"` // 1 (rare) to 5 (popular)
+}
+
+// ============ TAXONOMY RESPONSE STRUCTS ============
+
+// GetCategoryResponse represents the response when getting a category
+type GetCategoryResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message,omitempty"`
+	Data    *Category `json:"data,omitempty"`
+	Total   int       `json:"total"`
+}
+
+// ListCategoriesResponse represents the response when listing top-level categories
+type ListCategoriesResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Data    []string `json:"data"`
+	Total   int      `json:"total"`
+}
+
+// TagCloudResponse represents the response for the tag cloud
+type TagCloudResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    []*TagCount `json:"data"`
+	Total   int         `json:"total"`
+}
+
+// ============ REVISION & DIFF STRUCTS ============
+
+// Revision represents one stored version of a wiki article
+type Revision struct {
+	ContentID  string    `json:"content_id"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Categories []string  `json:"categories,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Creator    string    `json:"creator"`
+	TxHash     string    `json:"tx_hash,omitempty"`
+	RecordHash string    `json:"record_hash,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// RevisionRef identifies a revision without its body. TxHash is the
+// transaction that anchored it and RecordHash the hash the contract keeps
+// of the anchored record (getContentRevisions).
+type RevisionRef struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Creator    string    `json:"creator"`
+	TxHash     string    `json:"tx_hash,omitempty"`
+	RecordHash string    `json:"record_hash,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// DiffSegment is a word-level change inside a changed line
+type DiffSegment struct {
+	Op   string `json:"op"` // "equal", "insert" or "delete"
+	Text string `json:"text"`
+}
+
+// DiffLine is a line of a line-level diff
+type DiffLine struct {
+	Op        string         `json:"op"` // "equal", "insert" or "delete"
+	OldNumber int            `json:"old_number,omitempty"`
+	NewNumber int            `json:"new_number,omitempty"`
+	Text      string         `json:"text"`
+	Segments  []*DiffSegment `json:"segments,omitempty"`
+}
+
+// DiffStats summarizes a diff
+type DiffStats struct {
+	LinesAdded   int `json:"lines_added"`
+	LinesRemoved int `json:"lines_removed"`
+	WordsAdded   int `json:"words_added"`
+	WordsRemoved int `json:"words_removed"`
+}
+
+// ContentDiff is the difference between two revisions of an article
+type ContentDiff struct {
+	ContentID string       `json:"content_id"`
+	From      *RevisionRef `json:"from"`
+	To        *RevisionRef `json:"to"`
+	Lines     []*DiffLine  `json:"lines"`
+	Stats     *DiffStats   `json:"stats"`
+	Unified   string       `json:"unified"`
+}
+
+// ============ REVISION RESPONSE STRUCTS ============
+
+// ListRevisionsResponse represents the response when listing revisions of an article
+type ListRevisionsResponse struct {
+	Success   bool           `json:"success"`
+	Message   string         `json:"message,omitempty"`
+	ContentID string         `json:"content_id"`
+	Data      []*RevisionRef `json:"data"`
+	Total     int            `json:"total"`
+}
+
+// ContentDiffResponse represents the response when diffing two revisions
+type ContentDiffResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    *ContentDiff `json:"data,omitempty"`
+}
@@ -0,0 +1,110 @@
+package moderation
+
+import (
+	"blockchain-demo/internal/models"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesCheck(t *testing.T) {
+	rules := NewRules([]string{"spam", "đồ ngốc"}, 2, 50)
+
+	assert.Empty(t, rules.Check("Hello", "A classic board game"))
+	assert.Len(t, rules.Check("Buy SPAM now", "ok"), 1)
+	assert.Len(t, rules.Check("Title", "Bạn là do ngoc"), 1, "diacritics are folded")
+	assert.Empty(t, rules.Check("Title", "spammer is not a whole-word match"))
+	assert.Len(t, rules.Check("Links", "http://a.com https://b.com www.c.com"), 1)
+	assert.Len(t, rules.Check("Long", "Lorem ipsum dolor sit amet, consectetur adipiscing elit"), 1)
+
+	var disabled *Rules
+	assert.Empty(t, disabled.Check("spam", "spam"))
+}
+
+func TestQueueWorkflow(t *testing.T) {
+	q := NewQueue()
+	req := &models.CreateContentRequest{Title: "T", Content: "C"}
+
+	sub := q.Submit(req, "")
+	assert.Equal(t, models.SubmissionPending, sub.Status)
+	assert.Len(t, q.List(models.SubmissionPending), 1)
+
+	claimed, err := q.Claim(sub.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, sub.ID, claimed.ID)
+
+	_, err = q.Claim(sub.ID)
+	assert.ErrorIs(t, err, ErrInProgress)
+	_, err = q.Reject(sub.ID, "mod", "late")
+	assert.ErrorIs(t, err, ErrInProgress)
+
+	q.Release(sub.ID)
+	_, err = q.Claim(sub.ID)
+	assert.NoError(t, err)
+
+	approved, err := q.Approve(sub.ID, "mod", "", &models.CreateContentResponse{ID: "content-1", TxHash: "0xabc", RecordHash: "0xdef"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.SubmissionApproved, approved.Status)
+	assert.Equal(t, "content-1", approved.ContentID)
+	assert.Equal(t, "0xabc", approved.TxHash)
+	assert.Equal(t, "0xdef", approved.RecordHash)
+
+	_, err = q.Reject(sub.ID, "mod", "too late")
+	assert.ErrorIs(t, err, ErrAlreadyHandled)
+
+	other := q.Submit(req, "content-1")
+	rejected, err := q.Reject(other.ID, "mod", "off-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, "off-topic", rejected.Reason)
+
+	auto := q.RecordRejected(req, "", []string{"banned"})
+	assert.Equal(t, "automatic", auto.Moderator)
+	assert.Len(t, q.List(models.SubmissionRejected), 2)
+
+	_, err = q.Claim("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moderation")
+	q, err := OpenQueue(path)
+	require.NoError(t, err)
+	req := &models.CreateContentRequest{Title: "T", Content: "C"}
+
+	pending := q.Submit(req, "")
+	approved := q.Submit(req, "")
+	_, err = q.Claim(approved.ID)
+	require.NoError(t, err)
+	_, err = q.Approve(approved.ID, "mod", "", &models.CreateContentResponse{ID: "content-1", TxHash: "0xabc"})
+	require.NoError(t, err)
+	rejected := q.Submit(req, "content-1")
+	_, err = q.Reject(rejected.ID, "mod", "off-topic")
+	require.NoError(t, err)
+	q.RecordRejected(req, "", []string{"banned"})
+
+	// A claim in flight is not kept: the submission is pending again
+	inFlight := q.Submit(req, "")
+	_, err = q.Claim(inFlight.ID)
+	require.NoError(t, err)
+	require.NoError(t, q.Close())
+
+	q, err = OpenQueue(path)
+	require.NoError(t, err)
+	defer q.Close()
+
+	assert.Len(t, q.List(""), 5)
+	assert.Len(t, q.List(models.SubmissionPending), 2)
+	assert.Len(t, q.List(models.SubmissionRejected), 2)
+	restored, ok := q.Get(approved.ID)
+	require.True(t, ok)
+	assert.Equal(t, "0xabc", restored.TxHash)
+	restored, ok = q.Get(rejected.ID)
+	require.True(t, ok)
+	assert.Equal(t, "off-topic", restored.Reason)
+	assert.Equal(t, pending.SubmittedAt.Unix(), q.List(models.SubmissionPending)[0].SubmittedAt.Unix())
+
+	_, err = q.Claim(inFlight.ID)
+	assert.NoError(t, err)
+}
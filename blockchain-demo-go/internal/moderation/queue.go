@@ -0,0 +1,244 @@
+package moderation
+
+import (
+	"blockchain-demo/internal/models"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// Errors returned by the queue
+var (
+	ErrNotFound       = fmt.Errorf("submission not found")
+	ErrAlreadyHandled = fmt.Errorf("submission already reviewed")
+	ErrInProgress     = fmt.Errorf("submission is being approved")
+)
+
+// Queue stores submissions waiting for moderation and keeps reviewed ones
+// (including rejections) off-chain for auditing. Every submission is written
+// to an embedded LevelDB database and loaded back on start, so pending
+// submissions and the review history survive restarts.
+type Queue struct {
+	mu          sync.RWMutex
+	db          *leveldb.DB
+	submissions map[string]*models.Submission
+	approving   map[string]bool // Not stored: an approval in flight is pending again after a restart
+}
+
+// NewQueue creates an empty in-memory moderation queue
+func NewQueue() *Queue {
+	q, _ := OpenQueue("")
+	return q
+}
+
+// OpenQueue opens the moderation queue stored at path, or an in-memory queue
+// if path is empty
+func OpenQueue(path string) (*Queue, error) {
+	var db *leveldb.DB
+	var err error
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open moderation queue: %v", err)
+	}
+
+	q := &Queue{
+		db:          db,
+		submissions: make(map[string]*models.Submission),
+		approving:   make(map[string]bool),
+	}
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		var sub models.Submission
+		if err := json.Unmarshal(iter.Value(), &sub); err != nil {
+			return nil, fmt.Errorf("corrupt submission %q: %v", iter.Key(), err)
+		}
+		q.submissions[sub.ID] = &sub
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to load submissions: %v", err)
+	}
+	return q, nil
+}
+
+// Close closes the database
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// save writes a submission. The change is kept in memory even if it could
+// not be written to disk. The caller must hold the write lock.
+func (q *Queue) save(sub *models.Submission) {
+	data, err := json.Marshal(sub)
+	if err == nil {
+		err = q.db.Put([]byte(sub.ID), data, nil)
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to persist submission %s: %v", sub.ID, err)
+	}
+}
+
+// Submit adds a content request to the queue as pending. targetID is the ID of
+// the content being edited, empty for new content.
+func (q *Queue) Submit(req *models.CreateContentRequest, targetID string) *models.Submission {
+	return q.add(&models.Submission{
+		Status:   models.SubmissionPending,
+		TargetID: targetID,
+		Request:  *req,
+	})
+}
+
+// RecordRejected stores a submission rejected by the automatic checks
+func (q *Queue) RecordRejected(req *models.CreateContentRequest, targetID string, violations []string) *models.Submission {
+	now := time.Now()
+	return q.add(&models.Submission{
+		Status:     models.SubmissionRejected,
+		TargetID:   targetID,
+		Request:    *req,
+		ReviewedAt: &now,
+		Moderator:  "automatic",
+		Reason:     "failed automatic checks",
+		Violations: violations,
+	})
+}
+
+func (q *Queue) add(sub *models.Submission) *models.Submission {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sub.ID = generateID()
+	sub.SubmittedAt = time.Now()
+	q.submissions[sub.ID] = sub
+	q.save(sub)
+	copied := *sub
+	return &copied
+}
+
+// Get returns a copy of a submission
+func (q *Queue) Get(id string) (*models.Submission, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	sub, exists := q.submissions[id]
+	if !exists {
+		return nil, false
+	}
+	copied := *sub
+	return &copied, true
+}
+
+// List returns submissions with the given status (all if empty), oldest first
+func (q *Queue) List(status string) []*models.Submission {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	subs := make([]*models.Submission, 0, len(q.submissions))
+	for _, sub := range q.submissions {
+		if status == "" || sub.Status == status {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].SubmittedAt.Before(subs[j].SubmittedAt) })
+	return subs
+}
+
+// Claim marks a pending submission as being approved so it is anchored only once.
+// The caller must call either Approve or Release afterwards.
+func (q *Queue) Claim(id string) (*models.Submission, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sub, exists := q.submissions[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if sub.Status != models.SubmissionPending {
+		return nil, ErrAlreadyHandled
+	}
+	if q.approving[id] {
+		return nil, ErrInProgress
+	}
+	q.approving[id] = true
+	copied := *sub
+	return &copied, nil
+}
+
+// Release gives back a claimed submission, e.g. when anchoring failed
+func (q *Queue) Release(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.approving, id)
+}
+
+// Approve marks a claimed submission as approved, with the revision it was
+// anchored as
+func (q *Queue) Approve(id, moderator, reason string, anchored *models.CreateContentResponse) (*models.Submission, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sub, exists := q.submissions[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	delete(q.approving, id)
+
+	now := time.Now()
+	sub.Status = models.SubmissionApproved
+	sub.ReviewedAt = &now
+	sub.Moderator = moderator
+	sub.Reason = reason
+	sub.ContentID = anchored.ID
+	sub.TxHash = anchored.TxHash
+	sub.RecordHash = anchored.RecordHash
+	q.save(sub)
+	copied := *sub
+	return &copied, nil
+}
+
+// Reject marks a pending submission as rejected with a reason
+func (q *Queue) Reject(id, moderator, reason string) (*models.Submission, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	sub, exists := q.submissions[id]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	if sub.Status != models.SubmissionPending {
+		return nil, ErrAlreadyHandled
+	}
+	if q.approving[id] {
+		return nil, ErrInProgress
+	}
+
+	now := time.Now()
+	sub.Status = models.SubmissionRejected
+	sub.ReviewedAt = &now
+	sub.Moderator = moderator
+	sub.Reason = reason
+	q.save(sub)
+	copied := *sub
+	return &copied, nil
+}
+
+// generateID generates a random submission ID
+func generateID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("submission_%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(bytes)
+}
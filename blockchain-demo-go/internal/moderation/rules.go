@@ -0,0 +1,90 @@
+// Package moderation holds the pre-anchoring moderation queue and the automatic
+// checks run on submissions before they enter it.
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// urlPattern matches external links in a body
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>()\[\]]+|\bwww\.[^\s<>()\[\]]+`)
+
+// Rules are the automatic checks run on every content submission
+type Rules struct {
+	BannedWords []string
+	MaxLinks    int // Maximum number of external links, 0 disables the check
+	MaxLength   int // Maximum body length in characters, 0 disables the check
+
+	banned []*regexp.Regexp
+}
+
+// NewRules compiles the banned word list into the rules
+func NewRules(bannedWords []string, maxLinks, maxLength int) *Rules {
+	rules := &Rules{
+		BannedWords: bannedWords,
+		MaxLinks:    maxLinks,
+		MaxLength:   maxLength,
+	}
+	for _, word := range bannedWords {
+		word = fold(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		// Whole-word match, so banning "ass" does not reject "class"
+		pattern := `(^|[^\pL\pN])` + regexp.QuoteMeta(word) + `($|[^\pL\pN])`
+		rules.banned = append(rules.banned, regexp.MustCompile(pattern))
+	}
+	return rules
+}
+
+// Check returns the list of violations of a submission, empty if it passes
+func (r *Rules) Check(title, body string) []string {
+	if r == nil {
+		return nil
+	}
+
+	var violations []string
+
+	if r.MaxLength > 0 {
+		if length := utf8.RuneCountInString(body); length > r.MaxLength {
+			violations = append(violations, fmt.Sprintf("content is %d characters long, maximum is %d", length, r.MaxLength))
+		}
+	}
+
+	if r.MaxLinks > 0 {
+		if links := len(urlPattern.FindAllString(body, -1)); links > r.MaxLinks {
+			violations = append(violations, fmt.Sprintf("content has %d external links, maximum is %d", links, r.MaxLinks))
+		}
+	}
+
+	text := fold(title + "\n" + body)
+	for i, pattern := range r.banned {
+		if pattern.MatchString(text) {
+			violations = append(violations, fmt.Sprintf("content contains banned word #%d", i+1))
+		}
+	}
+
+	return violations
+}
+
+// fold lower-cases text and strips diacritics so "Đồ ngốc" matches "do ngoc"
+func fold(s string) string {
+	t := norm.NFD.String(strings.ToLower(s))
+	out := make([]rune, 0, len(t))
+	for _, r := range t {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r == 'đ' {
+			r = 'd'
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned when a client has spent its daily gas
+var ErrBudgetExceeded = apperr.New(apperr.KindRateLimited, models.ErrorCodeGasBudgetExceeded, "daily gas budget exceeded")
+
+// GasBudget caps the gas each client can spend per UTC day
+type GasBudget struct {
+	mu    sync.Mutex
+	daily uint64
+	day   time.Time
+	used  map[string]uint64
+	now   func() time.Time
+}
+
+// NewGasBudget creates a budget of daily gas per client. daily == 0 returns
+// nil, which allows everything.
+func NewGasBudget(daily uint64) *GasBudget {
+	if daily == 0 {
+		return nil
+	}
+	return &GasBudget{
+		daily: daily,
+		used:  make(map[string]uint64),
+		now:   time.Now,
+	}
+}
+
+// Check reports whether the client has budget left, and when it has not the
+// time until the budget resets
+func (b *GasBudget) Check(key string) (time.Duration, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	reset := b.rollover()
+	if b.used[key] >= b.daily {
+		return reset.Sub(b.now()), ErrBudgetExceeded
+	}
+	return 0, nil
+}
+
+// Reserve charges gas to the client. When the budget would be exceeded
+// nothing is charged and the time until the budget resets is returned.
+func (b *GasBudget) Reserve(key string, gas uint64) (time.Duration, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	reset := b.rollover()
+	if b.used[key]+gas > b.daily {
+		return reset.Sub(b.now()), ErrBudgetExceeded
+	}
+	b.used[key] += gas
+	return 0, nil
+}
+
+// Refund gives back gas reserved for a transaction that was not sent
+func (b *GasBudget) Refund(key string, gas uint64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	if b.used[key] <= gas {
+		delete(b.used, key)
+		return
+	}
+	b.used[key] -= gas
+}
+
+// Usage returns the gas spent by the client today, the daily budget and when
+// the budget resets
+func (b *GasBudget) Usage(key string) (used, daily uint64, resetAt time.Time) {
+	if b == nil {
+		return 0, 0, time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	reset := b.rollover()
+	return b.used[key], b.daily, reset
+}
+
+// rollover clears usage when a new day starts and returns the next reset
+func (b *GasBudget) rollover() time.Time {
+	today := b.now().UTC().Truncate(24 * time.Hour)
+	if !today.Equal(b.day) {
+		b.day = today
+		b.used = make(map[string]uint64)
+	}
+	return today.Add(24 * time.Hour)
+}
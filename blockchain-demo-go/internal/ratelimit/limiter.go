@@ -0,0 +1,100 @@
+// Package ratelimit throttles API clients with token buckets and caps the gas
+// each client can make the server's wallet spend per day.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many calls pass between removals of idle buckets
+const sweepEvery = 1024
+
+// Limiter is a set of token buckets, one per client key. Every bucket holds
+// up to limit tokens and refills at limit tokens per minute.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   float64
+	rate    float64 // tokens per second
+	buckets map[string]*bucket
+	calls   int
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter creates a limiter allowing perMinute requests per client with
+// bursts of the same size. perMinute <= 0 returns nil, which allows everything.
+func NewLimiter(perMinute int) *Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &Limiter{
+		limit:   float64(perMinute),
+		rate:    float64(perMinute) / 60,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the client's bucket. When the bucket is empty it
+// returns false and how long until the next token.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep()
+	}
+
+	b := l.refill(key)
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// Remaining returns the tokens left in the client's bucket and the bucket size
+func (l *Limiter) Remaining(key string) (remaining, limit int) {
+	if l == nil {
+		return -1, -1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(math.Floor(l.refill(key).tokens)), int(l.limit)
+}
+
+// refill returns the client's bucket topped up for the time elapsed
+func (l *Limiter) refill(key string) *bucket {
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.limit, updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	b.tokens = math.Min(l.limit, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+	b.updated = now
+	return b
+}
+
+// sweep drops buckets that have refilled completely; they hold no state
+func (l *Limiter) sweep() {
+	full := time.Duration(l.limit / l.rate * float64(time.Second))
+	now := l.now()
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
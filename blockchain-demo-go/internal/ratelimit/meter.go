@@ -0,0 +1,48 @@
+package ratelimit
+
+import "context"
+
+// Meter charges the gas of the transactions a request sends to the daily
+// budget of its client. The service charges it with the estimated gas of each
+// transaction right before sending it, so only gas actually spent is counted.
+type Meter struct {
+	budget *GasBudget
+	key    string
+}
+
+// NewMeter creates a meter charging the budget of a client
+func NewMeter(budget *GasBudget, key string) *Meter {
+	return &Meter{budget: budget, key: key}
+}
+
+type meterKey struct{}
+
+// WithMeter attaches a meter to a request context
+func WithMeter(ctx context.Context, meter *Meter) context.Context {
+	return context.WithValue(ctx, meterKey{}, meter)
+}
+
+// MeterFromContext returns the meter of a request, nil outside the API (the
+// scheduler, for example), which charges nothing
+func MeterFromContext(ctx context.Context) *Meter {
+	meter, _ := ctx.Value(meterKey{}).(*Meter)
+	return meter
+}
+
+// Charge reserves gas for a transaction about to be sent. It fails with
+// ErrBudgetExceeded, and nothing is charged, when the budget would be exceeded.
+func (m *Meter) Charge(gas uint64) error {
+	if m == nil {
+		return nil
+	}
+	_, err := m.budget.Reserve(m.key, gas)
+	return err
+}
+
+// Refund gives back the gas of a transaction the node did not accept
+func (m *Meter) Refund(gas uint64) {
+	if m == nil {
+		return
+	}
+	m.budget.Refund(m.key, gas)
+}
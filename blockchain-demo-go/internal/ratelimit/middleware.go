@@ -0,0 +1,205 @@
+package ratelimit
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimiter applies the request limits and gas budgets to HTTP handlers
+type RateLimiter struct {
+	read    *Limiter
+	write   *Limiter
+	budget  *GasBudget
+	proxies []*net.IPNet
+}
+
+// New creates a rate limiter. Nil limiters and budgets allow everything.
+// X-Forwarded-For entries are only trusted when added by one of proxies.
+func New(read, write *Limiter, budget *GasBudget, proxies []*net.IPNet) *RateLimiter {
+	return &RateLimiter{read: read, write: write, budget: budget, proxies: proxies}
+}
+
+// ParseProxies parses the addresses of trusted reverse proxies, as IPs or
+// CIDR ranges
+func ParseProxies(values []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address %q", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy range %q: %v", value, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// ClientKey identifies the client limits are tracked by: the API key, the
+// wallet, the token subject, or the IP address of anonymous callers
+func (rl *RateLimiter) ClientKey(r *http.Request) string {
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil {
+		switch {
+		case identity.Method == auth.MethodAPIKey:
+			return identity.Subject
+		case identity.Address != "":
+			return "wallet:" + strings.ToLower(identity.Address)
+		default:
+			return identity.Method + ":" + identity.Subject
+		}
+	}
+	return "ip:" + rl.clientIP(r)
+}
+
+// BudgetKey identifies the client gas budgets are tracked by. It is the
+// ClientKey, except for wallets signed in with SIWE that hold no scope from an
+// allowlist or role: anyone can mint such a wallet, so they share the budget
+// of their IP address with anonymous callers.
+func (rl *RateLimiter) BudgetKey(r *http.Request) string {
+	if identity := auth.IdentityFromContext(r.Context()); identity != nil && identity.Method == auth.MethodSIWE && len(identity.Scopes) == 0 {
+		return "ip:" + rl.clientIP(r)
+	}
+	return rl.ClientKey(r)
+}
+
+// clientIP walks X-Forwarded-For from the right while the hop that added an
+// entry is a trusted proxy. The first address not added by one is the client;
+// entries further left were written by the client and may be forged.
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	for i := len(hops) - 1; i >= 0 && rl.trusted(client); i-- {
+		if net.ParseIP(hops[i]) == nil {
+			break
+		}
+		client = hops[i]
+	}
+	return client
+}
+
+// trusted reports whether an address belongs to a trusted proxy
+func (rl *RateLimiter) trusted(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range rl.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware takes a token from the client's read or write bucket. It must run
+// after the authenticator so authenticated clients get their own bucket.
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limiter := rl.read
+		if isWrite(r.Method) {
+			limiter = rl.write
+		}
+		key := rl.ClientKey(r)
+		ok, wait := limiter.Allow(key)
+		if remaining, limit := limiter.Remaining(key); limit > 0 {
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		}
+		if !ok {
+			log.Printf("🚦 Rate limit hit by %s on %s %s", key, r.Method, r.URL.Path)
+			tooManyRequests(w, wait, models.ErrorCodeRateLimited, "Too many requests", "retry after the Retry-After delay")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Budget refuses writes from clients that have spent their daily gas, and
+// attaches a Meter to the others. The service charges the meter with the
+// estimated gas of every transaction it sends for the request, so writes that
+// fail before sending cost nothing and writes that sent one are never refunded.
+func (rl *RateLimiter) Budget(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rl.budget == nil || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		key := rl.BudgetKey(r)
+		if wait, err := rl.budget.Check(key); err != nil {
+			log.Printf("⛽ Gas budget exhausted by %s on %s %s", key, r.Method, r.URL.Path)
+			tooManyRequests(w, wait, models.ErrorCodeGasBudgetExceeded, "Daily gas budget exceeded", err.Error())
+			return
+		}
+		next(w, r.WithContext(WithMeter(r.Context(), NewMeter(rl.budget, key))))
+	}
+}
+
+// Quota returns what the client of a request may still do
+func (rl *RateLimiter) Quota(r *http.Request) *models.Quota {
+	key := rl.ClientKey(r)
+	quota := &models.Quota{Client: key}
+	quota.Read.Remaining, quota.Read.Limit = rl.read.Remaining(key)
+	quota.Write.Remaining, quota.Write.Limit = rl.write.Remaining(key)
+
+	used, daily, resetAt := rl.budget.Usage(rl.BudgetKey(r))
+	quota.Gas = models.GasQuota{Limit: daily, Used: used}
+	if daily > 0 {
+		quota.Gas.Remaining = daily - min(used, daily)
+		quota.Gas.ResetAt = &resetAt
+	}
+	return quota
+}
+
+// isWrite reports whether a method changes state
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// tooManyRequests writes a 429 with a Retry-After in whole seconds
+func tooManyRequests(w http.ResponseWriter, wait time.Duration, code, message, details string) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+	writeError(w, http.StatusTooManyRequests, code, message, details)
+}
+
+// writeError writes problem details in the API's error format
+func writeError(w http.ResponseWriter, status int, code, message, details string) {
+	apperr.Write(w, nil, status, code, message, details)
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"blockchain-demo/internal/auth"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestLimiter(t *testing.T) {
+	c := &clock{t: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)}
+	l := NewLimiter(60)
+	l.now = c.now
+
+	for i := 0; i < 60; i++ {
+		ok, _ := l.Allow("a")
+		require.True(t, ok, "request %d within the burst", i)
+	}
+	ok, wait := l.Allow("a")
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// Other clients have their own bucket
+	ok, _ = l.Allow("b")
+	assert.True(t, ok)
+
+	c.advance(time.Second)
+	ok, _ = l.Allow("a")
+	assert.True(t, ok, "one token refilled after a second")
+
+	remaining, limit := l.Remaining("a")
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, 60, limit)
+
+	var disabled *Limiter = NewLimiter(0)
+	ok, _ = disabled.Allow("a")
+	assert.True(t, ok)
+}
+
+func TestGasBudget(t *testing.T) {
+	c := &clock{t: time.Date(2025, 7, 1, 23, 0, 0, 0, time.UTC)}
+	b := NewGasBudget(1000)
+	b.now = c.now
+
+	_, err := b.Reserve("a", 600)
+	require.NoError(t, err)
+	wait, err := b.Reserve("a", 600)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Equal(t, time.Hour, wait, "the budget resets at UTC midnight")
+
+	b.Refund("a", 600)
+	_, err = b.Reserve("a", 1000)
+	assert.NoError(t, err)
+
+	c.advance(time.Hour)
+	used, daily, resetAt := b.Usage("a")
+	assert.Zero(t, used, "usage is cleared on a new day")
+	assert.Equal(t, uint64(1000), daily)
+	assert.Equal(t, time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC), resetAt)
+}
+
+func TestMiddleware(t *testing.T) {
+	rl := New(NewLimiter(1), NewLimiter(1), nil, nil)
+	handler := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(method string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/api/v1/contests", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		handler.ServeHTTP(rr, r)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, request("GET").Code)
+	rr := request("GET")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), `"code":"rate_limited"`)
+
+	// Writes use a separate bucket
+	assert.Equal(t, http.StatusOK, request("POST").Code)
+}
+
+func TestBudgetChargesTheGasSent(t *testing.T) {
+	rl := New(nil, nil, NewGasBudget(100000), nil)
+	var sent []uint64
+	handler := rl.Budget(func(w http.ResponseWriter, r *http.Request) {
+		meter := MeterFromContext(r.Context())
+		require.NotNil(t, meter)
+		for _, gas := range sent {
+			if err := meter.Charge(gas); err != nil {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		}
+		// Failing after a transaction was sent keeps its gas charged
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	post := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/api/v1/content", strings.NewReader(`{"title":1}`)))
+		return rr
+	}
+
+	// A request failing before sending anything costs nothing
+	assert.Equal(t, http.StatusConflict, post().Code)
+	used, _, _ := rl.budget.Usage("ip:192.0.2.1")
+	assert.Zero(t, used)
+
+	sent = []uint64{60000, 30000}
+	assert.Equal(t, http.StatusConflict, post().Code)
+	used, _, _ = rl.budget.Usage("ip:192.0.2.1")
+	assert.Equal(t, uint64(90000), used)
+
+	// A transaction over the budget is refused before it is sent
+	assert.Equal(t, http.StatusTooManyRequests, post().Code)
+	used, _, _ = rl.budget.Usage("ip:192.0.2.1")
+	assert.Equal(t, uint64(90000), used)
+
+	sent = []uint64{10000}
+	assert.Equal(t, http.StatusConflict, post().Code)
+	rr := post()
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), `"code":"gas_budget_exceeded"`)
+
+	// Outside a request nothing is metered
+	assert.NoError(t, MeterFromContext(context.Background()).Charge(1<<40))
+}
+
+func TestClientKey(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	require.NoError(t, err)
+	rl := New(nil, nil, nil, proxies)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	assert.Equal(t, "ip:203.0.113.7", rl.ClientKey(r))
+
+	// Entries the client wrote before the trusted proxies are ignored
+	r.Header.Set("X-Forwarded-For", "198.51.100.99, 203.0.113.7, 10.0.0.1")
+	assert.Equal(t, "ip:203.0.113.7", rl.ClientKey(r))
+
+	// Headers from untrusted peers are ignored
+	direct := httptest.NewRequest("GET", "/", nil)
+	direct.RemoteAddr = "203.0.113.50:1234"
+	direct.Header.Set("X-Forwarded-For", "198.51.100.99")
+	assert.Equal(t, "ip:203.0.113.50", rl.ClientKey(direct))
+	assert.Equal(t, "ip:192.0.2.1", New(nil, nil, nil, nil).ClientKey(r))
+
+	_, err = ParseProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+
+	wallet := r.WithContext(auth.WithIdentity(r.Context(), &auth.Identity{Subject: "0xAbC", Address: "0xAbC", Method: auth.MethodSIWE}))
+	assert.Equal(t, "wallet:0xabc", rl.ClientKey(wallet))
+
+	key := r.WithContext(auth.WithIdentity(r.Context(), &auth.Identity{Subject: "apikey:1234", Method: auth.MethodAPIKey}))
+	assert.Equal(t, "apikey:1234", rl.ClientKey(key))
+}
+
+func TestFreshWalletsShareTheBudgetOfTheirIP(t *testing.T) {
+	rl := New(nil, nil, NewGasBudget(100000), nil)
+	handler := rl.Budget(func(w http.ResponseWriter, r *http.Request) {
+		if err := MeterFromContext(r.Context()).Charge(60000); err != nil {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	post := func(identity *auth.Identity) int {
+		r := httptest.NewRequest("POST", "/api/v1/content", nil)
+		r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		rr := httptest.NewRecorder()
+		handler(rr, r)
+		return rr.Code
+	}
+	wallet := func(address string, scopes ...string) *auth.Identity {
+		return &auth.Identity{Subject: address, Address: address, Method: auth.MethodSIWE, Scopes: scopes}
+	}
+
+	// A new wallet does not come with a new budget
+	assert.Equal(t, http.StatusCreated, post(wallet("0x01")))
+	assert.Equal(t, http.StatusTooManyRequests, post(wallet("0x02")))
+	used, _, _ := rl.budget.Usage("ip:192.0.2.1")
+	assert.Equal(t, uint64(60000), used)
+
+	// Wallets granted a scope by an allowlist or role keep their own budget
+	assert.Equal(t, http.StatusCreated, post(wallet("0x03", auth.ScopeContentWrite)))
+	used, _, _ = rl.budget.Usage("wallet:0x03")
+	assert.Equal(t, uint64(60000), used)
+}
@@ -0,0 +1,167 @@
+// Package registration decides whether a contestant may register for a
+// contest, and keeps the roster of registered and waitlisted contestants.
+//
+// A registration is checked in order against the contest state, the
+// registration window, duplicates, eligibility and capacity; the first
+// failing check is reported with its own error code. Once the contest is
+// full, contestants join the waitlist if the contest has one and are
+// promoted in order when a registered contestant withdraws or is removed.
+package registration
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/models"
+	"strings"
+	"time"
+)
+
+// Policy converts the registration rules of a create request. Dates must
+// already be validated.
+func Policy(req *models.RegistrationPolicyRequest) *models.RegistrationPolicy {
+	if req == nil {
+		return nil
+	}
+	policy := &models.RegistrationPolicy{
+		MaxContestants:  req.MaxContestants,
+		Waitlist:        req.Waitlist,
+		RequireVerified: req.RequireVerified,
+		MinTeamSize:     req.MinTeamSize,
+		MaxTeamSize:     req.MaxTeamSize,
+	}
+	if opensAt, err := time.Parse(time.RFC3339, req.OpensAt); err == nil {
+		policy.OpensAt = &opensAt
+	}
+	if closesAt, err := time.Parse(time.RFC3339, req.ClosesAt); err == nil {
+		policy.ClosesAt = &closesAt
+	}
+	return policy
+}
+
+// Decide returns the status a contestant gets by registering for a contest at
+// now, or why it may not register. contestant may be nil when the policy does
+// not require verification.
+func Decide(contest *models.Contest, roster *Roster, contestant *models.Contestant, contestantID string, now time.Time) (string, error) {
+	if err := lifecycle.RequireRegistration(lifecycle.StateOf(contest)); err != nil {
+		return "", err
+	}
+
+	policy := contest.Registration
+	if policy == nil {
+		policy = &models.RegistrationPolicy{}
+	}
+	if policy.OpensAt != nil && now.Before(*policy.OpensAt) {
+		return "", apperr.New(apperr.KindConflict, models.ErrorCodeRegistrationNotOpen,
+			"registration opens at "+policy.OpensAt.Format(time.RFC3339))
+	}
+	if policy.ClosesAt != nil && !now.Before(*policy.ClosesAt) {
+		return "", apperr.New(apperr.KindConflict, models.ErrorCodeRegistrationClosed,
+			"registration closed at "+policy.ClosesAt.Format(time.RFC3339))
+	}
+
+	if status := roster.Status(contestantID); status != "" {
+		return "", apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyRegistered,
+			"contestant "+contestantID+" is already "+status)
+	}
+
+	if policy.RequireVerified && (contestant == nil || !contestant.Verified) {
+		return "", apperr.New(apperr.KindForbidden, models.ErrorCodeNotEligible,
+			"only verified contestants may register for this contest")
+	}
+
+	if policy.MaxContestants > 0 && len(roster.Registered) >= policy.MaxContestants {
+		if policy.Waitlist {
+			return models.RegistrationWaitlisted, nil
+		}
+		return "", apperr.New(apperr.KindConflict, models.ErrorCodeContestFull,
+			"contest is full")
+	}
+	return models.RegistrationRegistered, nil
+}
+
+// Withdraw takes a contestant off the roster of a contest, and returns the
+// contestant promoted from the waitlist in its place, empty if none. The
+// organizer must give a reason to remove someone.
+func Withdraw(contest *models.Contest, roster *Roster, req *models.WithdrawContestantRequest) (string, error) {
+	if state := lifecycle.StateOf(contest); !lifecycle.AllowsWithdrawal(state) {
+		return "", apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState,
+			"registrations cannot change while the contest is "+state)
+	}
+	if roster.Status(req.ContestantID) == "" {
+		return "", apperr.NotFound("contestant %s is not registered for contest %s", req.ContestantID, req.ContestID)
+	}
+	if req.Removed && strings.TrimSpace(req.Reason) == "" {
+		return "", apperr.Fields([]models.FieldError{{Field: "reason", Rule: "required", Message: "Reason is required to remove a contestant"}})
+	}
+
+	maxContestants := 0
+	if contest.Registration != nil {
+		maxContestants = contest.Registration.MaxContestants
+	}
+	return roster.Remove(req.ContestantID, maxContestants), nil
+}
+
+// Roster lists the contestants of a contest in registration order
+type Roster struct {
+	Registered []string
+	Waitlist   []string
+}
+
+// Status returns the registration status of a contestant, empty if it is not
+// on the roster
+func (r *Roster) Status(contestantID string) string {
+	if indexOf(r.Registered, contestantID) >= 0 {
+		return models.RegistrationRegistered
+	}
+	if indexOf(r.Waitlist, contestantID) >= 0 {
+		return models.RegistrationWaitlisted
+	}
+	return ""
+}
+
+// Position returns the 1-based place of a contestant on the waitlist, 0 if it
+// is not waitlisted
+func (r *Roster) Position(contestantID string) int {
+	return indexOf(r.Waitlist, contestantID) + 1
+}
+
+// Add puts a contestant on the roster with a status returned by Decide
+func (r *Roster) Add(contestantID, status string) {
+	if status == models.RegistrationWaitlisted {
+		r.Waitlist = append(r.Waitlist, contestantID)
+		return
+	}
+	r.Registered = append(r.Registered, contestantID)
+}
+
+// Remove takes a contestant off the roster. When a registered contestant
+// leaves, the first waitlisted contestant fits under maxContestants and is
+// promoted; Remove returns its ID, empty if nobody was promoted.
+func (r *Roster) Remove(contestantID string, maxContestants int) string {
+	if i := indexOf(r.Waitlist, contestantID); i >= 0 {
+		r.Waitlist = append(r.Waitlist[:i:i], r.Waitlist[i+1:]...)
+		return ""
+	}
+	i := indexOf(r.Registered, contestantID)
+	if i < 0 {
+		return ""
+	}
+	r.Registered = append(r.Registered[:i:i], r.Registered[i+1:]...)
+
+	if len(r.Waitlist) == 0 || (maxContestants > 0 && len(r.Registered) >= maxContestants) {
+		return ""
+	}
+	promoted := r.Waitlist[0]
+	r.Waitlist = r.Waitlist[1:]
+	r.Registered = append(r.Registered, promoted)
+	return promoted
+}
+
+func indexOf(ids []string, id string) int {
+	for i, candidate := range ids {
+		if candidate == id {
+			return i
+		}
+	}
+	return -1
+}
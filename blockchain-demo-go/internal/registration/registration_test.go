@@ -0,0 +1,124 @@
+package registration
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	assert.Nil(t, Policy(nil))
+
+	policy := Policy(&models.RegistrationPolicyRequest{OpensAt: "2025-07-01T00:00:00Z", MaxContestants: 2, Waitlist: true})
+	require.NotNil(t, policy.OpensAt)
+	assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), policy.OpensAt.UTC())
+	assert.Nil(t, policy.ClosesAt)
+	assert.Equal(t, 2, policy.MaxContestants)
+	assert.True(t, policy.Waitlist)
+}
+
+func TestDecide(t *testing.T) {
+	opensAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	closesAt := opensAt.Add(7 * 24 * time.Hour)
+	contest := &models.Contest{
+		State: models.ContestOpen,
+		Registration: &models.RegistrationPolicy{
+			OpensAt:         &opensAt,
+			ClosesAt:        &closesAt,
+			MaxContestants:  1,
+			RequireVerified: true,
+		},
+	}
+	verified := &models.Contestant{ID: "alice", Verified: true}
+	now := opensAt.Add(time.Hour)
+
+	status, err := Decide(contest, &Roster{}, verified, "alice", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.RegistrationRegistered, status)
+
+	tests := []struct {
+		name       string
+		contest    *models.Contest
+		roster     *Roster
+		contestant *models.Contestant
+		now        time.Time
+		status     int
+		code       string
+	}{
+		{"draft", &models.Contest{State: models.ContestDraft}, &Roster{}, verified, now, http.StatusConflict, models.ErrorCodeInvalidContestState},
+		{"before window", contest, &Roster{}, verified, opensAt.Add(-time.Second), http.StatusConflict, models.ErrorCodeRegistrationNotOpen},
+		{"after window", contest, &Roster{}, verified, closesAt, http.StatusConflict, models.ErrorCodeRegistrationClosed},
+		{"duplicate", contest, &Roster{Registered: []string{"alice"}}, verified, now, http.StatusConflict, models.ErrorCodeAlreadyRegistered},
+		{"waitlisted twice", &models.Contest{State: models.ContestOpen}, &Roster{Waitlist: []string{"alice"}}, verified, now, http.StatusConflict, models.ErrorCodeAlreadyRegistered},
+		{"unverified", contest, &Roster{}, &models.Contestant{ID: "alice"}, now, http.StatusForbidden, models.ErrorCodeNotEligible},
+		{"unknown contestant", contest, &Roster{}, nil, now, http.StatusForbidden, models.ErrorCodeNotEligible},
+		{"full", contest, &Roster{Registered: []string{"bob"}}, verified, now, http.StatusConflict, models.ErrorCodeContestFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decide(tt.contest, tt.roster, tt.contestant, "alice", tt.now)
+			require.Error(t, err)
+			assert.Equal(t, tt.status, apperr.StatusOf(err))
+			assert.Equal(t, tt.code, apperr.CodeOf(err))
+		})
+	}
+
+	// A full contest with a waitlist queues instead of refusing
+	contest.Registration.Waitlist = true
+	status, err = Decide(contest, &Roster{Registered: []string{"bob"}}, verified, "alice", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.RegistrationWaitlisted, status)
+}
+
+func TestRoster(t *testing.T) {
+	roster := &Roster{}
+	roster.Add("a", models.RegistrationRegistered)
+	roster.Add("b", models.RegistrationRegistered)
+	roster.Add("c", models.RegistrationWaitlisted)
+	roster.Add("d", models.RegistrationWaitlisted)
+
+	assert.Equal(t, models.RegistrationRegistered, roster.Status("a"))
+	assert.Equal(t, models.RegistrationWaitlisted, roster.Status("d"))
+	assert.Equal(t, "", roster.Status("z"))
+	assert.Equal(t, 2, roster.Position("d"))
+	assert.Equal(t, 0, roster.Position("a"))
+
+	// Leaving the waitlist promotes nobody
+	assert.Equal(t, "", roster.Remove("c", 2))
+	assert.Equal(t, 1, roster.Position("d"))
+
+	// A registered contestant leaving frees a place for the first in line
+	assert.Equal(t, "d", roster.Remove("a", 2))
+	assert.Equal(t, []string{"b", "d"}, roster.Registered)
+	assert.Empty(t, roster.Waitlist)
+
+	assert.Equal(t, "", roster.Remove("z", 2))
+	assert.Equal(t, "", roster.Remove("b", 2))
+	assert.Equal(t, []string{"d"}, roster.Registered)
+}
+
+func TestWithdraw(t *testing.T) {
+	contest := &models.Contest{ID: "c1", State: models.ContestRunning, Registration: &models.RegistrationPolicy{MaxContestants: 1, Waitlist: true}}
+	roster := &Roster{Registered: []string{"alice"}, Waitlist: []string{"bob"}}
+
+	_, err := Withdraw(contest, roster, &models.WithdrawContestantRequest{ContestID: "c1", ContestantID: "carol"})
+	assert.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+
+	// The organizer has to say why
+	_, err = Withdraw(contest, roster, &models.WithdrawContestantRequest{ContestID: "c1", ContestantID: "alice", Removed: true})
+	assert.Equal(t, "reason", apperr.FieldsOf(err)[0].Field)
+
+	promoted, err := Withdraw(contest, roster, &models.WithdrawContestantRequest{ContestID: "c1", ContestantID: "alice", Reason: "cheating", Removed: true})
+	require.NoError(t, err)
+	assert.Equal(t, "bob", promoted)
+	assert.Equal(t, []string{"bob"}, roster.Registered)
+
+	contest.State = models.ContestClosed
+	_, err = Withdraw(contest, roster, &models.WithdrawContestantRequest{ContestID: "c1", ContestantID: "bob"})
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(err))
+}
@@ -1,10 +1,20 @@
 package service
 
 import (
+	"blockchain-demo/internal/apperr"
 	"blockchain-demo/internal/config"
+	"blockchain-demo/internal/ids"
+	"blockchain-demo/internal/lifecycle"
 	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/ratelimit"
+	"blockchain-demo/internal/registration"
+	"blockchain-demo/internal/signer"
+	"blockchain-demo/internal/sponsorship"
+	"blockchain-demo/internal/teams"
+	"blockchain-demo/internal/validation"
+	"blockchain-demo/internal/voting"
+	"blockchain-demo/internal/wiki"
 	"context"
-	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -19,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"golang.org/x/text/unicode/norm"
@@ -26,11 +37,12 @@ import (
 
 // BlockchainService handles all blockchain interactions
 type BlockchainService struct {
-	client     *ethclient.Client
-	config     *config.Config
-	privateKey *ecdsa.PrivateKey
-	fromAddr   common.Address
-	chainID    *big.Int
+	client   *meteredClient
+	ctx      context.Context // Request the transactions are sent for, see WithContext
+	config   *config.Config
+	signer   signer.Signer
+	fromAddr common.Address
+	chainID  *big.Int
 
 	// In-memory storage for demo purposes
 	contents      map[string]*models.Content
@@ -38,6 +50,8 @@ type BlockchainService struct {
 	contestants   map[string]*models.Contestant
 	sponsors      map[string]*models.Sponsor
 	registrations map[string]map[string]bool // contestID -> contestantID -> registered
+
+	rosters *pendingRosters // Roster changes sent but not mined yet, shared by WithContext copies
 }
 
 // NewBlockchainService creates a new blockchain service instance
@@ -53,7 +67,7 @@ func NewBlockchainService(cfg *config.Config) (*BlockchainService, error) {
 	chainID.SetString(cfg.ChainID, 10)
 
 	service := &BlockchainService{
-		client:        client,
+		client:        &meteredClient{client},
 		config:        cfg,
 		chainID:       chainID,
 		contents:      make(map[string]*models.Content),
@@ -61,19 +75,20 @@ func NewBlockchainService(cfg *config.Config) (*BlockchainService, error) {
 		contestants:   make(map[string]*models.Contestant),
 		sponsors:      make(map[string]*models.Sponsor),
 		registrations: make(map[string]map[string]bool),
+		rosters:       newPendingRosters(),
 	}
 
-	// Setup private key if provided
-	if cfg.PrivateKey != "" && cfg.PrivateKey != "your_private_key_here" {
-		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKey, "0x"))
-		if err != nil {
-			return nil, fmt.Errorf("invalid private key: %v", err)
-		}
-		service.privateKey = privateKey
-		service.fromAddr = crypto.PubkeyToAddress(privateKey.PublicKey)
-		log.Printf("✅ Loaded wallet address: %s", service.fromAddr.Hex())
+	// Setup the transaction signer; without one the service is read-only
+	txSigner, err := signer.FromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize signer: %v", err)
+	}
+	service.signer = txSigner
+	service.fromAddr = txSigner.Address()
+	if signer.IsReadOnly(txSigner) {
+		log.Printf("👀 No signer configured: running read-only")
 	} else {
-		return nil, fmt.Errorf("private key is required")
+		log.Printf("✅ Loaded wallet address: %s", service.fromAddr.Hex())
 	}
 
 	// Check if contract address is provided
@@ -87,21 +102,38 @@ func NewBlockchainService(cfg *config.Config) (*BlockchainService, error) {
 
 // StoreContent pushes content to blockchain
 func (bs *BlockchainService) StoreContent(req *models.CreateContentRequest) (*models.CreateContentResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
 	// Generate unique ID
-	id := bs.generateID()
+	id, err := bs.newID(ids.EntityContent, req.Creator, req.Title, req.Nonce)
+	if err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: "Failed to generate content ID",
+		}, err
+	}
 
 	// Create content object
 	content := &models.Content{
-		ID:        id,
-		Title:     req.Title,
-		Content:   req.Content,
-		Creator:   req.Creator,
-		Timestamp: time.Now(),
-		Verified:  false,
+		ID:         id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Creator:    req.Creator,
+		Namespace:  wiki.NormalizeNamespace(req.Namespace),
+		Categories: req.Categories,
+		Tags:       req.Tags,
+		Links:      wiki.ParseLinks(req.Content),
+		Timestamp:  time.Now(),
+		Verified:   false,
 	}
 
 	// Push to blockchain
-	txHash, err := bs.pushToBlockchain(content)
+	txHash, recordHash, err := bs.pushToBlockchain(content)
 	if err != nil {
 		return &models.CreateContentResponse{
 			Success: false,
@@ -114,10 +146,54 @@ func (bs *BlockchainService) StoreContent(req *models.CreateContentRequest) (*mo
 	log.Printf("✅ Content pushed to blockchain with tx: %s", txHash)
 
 	return &models.CreateContentResponse{
-		Success: true,
-		Message: "Content created successfully",
-		TxHash:  content.TxHash,
-		ID:      id,
+		Success:    true,
+		Message:    "Content created successfully",
+		TxHash:     content.TxHash,
+		RecordHash: recordHash,
+		ID:         id,
+	}, nil
+}
+
+// UpdateContent pushes a new revision of existing content to blockchain
+func (bs *BlockchainService) UpdateContent(id string, req *models.CreateContentRequest) (*models.CreateContentResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	content := &models.Content{
+		ID:         id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Creator:    req.Creator,
+		Namespace:  wiki.NormalizeNamespace(req.Namespace),
+		Categories: req.Categories,
+		Tags:       req.Tags,
+		Links:      wiki.ParseLinks(req.Content),
+		Timestamp:  time.Now(),
+		Verified:   false,
+	}
+
+	txHash, recordHash, err := bs.pushToBlockchain(content)
+	if err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: "Failed to push revision to blockchain",
+		}, err
+	}
+
+	content.TxHash = txHash
+	content.Verified = true
+	log.Printf("✅ Content revision pushed to blockchain with tx: %s", txHash)
+
+	return &models.CreateContentResponse{
+		Success:    true,
+		Message:    "Content updated successfully",
+		TxHash:     content.TxHash,
+		RecordHash: recordHash,
+		ID:         id,
 	}, nil
 }
 
@@ -150,38 +226,37 @@ func (bs *BlockchainService) GetAllContents() (*models.ListContentsResponse, err
 	}, nil
 }
 
-// pushToBlockchain simulates pushing data to blockchain
-func (bs *BlockchainService) pushToBlockchain(content *models.Content) (string, error) {
-	if bs.privateKey == nil {
-		return "", fmt.Errorf("no private key configured")
+// pushToBlockchain anchors a revision of content with anchorContent and
+// returns the transaction hash and the hash of the anchored record. The
+// record, with its namespace, categories and tags, is emitted in the
+// ContentAnchored event and the contract keeps its hash.
+func (bs *BlockchainService) pushToBlockchain(content *models.Content) (string, string, error) {
+	if bs.ReadOnly() {
+		return "", "", signer.ErrReadOnly
 	}
 
-	// For demo purposes, we'll simulate a transaction
-	// In a real implementation, you would:
-	// 1. Create the transaction data using ABI encoding
-	// 2. Estimate gas
-	// 3. Sign and send the transaction
-
-	log.Printf("📤 Simulating blockchain transaction for content: %s", content.Title)
-
-	// Simulate getting nonce
-	nonce, err := bs.client.PendingNonceAt(context.Background(), bs.fromAddr)
+	record, err := json.Marshal(content)
 	if err != nil {
-		return "", fmt.Errorf("failed to get nonce: %v", err)
+		return "", "", fmt.Errorf("failed to encode content: %w", err)
 	}
 
-	// Simulate gas price
-	gasPrice, err := bs.client.SuggestGasPrice(context.Background())
+	contract, err := bs.contract()
 	if err != nil {
-		return "", fmt.Errorf("failed to get gas price: %v", err)
+		return "", "", err
 	}
 
-	// For demo, generate a fake transaction hash
-	txHash := bs.generateTxHash()
+	auth, err := bs.transactor()
+	if err != nil {
+		return "", "", err
+	}
 
-	log.Printf("📝 Simulated tx - Nonce: %d, GasPrice: %s, Hash: %s", nonce, gasPrice.String(), txHash)
+	tx, err := contract.Transact(auth, "anchorContent", content.ID, string(record))
+	if err != nil {
+		return "", "", apperr.FromChain(err)
+	}
 
-	return txHash, nil
+	log.Printf("📤 Anchored revision of content %s: %s", content.ID, tx.Hash().Hex())
+	return tx.Hash().Hex(), crypto.Keccak256Hash(record).Hex(), nil
 }
 
 // getFromBlockchain simulates getting data from blockchain
@@ -194,7 +269,7 @@ func (bs *BlockchainService) getFromBlockchain(id string) (*models.Content, erro
 	log.Printf("📥 Simulating blockchain read for content ID: %s", id)
 
 	// Return nil to indicate not found on blockchain
-	return nil, fmt.Errorf("content not found on blockchain")
+	return nil, apperr.NotFound("content %s not found on blockchain", id)
 }
 
 // generateID generates a unique ID for content
@@ -217,6 +292,63 @@ func (bs *BlockchainService) generateTxHash() string {
 	return "0x" + hex.EncodeToString(bytes)
 }
 
+// transactor returns transact options signing with the configured signer
+func (bs *BlockchainService) transactor() (*bind.TransactOpts, error) {
+	opts, err := signer.Transactor(bs.signer, bs.chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = bs.context()
+	return opts, nil
+}
+
+// WithContext returns a copy of the service sending its transactions for the
+// request of ctx, whose gas meter they are charged to
+func (bs *BlockchainService) WithContext(ctx context.Context) BlockchainServiceInterface {
+	bound := *bs
+	bound.ctx = ctx
+	return &bound
+}
+
+// context returns the context of the request being served, if any
+func (bs *BlockchainService) context() context.Context {
+	if bs.ctx == nil {
+		return context.Background()
+	}
+	return bs.ctx
+}
+
+// chainBackend sends transactions and reads their receipts
+type chainBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// meteredClient charges every transaction to the gas meter of the request it
+// is sent for, with the gas estimated for it, right before sending it
+type meteredClient struct {
+	chainBackend
+}
+
+// SendTransaction charges the gas limit of tx, which bind sets from
+// eth_estimateGas, and gives it back if the node refuses the transaction
+func (c *meteredClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	meter := ratelimit.MeterFromContext(ctx)
+	if err := meter.Charge(tx.Gas()); err != nil {
+		return err
+	}
+	if err := c.chainBackend.SendTransaction(ctx, tx); err != nil {
+		meter.Refund(tx.Gas())
+		return err
+	}
+	return nil
+}
+
+// ReadOnly reports whether the service has no signer and rejects writes
+func (bs *BlockchainService) ReadOnly() bool {
+	return signer.IsReadOnly(bs.signer)
+}
+
 // ============ CONTEST OPERATIONS ============
 
 // LoadContractABI loads the ABI from ContentStorage.json
@@ -247,46 +379,23 @@ type ContestTuple struct {
 
 // CreateContest creates a new contest and pushes to blockchain
 func (bs *BlockchainService) CreateContest(req *models.CreateContestRequest) (*models.CreateContestResponse, error) {
-	id := bs.generateID()
-	startDate, err := time.Parse(time.RFC3339, req.StartDate)
-	if err != nil {
+	if err := validation.Struct(req); err != nil {
 		return &models.CreateContestResponse{
 			Success: false,
-			Message: "Invalid start date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
+			Message: err.Error(),
 		}, err
 	}
-	endDate, err := time.Parse(time.RFC3339, req.EndDate)
+
+	id, err := bs.newID(ids.EntityContest, req.Organizer, req.Name, req.Nonce)
 	if err != nil {
 		return &models.CreateContestResponse{
 			Success: false,
-			Message: "Invalid end date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
+			Message: "Failed to generate contest ID",
 		}, err
 	}
-	if endDate.Before(startDate) {
-		return &models.CreateContestResponse{
-			Success: false,
-			Message: "End date must be after start date",
-		}, fmt.Errorf("invalid date range")
-	}
-
-	// Serialize contest to formatted JSON
-	contestJson := map[string]interface{}{
-		"id":          id,
-		"name":        req.Name,
-		"description": req.Description,
-		"start_date":  startDate.Format(time.RFC3339),
-		"end_date":    endDate.Format(time.RFC3339),
-		"organizer":   bs.fromAddr.Hex(),
-		"image_url":   req.ImageURL,
-		"timestamp":   time.Now().Format(time.RFC3339),
-	}
-
-	jsonBytes, err := json.MarshalIndent(contestJson, "", "  ")
+	jsonBytes, failure, err := bs.contestJSON(id, req, bs.fromAddr.Hex())
 	if err != nil {
-		return &models.CreateContestResponse{
-			Success: false,
-			Message: "Failed to marshal contest JSON",
-		}, err
+		return failure, err
 	}
 
 	defer func() {
@@ -304,7 +413,7 @@ func (bs *BlockchainService) CreateContest(req *models.CreateContestRequest) (*m
 		}, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(bs.privateKey, bs.chainID)
+	auth, err := bs.transactor()
 	if err != nil {
 		return &models.CreateContestResponse{
 			Success: false,
@@ -312,32 +421,17 @@ func (bs *BlockchainService) CreateContest(req *models.CreateContestRequest) (*m
 		}, err
 	}
 
-	// Get transaction hash first
-	txHash := bs.generateTxHash()
-
-	// Add txHash to contest JSON
-	contestJson["tx_hash"] = txHash
-	// Add transaction URL
-	contestJson["tx_url"] = fmt.Sprintf("https://explorer.testnet.hii.network/tx/%s", txHash)
-
-	// Re-marshal JSON with txHash
-	jsonBytes, err = json.MarshalIndent(contestJson, "", "  ")
-	if err != nil {
-		return &models.CreateContestResponse{
-			Success: false,
-			Message: "Failed to marshal contest JSON with txHash",
-		}, err
-	}
-
-	// Call new createContestJson function with id and JSON string
-	input := []interface{}{id, string(jsonBytes)}
+	// Call new createContestJson function with id, JSON string and the
+	// capacity the contract enforces
+	maxContestants, waitlist := contestCapacity(req)
+	input := []interface{}{id, string(jsonBytes), maxContestants, waitlist}
 	contract := bind.NewBoundContract(contractAddr, parsedABI, bs.client, bs.client, bs.client)
 	tx, err := contract.Transact(auth, "createContestJson", input...)
 	if err != nil {
 		return &models.CreateContestResponse{
 			Success: false,
 			Message: "Failed to create contest on blockchain",
-		}, err
+		}, apperr.FromChain(err)
 	}
 
 	log.Printf("[OK] Contest JSON pushed to blockchain: %s", tx.Hash().Hex())
@@ -350,6 +444,94 @@ func (bs *BlockchainService) CreateContest(req *models.CreateContestRequest) (*m
 	}, nil
 }
 
+// contestJSON validates a contest request and serializes the JSON stored on-chain.
+// defaultOrganizer is used when the request has no organizer.
+func (bs *BlockchainService) contestJSON(id string, req *models.CreateContestRequest, defaultOrganizer string) ([]byte, *models.CreateContestResponse, error) {
+	startDate, err := time.Parse(time.RFC3339, req.StartDate)
+	if err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Invalid start date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
+		}, apperr.Validation("invalid start date: %v", err)
+	}
+	endDate, err := time.Parse(time.RFC3339, req.EndDate)
+	if err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Invalid end date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
+		}, apperr.Validation("invalid end date: %v", err)
+	}
+	if endDate.Before(startDate) {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "End date must be after start date",
+		}, apperr.Validation("end date must be after start date")
+	}
+	if err := sponsorship.CheckTiers(req.SponsorTiers); err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Sponsor tier thresholds must decrease from gold to bronze",
+		}, err
+	}
+
+	organizer := defaultOrganizer
+	if req.Organizer != "" {
+		organizer = req.Organizer
+	}
+
+	// New contests open for registration right away unless created as drafts
+	state := models.ContestOpen
+	if req.Draft {
+		state = models.ContestDraft
+	}
+
+	// Get transaction hash first
+	txHash := bs.generateTxHash()
+
+	// Serialize contest to formatted JSON
+	contestJson := map[string]interface{}{
+		"id":          id,
+		"name":        req.Name,
+		"description": req.Description,
+		"start_date":  startDate.Format(time.RFC3339),
+		"end_date":    endDate.Format(time.RFC3339),
+		"organizer":   organizer,
+		"state":       state,
+		"active":      lifecycle.Active(state),
+		"image_url":   req.ImageURL,
+		"categories":  req.Categories,
+		"tags":        req.Tags,
+		"timestamp":   time.Now().Format(time.RFC3339),
+		"tx_hash":     txHash,
+		// Add transaction URL
+		"tx_url": fmt.Sprintf("https://explorer.testnet.hii.network/tx/%s", txHash),
+		// Registration window, capacity and eligibility rules
+		"registration": registration.Policy(req.Registration),
+		// Commit and reveal deadlines of the audience vote
+		"voting": voting.Policy(req.Voting),
+		// Pledge thresholds of the sponsor tiers, the defaults when absent
+		"sponsor_tiers": sponsorship.Tiers(req.SponsorTiers),
+	}
+
+	jsonBytes, err := json.MarshalIndent(contestJson, "", "  ")
+	if err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Failed to marshal contest JSON",
+		}, err
+	}
+	return jsonBytes, nil, nil
+}
+
+// contestCapacity returns the capacity and waitlist rule of a new contest,
+// which the contract keeps to place registrations itself
+func contestCapacity(req *models.CreateContestRequest) (*big.Int, bool) {
+	if req.Registration == nil {
+		return new(big.Int), false
+	}
+	return big.NewInt(int64(req.Registration.MaxContestants)), req.Registration.Waitlist
+}
+
 // SearchContests tìm kiếm contest trên blockchain theo từ khóa ở mọi trường (JSON version)
 func (bs *BlockchainService) SearchContests(keyword string) ([]*models.Contest, error) {
 	contractAddr := common.HexToAddress(bs.config.ContractAddress)
@@ -363,7 +545,7 @@ func (bs *BlockchainService) SearchContests(keyword string) ([]*models.Contest,
 	var idsRaw []interface{}
 	err = contract.Call(callOpts, &idsRaw, "getAllContestIds")
 	if err != nil {
-		return []*models.Contest{}, err
+		return []*models.Contest{}, apperr.FromChain(err)
 	}
 	var ids []string
 	if len(idsRaw) > 0 {
@@ -395,6 +577,7 @@ func (bs *BlockchainService) SearchContests(keyword string) ([]*models.Contest,
 		if err := json.Unmarshal([]byte(jsonStr), &c); err != nil {
 			continue
 		}
+		bs.readState(contract, callOpts, &c)
 		if strings.Contains(strings.ToLower(removeDiacritics(c.Name)), keyword) ||
 			strings.Contains(strings.ToLower(removeDiacritics(c.Description)), keyword) ||
 			strings.Contains(strings.ToLower(removeDiacritics(c.ImageURL)), keyword) ||
@@ -423,7 +606,7 @@ func (bs *BlockchainService) GetContest(id string) (*models.GetContestResponse,
 		return &models.GetContestResponse{
 			Success: false,
 			Message: "Contest not found on blockchain",
-		}, err
+		}, apperr.FromChain(err)
 	}
 	jsonStr, ok := jsonStrRaw[0].(string)
 	if !ok || jsonStr == "" {
@@ -439,6 +622,8 @@ func (bs *BlockchainService) GetContest(id string) (*models.GetContestResponse,
 			Message: "Failed to parse contest JSON",
 		}, err
 	}
+	contest.ID = id
+	bs.readState(contract, callOpts, &contest)
 	return &models.GetContestResponse{
 		Success: true,
 		Data:    &contest,
@@ -473,7 +658,7 @@ func (bs *BlockchainService) GetAllContests() (*models.ListContestsResponse, err
 		return &models.ListContestsResponse{
 			Success: false,
 			Message: "Failed to get contest IDs from blockchain",
-		}, err
+		}, apperr.FromChain(err)
 	}
 
 	var ids []string
@@ -514,7 +699,7 @@ func (bs *BlockchainService) GetAllContests() (*models.ListContestsResponse, err
 				Active:      result[5].(bool),
 				ImageURL:    result[6].(string),
 			}
-			contests = append(contests, &models.Contest{
+			contest := &models.Contest{
 				ID:          id,
 				Name:        contestTuple.Name,
 				Description: contestTuple.Description,
@@ -523,7 +708,9 @@ func (bs *BlockchainService) GetAllContests() (*models.ListContestsResponse, err
 				Organizer:   contestTuple.Organizer.Hex(),
 				Active:      contestTuple.Active,
 				ImageURL:    contestTuple.ImageURL,
-			})
+			}
+			bs.readState(contract, callOpts, contest)
+			contests = append(contests, contest)
 		} else {
 			log.Printf("[ERROR] Unexpected result length for contest %s: got %d, want 7", id, len(result))
 		}
@@ -536,72 +723,285 @@ func (bs *BlockchainService) GetAllContests() (*models.ListContestsResponse, err
 	}, nil
 }
 
+// readState sets the lifecycle state of a contest from the contract. Contracts
+// deployed before setContestState, and contests never moved, keep the state
+// of their JSON.
+func (bs *BlockchainService) readState(contract *bind.BoundContract, callOpts *bind.CallOpts, contest *models.Contest) {
+	var stateRaw []interface{}
+	if err := contract.Call(callOpts, &stateRaw, "contestStates", contest.ID); err == nil && len(stateRaw) > 0 {
+		if state, ok := stateRaw[0].(string); ok && state != "" {
+			contest.State = state
+		}
+	}
+	contest.State = lifecycle.StateOf(contest)
+	contest.Active = lifecycle.Active(contest.State)
+}
+
+// TransitionContest moves a contest to a new lifecycle state and anchors the
+// transition on-chain with setContestState
+func (bs *BlockchainService) TransitionContest(id, to, reason, actor string) (*models.ContestTransitionResponse, error) {
+	if bs.ReadOnly() {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	current, err := bs.GetContest(id)
+	if err != nil || !current.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", id)
+		}
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Contest not found on blockchain",
+		}, err
+	}
+
+	transition, err := lifecycle.Transition(current.Data, to, reason, actor, time.Now())
+	if err != nil {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	contract := bind.NewBoundContract(common.HexToAddress(bs.config.ContractAddress), parsedABI, bs.client, bs.client, bs.client)
+	tx, err := contract.Transact(auth, "setContestState", id, transition.From, transition.To, reason)
+	if err != nil {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Failed to change contest state on blockchain",
+		}, apperr.FromChain(err)
+	}
+	transition.TxHash = tx.Hash().Hex()
+
+	log.Printf("[OK] Contest %s moved from %s to %s by %s: %s", id, transition.From, transition.To, actor, transition.TxHash)
+	return &models.ContestTransitionResponse{
+		Success: true,
+		Message: "Contest is now " + to,
+		Data:    transition,
+	}, nil
+}
+
 // ============ CONTESTANT OPERATIONS ============
 
 // CreateContestant creates a new contestant on blockchain
 func (bs *BlockchainService) CreateContestant(req *models.CreateContestantRequest) (*models.CreateContestantResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
 	// Generate unique ID
-	id := bs.generateID()
+	id, err := bs.newID(ids.EntityContestant, req.Creator, req.Name, req.Nonce)
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to generate contestant ID",
+		}, err
+	}
 
-	// Create contestant object
+	// Create contestant object. The contestant is verified once stored on-chain.
 	contestant := &models.Contestant{
 		ID:        id,
 		Name:      req.Name,
 		Details:   req.Details,
 		Creator:   req.Creator,
 		Timestamp: time.Now(),
-		Verified:  false,
+		Verified:  true,
+	}
+	record, err := json.Marshal(contestant)
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to encode contestant",
+		}, err
 	}
 
-	// Push to blockchain
-	txHash := bs.generateTxHash()
-	contestant.TxHash = txHash
-	contestant.Verified = true
-	log.Printf("✅ Contestant created with tx: %s", txHash)
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	// The creator wallet is kept in the JSON: it is the wallet that may act
+	// for the contestant and that prizes are paid to
+	tx, err := contract.Transact(auth, "createContestantJson", id, string(record))
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to create contestant on blockchain",
+		}, apperr.FromChain(err)
+	}
+	log.Printf("✅ Contestant created with tx: %s", tx.Hash().Hex())
 
 	return &models.CreateContestantResponse{
 		Success: true,
 		Message: "Contestant created successfully",
-		TxHash:  contestant.TxHash,
+		TxHash:  tx.Hash().Hex(),
 		ID:      id,
 	}, nil
 }
 
 // GetContestant retrieves contestant by ID from blockchain
 func (bs *BlockchainService) GetContestant(id string) (*models.GetContestantResponse, error) {
-	// This would need to be implemented to read contestant from blockchain
-	// For now, return not found as this requires smart contract support
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.GetContestantResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	contestant, err := bs.readContestant(contract, &bind.CallOpts{From: bs.fromAddr}, id)
+	if err != nil {
+		return &models.GetContestantResponse{
+			Success: false,
+			Message: "Failed to read contestant from blockchain",
+		}, err
+	}
+	if contestant == nil {
+		return &models.GetContestantResponse{
+			Success: false,
+			Message: "Contestant not found on blockchain",
+		}, nil
+	}
 	return &models.GetContestantResponse{
-		Success: false,
-		Message: "Contestant not found on blockchain",
+		Success: true,
+		Data:    contestant,
 	}, nil
 }
 
 // GetAllContestants returns all contestants from blockchain
 func (bs *BlockchainService) GetAllContestants() (*models.ListContestantsResponse, error) {
-	// This would need to be implemented to read all contestants from blockchain
-	// For now, return empty list as this requires smart contract support
-	return &models.ListContestantsResponse{
-		Success: true,
-		Data:    []*models.Contestant{},
-		Total:   0,
-	}, nil
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.ListContestantsResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+	callOpts := &bind.CallOpts{From: bs.fromAddr}
+
+	var out []interface{}
+	if err := contract.Call(callOpts, &out, "getAllContestantIds"); err != nil {
+		return &models.ListContestantsResponse{
+			Success: false,
+			Message: "Failed to get contestant IDs from blockchain",
+		}, apperr.FromChain(err)
+	}
+	contestantIDs, _ := out[0].([]string)
+
+	contestants := make([]*models.Contestant, 0, len(contestantIDs))
+	for _, id := range contestantIDs {
+		contestant, err := bs.readContestant(contract, callOpts, id)
+		if err != nil {
+			return &models.ListContestantsResponse{
+				Success: false,
+				Message: "Failed to read contestant from blockchain",
+			}, err
+		}
+		// Contestants added before createContestantJson have no JSON
+		if contestant != nil {
+			contestants = append(contestants, contestant)
+		}
+	}
+
+	return &models.ListContestantsResponse{
+		Success: true,
+		Data:    contestants,
+		Total:   len(contestants),
+	}, nil
+}
+
+// readContestant reads the JSON of a contestant, nil if it was never created
+func (bs *BlockchainService) readContestant(contract *bind.BoundContract, callOpts *bind.CallOpts, id string) (*models.Contestant, error) {
+	var out []interface{}
+	if err := contract.Call(callOpts, &out, "getContestantJson", id); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	record, _ := out[0].(string)
+	if record == "" {
+		return nil, nil
+	}
+
+	var contestant models.Contestant
+	if err := json.Unmarshal([]byte(record), &contestant); err != nil {
+		return nil, fmt.Errorf("failed to parse contestant JSON: %v", err)
+	}
+	contestant.ID = id
+	return &contestant, nil
 }
 
 // ============ SPONSOR OPERATIONS ============
 
 // CreateSponsor creates a new sponsor on blockchain
 func (bs *BlockchainService) CreateSponsor(req *models.CreateSponsorRequest) (*models.CreateSponsorResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateSponsorResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.CreateSponsorResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
 	// Generate unique ID
 	id := bs.generateID()
 
+	wallet := req.WalletAddress
+	if wallet == "" {
+		wallet = bs.fromAddr.Hex()
+	}
+
 	// Create sponsor object
 	sponsor := &models.Sponsor{
 		ID:                id,
 		Name:              req.Name,
 		ContactInfo:       req.ContactInfo,
 		SponsorshipAmount: req.SponsorshipAmount,
-		WalletAddress:     bs.fromAddr.Hex(),
+		WalletAddress:     wallet,
 		Timestamp:         time.Now(),
 	}
 
@@ -643,35 +1043,954 @@ func (bs *BlockchainService) GetAllSponsors() (*models.ListSponsorsResponse, err
 
 // RegisterContestant registers a contestant for a contest on blockchain
 func (bs *BlockchainService) RegisterContestant(req *models.RegisterContestantRequest) (*models.RegisterContestantResponse, error) {
-	// This would need to be implemented to register contestant on blockchain
-	// For now, return success as this requires smart contract support
-	txHash := bs.generateTxHash()
-	log.Printf("✅ Registration completed with tx: %s", txHash)
+	if err := validation.Struct(req); err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", req.ContestID)
+		}
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Contest not found on blockchain",
+		}, err
+	}
+
+	// Decided against the roster every registration already sent leaves
+	// behind, so concurrent registrations cannot overbook the contest
+	roster, pending, err := bs.lockRoster(contest.Data)
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Failed to read contest registrations",
+		}, err
+	}
+	defer pending.Unlock()
+
+	// Contestants are only looked up when the contest requires verification
+	var contestant *models.Contestant
+	if policy := contest.Data.Registration; policy != nil && policy.RequireVerified {
+		found, err := bs.GetContestant(req.ContestantID)
+		if err != nil {
+			return &models.RegisterContestantResponse{
+				Success: false,
+				Message: "Failed to read contestant from blockchain",
+			}, err
+		}
+		contestant = found.Data
+	}
+
+	status, err := registration.Decide(contest.Data, roster, contestant, req.ContestantID, time.Now())
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
 
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	// The contract applies the same capacity and puts the contestant on the
+	// waitlist itself once the contest is full
+	tx, err := contract.Transact(auth, "joinContest", req.ContestID, req.ContestantID)
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: "Failed to register contestant on blockchain",
+		}, apperr.FromChain(err)
+	}
+	pending.sent(req.ContestantID, status, tx.Hash())
+	roster.Add(req.ContestantID, status)
+	log.Printf("✅ Contestant %s %s for contest %s with tx: %s", req.ContestantID, status, req.ContestID, tx.Hash().Hex())
+
+	message := "Contestant registered successfully"
+	if status == models.RegistrationWaitlisted {
+		message = "Contest is full, contestant added to the waitlist"
+	}
 	return &models.RegisterContestantResponse{
 		Success: true,
-		Message: "Contestant registered successfully",
-		TxHash:  txHash,
+		Message: message,
+		TxHash:  tx.Hash().Hex(),
+		Data: &models.ContestRegistration{
+			ContestID:    req.ContestID,
+			ContestantID: req.ContestantID,
+			Status:       status,
+			Position:     roster.Position(req.ContestantID),
+			RegisteredAt: time.Now(),
+			TxHash:       tx.Hash().Hex(),
+		},
 	}, nil
 }
 
+// WithdrawContestant takes a contestant off a contest. The registration is
+// kept on-chain: leaveContest emits a ContestantWithdrawn tombstone event.
+func (bs *BlockchainService) WithdrawContestant(req *models.WithdrawContestantRequest) (*models.WithdrawContestantResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", req.ContestID)
+		}
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Contest not found on blockchain",
+		}, err
+	}
+
+	roster, pending, err := bs.lockRoster(contest.Data)
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Failed to read contest registrations",
+		}, err
+	}
+	defer pending.Unlock()
+
+	promoted, err := registration.Withdraw(contest.Data, roster, req)
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	// The contract promotes the same waitlisted contestant in the same
+	// transaction, so the roster never has a free place while someone is waiting
+	tx, err := contract.Transact(auth, "leaveContest", req.ContestID, req.ContestantID, req.Reason, req.Removed)
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Failed to withdraw contestant on blockchain",
+		}, apperr.FromChain(err)
+	}
+	pending.sent(req.ContestantID, "", tx.Hash())
+
+	status := models.RegistrationWithdrawn
+	if req.Removed {
+		status = models.RegistrationRemoved
+	}
+	log.Printf("✅ Contestant %s %s from contest %s with tx: %s", req.ContestantID, status, req.ContestID, tx.Hash().Hex())
+
+	return &models.WithdrawContestantResponse{
+		Success: true,
+		Message: "Contestant " + status,
+		TxHash:  tx.Hash().Hex(),
+		Data: &models.ContestWithdrawal{
+			ContestID:    req.ContestID,
+			ContestantID: req.ContestantID,
+			Status:       status,
+			Reason:       req.Reason,
+			Actor:        req.Actor,
+			Promoted:     promoted,
+			At:           time.Now(),
+			TxHash:       tx.Hash().Hex(),
+		},
+	}, nil
+}
+
+// readRoster reads the registered and waitlisted contestants of a contest
+func (bs *BlockchainService) readRoster(contestID string) (*registration.Roster, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "getContestRoster", contestID); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("unexpected getContestRoster result length %d", len(result))
+	}
+	ids, _ := result[0].([]string)
+	statuses, _ := result[1].([]uint8)
+
+	roster := &registration.Roster{}
+	for i, id := range ids {
+		if i < len(statuses) && statuses[i] == 2 {
+			roster.Add(id, models.RegistrationWaitlisted)
+		} else {
+			roster.Add(id, models.RegistrationRegistered)
+		}
+	}
+	return roster, nil
+}
+
+// contract binds the ContentStorage contract
+func (bs *BlockchainService) contract() (*bind.BoundContract, error) {
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract ABI: %v", err)
+	}
+	return bind.NewBoundContract(common.HexToAddress(bs.config.ContractAddress), parsedABI, bs.client, bs.client, bs.client), nil
+}
+
 // GetContestantsInContest returns all contestants registered for a contest from blockchain
 func (bs *BlockchainService) GetContestantsInContest(contestID string) (*models.ListContestantsInContestResponse, error) {
-	// This would need to be implemented to read contestants from blockchain
-	// For now, return empty list as this requires smart contract support
+	roster, err := bs.readRoster(contestID)
+	if err != nil {
+		return &models.ListContestantsInContestResponse{
+			Success:   false,
+			Message:   "Failed to read contest registrations",
+			ContestID: contestID,
+		}, err
+	}
+
 	return &models.ListContestantsInContestResponse{
 		Success:     true,
 		ContestID:   contestID,
-		Contestants: []*models.Contestant{},
-		Total:       0,
+		Contestants: bs.contestantsByID(roster.Registered),
+		Total:       len(roster.Registered),
+		Waitlist:    bs.contestantsByID(roster.Waitlist),
 	}, nil
 }
 
+// contestantsByID looks contestants up in order. Contestants missing from the
+// chain are listed by ID only.
+func (bs *BlockchainService) contestantsByID(ids []string) []*models.Contestant {
+	contestants := make([]*models.Contestant, 0, len(ids))
+	for _, id := range ids {
+		if found, err := bs.GetContestant(id); err == nil && found.Success && found.Data != nil {
+			contestants = append(contestants, found.Data)
+			continue
+		}
+		contestants = append(contestants, &models.Contestant{ID: id})
+	}
+	return contestants
+}
+
 // IsContestantRegistered checks if a contestant is registered for a contest on blockchain
 func (bs *BlockchainService) IsContestantRegistered(contestID, contestantID string) (bool, error) {
-	// This would need to be implemented to check registration on blockchain
-	// For now, return false as this requires smart contract support
-	return false, nil
+	roster, err := bs.readRoster(contestID)
+	if err != nil {
+		return false, err
+	}
+	return roster.Status(contestantID) == models.RegistrationRegistered, nil
+}
+
+// RegisterTeam anchors a team on blockchain as one entry of the contest's
+// roster. The team JSON carries every member's signed acceptance.
+func (bs *BlockchainService) RegisterTeam(team *models.Team) (*models.TeamResponse, error) {
+	if bs.ReadOnly() {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(team.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", team.ContestID)
+		}
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Contest not found on blockchain",
+		}, err
+	}
+
+	if err := teams.CheckReady(team, contest.Data.Registration); err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	roster, pending, err := bs.lockRoster(contest.Data)
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to read contest registrations",
+		}, err
+	}
+	defer pending.Unlock()
+
+	// The team is verified when every member is
+	entry := &models.Contestant{ID: team.ID}
+	if policy := contest.Data.Registration; policy != nil && policy.RequireVerified {
+		all, err := bs.GetAllContestants()
+		if err != nil {
+			return &models.TeamResponse{
+				Success: false,
+				Message: "Failed to read contestants from blockchain",
+			}, err
+		}
+		entry.Verified = teams.Verified(team, all.Data)
+	}
+	status, err := registration.Decide(contest.Data, roster, entry, team.ID, time.Now())
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	registered := *team
+	registered.Status = models.TeamRegistered
+	registered.Entry = status
+	teamJSON, err := json.Marshal(&registered)
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to serialize team",
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	tx, err := contract.Transact(auth, "registerTeam", team.ContestID, team.ID, string(teamJSON))
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to register team on blockchain",
+		}, apperr.FromChain(err)
+	}
+	pending.sent(team.ID, status, tx.Hash())
+	log.Printf("✅ Team %s (%s) %s for contest %s with tx: %s", team.Name, team.ID, status, team.ContestID, tx.Hash().Hex())
+
+	registered.TxHash = tx.Hash().Hex()
+	return &models.TeamResponse{
+		Success: true,
+		Message: "Team " + status,
+		TxHash:  tx.Hash().Hex(),
+		Data:    &registered,
+	}, nil
+}
+
+// GetContestTeams reads the teams on the roster of a contest back from the
+// contract, with the roster entry each team has now
+func (bs *BlockchainService) GetContestTeams(contestID string) (*models.ListTeamsResponse, error) {
+	roster, err := bs.readRoster(contestID)
+	if err != nil {
+		return &models.ListTeamsResponse{
+			Success:   false,
+			Message:   "Failed to read contest registrations",
+			ContestID: contestID,
+		}, err
+	}
+
+	list := make([]*models.Team, 0)
+	for _, id := range append(append([]string(nil), roster.Registered...), roster.Waitlist...) {
+		team, err := bs.readTeam(id)
+		if err != nil {
+			return &models.ListTeamsResponse{
+				Success:   false,
+				Message:   "Failed to read team from blockchain",
+				ContestID: contestID,
+			}, err
+		}
+		// Single contestants share the roster and have no team JSON
+		if team == nil {
+			continue
+		}
+		team.Entry = roster.Status(id)
+		list = append(list, team)
+	}
+
+	return &models.ListTeamsResponse{
+		Success:   true,
+		ContestID: contestID,
+		Data:      list,
+		Total:     len(list),
+	}, nil
+}
+
+// GetTeam reads a registered team back from the contract
+func (bs *BlockchainService) GetTeam(id string) (*models.TeamResponse, error) {
+	team, err := bs.readTeam(id)
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Failed to read team from blockchain",
+		}, err
+	}
+	if team == nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Team not found on blockchain",
+		}, apperr.NotFound("team %s not found", id)
+	}
+
+	return &models.TeamResponse{
+		Success: true,
+		Data:    team,
+	}, nil
+}
+
+// readTeam reads the JSON a team was registered with, nil if none
+func (bs *BlockchainService) readTeam(id string) (*models.Team, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "teamJsons", id); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	jsonStr, _ := result[0].(string)
+	if jsonStr == "" {
+		return nil, nil
+	}
+
+	var team models.Team
+	if err := json.Unmarshal([]byte(jsonStr), &team); err != nil {
+		return nil, fmt.Errorf("failed to parse team JSON: %v", err)
+	}
+	return &team, nil
+}
+
+// ============ JUDGING OPERATIONS ============
+
+// FinalizeResults anchors the final results of a contest, with every
+// scorecard, on blockchain. The contract accepts them once, while the contest
+// is judging.
+func (bs *BlockchainService) FinalizeResults(results *models.ContestResults) (*models.ContestResultsResponse, error) {
+	if bs.ReadOnly() {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to serialize results",
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	tx, err := contract.Transact(auth, "finalizeResults", results.ContestID, string(resultsJSON))
+	if err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to finalize results on blockchain",
+		}, apperr.FromChain(err)
+	}
+	log.Printf("🏆 Results of contest %s finalized with tx: %s", results.ContestID, tx.Hash().Hex())
+
+	finalized := *results
+	finalized.TxHash = tx.Hash().Hex()
+	return &models.ContestResultsResponse{
+		Success: true,
+		Message: "Results finalized",
+		TxHash:  finalized.TxHash,
+		Data:    &finalized,
+	}, nil
+}
+
+// GetContestResults reads the final results of a contest from blockchain
+func (bs *BlockchainService) GetContestResults(contestID string) (*models.ContestResultsResponse, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "contestResults", contestID); err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to read results from blockchain",
+		}, apperr.FromChain(err)
+	}
+	resultsJSON := ""
+	if len(result) > 0 {
+		resultsJSON, _ = result[0].(string)
+	}
+	if resultsJSON == "" {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Results not found on blockchain",
+		}, apperr.NotFound("contest %s has no final results", contestID)
+	}
+
+	var results models.ContestResults
+	if err := json.Unmarshal([]byte(resultsJSON), &results); err != nil {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Failed to parse results",
+		}, err
+	}
+
+	return &models.ContestResultsResponse{
+		Success: true,
+		Data:    &results,
+	}, nil
+}
+
+// ============ VOTING OPERATIONS ============
+
+// CommitVote records the hash of a wallet's vote on blockchain
+func (bs *BlockchainService) CommitVote(req *models.CommitVoteRequest) (*models.VoteResponse, error) {
+	if bs.ReadOnly() {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+
+	votes, err := bs.GetVotes(req.ContestID)
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to read votes from blockchain",
+		}, err
+	}
+	vote, err := voting.CheckCommit(contest.Data, findVote(votes, req.Voter), req, time.Now())
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	tx, err := contract.Transact(auth, "commitVote", vote.ContestID, common.HexToAddress(vote.Voter),
+		common.HexToHash(vote.Commitment), vote.Signature)
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to commit vote on blockchain",
+		}, apperr.FromChain(err)
+	}
+	log.Printf("🗳️ %s committed a vote in contest %s with tx: %s", vote.Voter, vote.ContestID, tx.Hash().Hex())
+
+	vote.TxHash = tx.Hash().Hex()
+	return &models.VoteResponse{
+		Success: true,
+		Message: "Vote committed",
+		TxHash:  vote.TxHash,
+		Data:    vote,
+	}, nil
+}
+
+// RevealVote records the contestant and salt behind a committed vote. The
+// contract checks them against the commitment again.
+func (bs *BlockchainService) RevealVote(req *models.RevealVoteRequest) (*models.VoteResponse, error) {
+	if bs.ReadOnly() {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+
+	votes, err := bs.GetVotes(req.ContestID)
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to read votes from blockchain",
+		}, err
+	}
+	vote, err := voting.CheckReveal(contest.Data, findVote(votes, req.Voter), req, time.Now())
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	tx, err := contract.Transact(auth, "revealVote", vote.ContestID, common.HexToAddress(vote.Voter),
+		vote.ContestantID, common.HexToHash(vote.Salt))
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Failed to reveal vote on blockchain",
+		}, apperr.FromChain(err)
+	}
+	log.Printf("🔓 %s revealed its vote in contest %s with tx: %s", vote.Voter, vote.ContestID, tx.Hash().Hex())
+
+	vote.TxHash = tx.Hash().Hex()
+	return &models.VoteResponse{
+		Success: true,
+		Message: "Vote revealed",
+		TxHash:  vote.TxHash,
+		Data:    vote,
+	}, nil
+}
+
+// GetVotes reads every vote of a contest from blockchain, in commit order
+func (bs *BlockchainService) GetVotes(contestID string) ([]*models.Vote, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "getVotes", contestID); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	if len(result) != 7 {
+		return nil, fmt.Errorf("unexpected getVotes result length %d", len(result))
+	}
+	voters, _ := result[0].([]common.Address)
+	commitments, _ := result[1].([][32]byte)
+	signatures, _ := result[2].([]string)
+	contestantIDs, _ := result[3].([]string)
+	salts, _ := result[4].([][32]byte)
+	committedAt, _ := result[5].([]*big.Int)
+	revealedAt, _ := result[6].([]*big.Int)
+	if len(commitments) != len(voters) || len(signatures) != len(voters) || len(contestantIDs) != len(voters) ||
+		len(salts) != len(voters) || len(committedAt) != len(voters) || len(revealedAt) != len(voters) {
+		return nil, fmt.Errorf("malformed getVotes result")
+	}
+
+	votes := make([]*models.Vote, 0, len(voters))
+	for i, voter := range voters {
+		vote := &models.Vote{
+			ContestID:   contestID,
+			Voter:       strings.ToLower(voter.Hex()),
+			Commitment:  common.Hash(commitments[i]).Hex(),
+			Signature:   signatures[i],
+			CommittedAt: time.Unix(committedAt[i].Int64(), 0),
+		}
+		if revealedAt[i].Sign() > 0 {
+			at := time.Unix(revealedAt[i].Int64(), 0)
+			vote.ContestantID = contestantIDs[i]
+			vote.Salt = common.Hash(salts[i]).Hex()
+			vote.RevealedAt = &at
+			vote.Verified = voting.Verify(vote)
+		}
+		votes = append(votes, vote)
+	}
+	return votes, nil
+}
+
+// findVote returns the vote of a wallet, nil if it has not voted
+func findVote(votes []*models.Vote, voter string) *models.Vote {
+	for _, vote := range votes {
+		if strings.EqualFold(vote.Voter, voter) {
+			return vote
+		}
+	}
+	return nil
+}
+
+// ============ SPONSORSHIP OPERATIONS ============
+
+// PledgeSponsor anchors a sponsor's pledge to a contest on blockchain
+func (bs *BlockchainService) PledgeSponsor(req *models.PledgeRequest) (*models.PledgeResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", req.ContestID)
+		}
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, err
+	}
+
+	// Sponsors are not stored on blockchain yet, so the name is best effort
+	sponsorName := ""
+	if sponsor, err := bs.GetSponsor(req.SponsorID); err == nil && sponsor.Success && sponsor.Data != nil {
+		sponsorName = sponsor.Data.Name
+	}
+
+	pledge, err := sponsorship.NewPledge(contest.Data, sponsorName, req, bs.generateID(), time.Now())
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	jsonBytes, err := json.Marshal(pledge)
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Failed to marshal pledge JSON",
+		}, err
+	}
+
+	contract, err := bs.contract()
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Failed to load contract ABI",
+		}, err
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Failed to create transactor",
+		}, err
+	}
+
+	tx, err := contract.Transact(auth, "pledgeSponsor", pledge.ContestID, pledge.SponsorID,
+		new(big.Int).SetUint64(pledge.Amount), string(jsonBytes))
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Failed to record pledge on blockchain",
+		}, apperr.FromChain(err)
+	}
+	log.Printf("🤝 Sponsor %s pledged %d to contest %s with tx: %s", pledge.SponsorID, pledge.Amount, pledge.ContestID, tx.Hash().Hex())
+
+	pledge.TxHash = tx.Hash().Hex()
+	return &models.PledgeResponse{
+		Success: true,
+		Message: "Pledge recorded",
+		TxHash:  pledge.TxHash,
+		Data:    pledge,
+	}, nil
+}
+
+// GetContestPledges reads the pledges to a contest from blockchain, in the
+// order they were recorded
+func (bs *BlockchainService) GetContestPledges(contestID string) ([]*models.Pledge, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "getContestPledges", contestID); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	var pledgeJSONs []string
+	if len(result) > 0 {
+		pledgeJSONs, _ = result[0].([]string)
+	}
+
+	pledges := make([]*models.Pledge, 0, len(pledgeJSONs))
+	for _, pledgeJSON := range pledgeJSONs {
+		var pledge models.Pledge
+		if err := json.Unmarshal([]byte(pledgeJSON), &pledge); err != nil {
+			log.Printf("⚠️ Skipping malformed pledge in contest %s: %v", contestID, err)
+			continue
+		}
+		pledges = append(pledges, &pledge)
+	}
+	return pledges, nil
+}
+
+// GetSponsorPledges reads a sponsor's pledges to every contest it sponsors
+func (bs *BlockchainService) GetSponsorPledges(sponsorID string) ([]*models.Pledge, error) {
+	contract, err := bs.contract()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{From: bs.fromAddr}, &result, "getSponsorContests", sponsorID); err != nil {
+		return nil, apperr.FromChain(err)
+	}
+	var contestIDs []string
+	if len(result) > 0 {
+		contestIDs, _ = result[0].([]string)
+	}
+
+	pledges := make([]*models.Pledge, 0)
+	for _, contestID := range contestIDs {
+		contestPledges, err := bs.GetContestPledges(contestID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pledge := range contestPledges {
+			if pledge.SponsorID == sponsorID {
+				pledges = append(pledges, pledge)
+			}
+		}
+	}
+	return pledges, nil
+}
+
+// ============ ACCESS CONTROL OPERATIONS ============
+
+// RoleID returns the identifier of a role in the contract: keccak256("<ROLE>_ROLE")
+func RoleID(role string) common.Hash {
+	return crypto.Keccak256Hash([]byte(strings.ToUpper(role) + "_ROLE"))
+}
+
+// GrantRole grants a role to a wallet in the contract's access-control mapping
+func (bs *BlockchainService) GrantRole(role, account string) (string, error) {
+	return bs.transactRole("grantRole", role, account)
+}
+
+// RevokeRole revokes a role from a wallet in the contract's access-control mapping
+func (bs *BlockchainService) RevokeRole(role, account string) (string, error) {
+	return bs.transactRole("revokeRole", role, account)
+}
+
+// transactRole sends a grantRole/revokeRole transaction
+func (bs *BlockchainService) transactRole(method, role, account string) (string, error) {
+	if !common.IsHexAddress(account) {
+		return "", apperr.Validation("account %q is not a wallet address", account)
+	}
+
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to load contract ABI: %v", err)
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return "", fmt.Errorf("failed to create transactor: %w", err)
+	}
+
+	contract := bind.NewBoundContract(common.HexToAddress(bs.config.ContractAddress), parsedABI, bs.client, bs.client, bs.client)
+	tx, err := contract.Transact(auth, method, RoleID(role), common.HexToAddress(account))
+	if err != nil {
+		return "", fmt.Errorf("failed to %s on blockchain: %w", method, apperr.FromChain(err))
+	}
+
+	log.Printf("[OK] %s(%s, %s) pushed to blockchain: %s", method, role, account, tx.Hash().Hex())
+	return tx.Hash().Hex(), nil
 }
 
 // ============ STATISTICS OPERATIONS ============
@@ -699,7 +2018,7 @@ func (bs *BlockchainService) GetBlockchainStats() (*models.BlockchainStatsRespon
 // HealthCheck checks if the service is healthy
 func (bs *BlockchainService) HealthCheck() error {
 	if bs.client == nil {
-		return fmt.Errorf("blockchain client not initialized")
+		return apperr.New(apperr.KindChainUnavailable, "", "blockchain client not initialized")
 	}
 
 	// Try to get latest block to check connection
@@ -708,7 +2027,7 @@ func (bs *BlockchainService) HealthCheck() error {
 
 	_, err := bs.client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("blockchain connection issue: %v", err)
+		return fmt.Errorf("blockchain connection issue: %w", apperr.FromChain(err))
 	}
 
 	return nil
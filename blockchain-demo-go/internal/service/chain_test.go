@@ -0,0 +1,577 @@
+package service
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/config"
+	"blockchain-demo/internal/models"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+// artifactDir holds the committed truffle artifacts the server loads
+var artifactDir = filepath.Join("..", "..", "..", "backend", "truffle", "build", "contracts")
+
+// testChain is a simulated chain running the contracts of the committed
+// artifacts, with a BlockchainService connected to it over IPC
+type testChain struct {
+	t       *testing.T
+	sim     *simulated.Backend
+	service *BlockchainService
+	owner   *bind.TransactOpts
+}
+
+// deployArtifact deploys a compiled contract from its truffle artifact
+func deployArtifact(t *testing.T, sim *simulated.Backend, opts *bind.TransactOpts, name string, args ...interface{}) common.Address {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(artifactDir, name+".json"))
+	require.NoError(t, err)
+	var artifact struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode string          `json:"bytecode"`
+	}
+	require.NoError(t, json.Unmarshal(data, &artifact))
+	parsed, err := abi.JSON(strings.NewReader(string(artifact.ABI)))
+	require.NoError(t, err)
+
+	address, tx, _, err := bind.DeployContract(opts, parsed, common.FromHex(artifact.Bytecode), sim.Client(), args...)
+	require.NoError(t, err)
+	sim.Commit()
+	receipt, err := sim.Client().TransactionReceipt(context.Background(), tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "deploying %s", name)
+	return address
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	owner, err := bind.NewKeyedTransactorWithChainID(key, params.AllDevChainProtocolChanges.ChainID)
+	require.NoError(t, err)
+
+	ipc := filepath.Join(t.TempDir(), "sim.ipc")
+	balance := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	sim := simulated.NewBackend(types.GenesisAlloc{owner.From: {Balance: balance}}, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+		nodeConf.IPCPath = ipc
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	forwarder := deployArtifact(t, sim, owner, "WikiChainForwarder")
+	contract := deployArtifact(t, sim, owner, "ContentStorage", forwarder)
+
+	service, err := NewBlockchainService(&config.Config{
+		NetworkURL:       ipc,
+		ChainID:          params.AllDevChainProtocolChanges.ChainID.String(),
+		PrivateKey:       hex.EncodeToString(crypto.FromECDSA(key)),
+		ContractAddress:  contract.Hex(),
+		ContractJSON:     filepath.Join(artifactDir, "ContentStorage.json"),
+		ForwarderAddress: forwarder.Hex(),
+		Signer:           "key",
+	})
+	require.NoError(t, err)
+	return &testChain{t: t, sim: sim, service: service, owner: owner}
+}
+
+// mine includes the pending transactions in a block and fails the test if
+// the one sent last reverted
+func (c *testChain) mine(txHash string) {
+	c.t.Helper()
+	c.sim.Commit()
+	receipt, err := c.sim.Client().TransactionReceipt(context.Background(), common.HexToHash(txHash))
+	require.NoError(c.t, err)
+	require.Equal(c.t, types.ReceiptStatusSuccessful, receipt.Status, "transaction %s reverted", txHash)
+}
+
+// TestCommittedArtifactsMatchContracts fails when a contract is changed
+// without regenerating its artifact in the same commit
+func TestCommittedArtifactsMatchContracts(t *testing.T) {
+	artifacts, err := filepath.Glob(filepath.Join(artifactDir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, artifacts)
+
+	for _, path := range artifacts {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var artifact struct {
+			Source     string `json:"source"`
+			SourcePath string `json:"sourcePath"`
+		}
+		require.NoError(t, json.Unmarshal(data, &artifact), path)
+
+		source, err := os.ReadFile(filepath.Join(artifactDir, "..", "..", artifact.SourcePath))
+		require.NoError(t, err, path)
+		require.True(t, string(source) == artifact.Source, "%s is stale, recompile %s", filepath.Base(path), artifact.SourcePath)
+	}
+}
+
+// TestCommittedArtifactMatchesService deploys the committed ContentStorage
+// artifact and drives the service against it, so an artifact compiled from
+// an older contract fails here rather than on a live network
+func TestCommittedArtifactMatchesService(t *testing.T) {
+	parsed, err := LoadContractABI(filepath.Join(artifactDir, "ContentStorage.json"))
+	require.NoError(t, err)
+	require.Len(t, parsed.Constructor.Inputs, 1, "constructor takes the trusted forwarder")
+	require.Equal(t, "address", parsed.Constructor.Inputs[0].Type.String())
+
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:        "Artifact contest",
+		Description: "Deployed from the committed artifact",
+		StartDate:   "2030-01-01T00:00:00Z",
+		EndDate:     "2030-02-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+
+	contest, err := chain.service.GetContest(created.ID)
+	require.NoError(t, err)
+	require.True(t, contest.Success)
+	require.Equal(t, "Artifact contest", contest.Data.Name)
+	require.Equal(t, models.ContestOpen, contest.Data.State)
+
+	registered, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: "contestant-1"})
+	require.NoError(t, err)
+	chain.mine(registered.TxHash)
+
+	ok, err := chain.service.IsContestantRegistered(created.ID, "contestant-1")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestRegistrationsSentTogetherCannotOverbook(t *testing.T) {
+	chain := newTestChain(t)
+	create := func(waitlist bool) string {
+		created, err := chain.service.CreateContest(&models.CreateContestRequest{
+			Name:         "Small contest",
+			Description:  "One place only",
+			StartDate:    "2030-01-01T00:00:00Z",
+			EndDate:      "2030-02-01T00:00:00Z",
+			Registration: &models.RegistrationPolicyRequest{MaxContestants: 1, Waitlist: waitlist},
+		})
+		require.NoError(t, err)
+		chain.mine(created.TxHash)
+		return created.ID
+	}
+	register := func(contestID, contestantID string) (*models.RegisterContestantResponse, error) {
+		return chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: contestID, ContestantID: contestantID})
+	}
+
+	// Neither registration is mined when the second one is decided
+	contestID := create(true)
+	first, err := register(contestID, "alice")
+	require.NoError(t, err)
+	second, err := register(contestID, "bob")
+	require.NoError(t, err)
+	require.Equal(t, models.RegistrationRegistered, first.Data.Status)
+	require.Equal(t, models.RegistrationWaitlisted, second.Data.Status)
+	chain.mine(second.TxHash)
+
+	roster, err := chain.service.readRoster(contestID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, roster.Registered)
+	require.Equal(t, []string{"bob"}, roster.Waitlist)
+
+	// The promotion of a withdrawal still pending is known to the next registration
+	withdrawn, err := chain.service.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: contestID, ContestantID: "alice", Actor: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, "bob", withdrawn.Data.Promoted)
+	third, err := register(contestID, "carol")
+	require.NoError(t, err)
+	require.Equal(t, models.RegistrationWaitlisted, third.Data.Status)
+	chain.mine(third.TxHash)
+
+	roster, err = chain.service.readRoster(contestID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob"}, roster.Registered)
+	require.Equal(t, []string{"carol"}, roster.Waitlist)
+
+	// Without a waitlist the second registration is refused
+	contestID = create(false)
+	_, err = register(contestID, "alice")
+	require.NoError(t, err)
+	_, err = register(contestID, "bob")
+	require.Equal(t, models.ErrorCodeContestFull, apperr.CodeOf(err))
+	chain.sim.Commit()
+
+	// and the contract refuses it too when it bypasses the service
+	contract, err := chain.service.contract()
+	require.NoError(t, err)
+	_, err = contract.Transact(chain.owner, "joinContest", contestID, "bob")
+	require.ErrorContains(t, err, "Contest is full")
+}
+
+func TestWaitlistPromotesInQueueOrder(t *testing.T) {
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:         "Queued contest",
+		Description:  "One place and a waitlist",
+		StartDate:    "2030-01-01T00:00:00Z",
+		EndDate:      "2030-02-01T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{MaxContestants: 1, Waitlist: true},
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+	register := func(contestantID string) {
+		registered, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: contestantID})
+		require.NoError(t, err)
+		chain.mine(registered.TxHash)
+	}
+	withdraw := func(contestantID string) string {
+		withdrawn, err := chain.service.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: created.ID, ContestantID: contestantID, Actor: contestantID})
+		require.NoError(t, err)
+		chain.mine(withdrawn.TxHash)
+		return withdrawn.Data.Promoted
+	}
+
+	register("alice")
+	register("bob")
+	register("carol")
+	register("dave")
+
+	// bob leaves the waitlist and joins again behind dave
+	require.Empty(t, withdraw("bob"))
+	register("bob")
+
+	// The stale entry of bob is skipped, and the contract agrees with the service
+	require.Equal(t, "carol", withdraw("alice"))
+	roster, err := chain.service.readRoster(created.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"carol"}, roster.Registered)
+	require.Equal(t, []string{"dave", "bob"}, roster.Waitlist)
+
+	require.Equal(t, "dave", withdraw("carol"))
+	require.Equal(t, "bob", withdraw("dave"))
+	require.Empty(t, withdraw("bob"))
+
+	roster, err = chain.service.readRoster(created.ID)
+	require.NoError(t, err)
+	require.Empty(t, roster.Registered)
+	require.Empty(t, roster.Waitlist)
+
+	// The queue keeps working once it has been drained
+	register("erin")
+	register("frank")
+	require.Equal(t, "frank", withdraw("erin"))
+}
+
+func TestVerifiedRegistrationReadsContestantsFromChain(t *testing.T) {
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:         "Verified contest",
+		Description:  "Verified contestants only",
+		StartDate:    "2030-01-01T00:00:00Z",
+		EndDate:      "2030-02-01T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{RequireVerified: true},
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+
+	const wallet = "0x1111111111111111111111111111111111111111"
+	contestant, err := chain.service.CreateContestant(&models.CreateContestantRequest{Name: "Alice", Details: "Solo", Creator: wallet})
+	require.NoError(t, err)
+	chain.mine(contestant.TxHash)
+
+	// The contestant and its creator wallet are read back from the contract
+	found, err := chain.service.GetContestant(contestant.ID)
+	require.NoError(t, err)
+	require.True(t, found.Success)
+	require.Equal(t, wallet, found.Data.Creator)
+	require.True(t, found.Data.Verified)
+	all, err := chain.service.GetAllContestants()
+	require.NoError(t, err)
+	require.Len(t, all.Data, 1)
+	require.Equal(t, contestant.ID, all.Data[0].ID)
+	missing, err := chain.service.GetContestant("nobody")
+	require.NoError(t, err)
+	require.False(t, missing.Success)
+
+	// Only contestants stored on-chain may register
+	_, err = chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: "nobody"})
+	require.Equal(t, models.ErrorCodeNotEligible, apperr.CodeOf(err))
+	registered, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: contestant.ID})
+	require.NoError(t, err)
+	chain.mine(registered.TxHash)
+
+	// Creator wallets come from the backend only
+	contract, err := chain.service.contract()
+	require.NoError(t, err)
+	var out []interface{}
+	err = contract.Call(&bind.CallOpts{From: common.HexToAddress(wallet)}, &out, "createContestantJson", "forged", `{"creator":"`+wallet+`"}`)
+	require.ErrorContains(t, err, "Caller cannot create contestants")
+}
+
+func TestRegisteredTeamsAreReadBack(t *testing.T) {
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:         "Team contest",
+		Description:  "Teams of two",
+		StartDate:    "2030-01-01T00:00:00Z",
+		EndDate:      "2030-02-01T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{MaxTeamSize: 2},
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+
+	team := &models.Team{
+		ID:        "team-1",
+		ContestID: created.ID,
+		Name:      "Rockets",
+		Captain:   "0xaaaa",
+		Status:    models.TeamForming,
+		Members: []models.TeamMember{
+			{Address: "0xaaaa", Status: models.MemberAccepted, Signature: "0x01"},
+			{Address: "0xbbbb", Status: models.MemberAccepted, Signature: "0x02"},
+		},
+	}
+	registered, err := chain.service.RegisterTeam(team)
+	require.NoError(t, err)
+	chain.mine(registered.TxHash)
+	solo, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: "contestant-1"})
+	require.NoError(t, err)
+	chain.mine(solo.TxHash)
+
+	// Only teams are listed, with their members and signatures
+	list, err := chain.service.GetContestTeams(created.ID)
+	require.NoError(t, err)
+	require.Len(t, list.Data, 1)
+	require.Equal(t, "Rockets", list.Data[0].Name)
+	require.Equal(t, models.TeamRegistered, list.Data[0].Status)
+	require.Equal(t, models.RegistrationRegistered, list.Data[0].Entry)
+	require.Equal(t, team.Members, list.Data[0].Members)
+
+	found, err := chain.service.GetTeam("team-1")
+	require.NoError(t, err)
+	require.Equal(t, created.ID, found.Data.ContestID)
+	_, err = chain.service.GetTeam("team-2")
+	require.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+
+	// A withdrawn team is cleared and its ID may register again
+	withdrawn, err := chain.service.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: created.ID, ContestantID: "team-1", Actor: "0xaaaa"})
+	require.NoError(t, err)
+	chain.mine(withdrawn.TxHash)
+	list, err = chain.service.GetContestTeams(created.ID)
+	require.NoError(t, err)
+	require.Empty(t, list.Data)
+	_, err = chain.service.GetTeam("team-1")
+	require.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+	registered, err = chain.service.RegisterTeam(team)
+	require.NoError(t, err)
+	chain.mine(registered.TxHash)
+}
+
+func TestVerifiedTeamsNeedEveryMemberOnChain(t *testing.T) {
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:         "Verified teams",
+		Description:  "Teams of verified contestants",
+		StartDate:    "2030-01-01T00:00:00Z",
+		EndDate:      "2030-02-01T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{MaxTeamSize: 2, RequireVerified: true},
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+
+	const captain, member = "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"
+	contestant, err := chain.service.CreateContestant(&models.CreateContestantRequest{Name: "Captain", Details: "Team captain", Creator: captain})
+	require.NoError(t, err)
+	chain.mine(contestant.TxHash)
+
+	team := &models.Team{
+		ID:        "team-1",
+		ContestID: created.ID,
+		Name:      "Rockets",
+		Captain:   captain,
+		Status:    models.TeamForming,
+		Members: []models.TeamMember{
+			{Address: captain, Status: models.MemberAccepted, Signature: "0x01"},
+			{Address: member, Status: models.MemberAccepted, Signature: "0x02"},
+		},
+	}
+
+	// A member without a contestant on-chain keeps the team out
+	_, err = chain.service.RegisterTeam(team)
+	require.Equal(t, models.ErrorCodeNotEligible, apperr.CodeOf(err))
+
+	contestant, err = chain.service.CreateContestant(&models.CreateContestantRequest{Name: "Member", Details: "Team member", Creator: member})
+	require.NoError(t, err)
+	chain.mine(contestant.TxHash)
+	registered, err := chain.service.RegisterTeam(team)
+	require.NoError(t, err)
+	chain.mine(registered.TxHash)
+	require.Equal(t, models.RegistrationRegistered, registered.Data.Entry)
+}
+
+func TestPrizePoolPaysWinnersAtTheirContestantWallets(t *testing.T) {
+	chain := newTestChain(t)
+	escrowAddress := deployArtifact(t, chain.sim, chain.owner, "PrizeEscrow")
+	chain.service.config.EscrowAddress = escrowAddress.Hex()
+	chain.service.config.EscrowJSON = filepath.Join(artifactDir, "PrizeEscrow.json")
+
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:        "Prize contest",
+		Description: "Two paid places",
+		StartDate:   "2030-01-01T00:00:00Z",
+		EndDate:     "2030-02-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+
+	const aliceWallet, bobWallet = "0x1111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222"
+	var ids []string
+	for _, creator := range []string{aliceWallet, bobWallet} {
+		contestant, err := chain.service.CreateContestant(&models.CreateContestantRequest{Name: "Winner", Details: "Paid at its wallet", Creator: creator})
+		require.NoError(t, err)
+		chain.mine(contestant.TxHash)
+		registered, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: contestant.ID})
+		require.NoError(t, err)
+		chain.mine(registered.TxHash)
+		ids = append(ids, contestant.ID)
+	}
+
+	opened, err := chain.service.OpenPrizePool(&models.OpenPrizePoolRequest{ContestID: created.ID, SharesBps: []uint64{7000, 3000}})
+	require.NoError(t, err)
+	chain.mine(opened.TxHash)
+	client, err := chain.service.escrow()
+	require.NoError(t, err)
+	parsedABI, err := LoadContractABI(chain.service.config.EscrowJSON)
+	require.NoError(t, err)
+	deposit := *chain.owner
+	deposit.Value = big.NewInt(1000)
+	tx, err := bind.NewBoundContract(escrowAddress, parsedABI, chain.sim.Client(), chain.sim.Client(), chain.sim.Client()).Transact(&deposit, "deposit", created.ID)
+	require.NoError(t, err)
+	chain.mine(tx.Hash().Hex())
+
+	for _, state := range []string{models.ContestRunning, models.ContestJudging} {
+		moved, err := chain.service.TransitionContest(created.ID, state, "", "scheduler")
+		require.NoError(t, err)
+		chain.mine(moved.Data.TxHash)
+	}
+	finalized, err := chain.service.FinalizeResults(&models.ContestResults{
+		ContestID: created.ID,
+		Results:   []models.ContestantResult{{Rank: 1, ContestantID: ids[1]}, {Rank: 2, ContestantID: ids[0]}},
+		Final:     true,
+	})
+	require.NoError(t, err)
+	chain.mine(finalized.TxHash)
+
+	// Each winner is paid at the wallet that created its contestant on-chain
+	paid, err := chain.service.PayoutPrizePool(&models.PrizePayoutRequest{ContestID: created.ID})
+	require.NoError(t, err)
+	chain.mine(paid.TxHash)
+	for wallet, amount := range map[string]int64{bobWallet: 700, aliceWallet: 300} {
+		balance, err := chain.sim.Client().BalanceAt(context.Background(), common.HexToAddress(wallet), nil)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(amount), balance)
+	}
+	pool, err := client.Pool(created.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.PrizePoolPaid, pool.State)
+}
+
+func TestRosterIsRebuiltAsOfAPastTime(t *testing.T) {
+	chain := newTestChain(t)
+	created, err := chain.service.CreateContest(&models.CreateContestRequest{
+		Name:         "Voting contest",
+		Description:  "One place and a waitlist",
+		StartDate:    "2030-01-01T00:00:00Z",
+		EndDate:      "2030-02-01T00:00:00Z",
+		Registration: &models.RegistrationPolicyRequest{MaxContestants: 1, Waitlist: true},
+	})
+	require.NoError(t, err)
+	chain.mine(created.TxHash)
+	for _, id := range []string{"alice", "bob"} {
+		registered, err := chain.service.RegisterContestant(&models.RegisterContestantRequest{ContestID: created.ID, ContestantID: id})
+		require.NoError(t, err)
+		chain.mine(registered.TxHash)
+	}
+	header, err := chain.sim.Client().HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	before := time.Unix(int64(header.Time), 0)
+
+	require.NoError(t, chain.sim.AdjustTime(time.Hour))
+	withdrawn, err := chain.service.WithdrawContestant(&models.WithdrawContestantRequest{ContestID: created.ID, ContestantID: "alice", Actor: "alice"})
+	require.NoError(t, err)
+	chain.mine(withdrawn.TxHash)
+
+	roster, err := chain.service.GetRosterAt(created.ID, before)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, roster.Registered)
+	require.Equal(t, []string{"bob"}, roster.Waitlist)
+
+	// The promotion replays from the withdrawal, as the contract made it
+	roster, err = chain.service.GetRosterAt(created.ID, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []string{"bob"}, roster.Registered)
+	require.Empty(t, roster.Waitlist)
+}
+
+func TestContentRevisionsAreAnchored(t *testing.T) {
+	chain := newTestChain(t)
+	contract, err := chain.service.contract()
+	require.NoError(t, err)
+	parsedABI, err := LoadContractABI(filepath.Join(artifactDir, "ContentStorage.json"))
+	require.NoError(t, err)
+
+	// anchored returns the record emitted by the transaction
+	anchored := func(txHash string) (uint64, models.Content, []byte) {
+		t.Helper()
+		chain.mine(txHash)
+		receipt, err := chain.sim.Client().TransactionReceipt(context.Background(), common.HexToHash(txHash))
+		require.NoError(t, err)
+		require.Len(t, receipt.Logs, 1)
+		values, err := parsedABI.Unpack("ContentAnchored", receipt.Logs[0].Data)
+		require.NoError(t, err)
+		record := []byte(values[1].(string))
+		var content models.Content
+		require.NoError(t, json.Unmarshal(record, &content))
+		return values[0].(*big.Int).Uint64(), content, record
+	}
+
+	created, err := chain.service.StoreContent(&models.CreateContentRequest{
+		Title: "Hà Nội", Content: "Thủ đô", Creator: "0xaaaa", Categories: []string{"Địa lý"}, Tags: []string{"thu-do"},
+	})
+	require.NoError(t, err)
+	revision, content, first := anchored(created.TxHash)
+	require.Equal(t, uint64(1), revision)
+	require.Equal(t, crypto.Keccak256Hash(first).Hex(), created.RecordHash)
+	require.Equal(t, created.ID, content.ID)
+	require.Equal(t, []string{"thu-do"}, content.Tags)
+
+	updated, err := chain.service.UpdateContent(created.ID, &models.CreateContentRequest{
+		Title: "Hà Nội", Content: "Thủ đô của Việt Nam", Creator: "0xaaaa", Tags: []string{"thu-do", "viet-nam"},
+	})
+	require.NoError(t, err)
+	revision, content, second := anchored(updated.TxHash)
+	require.Equal(t, uint64(2), revision)
+	require.Equal(t, crypto.Keccak256Hash(second).Hex(), updated.RecordHash)
+	require.Equal(t, []string{"thu-do", "viet-nam"}, content.Tags)
+
+	// The contract keeps the hash of every revision to check the records against
+	var result []interface{}
+	require.NoError(t, contract.Call(&bind.CallOpts{}, &result, "getContentRevisions", created.ID))
+	require.Equal(t, [][32]byte{crypto.Keccak256Hash(first), crypto.Keccak256Hash(second)}, result[0])
+
+	// Only the backend anchors, so content skipping moderation cannot reach the chain
+	stranger := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	err = contract.Call(&bind.CallOpts{From: stranger}, &result, "anchorContent", created.ID, "{}")
+	require.ErrorContains(t, err, "Caller cannot anchor content")
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/escrow"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/signer"
+	"blockchain-demo/internal/validation"
+	"fmt"
+	"log"
+)
+
+// ============ PRIZE POOL OPERATIONS ============
+
+// OpenPrizePool opens the escrowed prize pool of a contest with its
+// distribution table. Sponsors then deposit into it from their own wallets.
+func (bs *BlockchainService) OpenPrizePool(req *models.OpenPrizePoolRequest) (*models.PrizePoolResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	if bs.ReadOnly() {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", req.ContestID)
+		}
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, err
+	}
+
+	client, err := bs.escrow()
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to bind escrow contract",
+		}, err
+	}
+
+	existing, err := client.Pool(req.ContestID)
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to read prize pool from blockchain",
+		}, err
+	}
+	if err := escrow.CheckOpen(contest.Data, existing, req); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	txHash, err := client.Open(req.ContestID, req.Token, req.SharesBps)
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to open prize pool on blockchain",
+		}, err
+	}
+
+	log.Printf("💰 Prize pool opened for contest %s: %d places, TX: %s", req.ContestID, len(req.SharesBps), txHash)
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: "Prize pool opened on blockchain",
+		TxHash:  txHash,
+		Data: &models.PrizePool{
+			ContestID: req.ContestID,
+			Escrow:    client.Address(),
+			Token:     req.Token,
+			State:     models.PrizePoolFunding,
+			Total:     "0",
+			Shares:    escrow.Shares(req.SharesBps),
+			Deposits:  []models.PrizeDeposit{},
+		},
+	}, nil
+}
+
+// GetPrizePool reads the prize pool of a contest, with the deposits and
+// payouts taken from the escrow's events
+func (bs *BlockchainService) GetPrizePool(contestID string) (*models.PrizePoolResponse, error) {
+	client, err := bs.escrow()
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to bind escrow contract",
+		}, err
+	}
+
+	pool, err := client.Pool(contestID)
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to read prize pool from blockchain",
+		}, err
+	}
+	if pool == nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Prize pool not found on blockchain",
+		}, apperr.NotFound("contest %s has no prize pool", contestID)
+	}
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Data:    pool,
+	}, nil
+}
+
+// PayoutPrizePool pays the whole pool out to the winners of the final
+// results in one transaction. Each winner is paid at the wallet it proved it
+// owns, see escrow.PayoutWallet.
+func (bs *BlockchainService) PayoutPrizePool(req *models.PrizePayoutRequest) (*models.PrizePoolResponse, error) {
+	if bs.ReadOnly() {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(req.ContestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", req.ContestID)
+		}
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, err
+	}
+
+	client, pool, resp, err := bs.prizePool(req.ContestID)
+	if err != nil {
+		return resp, err
+	}
+
+	// Missing results are reported by CheckPayout as not final
+	var results *models.ContestResults
+	if found, err := bs.GetContestResults(req.ContestID); err == nil && found.Success {
+		results = found.Data
+	} else if apperr.KindOf(err) != apperr.KindNotFound {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to read results from blockchain",
+		}, err
+	}
+	if err := escrow.CheckPayout(contest.Data, pool, results); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	payouts, err := escrow.Plan(pool, results.Results, func(contestantID string) (string, error) {
+		contestant, err := bs.GetContestant(contestantID)
+		if err != nil && apperr.KindOf(err) != apperr.KindNotFound {
+			return "", err
+		}
+		if err != nil || !contestant.Success {
+			return "", nil
+		}
+		return escrow.PayoutWallet(req.ContestID, contestant.Data, payoutAddress(req, contestantID))
+	})
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	txHash, err := client.Payout(req.ContestID, payouts)
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to pay out prize pool on blockchain",
+		}, err
+	}
+
+	log.Printf("🏆 Prize pool of contest %s paid out to %d winners, TX: %s", req.ContestID, len(payouts), txHash)
+
+	for i := range payouts {
+		payouts[i].TxHash = txHash
+	}
+	pool.State = models.PrizePoolPaid
+	pool.Payouts = payouts
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: fmt.Sprintf("Prize pool paid out to %d winners", len(payouts)),
+		TxHash:  txHash,
+		Data:    pool,
+	}, nil
+}
+
+// payoutAddress returns the payout address a winner signed, nil if none
+func payoutAddress(req *models.PrizePayoutRequest, contestantID string) *models.PayoutAddress {
+	if address, ok := req.Addresses[contestantID]; ok {
+		return &address
+	}
+	return nil
+}
+
+// RefundPrizePool cancels the prize pool of a closed or cancelled contest and returns
+// every deposit to the wallet it came from. A refund that fails stops the
+// others; calling again retries the depositors still owed.
+func (bs *BlockchainService) RefundPrizePool(contestID string) (*models.PrizePoolResponse, error) {
+	if bs.ReadOnly() {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Server is read-only",
+		}, signer.ErrReadOnly
+	}
+
+	contest, err := bs.GetContest(contestID)
+	if err != nil || !contest.Success {
+		if err == nil {
+			err = apperr.NotFound("contest %s not found", contestID)
+		}
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found",
+		}, err
+	}
+
+	client, pool, resp, err := bs.prizePool(contestID)
+	if err != nil {
+		return resp, err
+	}
+	if err := escrow.CheckRefund(contest.Data, pool); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	txHash := ""
+	if pool.State == models.PrizePoolFunding {
+		if txHash, err = client.Cancel(contestID); err != nil {
+			return &models.PrizePoolResponse{
+				Success: false,
+				Message: "Failed to cancel prize pool on blockchain",
+			}, err
+		}
+		log.Printf("🛑 Prize pool of contest %s cancelled, TX: %s", contestID, txHash)
+	}
+
+	refunds := escrow.Refundable(pool)
+	for _, refund := range refunds {
+		if txHash, err = client.Refund(contestID, refund.Depositor); err != nil {
+			return &models.PrizePoolResponse{
+				Success: false,
+				Message: "Failed to refund " + refund.Depositor,
+			}, err
+		}
+		log.Printf("↩️ Refunded %s to %s from prize pool of contest %s, TX: %s", refund.Amount, refund.Depositor, contestID, txHash)
+	}
+
+	pool.State = models.PrizePoolRefunding
+	for i := range pool.Deposits {
+		pool.Deposits[i].Refunded = true
+	}
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: fmt.Sprintf("Refunded %d depositors", len(refunds)),
+		TxHash:  txHash,
+		Data:    pool,
+	}, nil
+}
+
+// prizePool binds the escrow and reads the pool of a contest, with the
+// response to return when either fails
+func (bs *BlockchainService) prizePool(contestID string) (*escrow.Client, *models.PrizePool, *models.PrizePoolResponse, error) {
+	client, err := bs.escrow()
+	if err != nil {
+		return nil, nil, &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to bind escrow contract",
+		}, err
+	}
+
+	pool, err := client.Pool(contestID)
+	if err != nil {
+		return nil, nil, &models.PrizePoolResponse{
+			Success: false,
+			Message: "Failed to read prize pool from blockchain",
+		}, err
+	}
+	if pool == nil {
+		return nil, nil, &models.PrizePoolResponse{
+			Success: false,
+			Message: "Prize pool not found on blockchain",
+		}, apperr.NotFound("contest %s has no prize pool", contestID)
+	}
+	return client, pool, nil, nil
+}
+
+// escrow binds the PrizeEscrow contract, whose owner must be the signer
+func (bs *BlockchainService) escrow() (*escrow.Client, error) {
+	if bs.config.EscrowAddress == "" {
+		return nil, fmt.Errorf("prize pools are not enabled: ESCROW_ADDRESS is not set")
+	}
+	parsedABI, err := LoadContractABI(bs.config.EscrowJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load escrow ABI: %w", err)
+	}
+	return escrow.NewClient(bs.config.EscrowAddress, parsedABI, bs.config.EscrowStartBlock, bs.client, bs.transactor)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/ids"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/validation"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// forwarderABI is the part of the WikiChainForwarder ABI used by the relayer
+const forwarderABI = `[
+	{"type":"function","name":"getNonce","stateMutability":"view",
+	 "inputs":[{"name":"from","type":"address"}],
+	 "outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"execute","stateMutability":"payable",
+	 "inputs":[
+		{"name":"req","type":"tuple","components":[
+			{"name":"from","type":"address"},
+			{"name":"to","type":"address"},
+			{"name":"value","type":"uint256"},
+			{"name":"gas","type":"uint256"},
+			{"name":"nonce","type":"uint256"},
+			{"name":"deadline","type":"uint256"},
+			{"name":"data","type":"bytes"}]},
+		{"name":"signature","type":"bytes"}],
+	 "outputs":[{"name":"","type":"bytes"}]}
+]`
+
+// forwardRequestTuple is the Go form of the forwarder's ForwardRequest struct
+type forwardRequestTuple struct {
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+	Gas      *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+	Data     []byte
+}
+
+// ============ META-TRANSACTION OPERATIONS ============
+
+// EncodeCreateContest builds the createContestJson call a user signs to create
+// a contest through the forwarder. The organizer is the signer.
+func (bs *BlockchainService) EncodeCreateContest(req *models.CreateContestRequest) (string, []byte, error) {
+	if err := validation.Struct(req); err != nil {
+		return "", nil, err
+	}
+
+	id, err := bs.newID(ids.EntityContest, req.Organizer, req.Name, req.Nonce)
+	if err != nil {
+		return "", nil, err
+	}
+	jsonBytes, _, err := bs.contestJSON(id, req, req.Organizer)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load contract ABI: %v", err)
+	}
+	maxContestants, waitlist := contestCapacity(req)
+	data, err := parsedABI.Pack("createContestJson", id, string(jsonBytes), maxContestants, waitlist)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode createContestJson: %v", err)
+	}
+	return id, data, nil
+}
+
+// ForwarderNonce returns the next nonce of a signer stored in the forwarder
+func (bs *BlockchainService) ForwarderNonce(from string) (uint64, error) {
+	contract, err := bs.forwarder()
+	if err != nil {
+		return 0, err
+	}
+
+	var result []interface{}
+	if err := contract.Call(&bind.CallOpts{}, &result, "getNonce", common.HexToAddress(from)); err != nil {
+		return 0, fmt.Errorf("failed to read forwarder nonce: %w", apperr.FromChain(err))
+	}
+	return result[0].(*big.Int).Uint64(), nil
+}
+
+// Relay sends a signed forward request through the forwarder, paying the gas
+func (bs *BlockchainService) Relay(req *models.ForwardRequest, signature []byte) (string, error) {
+	contract, err := bs.forwarder()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := hexutil.Decode(req.Data)
+	if err != nil {
+		return "", apperr.Validation("invalid request data: %v", err)
+	}
+
+	auth, err := bs.transactor()
+	if err != nil {
+		return "", fmt.Errorf("failed to create transactor: %w", err)
+	}
+
+	tuple := forwardRequestTuple{
+		From:     common.HexToAddress(req.From),
+		To:       common.HexToAddress(req.To),
+		Value:    new(big.Int).SetUint64(req.Value),
+		Gas:      new(big.Int).SetUint64(req.Gas),
+		Nonce:    new(big.Int).SetUint64(req.Nonce),
+		Deadline: new(big.Int).SetUint64(req.Deadline),
+		Data:     data,
+	}
+	tx, err := contract.Transact(auth, "execute", tuple, signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to relay request: %w", apperr.FromChain(err))
+	}
+
+	log.Printf("[OK] Relayed request of %s (nonce %d): %s", req.From, req.Nonce, tx.Hash().Hex())
+	return tx.Hash().Hex(), nil
+}
+
+// forwarder returns the bound forwarder contract
+func (bs *BlockchainService) forwarder() (*bind.BoundContract, error) {
+	if bs.config.ForwarderAddress == "" {
+		return nil, fmt.Errorf("meta-transactions are not enabled: FORWARDER_ADDRESS is not set")
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(forwarderABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(common.HexToAddress(bs.config.ForwarderAddress), parsedABI, bs.client, bs.client, bs.client), nil
+}
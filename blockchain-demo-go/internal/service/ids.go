@@ -0,0 +1,75 @@
+package service
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/ids"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newID returns the ID of a new entity: random, or derived from the request
+// when the client sent a nonce, in which case it must not be taken yet
+func (bs *BlockchainService) newID(entity, creator, name, nonce string) (string, error) {
+	id, err := ids.New(entity, creator, name, nonce)
+	if err != nil || nonce == "" {
+		return id, err
+	}
+
+	taken, err := bs.IDTaken(entity, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to check id %s: %w", id, apperr.FromChain(err))
+	}
+	if taken {
+		return "", fmt.Errorf("%s %s: %w", entity, id, ids.ErrTaken)
+	}
+	return id, nil
+}
+
+// IDTaken checks the contract for an entity with the given ID
+func (bs *BlockchainService) IDTaken(entity, id string) (bool, error) {
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return false, fmt.Errorf("failed to load contract ABI: %v", err)
+	}
+	contract := bind.NewBoundContract(common.HexToAddress(bs.config.ContractAddress), parsedABI, bs.client, bs.client, bs.client)
+	callOpts := &bind.CallOpts{Pending: true, From: bs.fromAddr}
+
+	switch entity {
+	case ids.EntityContest:
+		var out []interface{}
+		if err := contract.Call(callOpts, &out, "getContestJsonById", id); err != nil {
+			return false, err
+		}
+		jsonStr, _ := out[0].(string)
+		return jsonStr != "", nil
+	case ids.EntityContent:
+		if _, exists := bs.contents[id]; exists {
+			return true, nil
+		}
+		return bs.listContains(contract, callOpts, "getAllContentIds", id)
+	case ids.EntityContestant:
+		if _, exists := bs.contestants[id]; exists {
+			return true, nil
+		}
+		return bs.listContains(contract, callOpts, "getAllContestantIds", id)
+	default:
+		return false, ids.ErrInvalidType
+	}
+}
+
+// listContains reports whether a contract method returning string[] lists id
+func (bs *BlockchainService) listContains(contract *bind.BoundContract, callOpts *bind.CallOpts, method, id string) (bool, error) {
+	var out []interface{}
+	if err := contract.Call(callOpts, &out, method); err != nil {
+		return false, err
+	}
+	list, _ := out[0].([]string)
+	for _, existing := range list {
+		if existing == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -1,18 +1,33 @@
 package service
 
 import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/escrow"
+	"blockchain-demo/internal/ids"
+	"blockchain-demo/internal/lifecycle"
 	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/registration"
+	"blockchain-demo/internal/sponsorship"
+	"blockchain-demo/internal/teams"
+	"blockchain-demo/internal/validation"
+	"blockchain-demo/internal/voting"
+	"blockchain-demo/internal/wiki"
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 )
 
-// BlockchainServiceInterface định nghĩa các phương thức cần thiết cho blockchain service
+// BlockchainServiceInterface định nghĩa các phương thức cần thiết cho blockchain service.
+// Các phương thức ghi kiểm tra request bằng validation.Struct, cùng quy tắc với
+// handler, để caller ngoài API (scheduler, tool) nhận đúng lỗi như API.
 type BlockchainServiceInterface interface {
 	// Content operations
 	StoreContent(req *models.CreateContentRequest) (*models.CreateContentResponse, error)
+	UpdateContent(id string, req *models.CreateContentRequest) (*models.CreateContentResponse, error)
 	GetContent(id string) (*models.GetContentResponse, error)
 	GetAllContents() (*models.ListContentsResponse, error)
 
@@ -21,6 +36,7 @@ type BlockchainServiceInterface interface {
 	GetContest(id string) (*models.GetContestResponse, error)
 	GetAllContests() (*models.ListContestsResponse, error)
 	SearchContests(keyword string) ([]*models.Contest, error)
+	TransitionContest(id, to, reason, actor string) (*models.ContestTransitionResponse, error)
 
 	// Contestant operations
 	CreateContestant(req *models.CreateContestantRequest) (*models.CreateContestantResponse, error)
@@ -34,12 +50,49 @@ type BlockchainServiceInterface interface {
 
 	// Registration operations
 	RegisterContestant(req *models.RegisterContestantRequest) (*models.RegisterContestantResponse, error)
+	WithdrawContestant(req *models.WithdrawContestantRequest) (*models.WithdrawContestantResponse, error)
 	GetContestantsInContest(contestID string) (*models.ListContestantsInContestResponse, error)
 	IsContestantRegistered(contestID, contestantID string) (bool, error)
+	GetRosterAt(contestID string, at time.Time) (*registration.Roster, error)
+	RegisterTeam(team *models.Team) (*models.TeamResponse, error)
+	GetContestTeams(contestID string) (*models.ListTeamsResponse, error)
+	GetTeam(id string) (*models.TeamResponse, error)
+
+	// Judging operations
+	FinalizeResults(results *models.ContestResults) (*models.ContestResultsResponse, error)
+	GetContestResults(contestID string) (*models.ContestResultsResponse, error)
+
+	// Voting operations
+	CommitVote(req *models.CommitVoteRequest) (*models.VoteResponse, error)
+	RevealVote(req *models.RevealVoteRequest) (*models.VoteResponse, error)
+	GetVotes(contestID string) ([]*models.Vote, error)
+
+	// Sponsorship operations
+	PledgeSponsor(req *models.PledgeRequest) (*models.PledgeResponse, error)
+	GetContestPledges(contestID string) ([]*models.Pledge, error)
+	GetSponsorPledges(sponsorID string) ([]*models.Pledge, error)
+
+	// Prize pool operations (PrizeEscrow contract)
+	OpenPrizePool(req *models.OpenPrizePoolRequest) (*models.PrizePoolResponse, error)
+	GetPrizePool(contestID string) (*models.PrizePoolResponse, error)
+	PayoutPrizePool(req *models.PrizePayoutRequest) (*models.PrizePoolResponse, error)
+	RefundPrizePool(contestID string) (*models.PrizePoolResponse, error)
+
+	// Access control operations (mirror of the contract's role mapping)
+	GrantRole(role, account string) (string, error)
+	RevokeRole(role, account string) (string, error)
+
+	// Meta-transaction operations (EIP-712 requests relayed through the forwarder)
+	EncodeCreateContest(req *models.CreateContestRequest) (string, []byte, error)
+	ForwarderNonce(from string) (uint64, error)
+	Relay(req *models.ForwardRequest, signature []byte) (string, error)
 
 	// Utils
+	WithContext(ctx context.Context) BlockchainServiceInterface
+	IDTaken(entity, id string) (bool, error)
 	GetBlockchainStats() (*models.BlockchainStatsResponse, error)
 	HealthCheck() error
+	ReadOnly() bool
 }
 
 // MockBlockchainService là phiên bản mô phỏng của BlockchainService để dùng cho test
@@ -48,7 +101,27 @@ type MockBlockchainService struct {
 	contests      map[string]*models.Contest
 	contestants   map[string]*models.Contestant
 	sponsors      map[string]*models.Sponsor
-	registrations map[string]map[string]bool
+	registrations map[string]*registration.Roster // contestID -> thí sinh đã đăng ký và danh sách chờ
+	teamJsons     map[string]*models.Team         // teamID -> đội đã đăng ký
+	rosterHistory map[string][]rosterChange       // contestID -> các thay đổi danh sách theo thứ tự
+
+	// Chấm điểm
+	results map[string]*models.ContestResults // contestID -> kết quả chấm đã chốt
+
+	// Bỏ phiếu
+	votes map[string][]*models.Vote // contestID -> phiếu theo thứ tự commit
+
+	// Tài trợ
+	pledges []*models.Pledge // các khoản cam kết theo thứ tự ghi nhận
+
+	// Quỹ giải thưởng
+	prizePools map[string]*models.PrizePool // contestID -> quỹ ký quỹ
+
+	// Meta-transaction
+	pendingContests map[string]*models.Contest // calldata -> contest chờ relay
+	forwarderNonces map[string]uint64
+
+	readOnly bool
 }
 
 // NewMockBlockchainService tạo instance mới của MockBlockchainService
@@ -58,10 +131,28 @@ func NewMockBlockchainService() BlockchainServiceInterface {
 		contests:      make(map[string]*models.Contest),
 		contestants:   make(map[string]*models.Contestant),
 		sponsors:      make(map[string]*models.Sponsor),
-		registrations: make(map[string]map[string]bool),
+		registrations: make(map[string]*registration.Roster),
+		teamJsons:     make(map[string]*models.Team),
+		rosterHistory: make(map[string][]rosterChange),
+
+		results: make(map[string]*models.ContestResults),
+
+		votes: make(map[string][]*models.Vote),
+
+		prizePools: make(map[string]*models.PrizePool),
+
+		pendingContests: make(map[string]*models.Contest),
+		forwarderNonces: make(map[string]uint64),
 	}
 }
 
+// NewReadOnlyMockBlockchainService tạo mock service ở chế độ chỉ đọc
+func NewReadOnlyMockBlockchainService() BlockchainServiceInterface {
+	m := NewMockBlockchainService().(*MockBlockchainService)
+	m.readOnly = true
+	return m
+}
+
 // generateID tạo ID ngẫu nhiên
 func (m *MockBlockchainService) generateID() string {
 	bytes := make([]byte, 16)
@@ -72,6 +163,18 @@ func (m *MockBlockchainService) generateID() string {
 	return hex.EncodeToString(bytes)
 }
 
+// newID tạo ID ngẫu nhiên, hoặc ID xác định từ request nếu client gửi nonce
+func (m *MockBlockchainService) newID(entity, creator, name, nonce string) (string, error) {
+	id, err := ids.New(entity, creator, name, nonce)
+	if err != nil || nonce == "" {
+		return id, err
+	}
+	if taken, _ := m.IDTaken(entity, id); taken {
+		return "", fmt.Errorf("%s %s: %w", entity, id, ids.ErrTaken)
+	}
+	return id, nil
+}
+
 // generateTxHash tạo hash giao dịch giả
 func (m *MockBlockchainService) generateTxHash() string {
 	bytes := make([]byte, 32)
@@ -84,15 +187,32 @@ func (m *MockBlockchainService) generateTxHash() string {
 
 // StoreContent giả lập lưu trữ nội dung
 func (m *MockBlockchainService) StoreContent(req *models.CreateContentRequest) (*models.CreateContentResponse, error) {
-	id := m.generateID()
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	id, err := m.newID(ids.EntityContent, req.Creator, req.Title, req.Nonce)
+	if err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: "Failed to generate content ID in mock",
+		}, err
+	}
 	content := &models.Content{
-		ID:        id,
-		Title:     req.Title,
-		Content:   req.Content,
-		Creator:   req.Creator,
-		Timestamp: time.Now(),
-		Verified:  true,
-		TxHash:    m.generateTxHash(),
+		ID:         id,
+		Title:      req.Title,
+		Content:    req.Content,
+		Creator:    req.Creator,
+		Namespace:  wiki.NormalizeNamespace(req.Namespace),
+		Categories: req.Categories,
+		Tags:       req.Tags,
+		Links:      wiki.ParseLinks(req.Content),
+		Timestamp:  time.Now(),
+		Verified:   true,
+		TxHash:     m.generateTxHash(),
 	}
 	m.contents[id] = content
 
@@ -104,6 +224,41 @@ func (m *MockBlockchainService) StoreContent(req *models.CreateContentRequest) (
 	}, nil
 }
 
+// UpdateContent giả lập ghi một phiên bản mới của nội dung
+func (m *MockBlockchainService) UpdateContent(id string, req *models.CreateContentRequest) (*models.CreateContentResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	content, exists := m.contents[id]
+	if !exists {
+		return &models.CreateContentResponse{
+			Success: false,
+			Message: "Content not found in mock",
+		}, apperr.NotFound("content %s not found", id)
+	}
+
+	content.Title = req.Title
+	content.Content = req.Content
+	content.Creator = req.Creator
+	content.Namespace = wiki.NormalizeNamespace(req.Namespace)
+	content.Categories = req.Categories
+	content.Tags = req.Tags
+	content.Links = wiki.ParseLinks(req.Content)
+	content.Timestamp = time.Now()
+	content.TxHash = m.generateTxHash()
+
+	return &models.CreateContentResponse{
+		Success: true,
+		Message: "Content updated successfully in mock",
+		TxHash:  content.TxHash,
+		ID:      id,
+	}, nil
+}
+
 // GetContent giả lập lấy nội dung
 func (m *MockBlockchainService) GetContent(id string) (*models.GetContentResponse, error) {
 	content, exists := m.contents[id]
@@ -111,7 +266,7 @@ func (m *MockBlockchainService) GetContent(id string) (*models.GetContentRespons
 		return &models.GetContentResponse{
 			Success: false,
 			Message: "Content not found in mock",
-		}, fmt.Errorf("content not found")
+		}, apperr.NotFound("content %s not found", id)
 	}
 
 	return &models.GetContentResponse{
@@ -136,52 +291,96 @@ func (m *MockBlockchainService) GetAllContents() (*models.ListContentsResponse,
 
 // CreateContest giả lập tạo cuộc thi
 func (m *MockBlockchainService) CreateContest(req *models.CreateContestRequest) (*models.CreateContestResponse, error) {
+	contest, failure, err := m.newContest(req)
+	if err != nil {
+		return failure, err
+	}
+
+	m.contests[contest.ID] = contest
+
+	return &models.CreateContestResponse{
+		Success: true,
+		Message: "Contest created successfully in mock",
+		TxHash:  contest.TxHash,
+		ID:      contest.ID,
+	}, nil
+}
+
+// newContest kiểm tra dữ liệu và tạo cuộc thi (chưa lưu)
+func (m *MockBlockchainService) newContest(req *models.CreateContestRequest) (*models.Contest, *models.CreateContestResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
 	// Validate dates
 	startDate, err := time.Parse(time.RFC3339, req.StartDate)
 	if err != nil {
-		return &models.CreateContestResponse{
+		return nil, &models.CreateContestResponse{
 			Success: false,
 			Message: "Invalid start date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
-		}, err
+		}, apperr.Validation("invalid start date: %v", err)
 	}
 	endDate, err := time.Parse(time.RFC3339, req.EndDate)
 	if err != nil {
-		return &models.CreateContestResponse{
+		return nil, &models.CreateContestResponse{
 			Success: false,
 			Message: "Invalid end date format. Use RFC3339 format: 2006-01-02T15:04:05Z",
-		}, err
+		}, apperr.Validation("invalid end date: %v", err)
 	}
 	if endDate.Before(startDate) {
-		return &models.CreateContestResponse{
+		return nil, &models.CreateContestResponse{
 			Success: false,
 			Message: "End date must be after start date",
-		}, fmt.Errorf("invalid date range")
+		}, apperr.Validation("end date must be after start date")
+	}
+	if err := sponsorship.CheckTiers(req.SponsorTiers); err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Sponsor tier thresholds must decrease from gold to bronze",
+		}, err
 	}
 
-	id := m.generateID()
-	txHash := m.generateTxHash()
+	id, err := m.newID(ids.EntityContest, req.Organizer, req.Name, req.Nonce)
+	if err != nil {
+		return nil, &models.CreateContestResponse{
+			Success: false,
+			Message: "Failed to generate contest ID in mock",
+		}, err
+	}
+
+	organizer := "0xMockAddress"
+	if req.Organizer != "" {
+		organizer = req.Organizer
+	}
 
-	contest := &models.Contest{
+	// Cuộc thi mới mở đăng ký ngay, trừ khi tạo ở dạng nháp
+	state := models.ContestOpen
+	if req.Draft {
+		state = models.ContestDraft
+	}
+
+	return &models.Contest{
 		ID:          id,
 		Name:        req.Name,
 		Description: req.Description,
 		StartDate:   startDate,
 		EndDate:     endDate,
 		ImageURL:    req.ImageURL,
-		Organizer:   "0xMockAddress",
-		TxHash:      txHash,
-		Active:      true,
+		Categories:  req.Categories,
+		Tags:        req.Tags,
+		Organizer:   organizer,
+		TxHash:      m.generateTxHash(),
+		Active:      lifecycle.Active(state),
+		State:       state,
 		Timestamp:   time.Now(),
-	}
-
-	m.contests[id] = contest
 
-	return &models.CreateContestResponse{
-		Success: true,
-		Message: "Contest created successfully in mock",
-		TxHash:  txHash,
-		ID:      id,
-	}, nil
+		Registration: registration.Policy(req.Registration),
+		Voting:       voting.Policy(req.Voting),
+		SponsorTiers: sponsorship.Tiers(req.SponsorTiers),
+	}, nil, nil
 }
 
 // GetContest giả lập lấy thông tin cuộc thi
@@ -191,7 +390,7 @@ func (m *MockBlockchainService) GetContest(id string) (*models.GetContestRespons
 		return &models.GetContestResponse{
 			Success: false,
 			Message: "Contest not found in mock",
-		}, fmt.Errorf("contest not found")
+		}, apperr.NotFound("contest %s not found", id)
 	}
 
 	return &models.GetContestResponse{
@@ -226,9 +425,48 @@ func (m *MockBlockchainService) SearchContests(keyword string) ([]*models.Contes
 	return results, nil
 }
 
+// TransitionContest giả lập chuyển trạng thái cuộc thi và ghi nhận on-chain
+func (m *MockBlockchainService) TransitionContest(id, to, reason, actor string) (*models.ContestTransitionResponse, error) {
+	contest, exists := m.contests[id]
+	if !exists {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", id)
+	}
+
+	transition, err := lifecycle.Transition(contest, to, reason, actor, time.Now())
+	if err != nil {
+		return &models.ContestTransitionResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	transition.TxHash = m.generateTxHash()
+
+	return &models.ContestTransitionResponse{
+		Success: true,
+		Message: "Contest is now " + to + " in mock",
+		Data:    transition,
+	}, nil
+}
+
 // CreateContestant giả lập tạo thí sinh
 func (m *MockBlockchainService) CreateContestant(req *models.CreateContestantRequest) (*models.CreateContestantResponse, error) {
-	id := m.generateID()
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	id, err := m.newID(ids.EntityContestant, req.Creator, req.Name, req.Nonce)
+	if err != nil {
+		return &models.CreateContestantResponse{
+			Success: false,
+			Message: "Failed to generate contestant ID in mock",
+		}, err
+	}
 	txHash := m.generateTxHash()
 
 	contestant := &models.Contestant{
@@ -258,7 +496,7 @@ func (m *MockBlockchainService) GetContestant(id string) (*models.GetContestantR
 		return &models.GetContestantResponse{
 			Success: false,
 			Message: "Contestant not found in mock",
-		}, fmt.Errorf("contestant not found")
+		}, apperr.NotFound("contestant %s not found", id)
 	}
 
 	return &models.GetContestantResponse{
@@ -283,15 +521,27 @@ func (m *MockBlockchainService) GetAllContestants() (*models.ListContestantsResp
 
 // CreateSponsor giả lập tạo nhà tài trợ
 func (m *MockBlockchainService) CreateSponsor(req *models.CreateSponsorRequest) (*models.CreateSponsorResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.CreateSponsorResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
 	id := m.generateID()
 	txHash := m.generateTxHash()
 
+	wallet := req.WalletAddress
+	if wallet == "" {
+		wallet = "0xMockSponsorAddress"
+	}
+
 	sponsor := &models.Sponsor{
 		ID:                id,
 		Name:              req.Name,
 		ContactInfo:       req.ContactInfo,
 		SponsorshipAmount: req.SponsorshipAmount,
-		WalletAddress:     "0xMockSponsorAddress",
+		WalletAddress:     wallet,
 		TxHash:            txHash,
 		Timestamp:         time.Now(),
 	}
@@ -313,7 +563,7 @@ func (m *MockBlockchainService) GetSponsor(id string) (*models.GetSponsorRespons
 		return &models.GetSponsorResponse{
 			Success: false,
 			Message: "Sponsor not found in mock",
-		}, fmt.Errorf("sponsor not found")
+		}, apperr.NotFound("sponsor %s not found", id)
 	}
 
 	return &models.GetSponsorResponse{
@@ -338,34 +588,124 @@ func (m *MockBlockchainService) GetAllSponsors() (*models.ListSponsorsResponse,
 
 // RegisterContestant giả lập đăng ký thí sinh vào cuộc thi
 func (m *MockBlockchainService) RegisterContestant(req *models.RegisterContestantRequest) (*models.RegisterContestantResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
 	// Kiểm tra xem cuộc thi và thí sinh có tồn tại không
-	if _, exists := m.contests[req.ContestID]; !exists {
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
 		return &models.RegisterContestantResponse{
 			Success: false,
 			Message: "Contest not found in mock",
-		}, fmt.Errorf("contest not found")
+		}, apperr.NotFound("contest %s not found", req.ContestID)
 	}
 
-	if _, exists := m.contestants[req.ContestantID]; !exists {
+	contestant, exists := m.contestants[req.ContestantID]
+	if !exists {
 		return &models.RegisterContestantResponse{
 			Success: false,
 			Message: "Contestant not found in mock",
-		}, fmt.Errorf("contestant not found")
+		}, apperr.NotFound("contestant %s not found", req.ContestantID)
 	}
 
-	// Khởi tạo map cho cuộc thi nếu chưa tồn tại
-	if _, exists := m.registrations[req.ContestID]; !exists {
-		m.registrations[req.ContestID] = make(map[string]bool)
+	// Khởi tạo danh sách cho cuộc thi nếu chưa tồn tại
+	roster, exists := m.registrations[req.ContestID]
+	if !exists {
+		roster = &registration.Roster{}
+		m.registrations[req.ContestID] = roster
 	}
 
-	// Đăng ký thí sinh
-	m.registrations[req.ContestID][req.ContestantID] = true
+	// Kiểm tra trạng thái, thời gian đăng ký, điều kiện và số lượng
+	status, err := registration.Decide(contest, roster, contestant, req.ContestantID, time.Now())
+	if err != nil {
+		return &models.RegisterContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	// Đăng ký thí sinh (hoặc đưa vào danh sách chờ)
+	roster.Add(req.ContestantID, status)
 	txHash := m.generateTxHash()
+	m.recordRoster(req.ContestID, req.ContestantID, status)
 
+	message := "Registration successful in mock"
+	if status == models.RegistrationWaitlisted {
+		message = "Contest is full, contestant added to the waitlist in mock"
+	}
 	return &models.RegisterContestantResponse{
 		Success: true,
-		Message: "Registration successful in mock",
+		Message: message,
+		TxHash:  txHash,
+		Data: &models.ContestRegistration{
+			ContestID:    req.ContestID,
+			ContestantID: req.ContestantID,
+			Status:       status,
+			Position:     roster.Position(req.ContestantID),
+			RegisteredAt: time.Now(),
+			TxHash:       txHash,
+		},
+	}, nil
+}
+
+// WithdrawContestant giả lập thí sinh rút lui hoặc bị ban tổ chức loại khỏi cuộc thi
+func (m *MockBlockchainService) WithdrawContestant(req *models.WithdrawContestantRequest) (*models.WithdrawContestantResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+
+	roster, exists := m.registrations[req.ContestID]
+	if !exists {
+		roster = &registration.Roster{}
+	}
+
+	// Người đầu danh sách chờ được chuyển lên nếu còn chỗ
+	promoted, err := registration.Withdraw(contest, roster, req)
+	if err != nil {
+		return &models.WithdrawContestantResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	status := models.RegistrationWithdrawn
+	if req.Removed {
+		status = models.RegistrationRemoved
+	}
+	// Đội rút lui được xoá như trong contract
+	delete(m.teamJsons, req.ContestantID)
+	txHash := m.generateTxHash()
+	m.recordRoster(req.ContestID, req.ContestantID, "")
+
+	return &models.WithdrawContestantResponse{
+		Success: true,
+		Message: "Contestant " + status + " in mock",
 		TxHash:  txHash,
+		Data: &models.ContestWithdrawal{
+			ContestID:    req.ContestID,
+			ContestantID: req.ContestantID,
+			Status:       status,
+			Reason:       req.Reason,
+			Actor:        req.Actor,
+			Promoted:     promoted,
+			At:           time.Now(),
+			TxHash:       txHash,
+		},
 	}, nil
 }
 
@@ -376,33 +716,623 @@ func (m *MockBlockchainService) GetContestantsInContest(contestID string) (*mode
 			Success:   false,
 			Message:   "Contest not found in mock",
 			ContestID: contestID,
-		}, fmt.Errorf("contest not found")
+		}, apperr.NotFound("contest %s not found", contestID)
 	}
 
-	registeredContestants := m.registrations[contestID]
-	contestants := make([]*models.Contestant, 0)
-
-	for id := range registeredContestants {
-		if contestant, exists := m.contestants[id]; exists {
-			contestants = append(contestants, contestant)
-		}
+	roster := m.registrations[contestID]
+	if roster == nil {
+		roster = &registration.Roster{}
 	}
 
 	return &models.ListContestantsInContestResponse{
 		Success:     true,
 		ContestID:   contestID,
-		Contestants: contestants,
-		Total:       len(contestants),
+		Contestants: m.contestantsByID(roster.Registered),
+		Total:       len(roster.Registered),
+		Waitlist:    m.contestantsByID(roster.Waitlist),
 	}, nil
 }
 
+// contestantsByID tra cứu thí sinh theo danh sách ID, giữ nguyên thứ tự
+func (m *MockBlockchainService) contestantsByID(ids []string) []*models.Contestant {
+	contestants := make([]*models.Contestant, 0, len(ids))
+	for _, id := range ids {
+		if contestant, exists := m.contestants[id]; exists {
+			contestants = append(contestants, contestant)
+		}
+	}
+	return contestants
+}
+
+// recordRoster ghi lại một thay đổi danh sách cùng thời điểm, như event trên blockchain
+func (m *MockBlockchainService) recordRoster(contestID, contestantID, status string) {
+	m.rosterHistory[contestID] = append(m.rosterHistory[contestID], rosterChange{
+		contestantID: contestantID,
+		status:       status,
+		sentAt:       time.Now(),
+	})
+}
+
+// GetRosterAt giả lập dựng lại danh sách thí sinh của cuộc thi tại một thời điểm
+func (m *MockBlockchainService) GetRosterAt(contestID string, at time.Time) (*registration.Roster, error) {
+	contest, exists := m.contests[contestID]
+	if !exists {
+		return nil, apperr.NotFound("contest %s not found", contestID)
+	}
+
+	changes := make([]rosterChange, 0)
+	for _, change := range m.rosterHistory[contestID] {
+		if change.sentAt.After(at) {
+			break
+		}
+		changes = append(changes, change)
+	}
+	roster := &registration.Roster{}
+	replay(roster, changes, maxContestants(contest))
+	return roster, nil
+}
+
 // IsContestantRegistered giả lập kiểm tra thí sinh đã đăng ký vào cuộc thi chưa
 func (m *MockBlockchainService) IsContestantRegistered(contestID, contestantID string) (bool, error) {
-	if _, exists := m.registrations[contestID]; !exists {
+	roster, exists := m.registrations[contestID]
+	if !exists {
 		return false, nil
 	}
 
-	return m.registrations[contestID][contestantID], nil
+	return roster.Status(contestantID) == models.RegistrationRegistered, nil
+}
+
+// RegisterTeam giả lập đăng ký một đội vào cuộc thi. Đội chiếm một chỗ trong
+// danh sách như một thí sinh, với ID của đội.
+func (m *MockBlockchainService) RegisterTeam(team *models.Team) (*models.TeamResponse, error) {
+	contest, exists := m.contests[team.ContestID]
+	if !exists {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", team.ContestID)
+	}
+
+	// Mọi thành viên phải đã ký chấp nhận và số thành viên nằm trong giới hạn
+	if err := teams.CheckReady(team, contest.Registration); err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	roster, exists := m.registrations[team.ContestID]
+	if !exists {
+		roster = &registration.Roster{}
+		m.registrations[team.ContestID] = roster
+	}
+
+	// Chữ ký của các thành viên thay cho bước xác minh thí sinh
+	// Đội được xác minh khi mọi thành viên đều đã xác minh
+	entry := &models.Contestant{ID: team.ID}
+	if contest.Registration != nil && contest.Registration.RequireVerified {
+		all, _ := m.GetAllContestants()
+		entry.Verified = teams.Verified(team, all.Data)
+	}
+	status, err := registration.Decide(contest, roster, entry, team.ID, time.Now())
+	if err != nil {
+		return &models.TeamResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	roster.Add(team.ID, status)
+	txHash := m.generateTxHash()
+	m.recordRoster(team.ContestID, team.ID, status)
+
+	registered := *team
+	registered.Status = models.TeamRegistered
+	registered.Entry = status
+	stored := registered
+	m.teamJsons[team.ID] = &stored
+
+	registered.TxHash = txHash
+	return &models.TeamResponse{
+		Success: true,
+		Message: "Team " + status + " in mock",
+		TxHash:  txHash,
+		Data:    &registered,
+	}, nil
+}
+
+// GetContestTeams giả lập đọc lại các đội trong danh sách của cuộc thi, với
+// trạng thái hiện tại của từng đội trong danh sách
+func (m *MockBlockchainService) GetContestTeams(contestID string) (*models.ListTeamsResponse, error) {
+	list := make([]*models.Team, 0)
+	if roster, exists := m.registrations[contestID]; exists {
+		for _, id := range append(append([]string(nil), roster.Registered...), roster.Waitlist...) {
+			if team, exists := m.teamJsons[id]; exists {
+				copied := *team
+				copied.Entry = roster.Status(id)
+				list = append(list, &copied)
+			}
+		}
+	}
+
+	return &models.ListTeamsResponse{
+		Success:   true,
+		ContestID: contestID,
+		Data:      list,
+		Total:     len(list),
+	}, nil
+}
+
+// GetTeam giả lập đọc lại một đội đã đăng ký
+func (m *MockBlockchainService) GetTeam(id string) (*models.TeamResponse, error) {
+	team, exists := m.teamJsons[id]
+	if !exists {
+		return &models.TeamResponse{
+			Success: false,
+			Message: "Team not found in mock",
+		}, apperr.NotFound("team %s not found", id)
+	}
+
+	copied := *team
+	return &models.TeamResponse{
+		Success: true,
+		Data:    &copied,
+	}, nil
+}
+
+// FinalizeResults giả lập ghi kết quả chấm cuối cùng lên blockchain
+func (m *MockBlockchainService) FinalizeResults(results *models.ContestResults) (*models.ContestResultsResponse, error) {
+	contest, exists := m.contests[results.ContestID]
+	if !exists {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", results.ContestID)
+	}
+
+	// Giống contract: chỉ chốt khi đang chấm và chỉ chốt một lần
+	if state := lifecycle.StateOf(contest); !lifecycle.AllowsJudging(state) {
+		err := apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is "+state+", not judging")
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+	if _, exists := m.results[results.ContestID]; exists {
+		err := apperr.New(apperr.KindConflict, models.ErrorCodeResultsFinalized, "results are final")
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	finalized := *results
+	finalized.TxHash = m.generateTxHash()
+	m.results[results.ContestID] = &finalized
+
+	return &models.ContestResultsResponse{
+		Success: true,
+		Message: "Results finalized in mock",
+		TxHash:  finalized.TxHash,
+		Data:    &finalized,
+	}, nil
+}
+
+// GetContestResults giả lập đọc kết quả chấm đã chốt
+func (m *MockBlockchainService) GetContestResults(contestID string) (*models.ContestResultsResponse, error) {
+	results, exists := m.results[contestID]
+	if !exists {
+		return &models.ContestResultsResponse{
+			Success: false,
+			Message: "Results not found in mock",
+		}, apperr.NotFound("contest %s has no final results", contestID)
+	}
+
+	return &models.ContestResultsResponse{
+		Success: true,
+		TxHash:  results.TxHash,
+		Data:    results,
+	}, nil
+}
+
+// CommitVote giả lập ghi hash phiếu bầu lên blockchain
+func (m *MockBlockchainService) CommitVote(req *models.CommitVoteRequest) (*models.VoteResponse, error) {
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+
+	// Đúng giai đoạn commit, chữ ký hợp lệ và mỗi ví chỉ một phiếu
+	vote, err := voting.CheckCommit(contest, findVote(m.votes[req.ContestID], req.Voter), req, time.Now())
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	vote.TxHash = m.generateTxHash()
+	m.votes[req.ContestID] = append(m.votes[req.ContestID], vote)
+
+	committed := *vote
+	return &models.VoteResponse{
+		Success: true,
+		Message: "Vote committed in mock",
+		TxHash:  vote.TxHash,
+		Data:    &committed,
+	}, nil
+}
+
+// RevealVote giả lập công khai phiếu bầu đã commit
+func (m *MockBlockchainService) RevealVote(req *models.RevealVoteRequest) (*models.VoteResponse, error) {
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.VoteResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+
+	// Giống contract: thí sinh và salt phải khớp với hash đã commit
+	committed := findVote(m.votes[req.ContestID], req.Voter)
+	vote, err := voting.CheckReveal(contest, committed, req, time.Now())
+	if err != nil {
+		return &models.VoteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	vote.TxHash = m.generateTxHash()
+	*committed = *vote
+
+	revealed := *vote
+	return &models.VoteResponse{
+		Success: true,
+		Message: "Vote revealed in mock",
+		TxHash:  vote.TxHash,
+		Data:    &revealed,
+	}, nil
+}
+
+// GetVotes giả lập đọc mọi phiếu bầu của cuộc thi
+func (m *MockBlockchainService) GetVotes(contestID string) ([]*models.Vote, error) {
+	if _, exists := m.contests[contestID]; !exists {
+		return nil, apperr.NotFound("contest %s not found", contestID)
+	}
+
+	votes := make([]*models.Vote, 0, len(m.votes[contestID]))
+	for _, vote := range m.votes[contestID] {
+		copied := *vote
+		votes = append(votes, &copied)
+	}
+	return votes, nil
+}
+
+// PledgeSponsor giả lập ghi khoản cam kết của nhà tài trợ cho cuộc thi
+func (m *MockBlockchainService) PledgeSponsor(req *models.PledgeRequest) (*models.PledgeResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+	sponsor, exists := m.sponsors[req.SponsorID]
+	if !exists {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: "Sponsor not found in mock",
+		}, apperr.NotFound("sponsor %s not found", req.SponsorID)
+	}
+
+	pledge, err := sponsorship.NewPledge(contest, sponsor.Name, req, m.generateID(), time.Now())
+	if err != nil {
+		return &models.PledgeResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	pledge.TxHash = m.generateTxHash()
+	m.pledges = append(m.pledges, pledge)
+
+	pledged := *pledge
+	return &models.PledgeResponse{
+		Success: true,
+		Message: "Pledge recorded in mock",
+		TxHash:  pledge.TxHash,
+		Data:    &pledged,
+	}, nil
+}
+
+// GetContestPledges giả lập đọc các khoản cam kết cho cuộc thi
+func (m *MockBlockchainService) GetContestPledges(contestID string) ([]*models.Pledge, error) {
+	if _, exists := m.contests[contestID]; !exists {
+		return nil, apperr.NotFound("contest %s not found", contestID)
+	}
+
+	pledges := make([]*models.Pledge, 0)
+	for _, pledge := range m.pledges {
+		if pledge.ContestID == contestID {
+			copied := *pledge
+			pledges = append(pledges, &copied)
+		}
+	}
+	return pledges, nil
+}
+
+// GetSponsorPledges giả lập đọc các khoản cam kết của nhà tài trợ cho mọi cuộc thi
+func (m *MockBlockchainService) GetSponsorPledges(sponsorID string) ([]*models.Pledge, error) {
+	pledges := make([]*models.Pledge, 0)
+	for _, pledge := range m.pledges {
+		if pledge.SponsorID == sponsorID {
+			copied := *pledge
+			pledges = append(pledges, &copied)
+		}
+	}
+	return pledges, nil
+}
+
+// mockEscrowAddress là địa chỉ giả của contract escrow trong mock
+const mockEscrowAddress = "0x000000000000000000000000000000000000E5C0"
+
+// OpenPrizePool giả lập mở quỹ giải thưởng cho cuộc thi
+func (m *MockBlockchainService) OpenPrizePool(req *models.OpenPrizePoolRequest) (*models.PrizePoolResponse, error) {
+	if err := validation.Struct(req); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+	if err := escrow.CheckOpen(contest, m.prizePools[req.ContestID], req); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	m.prizePools[req.ContestID] = &models.PrizePool{
+		ContestID: req.ContestID,
+		Escrow:    mockEscrowAddress,
+		Token:     req.Token,
+		State:     models.PrizePoolFunding,
+		Total:     "0",
+		Shares:    escrow.Shares(req.SharesBps),
+		Deposits:  []models.PrizeDeposit{},
+	}
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: "Prize pool opened in mock",
+		TxHash:  m.generateTxHash(),
+		Data:    m.copyPrizePool(req.ContestID),
+	}, nil
+}
+
+// GetPrizePool giả lập đọc quỹ giải thưởng cùng các khoản gửi và khoản trả
+func (m *MockBlockchainService) GetPrizePool(contestID string) (*models.PrizePoolResponse, error) {
+	if _, exists := m.prizePools[contestID]; !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Prize pool not found in mock",
+		}, apperr.NotFound("contest %s has no prize pool", contestID)
+	}
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Data:    m.copyPrizePool(contestID),
+	}, nil
+}
+
+// DepositPrize mô phỏng nhà tài trợ gửi tiền từ ví vào escrow. Chỉ có ở mock:
+// ngoài mock, nhà tài trợ tự gửi giao dịch tới contract.
+func (m *MockBlockchainService) DepositPrize(contestID, depositor string, amount *big.Int) error {
+	pool, exists := m.prizePools[contestID]
+	if !exists {
+		return apperr.NotFound("contest %s has no prize pool", contestID)
+	}
+	// Giống contract: chỉ nhận tiền khi quỹ đang mở
+	if pool.State != models.PrizePoolFunding {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeInvalidPoolState, "prize pool is "+pool.State+", not "+models.PrizePoolFunding)
+	}
+	if amount.Sign() <= 0 {
+		return apperr.Validation("amount must be positive")
+	}
+
+	total, _ := new(big.Int).SetString(pool.Total, 10)
+	pool.Total = total.Add(total, amount).String()
+	pool.Deposits = append(pool.Deposits, models.PrizeDeposit{
+		Depositor: depositor,
+		Amount:    amount.String(),
+		TxHash:    m.generateTxHash(),
+	})
+	return nil
+}
+
+// PayoutPrizePool giả lập trả quỹ giải thưởng cho người thắng theo kết quả cuối cùng
+func (m *MockBlockchainService) PayoutPrizePool(req *models.PrizePayoutRequest) (*models.PrizePoolResponse, error) {
+	contest, exists := m.contests[req.ContestID]
+	if !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", req.ContestID)
+	}
+	pool, exists := m.prizePools[req.ContestID]
+	if !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Prize pool not found in mock",
+		}, apperr.NotFound("contest %s has no prize pool", req.ContestID)
+	}
+
+	results := m.results[req.ContestID]
+	if err := escrow.CheckPayout(contest, pool, results); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	// Ví nhận giải: địa chỉ người thắng đã ký, nếu không thì ví đã tạo thí sinh đã xác minh
+	payouts, err := escrow.Plan(pool, results.Results, func(contestantID string) (string, error) {
+		return escrow.PayoutWallet(req.ContestID, m.contestants[contestantID], payoutAddress(req, contestantID))
+	})
+	if err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	txHash := m.generateTxHash()
+	for i := range payouts {
+		payouts[i].TxHash = txHash
+	}
+	pool.Payouts = payouts
+	pool.State = models.PrizePoolPaid
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: fmt.Sprintf("Prize pool paid out to %d winners in mock", len(payouts)),
+		TxHash:  txHash,
+		Data:    m.copyPrizePool(req.ContestID),
+	}, nil
+}
+
+// RefundPrizePool giả lập hủy quỹ của cuộc thi đã đóng hoặc đã hủy và hoàn tiền về đúng ví đã gửi
+func (m *MockBlockchainService) RefundPrizePool(contestID string) (*models.PrizePoolResponse, error) {
+	contest, exists := m.contests[contestID]
+	if !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Contest not found in mock",
+		}, apperr.NotFound("contest %s not found", contestID)
+	}
+	pool, exists := m.prizePools[contestID]
+	if !exists {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: "Prize pool not found in mock",
+		}, apperr.NotFound("contest %s has no prize pool", contestID)
+	}
+	if err := escrow.CheckRefund(contest, pool); err != nil {
+		return &models.PrizePoolResponse{
+			Success: false,
+			Message: err.Error(),
+		}, err
+	}
+
+	txHash := ""
+	if pool.State == models.PrizePoolFunding {
+		pool.State = models.PrizePoolRefunding
+		txHash = m.generateTxHash()
+	}
+	refunds := escrow.Refundable(pool)
+	for range refunds {
+		txHash = m.generateTxHash()
+	}
+	for i := range pool.Deposits {
+		pool.Deposits[i].Refunded = true
+	}
+
+	return &models.PrizePoolResponse{
+		Success: true,
+		Message: fmt.Sprintf("Refunded %d depositors in mock", len(refunds)),
+		TxHash:  txHash,
+		Data:    m.copyPrizePool(contestID),
+	}, nil
+}
+
+// copyPrizePool trả về bản sao của quỹ để test không sửa được dữ liệu trong mock
+func (m *MockBlockchainService) copyPrizePool(contestID string) *models.PrizePool {
+	pool := *m.prizePools[contestID]
+	pool.Shares = append([]models.PrizeShare(nil), pool.Shares...)
+	pool.Deposits = append([]models.PrizeDeposit{}, pool.Deposits...)
+	pool.Payouts = append([]models.PrizePayout(nil), pool.Payouts...)
+	return &pool
+}
+
+// GrantRole giả lập cấp vai trò on-chain
+func (m *MockBlockchainService) GrantRole(role, account string) (string, error) {
+	return m.generateTxHash(), nil
+}
+
+// RevokeRole giả lập thu hồi vai trò on-chain
+func (m *MockBlockchainService) RevokeRole(role, account string) (string, error) {
+	return m.generateTxHash(), nil
+}
+
+// EncodeCreateContest giả lập mã hóa lời gọi tạo cuộc thi qua forwarder
+func (m *MockBlockchainService) EncodeCreateContest(req *models.CreateContestRequest) (string, []byte, error) {
+	contest, _, err := m.newContest(req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data := []byte("createContestJson:" + contest.ID)
+	m.pendingContests[hex.EncodeToString(data)] = contest
+	return contest.ID, data, nil
+}
+
+// ForwarderNonce giả lập nonce của forwarder
+func (m *MockBlockchainService) ForwarderNonce(from string) (uint64, error) {
+	return m.forwarderNonces[strings.ToLower(from)], nil
+}
+
+// Relay giả lập forwarder: kiểm tra nonce rồi thực thi lời gọi đã mã hóa
+func (m *MockBlockchainService) Relay(req *models.ForwardRequest, signature []byte) (string, error) {
+	from := strings.ToLower(req.From)
+	if req.Nonce != m.forwarderNonces[from] {
+		return "", apperr.New(apperr.KindReverted, "", "forwarder: signature does not match request")
+	}
+
+	data := strings.TrimPrefix(req.Data, "0x")
+	contest, exists := m.pendingContests[data]
+	if !exists {
+		return "", apperr.New(apperr.KindReverted, "", "forwarder: call reverted")
+	}
+
+	m.forwarderNonces[from]++
+	delete(m.pendingContests, data)
+	m.contests[contest.ID] = contest
+	return contest.TxHash, nil
+}
+
+// IDTaken giả lập kiểm tra ID đã tồn tại trên contract
+func (m *MockBlockchainService) IDTaken(entity, id string) (bool, error) {
+	switch entity {
+	case ids.EntityContent:
+		_, exists := m.contents[id]
+		return exists, nil
+	case ids.EntityContest:
+		_, exists := m.contests[id]
+		return exists, nil
+	case ids.EntityContestant:
+		_, exists := m.contestants[id]
+		return exists, nil
+	default:
+		return false, ids.ErrInvalidType
+	}
 }
 
 // GetBlockchainStats giả lập lấy thống kê blockchain
@@ -423,3 +1353,13 @@ func (m *MockBlockchainService) GetBlockchainStats() (*models.BlockchainStatsRes
 func (m *MockBlockchainService) HealthCheck() error {
 	return nil
 }
+
+// ReadOnly giả lập chế độ chỉ đọc
+func (m *MockBlockchainService) ReadOnly() bool {
+	return m.readOnly
+}
+
+// WithContext trả về chính mock: mock không gửi giao dịch nên không tốn gas
+func (m *MockBlockchainService) WithContext(ctx context.Context) BlockchainServiceInterface {
+	return m
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"blockchain-demo/internal/ratelimit"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeteredClientChargesSentTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sim := simulated.NewBackend(types.GenesisAlloc{from: {Balance: big.NewInt(params.Ether)}})
+	defer sim.Close()
+	client := &meteredClient{sim.Client()}
+
+	transfer := func(nonce uint64) *types.Transaction {
+		gasPrice, err := client.SuggestGasPrice(context.Background())
+		require.NoError(t, err)
+		tx := types.NewTransaction(nonce, common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(1), params.TxGas, gasPrice, nil)
+		signed, err := types.SignTx(tx, types.LatestSignerForChainID(params.AllDevChainProtocolChanges.ChainID), key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	budget := ratelimit.NewGasBudget(2 * params.TxGas)
+	ctx := ratelimit.WithMeter(context.Background(), ratelimit.NewMeter(budget, "ip:192.0.2.1"))
+
+	require.NoError(t, client.SendTransaction(ctx, transfer(0)))
+	used, _, _ := budget.Usage("ip:192.0.2.1")
+	assert.Equal(t, params.TxGas, used)
+
+	// A transaction the node refuses is given back
+	assert.Error(t, client.SendTransaction(ctx, transfer(0)))
+	used, _, _ = budget.Usage("ip:192.0.2.1")
+	assert.Equal(t, params.TxGas, used)
+
+	require.NoError(t, client.SendTransaction(ctx, transfer(1)))
+
+	// Over the budget nothing is sent
+	assert.ErrorIs(t, client.SendTransaction(ctx, transfer(2)), ratelimit.ErrBudgetExceeded)
+	sim.Commit()
+	nonce, err := client.PendingNonceAt(context.Background(), from)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), nonce)
+
+	// Transactions sent outside a request are not metered
+	require.NoError(t, client.SendTransaction(context.Background(), transfer(2)))
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"blockchain-demo/internal/registration"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// pendingRosterTTL bounds how long a roster change that never shows up
+// on-chain, such as a dropped transaction, is still taken as pending
+const pendingRosterTTL = 10 * time.Minute
+
+// pendingRosters tracks the roster changes the service has sent but that are
+// not mined yet. The server wallet's transactions are mined in nonce order,
+// so replaying them over the mined roster gives the roster the contract will
+// see when the next change is mined.
+type pendingRosters struct {
+	mu       sync.Mutex
+	contests map[string]*pendingRoster
+}
+
+// pendingRoster holds the unmined roster changes of one contest. It is
+// locked while a change is decided and sent, so the changes of a contest are
+// decided one at a time.
+type pendingRoster struct {
+	sync.Mutex
+	changes []rosterChange
+}
+
+// rosterChange is a join with the status the contract gives it, or a
+// withdrawal when status is empty
+type rosterChange struct {
+	contestantID string
+	status       string
+	txHash       common.Hash
+	sentAt       time.Time
+}
+
+func newPendingRosters() *pendingRosters {
+	return &pendingRosters{contests: make(map[string]*pendingRoster)}
+}
+
+// contest returns the pending roster of a contest
+func (p *pendingRosters) contest(contestID string) *pendingRoster {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending, ok := p.contests[contestID]
+	if !ok {
+		pending = &pendingRoster{}
+		p.contests[contestID] = pending
+	}
+	return pending
+}
+
+// sent records a change whose transaction was just sent
+func (p *pendingRoster) sent(contestantID, status string, txHash common.Hash) {
+	p.changes = append(p.changes, rosterChange{contestantID: contestantID, status: status, txHash: txHash, sentAt: time.Now()})
+}
+
+// lockRoster locks the roster of a contest and returns it as it will be once
+// every change already sent is mined. The caller records its own change with
+// sent, then unlocks the pending roster.
+func (bs *BlockchainService) lockRoster(contest *models.Contest) (*registration.Roster, *pendingRoster, error) {
+	pending := bs.rosters.contest(contest.ID)
+	pending.Lock()
+
+	// Receipts are checked before the roster is read: a change mined in
+	// between is then replayed over a roster that has it already, which
+	// leaves the roster unchanged, instead of being lost
+	unmined := pending.changes[:0]
+	for _, change := range pending.changes {
+		if _, err := bs.client.TransactionReceipt(bs.context(), change.txHash); err == nil || time.Since(change.sentAt) > pendingRosterTTL {
+			continue
+		}
+		unmined = append(unmined, change)
+	}
+	pending.changes = unmined
+
+	roster, err := bs.readRoster(contest.ID)
+	if err != nil {
+		pending.Unlock()
+		return nil, nil, err
+	}
+
+	replay(roster, pending.changes, maxContestants(contest))
+	return roster, pending, nil
+}
+
+// replay applies roster changes in order, promoting from the waitlist as the
+// contract does. A join of an entry already on the roster changes nothing.
+func replay(roster *registration.Roster, changes []rosterChange, maxContestants int) {
+	for _, change := range changes {
+		switch {
+		case change.status == "":
+			roster.Remove(change.contestantID, maxContestants)
+		case roster.Status(change.contestantID) == "":
+			roster.Add(change.contestantID, change.status)
+		}
+	}
+}
+
+// maxContestants returns the capacity of a contest, 0 if unlimited
+func maxContestants(contest *models.Contest) int {
+	if contest.Registration == nil {
+		return 0
+	}
+	return contest.Registration.MaxContestants
+}
+
+// rosterEvents are the contract events that change a roster. Promotions are
+// replayed from the withdrawals that cause them.
+var rosterEvents = []string{"ContestantJoined", "TeamRegistered", "ContestantWithdrawn"}
+
+// GetRosterAt rebuilds the roster of a contest as it was at a time from the
+// events of the contract, using the time of the blocks they were mined in
+func (bs *BlockchainService) GetRosterAt(contestID string, at time.Time) (*registration.Roster, error) {
+	contest, err := bs.GetContest(contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !contest.Success || contest.Data == nil {
+		return nil, apperr.NotFound("contest %s not found", contestID)
+	}
+
+	parsedABI, err := LoadContractABI(bs.config.ContractJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract ABI: %v", err)
+	}
+	events := make([]common.Hash, 0, len(rosterEvents))
+	for _, name := range rosterEvents {
+		events = append(events, parsedABI.Events[name].ID)
+	}
+	logs, err := bs.client.FilterLogs(bs.context(), ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(bs.config.ContractAddress)},
+		Topics:    [][]common.Hash{events, {crypto.Keccak256Hash([]byte(contestID))}},
+	})
+	if err != nil {
+		return nil, apperr.FromChain(err)
+	}
+
+	changes := make([]rosterChange, 0, len(logs))
+	blockTimes := make(map[uint64]time.Time)
+	for _, entry := range logs {
+		minedAt, ok := blockTimes[entry.BlockNumber]
+		if !ok {
+			header, err := bs.client.HeaderByNumber(bs.context(), new(big.Int).SetUint64(entry.BlockNumber))
+			if err != nil {
+				return nil, apperr.FromChain(err)
+			}
+			minedAt = time.Unix(int64(header.Time), 0)
+			blockTimes[entry.BlockNumber] = minedAt
+		}
+		// Logs come oldest first
+		if minedAt.After(at) {
+			break
+		}
+
+		change, err := rosterChangeOf(parsedABI, entry)
+		if err != nil {
+			return nil, err
+		}
+		change.sentAt = minedAt
+		changes = append(changes, change)
+	}
+
+	roster := &registration.Roster{}
+	replay(roster, changes, maxContestants(contest.Data))
+	return roster, nil
+}
+
+// rosterChangeOf decodes a roster event
+func rosterChangeOf(parsedABI abi.ABI, entry types.Log) (rosterChange, error) {
+	event, err := parsedABI.EventByID(entry.Topics[0])
+	if err != nil {
+		return rosterChange{}, err
+	}
+	values, err := parsedABI.Unpack(event.Name, entry.Data)
+	if err != nil || len(values) < 2 {
+		return rosterChange{}, fmt.Errorf("malformed %s event in tx %s", event.Name, entry.TxHash.Hex())
+	}
+
+	change := rosterChange{txHash: entry.TxHash}
+	change.contestantID, _ = values[0].(string)
+	var waitlisted bool
+	switch event.Name {
+	case "ContestantWithdrawn":
+		return change, nil
+	case "TeamRegistered":
+		waitlisted, _ = values[2].(bool)
+	default:
+		waitlisted, _ = values[1].(bool)
+	}
+	change.status = models.RegistrationRegistered
+	if waitlisted {
+		change.status = models.RegistrationWaitlisted
+	}
+	return change, nil
+}
@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ClefSigner signs through a remote signer speaking Clef's external API
+// (account_list, account_signTransaction) over JSON-RPC
+type ClefSigner struct {
+	remote  *external.ExternalSigner
+	account accounts.Account
+}
+
+// NewClefSigner connects to a remote signer. address selects the account;
+// when empty the first account exposed by the signer is used.
+func NewClefSigner(endpoint, address string) (*ClefSigner, error) {
+	remote, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer %s: %v", endpoint, err)
+	}
+
+	available := remote.Accounts()
+	if address == "" {
+		if len(available) == 0 {
+			return nil, fmt.Errorf("remote signer %s exposes no accounts", endpoint)
+		}
+		return &ClefSigner{remote: remote, account: available[0]}, nil
+	}
+
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid signer address: %q", address)
+	}
+	account := accounts.Account{Address: common.HexToAddress(address)}
+	if !remote.Contains(account) {
+		return nil, fmt.Errorf("remote signer %s does not manage %s", endpoint, account.Address.Hex())
+	}
+	return &ClefSigner{remote: remote, account: account}, nil
+}
+
+// Address returns the remote account
+func (s *ClefSigner) Address() common.Address {
+	return s.account.Address
+}
+
+// SignTx asks the remote signer to sign a transaction
+func (s *ClefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.remote.SignTx(s.account, tx, chainID)
+}
@@ -0,0 +1,117 @@
+// Package signer signs the server's transactions. Keys can come from an
+// encrypted go-ethereum keystore, a remote Clef-compatible signer, or a raw
+// private key; without any of them the server runs read-only.
+package signer
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/config"
+	"blockchain-demo/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Signer kinds selected with the SIGNER setting
+const (
+	KindAuto     = "auto"
+	KindKey      = "key"
+	KindKeystore = "keystore"
+	KindClef     = "clef"
+	KindReadOnly = "readonly"
+)
+
+// ErrReadOnly is returned for writes when no signer is configured
+var ErrReadOnly = apperr.New(apperr.KindUnavailable, models.ErrorCodeReadOnly, "server is read-only: no signer configured")
+
+// Signer signs transactions sent from a single account
+type Signer interface {
+	// Address is the account transactions are sent from
+	Address() common.Address
+	// SignTx signs a transaction for the given chain
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// ReadOnly is the signer of servers without a key; it refuses to sign
+type ReadOnly struct{}
+
+// Address returns the zero address
+func (ReadOnly) Address() common.Address { return common.Address{} }
+
+// SignTx always fails with ErrReadOnly
+func (ReadOnly) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, ErrReadOnly
+}
+
+// IsReadOnly reports whether a signer cannot sign
+func IsReadOnly(s Signer) bool {
+	_, ok := s.(ReadOnly)
+	return s == nil || ok
+}
+
+// Transactor returns transact options signing with s
+func Transactor(s Signer, chainID *big.Int) (*bind.TransactOpts, error) {
+	if IsReadOnly(s) {
+		return nil, ErrReadOnly
+	}
+	from := s.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(tx, chainID)
+		},
+	}, nil
+}
+
+// FromConfig creates the signer selected by the configuration. In auto mode
+// the keystore wins over Clef, Clef over a raw key, and no key means read-only.
+// READ_ONLY forces read-only and fails if any key material is configured, so
+// public mirrors cannot be started with a key by mistake.
+func FromConfig(cfg *config.Config) (Signer, error) {
+	if cfg.ReadOnly {
+		if cfg.KeystorePath != "" || cfg.ClefURL != "" || (cfg.PrivateKey != "" && cfg.PrivateKey != "your_private_key_here") {
+			return nil, errors.New("READ_ONLY is set but a key is configured: unset PRIVATE_KEY, KEYSTORE_PATH and CLEF_URL")
+		}
+		return ReadOnly{}, nil
+	}
+
+	kind := cfg.Signer
+	if kind == "" || kind == KindAuto {
+		switch {
+		case cfg.KeystorePath != "":
+			kind = KindKeystore
+		case cfg.ClefURL != "":
+			kind = KindClef
+		case cfg.PrivateKey != "" && cfg.PrivateKey != "your_private_key_here":
+			kind = KindKey
+		default:
+			kind = KindReadOnly
+		}
+	}
+
+	switch kind {
+	case KindKeystore:
+		password, err := ReadPassword(cfg.KeystorePasswordFile, fmt.Sprintf("Password for keystore %s: ", cfg.KeystorePath))
+		if err != nil {
+			return nil, err
+		}
+		return NewKeystoreSigner(cfg.KeystorePath, password)
+	case KindClef:
+		return NewClefSigner(cfg.ClefURL, cfg.SignerAddress)
+	case KindKey:
+		log.Printf("⚠️ Signing with PRIVATE_KEY from the environment is deprecated, use an encrypted keystore or Clef")
+		return NewKeySigner(cfg.PrivateKey)
+	case KindReadOnly:
+		return ReadOnly{}, nil
+	default:
+		return nil, fmt.Errorf("unknown signer %q: use keystore, clef, key or readonly", kind)
+	}
+}
@@ -0,0 +1,96 @@
+package signer
+
+import (
+	"blockchain-demo/internal/config"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291"
+
+func writeKeystore(t *testing.T, password string) (string, common.Address) {
+	key, err := crypto.HexToECDSA(testKey)
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	data, err := keystore.EncryptKey(&keystore.Key{Address: address, PrivateKey: key}, password, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path, address
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	path, address := writeKeystore(t, "correct horse")
+
+	s, err := NewKeystoreSigner(path, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, address, s.Address())
+
+	_, err = NewKeystoreSigner(path, "wrong")
+	assert.Error(t, err)
+
+	// Signed transactions recover to the keystore account
+	chainID := big.NewInt(1337)
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), To: &address, Value: big.NewInt(0)})
+	signed, err := s.SignTx(tx, chainID)
+	require.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, address, sender)
+}
+
+func TestFromConfig(t *testing.T) {
+	path, address := writeKeystore(t, "secret")
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("secret\n"), 0600))
+
+	s, err := FromConfig(&config.Config{Signer: KindAuto, KeystorePath: path, KeystorePasswordFile: passwordFile, PrivateKey: "0x" + testKey})
+	require.NoError(t, err)
+	assert.IsType(t, &KeySigner{}, s, "the keystore wins over a raw key")
+	assert.Equal(t, address, s.Address())
+
+	s, err = FromConfig(&config.Config{Signer: KindAuto})
+	require.NoError(t, err)
+	assert.True(t, IsReadOnly(s), "no key means read-only")
+
+	s, err = FromConfig(&config.Config{Signer: KindReadOnly, PrivateKey: testKey})
+	require.NoError(t, err)
+	assert.True(t, IsReadOnly(s), "read-only can be forced")
+
+	_, err = FromConfig(&config.Config{Signer: "hsm"})
+	assert.Error(t, err)
+}
+
+func TestReadOnlyTransactor(t *testing.T) {
+	_, err := Transactor(ReadOnly{}, big.NewInt(1))
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	s, err := NewKeySigner(testKey)
+	require.NoError(t, err)
+	opts, err := Transactor(s, big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, s.Address(), opts.From)
+}
+
+func TestFromConfigReadOnlyRefusesKeys(t *testing.T) {
+	s, err := FromConfig(&config.Config{ReadOnly: true, Signer: KindAuto})
+	require.NoError(t, err)
+	assert.True(t, IsReadOnly(s))
+
+	_, err = FromConfig(&config.Config{ReadOnly: true, PrivateKey: testKey})
+	assert.Error(t, err, "a read-only deployment must never hold a key")
+
+	_, err = FromConfig(&config.Config{ReadOnly: true, ClefURL: "http://localhost:8550"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+// Package sponsorship links sponsors to contests through pledges.
+//
+// A sponsor may pledge to a contest several times; its association with the
+// contest sums the pledges and reaches the tier of the contest's thresholds
+// (DefaultTiers unless the contest sets its own). The most private
+// visibility of a sponsor's pledges applies to the whole association.
+package sponsorship
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/models"
+	"sort"
+	"time"
+)
+
+// DefaultTiers are the thresholds of contests that do not set their own
+var DefaultTiers = models.SponsorTiers{Gold: 10000, Silver: 5000, Bronze: 1000}
+
+// privacy ranks visibilities from the most public
+var privacy = map[string]int{models.VisibilityPublic: 0, models.VisibilityHideAmount: 1, models.VisibilityAnonymous: 2}
+
+// Tiers converts the tier thresholds of a create request. Values must
+// already be checked with CheckTiers.
+func Tiers(req *models.SponsorTiersRequest) *models.SponsorTiers {
+	if req == nil {
+		return nil
+	}
+	return &models.SponsorTiers{Gold: req.Gold, Silver: req.Silver, Bronze: req.Bronze}
+}
+
+// CheckTiers returns an error unless gold needs more than silver, and silver
+// more than bronze
+func CheckTiers(req *models.SponsorTiersRequest) error {
+	if req == nil {
+		return nil
+	}
+	var fields []models.FieldError
+	if req.Gold <= req.Silver {
+		fields = append(fields, models.FieldError{Field: "sponsor_tiers.gold", Rule: "gt", Message: "Gold threshold must be above silver"})
+	}
+	if req.Silver <= req.Bronze {
+		fields = append(fields, models.FieldError{Field: "sponsor_tiers.silver", Rule: "gt", Message: "Silver threshold must be above bronze"})
+	}
+	if len(fields) > 0 {
+		return apperr.Fields(fields)
+	}
+	return nil
+}
+
+// TierOf returns the tier a total pledge reaches in a contest
+func TierOf(contest *models.Contest, amount uint64) string {
+	tiers := DefaultTiers
+	if contest.SponsorTiers != nil {
+		tiers = *contest.SponsorTiers
+	}
+	switch {
+	case amount >= tiers.Gold:
+		return models.TierGold
+	case amount >= tiers.Silver:
+		return models.TierSilver
+	case amount >= tiers.Bronze:
+		return models.TierBronze
+	default:
+		return models.TierSupporter
+	}
+}
+
+// NewPledge checks a pledge to a contest and returns it, ready to anchor.
+// sponsorName may be empty when the sponsor cannot be looked up.
+func NewPledge(contest *models.Contest, sponsorName string, req *models.PledgeRequest, id string, now time.Time) (*models.Pledge, error) {
+	if state := lifecycle.StateOf(contest); lifecycle.Final(state) {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is "+state)
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+	if _, valid := privacy[visibility]; !valid {
+		return nil, apperr.Fields([]models.FieldError{{Field: "visibility", Rule: "oneof", Message: "Visibility must be public, hide_amount or anonymous"}})
+	}
+
+	return &models.Pledge{
+		ID:          id,
+		ContestID:   contest.ID,
+		SponsorID:   req.SponsorID,
+		SponsorName: sponsorName,
+		Amount:      req.Amount,
+		Visibility:  visibility,
+		Message:     req.Message,
+		PledgedBy:   req.PledgedBy,
+		PledgedAt:   now,
+	}, nil
+}
+
+// Sponsors groups the pledges to a contest by sponsor, from the highest
+// total. Nothing is hidden; see Redact.
+func Sponsors(contest *models.Contest, pledges []*models.Pledge) []models.ContestSponsor {
+	bySponsor := make(map[string]*models.ContestSponsor)
+	totals := make(map[string]uint64)
+	order := make([]string, 0)
+	for _, pledge := range pledges {
+		if pledge.ContestID != contest.ID {
+			continue
+		}
+		association, exists := bySponsor[pledge.SponsorID]
+		if !exists {
+			association = &models.ContestSponsor{
+				ContestID:   contest.ID,
+				ContestName: contest.Name,
+				SponsorID:   pledge.SponsorID,
+				Visibility:  models.VisibilityPublic,
+			}
+			bySponsor[pledge.SponsorID] = association
+			order = append(order, pledge.SponsorID)
+		}
+		totals[pledge.SponsorID] += pledge.Amount
+		association.Pledges++
+		if pledge.SponsorName != "" {
+			association.SponsorName = pledge.SponsorName
+		}
+		if privacy[pledge.Visibility] > privacy[association.Visibility] {
+			association.Visibility = pledge.Visibility
+		}
+		if pledge.PledgedAt.After(association.PledgedAt) {
+			association.PledgedAt = pledge.PledgedAt
+		}
+	}
+
+	sponsors := make([]models.ContestSponsor, 0, len(order))
+	for _, sponsorID := range order {
+		association := bySponsor[sponsorID]
+		amount := totals[sponsorID]
+		association.Amount = &amount
+		association.Tier = TierOf(contest, amount)
+		sponsors = append(sponsors, *association)
+	}
+	sort.SliceStable(sponsors, func(i, j int) bool {
+		if *sponsors[i].Amount != *sponsors[j].Amount {
+			return *sponsors[i].Amount > *sponsors[j].Amount
+		}
+		return sponsors[i].PledgedAt.Before(sponsors[j].PledgedAt)
+	})
+	return sponsors
+}
+
+// Summarize totals the pledges to a contest. Sponsors hiding their amount
+// are counted but left out of the total, which would otherwise reveal it.
+func Summarize(contest *models.Contest, pledges []*models.Pledge) *models.SponsorshipSummary {
+	summary := &models.SponsorshipSummary{Tiers: make(map[string]int)}
+	for _, association := range Sponsors(contest, pledges) {
+		if association.Visibility != models.VisibilityHideAmount {
+			summary.Total += *association.Amount
+		}
+		summary.Sponsors++
+		summary.Pledges += association.Pledges
+		summary.Tiers[association.Tier]++
+	}
+	return summary
+}
+
+// Redact hides what the visibility of an association keeps from viewers
+// other than the contest's organizer and the sponsor
+func Redact(association models.ContestSponsor) models.ContestSponsor {
+	switch association.Visibility {
+	case models.VisibilityHideAmount:
+		association.Amount = nil
+	case models.VisibilityAnonymous:
+		association.SponsorID = ""
+		association.SponsorName = ""
+	}
+	return association
+}
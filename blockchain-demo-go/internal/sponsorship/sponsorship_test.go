@@ -0,0 +1,94 @@
+package sponsorship
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiers(t *testing.T) {
+	assert.Nil(t, Tiers(nil))
+	assert.NoError(t, CheckTiers(nil))
+	assert.NoError(t, CheckTiers(&models.SponsorTiersRequest{Gold: 3, Silver: 2, Bronze: 1}))
+
+	err := CheckTiers(&models.SponsorTiersRequest{Gold: 2, Silver: 2, Bronze: 5})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, apperr.StatusOf(err))
+	assert.Len(t, apperr.FieldsOf(err), 2)
+
+	contest := &models.Contest{ID: "c1"}
+	assert.Equal(t, models.TierGold, TierOf(contest, DefaultTiers.Gold))
+	assert.Equal(t, models.TierSilver, TierOf(contest, DefaultTiers.Gold-1))
+	assert.Equal(t, models.TierSupporter, TierOf(contest, DefaultTiers.Bronze-1))
+
+	contest.SponsorTiers = Tiers(&models.SponsorTiersRequest{Gold: 30, Silver: 20, Bronze: 10})
+	assert.Equal(t, models.TierGold, TierOf(contest, 30))
+	assert.Equal(t, models.TierBronze, TierOf(contest, 19))
+}
+
+func TestNewPledge(t *testing.T) {
+	now := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	contest := &models.Contest{ID: "c1", State: models.ContestOpen, Active: true}
+
+	pledge, err := NewPledge(contest, "Acme", &models.PledgeRequest{SponsorID: "s1", Amount: 10}, "p1", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.VisibilityPublic, pledge.Visibility)
+	assert.Equal(t, "Acme", pledge.SponsorName)
+	assert.Equal(t, now, pledge.PledgedAt)
+
+	_, err = NewPledge(contest, "Acme", &models.PledgeRequest{SponsorID: "s1", Amount: 10, Visibility: "secret"}, "p2", now)
+	assert.Equal(t, "visibility", apperr.FieldsOf(err)[0].Field)
+
+	contest.State = models.ContestCancelled
+	_, err = NewPledge(contest, "Acme", &models.PledgeRequest{SponsorID: "s1", Amount: 10}, "p3", now)
+	assert.Equal(t, models.ErrorCodeInvalidContestState, apperr.CodeOf(err))
+}
+
+func TestSponsorsAndSummary(t *testing.T) {
+	start := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	contest := &models.Contest{ID: "c1", Name: "Contest"}
+	pledges := []*models.Pledge{
+		{ContestID: "c1", SponsorID: "s1", SponsorName: "Acme", Amount: 600, Visibility: models.VisibilityPublic, PledgedAt: start},
+		{ContestID: "c1", SponsorID: "s2", Amount: 5000, Visibility: models.VisibilityPublic, PledgedAt: start.Add(time.Minute)},
+		{ContestID: "c1", SponsorID: "s1", Amount: 600, Visibility: models.VisibilityAnonymous, PledgedAt: start.Add(2 * time.Minute)},
+		{ContestID: "c2", SponsorID: "s1", Amount: 99999, Visibility: models.VisibilityPublic, PledgedAt: start},
+	}
+
+	sponsors := Sponsors(contest, pledges)
+	require.Len(t, sponsors, 2)
+	assert.Equal(t, "s2", sponsors[0].SponsorID)
+	assert.Equal(t, models.TierSilver, sponsors[0].Tier)
+
+	// The most private visibility applies to every pledge of a sponsor
+	acme := sponsors[1]
+	assert.Equal(t, uint64(1200), *acme.Amount)
+	assert.Equal(t, models.TierBronze, acme.Tier)
+	assert.Equal(t, models.VisibilityAnonymous, acme.Visibility)
+	assert.Equal(t, 2, acme.Pledges)
+	assert.Equal(t, start.Add(2*time.Minute), acme.PledgedAt)
+
+	redacted := Redact(acme)
+	assert.Empty(t, redacted.SponsorID)
+	assert.Empty(t, redacted.SponsorName)
+	assert.Equal(t, uint64(1200), *redacted.Amount)
+	assert.Equal(t, "s1", acme.SponsorID)
+
+	acme.Visibility = models.VisibilityHideAmount
+	assert.Nil(t, Redact(acme).Amount)
+
+	summary := Summarize(contest, pledges)
+	assert.Equal(t, &models.SponsorshipSummary{Total: 6200, Sponsors: 2, Pledges: 3,
+		Tiers: map[string]int{models.TierSilver: 1, models.TierBronze: 1}}, summary)
+
+	// A hidden amount is not given away by the total
+	pledges = append(pledges, &models.Pledge{ContestID: "c1", SponsorID: "s3", Amount: 300, Visibility: models.VisibilityHideAmount, PledgedAt: start})
+	summary = Summarize(contest, pledges)
+	assert.Equal(t, uint64(6200), summary.Total)
+	assert.Equal(t, 3, summary.Sponsors)
+	assert.Equal(t, 1, summary.Tiers[models.TierSupporter])
+}
@@ -0,0 +1,384 @@
+// Package teams keeps the teams formed for contests until they register.
+//
+// A captain forms a team and invites members by wallet address. Every member,
+// the captain included, accepts by signing the message shown on their
+// invitation with personal_sign. Once everyone has accepted and the team fits
+// the contest's size limits, the captain registers it: the team and the
+// signatures are anchored on-chain as one entry of the contest's roster.
+package teams
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/models"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned by the store
+var (
+	ErrInProgress = apperr.New(apperr.KindConflict, "", "team is being registered")
+)
+
+// AcceptMessage returns the message a member signs to join a team
+func AcceptMessage(team *models.Team, address string) string {
+	return fmt.Sprintf("I accept to join team %q (%s) for contest %s with wallet %s",
+		team.Name, team.ID, team.ContestID, strings.ToLower(address))
+}
+
+// CheckSize returns an error unless a contest accepts teams of size members
+func CheckSize(policy *models.RegistrationPolicy, size int) error {
+	if policy == nil || policy.MaxTeamSize == 0 {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeTeamsNotAllowed, "contest does not accept teams")
+	}
+	if size > policy.MaxTeamSize {
+		return apperr.New(apperr.KindValidation, models.ErrorCodeInvalidTeamSize,
+			fmt.Sprintf("teams have at most %d members", policy.MaxTeamSize))
+	}
+	if size < policy.MinTeamSize {
+		return apperr.New(apperr.KindValidation, models.ErrorCodeInvalidTeamSize,
+			fmt.Sprintf("teams have at least %d members", policy.MinTeamSize))
+	}
+	return nil
+}
+
+// CheckReady returns an error unless a team may be registered for a contest
+// with policy: every member accepted and the team fits the size limits
+func CheckReady(team *models.Team, policy *models.RegistrationPolicy) error {
+	for _, member := range team.Members {
+		if member.Status != models.MemberAccepted {
+			return apperr.New(apperr.KindConflict, models.ErrorCodeTeamNotReady,
+				"member "+member.Address+" has not accepted the invitation")
+		}
+	}
+	return CheckSize(policy, len(team.Members))
+}
+
+// Verified reports whether every member of a team created a verified
+// contestant, which contests requiring verification ask of each member
+func Verified(team *models.Team, contestants []*models.Contestant) bool {
+	for _, member := range team.Members {
+		verified := false
+		for _, contestant := range contestants {
+			if contestant.Verified && strings.EqualFold(contestant.Creator, member.Address) {
+				verified = true
+				break
+			}
+		}
+		if !verified {
+			return false
+		}
+	}
+	return true
+}
+
+// pendingTeamTTL bounds how long a team registered by this server but not yet
+// read back from the chain, such as one whose transaction is not mined, is
+// kept when restoring
+const pendingTeamTTL = 10 * time.Minute
+
+// Store keeps teams in memory. Registered teams are also on-chain and are
+// restored from there, so their members stay on them across restarts.
+type Store struct {
+	mu          sync.RWMutex
+	teams       map[string]*models.Team
+	registering map[string]bool
+}
+
+// NewStore creates an empty team store
+func NewStore() *Store {
+	return &Store{
+		teams:       make(map[string]*models.Team),
+		registering: make(map[string]bool),
+	}
+}
+
+// Create forms a team for a contest. The captain is the first member; the
+// members are deduplicated. Invitations do not hold a wallet: only members
+// who accepted are on a team, and nobody may accept two teams of the same
+// contest.
+func (s *Store) Create(req *models.CreateTeamRequest, policy *models.RegistrationPolicy) (*models.Team, error) {
+	if req.Captain == "" {
+		return nil, apperr.Fields([]models.FieldError{{Field: "captain", Rule: "required", Message: "captain is required"}})
+	}
+
+	addresses := []string{strings.ToLower(req.Captain)}
+	for _, member := range req.Members {
+		if member = strings.ToLower(member); !contains(addresses, member) {
+			addresses = append(addresses, member)
+		}
+	}
+	if err := CheckSize(policy, len(addresses)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, address := range addresses {
+		if other := s.teamOf(req.ContestID, address); other != nil {
+			return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyOnTeam,
+				address+" is already on team "+other.Name)
+		}
+	}
+
+	team := &models.Team{
+		ID:        generateID(),
+		ContestID: req.ContestID,
+		Name:      req.Name,
+		Captain:   addresses[0],
+		Status:    models.TeamForming,
+		CreatedAt: time.Now(),
+	}
+	for _, address := range addresses {
+		team.Members = append(team.Members, models.TeamMember{
+			Address: address,
+			Status:  models.MemberInvited,
+			Message: AcceptMessage(team, address),
+		})
+	}
+	s.teams[team.ID] = team
+	return copyTeam(team), nil
+}
+
+// Accept records a member's signed acceptance
+func (s *Store) Accept(id, address, signature string) (*models.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, exists := s.teams[id]
+	if !exists {
+		return nil, apperr.NotFound("team %s not found", id)
+	}
+	if team.Status != models.TeamForming {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyRegistered, "team is already registered")
+	}
+
+	for i := range team.Members {
+		member := &team.Members[i]
+		if member.Address != strings.ToLower(address) {
+			continue
+		}
+		signer, err := auth.RecoverAddress([]byte(member.Message), signature)
+		if err != nil || !strings.EqualFold(signer.Hex(), member.Address) {
+			return nil, apperr.New(apperr.KindValidation, models.ErrorCodeInvalidSignature,
+				"signature does not match the invitation of "+member.Address)
+		}
+		if other := s.teamOf(team.ContestID, member.Address); other != nil && other.ID != team.ID {
+			return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyOnTeam,
+				member.Address+" is already on team "+other.Name)
+		}
+		now := time.Now()
+		member.Status = models.MemberAccepted
+		member.Signature = signature
+		member.AcceptedAt = &now
+		return copyTeam(team), nil
+	}
+	return nil, apperr.New(apperr.KindForbidden, "", address+" is not invited to team "+team.Name)
+}
+
+// Decline takes an invited member off a forming team. The captain disbands
+// the team instead.
+func (s *Store) Decline(id, address string) (*models.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, err := s.forming(id)
+	if err != nil {
+		return nil, err
+	}
+	address = strings.ToLower(address)
+	if address == team.Captain {
+		return nil, apperr.New(apperr.KindConflict, "", "the captain disbands the team instead")
+	}
+	for i, member := range team.Members {
+		if member.Address == address {
+			team.Members = append(team.Members[:i:i], team.Members[i+1:]...)
+			return copyTeam(team), nil
+		}
+	}
+	return nil, apperr.New(apperr.KindForbidden, "", address+" is not invited to team "+team.Name)
+}
+
+// Disband deletes a forming team. Only its captain disbands it.
+func (s *Store) Disband(id, captain string) (*models.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, err := s.forming(id)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(captain) != team.Captain {
+		return nil, apperr.New(apperr.KindForbidden, "", "only the team captain can disband the team")
+	}
+	delete(s.teams, id)
+	return copyTeam(team), nil
+}
+
+// forming returns a team that is neither registered nor being registered
+func (s *Store) forming(id string) (*models.Team, error) {
+	team, exists := s.teams[id]
+	if !exists {
+		return nil, apperr.NotFound("team %s not found", id)
+	}
+	if team.Status != models.TeamForming {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyRegistered, "team is already registered")
+	}
+	if s.registering[id] {
+		return nil, ErrInProgress
+	}
+	return team, nil
+}
+
+// Get returns a copy of a team
+func (s *Store) Get(id string) (*models.Team, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	team, exists := s.teams[id]
+	if !exists {
+		return nil, false
+	}
+	return copyTeam(team), true
+}
+
+// List returns the teams of a contest, oldest first
+func (s *Store) List(contestID string) []*models.Team {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	teams := make([]*models.Team, 0)
+	for _, team := range s.teams {
+		if team.ContestID == contestID {
+			teams = append(teams, copyTeam(team))
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].CreatedAt.Before(teams[j].CreatedAt) })
+	return teams
+}
+
+// Claim marks a forming team as being registered so it is anchored only once.
+// The caller must call either Registered or Release afterwards.
+func (s *Store) Claim(id string) (*models.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, err := s.forming(id)
+	if err != nil {
+		return nil, err
+	}
+	s.registering[id] = true
+	return copyTeam(team), nil
+}
+
+// Release gives back a claimed team, e.g. when anchoring failed
+func (s *Store) Release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.registering, id)
+}
+
+// Registered marks a claimed team as anchored with the roster entry it got
+func (s *Store) Registered(id, entry, txHash string) (*models.Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team, exists := s.teams[id]
+	if !exists {
+		return nil, apperr.NotFound("team %s not found", id)
+	}
+	delete(s.registering, id)
+
+	now := time.Now()
+	team.Status = models.TeamRegistered
+	team.Entry = entry
+	team.RegisteredAt = &now
+	team.TxHash = txHash
+	return copyTeam(team), nil
+}
+
+// Restore syncs a contest's teams with the ones registered on-chain. Teams
+// the store knows keep their state, apart from the roster entry, which changes
+// when a waitlisted team is promoted. Registered teams no longer on-chain have
+// withdrawn and are dropped, unless their registration may still be pending.
+func (s *Store) Restore(contestID string, teams []*models.Team) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	listed := make(map[string]bool, len(teams))
+	for _, team := range teams {
+		listed[team.ID] = true
+		known, exists := s.teams[team.ID]
+		switch {
+		case !exists:
+			restored := copyTeam(team)
+			restored.Status = models.TeamRegistered
+			s.teams[team.ID] = restored
+		case known.Status == models.TeamRegistered && team.Entry != "":
+			known.Entry = team.Entry
+		}
+	}
+
+	for id, team := range s.teams {
+		if team.ContestID != contestID || team.Status != models.TeamRegistered || listed[id] {
+			continue
+		}
+		if team.RegisteredAt == nil || time.Since(*team.RegisteredAt) > pendingTeamTTL {
+			delete(s.teams, id)
+		}
+	}
+}
+
+// Withdrawn drops a team that left its contest, so its members may join
+// another team
+func (s *Store) Withdrawn(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.teams, id)
+	delete(s.registering, id)
+}
+
+// teamOf returns the team of a contest a wallet accepted to join, nil if none
+func (s *Store) teamOf(contestID, address string) *models.Team {
+	for _, team := range s.teams {
+		if team.ContestID != contestID {
+			continue
+		}
+		for _, member := range team.Members {
+			if member.Address == address && member.Status == models.MemberAccepted {
+				return team
+			}
+		}
+	}
+	return nil
+}
+
+func copyTeam(team *models.Team) *models.Team {
+	copied := *team
+	copied.Members = append([]models.TeamMember(nil), team.Members...)
+	return &copied
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// generateID generates a random team ID
+func generateID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return fmt.Sprintf("team_%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,247 @@
+package teams
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"crypto/ecdsa"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wallet(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key, crypto.PubkeyToAddress(key.PublicKey).Hex()
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+func TestCheckSize(t *testing.T) {
+	assert.Equal(t, models.ErrorCodeTeamsNotAllowed, apperr.CodeOf(CheckSize(nil, 2)))
+	assert.Equal(t, models.ErrorCodeTeamsNotAllowed, apperr.CodeOf(CheckSize(&models.RegistrationPolicy{}, 2)))
+
+	policy := &models.RegistrationPolicy{MinTeamSize: 2, MaxTeamSize: 3}
+	assert.NoError(t, CheckSize(policy, 2))
+	assert.NoError(t, CheckSize(policy, 3))
+	assert.Equal(t, models.ErrorCodeInvalidTeamSize, apperr.CodeOf(CheckSize(policy, 1)))
+	assert.Equal(t, models.ErrorCodeInvalidTeamSize, apperr.CodeOf(CheckSize(policy, 4)))
+}
+
+func TestCreate(t *testing.T) {
+	store := NewStore()
+	policy := &models.RegistrationPolicy{MaxTeamSize: 3}
+	_, captain := wallet(t)
+	_, member := wallet(t)
+
+	team, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Rockets", Captain: captain, Members: []string{member, strings.ToLower(captain)}}, policy)
+	require.NoError(t, err)
+	assert.Equal(t, models.TeamForming, team.Status)
+	require.Len(t, team.Members, 2)
+	assert.Equal(t, strings.ToLower(captain), team.Captain)
+	assert.Equal(t, team.Captain, team.Members[0].Address)
+	assert.Equal(t, AcceptMessage(team, member), team.Members[1].Message)
+	assert.Equal(t, models.MemberInvited, team.Members[1].Status)
+
+	// An invitation alone does not put a wallet on a team
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Comets", Captain: member}, policy)
+	assert.NoError(t, err)
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c2", Name: "Comets", Captain: member}, policy)
+	assert.NoError(t, err)
+
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c3", Name: "Solo", Captain: member}, &models.RegistrationPolicy{})
+	assert.Equal(t, http.StatusConflict, apperr.StatusOf(err))
+
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c3", Name: "Nobody"}, policy)
+	assert.Equal(t, "captain", apperr.FieldsOf(err)[0].Field)
+
+	assert.Len(t, store.List("c1"), 2)
+	assert.Empty(t, store.List("missing"))
+}
+
+func TestAcceptAndRegister(t *testing.T) {
+	store := NewStore()
+	policy := &models.RegistrationPolicy{MinTeamSize: 2, MaxTeamSize: 2}
+	captainKey, captain := wallet(t)
+	memberKey, member := wallet(t)
+	_, stranger := wallet(t)
+
+	team, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Rockets", Captain: captain, Members: []string{member}}, policy)
+	require.NoError(t, err)
+	assert.Equal(t, models.ErrorCodeTeamNotReady, apperr.CodeOf(CheckReady(team, policy)))
+
+	// Signing someone else's invitation does not count
+	_, err = store.Accept(team.ID, member, sign(t, captainKey, team.Members[1].Message))
+	assert.Equal(t, models.ErrorCodeInvalidSignature, apperr.CodeOf(err))
+	_, err = store.Accept(team.ID, member, "0x1234")
+	assert.Equal(t, models.ErrorCodeInvalidSignature, apperr.CodeOf(err))
+	_, err = store.Accept(team.ID, stranger, sign(t, memberKey, team.Members[1].Message))
+	assert.Equal(t, http.StatusForbidden, apperr.StatusOf(err))
+	_, err = store.Accept("missing", member, "0x")
+	assert.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+
+	_, err = store.Accept(team.ID, captain, sign(t, captainKey, team.Members[0].Message))
+	require.NoError(t, err)
+	team, err = store.Accept(team.ID, member, sign(t, memberKey, team.Members[1].Message))
+	require.NoError(t, err)
+	assert.Equal(t, models.MemberAccepted, team.Members[1].Status)
+	assert.NotNil(t, team.Members[1].AcceptedAt)
+	assert.NoError(t, CheckReady(team, policy))
+
+	// Registration is anchored once
+	_, err = store.Claim(team.ID)
+	require.NoError(t, err)
+	_, err = store.Claim(team.ID)
+	assert.Equal(t, ErrInProgress, err)
+	store.Release(team.ID)
+	_, err = store.Claim(team.ID)
+	require.NoError(t, err)
+
+	team, err = store.Registered(team.ID, models.RegistrationRegistered, "0xabc")
+	require.NoError(t, err)
+	assert.Equal(t, models.TeamRegistered, team.Status)
+	assert.Equal(t, "0xabc", team.TxHash)
+	_, err = store.Claim(team.ID)
+	assert.Equal(t, models.ErrorCodeAlreadyRegistered, apperr.CodeOf(err))
+	_, err = store.Accept(team.ID, member, sign(t, memberKey, team.Members[1].Message))
+	assert.Equal(t, http.StatusConflict, apperr.StatusOf(err))
+}
+
+func TestOneTeamPerContest(t *testing.T) {
+	store := NewStore()
+	policy := &models.RegistrationPolicy{MaxTeamSize: 3}
+	captainKey, captain := wallet(t)
+	memberKey, member := wallet(t)
+
+	first, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Rockets", Captain: captain, Members: []string{member}}, policy)
+	require.NoError(t, err)
+	second, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Comets", Captain: member}, policy)
+	require.NoError(t, err)
+
+	_, err = store.Accept(first.ID, member, sign(t, memberKey, first.Members[1].Message))
+	require.NoError(t, err)
+	_, err = store.Accept(second.ID, member, sign(t, memberKey, second.Members[0].Message))
+	assert.Equal(t, models.ErrorCodeAlreadyOnTeam, apperr.CodeOf(err))
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Meteors", Captain: member}, policy)
+	assert.Equal(t, models.ErrorCodeAlreadyOnTeam, apperr.CodeOf(err))
+
+	// Declining frees nobody but the member; the captain disbands instead
+	_, err = store.Decline(first.ID, captain)
+	assert.Equal(t, http.StatusConflict, apperr.StatusOf(err))
+	_, stranger := wallet(t)
+	_, err = store.Decline(first.ID, stranger)
+	assert.Equal(t, http.StatusForbidden, apperr.StatusOf(err))
+	team, err := store.Decline(first.ID, member)
+	require.NoError(t, err)
+	require.Len(t, team.Members, 1)
+	_, err = store.Accept(second.ID, member, sign(t, memberKey, second.Members[0].Message))
+	require.NoError(t, err)
+
+	// Only the captain disbands a team, and only before it registers
+	_, err = store.Disband(first.ID, member)
+	assert.Equal(t, http.StatusForbidden, apperr.StatusOf(err))
+	_, err = store.Accept(first.ID, captain, sign(t, captainKey, first.Members[0].Message))
+	require.NoError(t, err)
+	_, err = store.Claim(first.ID)
+	require.NoError(t, err)
+	_, err = store.Disband(first.ID, captain)
+	assert.Equal(t, ErrInProgress, err)
+	store.Release(first.ID)
+	_, err = store.Disband(first.ID, captain)
+	require.NoError(t, err)
+	_, exists := store.Get(first.ID)
+	assert.False(t, exists)
+	assert.Len(t, store.List("c1"), 1)
+}
+
+func TestVerified(t *testing.T) {
+	team := &models.Team{Members: []models.TeamMember{{Address: "0xaaaa"}, {Address: "0xbbbb"}}}
+	contestants := []*models.Contestant{
+		{ID: "a", Creator: "0xAAAA", Verified: true},
+		{ID: "b", Creator: "0xbbbb", Verified: false},
+	}
+	assert.False(t, Verified(team, contestants))
+
+	contestants[1].Verified = true
+	assert.True(t, Verified(team, contestants))
+	assert.False(t, Verified(team, nil))
+}
+
+func TestRestore(t *testing.T) {
+	store := NewStore()
+	policy := &models.RegistrationPolicy{MaxTeamSize: 3}
+	_, member := wallet(t)
+	member = strings.ToLower(member)
+
+	// A team registered before a restart still holds its members
+	registered := &models.Team{
+		ID:        "t1",
+		ContestID: "c1",
+		Name:      "Rockets",
+		Status:    models.TeamRegistered,
+		Entry:     models.RegistrationWaitlisted,
+		Members:   []models.TeamMember{{Address: member, Status: models.MemberAccepted}},
+	}
+	store.Restore("c1", []*models.Team{registered})
+	_, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Comets", Captain: member}, policy)
+	assert.Equal(t, models.ErrorCodeAlreadyOnTeam, apperr.CodeOf(err))
+	_, err = store.Claim("t1")
+	assert.Equal(t, models.ErrorCodeAlreadyRegistered, apperr.CodeOf(err))
+
+	// Restoring again picks up a promotion and nothing else
+	promoted := *registered
+	promoted.Name = "Renamed"
+	promoted.Entry = models.RegistrationRegistered
+	store.Restore("c1", []*models.Team{&promoted})
+	team, exists := store.Get("t1")
+	require.True(t, exists)
+	assert.Equal(t, "Rockets", team.Name)
+	assert.Equal(t, models.RegistrationRegistered, team.Entry)
+
+	// A team gone from the chain withdrew and frees its members
+	store.Restore("c1", nil)
+	_, exists = store.Get("t1")
+	assert.False(t, exists)
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Comets", Captain: member}, policy)
+	assert.NoError(t, err)
+}
+
+func TestRestoreKeepsPendingRegistrations(t *testing.T) {
+	store := NewStore()
+	policy := &models.RegistrationPolicy{MaxTeamSize: 3}
+	captainKey, captain := wallet(t)
+
+	team, err := store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Rockets", Captain: captain}, policy)
+	require.NoError(t, err)
+	_, err = store.Accept(team.ID, captain, sign(t, captainKey, team.Members[0].Message))
+	require.NoError(t, err)
+	_, err = store.Claim(team.ID)
+	require.NoError(t, err)
+	_, err = store.Registered(team.ID, models.RegistrationRegistered, "0xabc")
+	require.NoError(t, err)
+
+	// The registration is not mined yet, so the chain does not list the team
+	store.Restore("c1", nil)
+	_, exists := store.Get(team.ID)
+	assert.True(t, exists)
+
+	// Withdrawing drops it at once
+	store.Withdrawn(team.ID)
+	_, exists = store.Get(team.ID)
+	assert.False(t, exists)
+	_, err = store.Create(&models.CreateTeamRequest{ContestID: "c1", Name: "Comets", Captain: captain}, policy)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the largest request body accepted by default
+const DefaultMaxBodyBytes = 1 << 20
+
+// Decode reads a JSON request body into v and validates it. Bodies larger
+// than maxBytes, unknown fields and trailing data are rejected.
+func Decode(w http.ResponseWriter, r *http.Request, v interface{}, maxBytes int64) error {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodyBytes
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err, maxBytes)
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		if err != nil {
+			return decodeError(err, maxBytes)
+		}
+		return apperr.New(apperr.KindBadRequest, "", "request body must contain a single JSON object")
+	}
+	return Struct(v)
+}
+
+// decodeError turns a JSON decoding error into a typed error
+func decodeError(err error, maxBytes int64) error {
+	var (
+		tooLarge  *http.MaxBytesError
+		syntax    *json.SyntaxError
+		typeError *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return apperr.New(apperr.KindTooLarge, "", fmt.Sprintf("request body must not be larger than %d bytes", maxBytes))
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperr.Wrap(apperr.KindBadRequest, "request body is not valid JSON", err)
+	case errors.Is(err, io.EOF):
+		return apperr.New(apperr.KindBadRequest, "", "request body is empty")
+	case errors.As(err, &typeError):
+		return apperr.Fields([]models.FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be a %s", typeError.Field, typeError.Type),
+		}})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return apperr.Fields([]models.FieldError{{Field: field, Rule: "unknown", Message: field + " is not a known field"}})
+	}
+	return apperr.Wrap(apperr.KindBadRequest, "invalid request body", err)
+}
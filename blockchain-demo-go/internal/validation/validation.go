@@ -0,0 +1,225 @@
+// Package validation checks request structs against their `validate` tags and
+// decodes request bodies strictly. Every invalid field is reported at once.
+//
+// Rules are comma-separated:
+//
+//	required      the value is not empty (blank strings are empty)
+//	min=N, max=N  length of strings (in characters) and slices, value of numbers
+//	range=A:B     number between A and B inclusive
+//	url           absolute http or https URL
+//	address       0x-prefixed Ethereum address
+//	rfc3339       RFC 3339 timestamp, e.g. 2006-01-02T15:04:05Z
+//	after=Field   RFC 3339 timestamp later than the one in Field
+//	each=rule     applies rule to every element of a slice (max=N, min=N)
+//
+// Rules other than required accept empty values, so optional fields are only
+// checked when set. Nested structs, struct pointers and slices of structs are
+// walked. Messages name the field by its `label` tag, or its JSON name.
+package validation
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Struct validates a struct, or a pointer to one, against its tags. It
+// returns an apperr validation error listing every invalid field, nil if
+// the struct is valid.
+func Struct(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []models.FieldError
+	walk(value, "", &fields)
+	if len(fields) == 0 {
+		return nil
+	}
+	return apperr.Fields(fields)
+}
+
+// walk validates the fields of a struct value, prefixing names with path
+func walk(value reflect.Value, path string, fields *[]models.FieldError) {
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := value.Field(i)
+
+		// Embedded structs share the JSON object of their parent
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			walk(fieldValue, path, fields)
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if tag := field.Tag.Get("validate"); tag != "" {
+			label := field.Tag.Get("label")
+			if label == "" {
+				label = name
+			}
+			for _, rule := range strings.Split(tag, ",") {
+				if message := check(value, fieldValue, rule); message != "" {
+					ruleName, _, _ := strings.Cut(rule, "=")
+					*fields = append(*fields, models.FieldError{Field: name, Rule: ruleName, Message: label + " " + message})
+					break
+				}
+			}
+		}
+
+		nested := fieldValue
+		if nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.Type() != reflect.TypeOf(time.Time{}) {
+			walk(nested, name, fields)
+		}
+		if nested.Kind() == reflect.Slice && nested.Type().Elem().Kind() == reflect.Struct {
+			for j := 0; j < nested.Len(); j++ {
+				walk(nested.Index(j), fmt.Sprintf("%s[%d]", name, j), fields)
+			}
+		}
+	}
+}
+
+// jsonName returns the JSON name of a field, empty if it is not decoded
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// check applies a rule to a field and returns why it fails, empty if it passes
+func check(parent, value reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	if name == "required" {
+		if isEmpty(value) {
+			return "is required"
+		}
+		return ""
+	}
+	if isEmpty(value) {
+		return ""
+	}
+
+	switch name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validation: invalid rule %q", rule))
+		}
+		size, unit := measure(value)
+		if name == "min" && size < limit {
+			return fmt.Sprintf("must be at least %s%s", arg, unit)
+		}
+		if name == "max" && size > limit {
+			return fmt.Sprintf("must be at most %s%s", arg, unit)
+		}
+	case "range":
+		low, high, ok := strings.Cut(arg, ":")
+		lowLimit, lowErr := strconv.ParseFloat(low, 64)
+		highLimit, highErr := strconv.ParseFloat(high, 64)
+		if !ok || lowErr != nil || highErr != nil {
+			panic(fmt.Sprintf("validation: invalid rule %q", rule))
+		}
+		if number, _ := measure(value); number < lowLimit || number > highLimit {
+			return fmt.Sprintf("must be between %s and %s", low, high)
+		}
+	case "url":
+		u, err := url.Parse(value.String())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be an absolute http or https URL"
+		}
+	case "address":
+		if !common.IsHexAddress(value.String()) || !strings.HasPrefix(value.String(), "0x") {
+			return "must be a 0x-prefixed wallet address"
+		}
+	case "rfc3339":
+		if _, err := time.Parse(time.RFC3339, value.String()); err != nil {
+			return "must be an RFC 3339 timestamp, e.g. 2006-01-02T15:04:05Z"
+		}
+	case "after":
+		other, found := parent.Type().FieldByName(arg)
+		if !found {
+			panic(fmt.Sprintf("validation: invalid rule %q", rule))
+		}
+		end, endErr := time.Parse(time.RFC3339, value.String())
+		start, startErr := time.Parse(time.RFC3339, parent.FieldByIndex(other.Index).String())
+		if endErr == nil && startErr == nil && !end.After(start) {
+			return "must be after " + jsonName(other)
+		}
+	case "each":
+		if value.Kind() != reflect.Slice {
+			panic(fmt.Sprintf("validation: rule %q needs a slice", rule))
+		}
+		for i := 0; i < value.Len(); i++ {
+			if message := check(parent, value.Index(i), arg); message != "" {
+				return fmt.Sprintf("[%d] %s", i, message)
+			}
+		}
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+	return ""
+}
+
+// isEmpty reports whether a value is unset
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return value.IsZero()
+}
+
+// measure returns the size a min/max rule compares: length for strings and
+// slices, value for numbers
+func measure(value reflect.Value) (float64, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		return float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), ""
+	}
+	panic(fmt.Sprintf("validation: cannot measure %s", value.Kind()))
+}
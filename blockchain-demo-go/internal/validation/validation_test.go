@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Label string `json:"label" validate:"required,max=5"`
+}
+
+type sample struct {
+	Name    string   `json:"name" validate:"required,min=2,max=10" label:"Name"`
+	Website string   `json:"website,omitempty" validate:"url"`
+	Wallet  string   `json:"wallet,omitempty" validate:"address"`
+	Start   string   `json:"start" validate:"required,rfc3339"`
+	End     string   `json:"end" validate:"required,rfc3339,after=Start"`
+	Score   int      `json:"score" validate:"range=1:10"`
+	Tags    []string `json:"tags,omitempty" validate:"max=2,each=max=3"`
+	Item    *item    `json:"item,omitempty"`
+	Items   []item   `json:"items,omitempty"`
+	Secret  string   `json:"-" validate:"required"`
+}
+
+func valid() sample {
+	return sample{
+		Name:   "Board",
+		Start:  "2025-07-05T00:00:00Z",
+		End:    "2025-08-05T00:00:00Z",
+		Secret: "set by the server",
+	}
+}
+
+func fieldsOf(t *testing.T, v interface{}) map[string]string {
+	t.Helper()
+	err := Struct(v)
+	require.Error(t, err)
+	assert.Equal(t, apperr.KindValidation, apperr.KindOf(err))
+	rules := make(map[string]string)
+	for _, field := range apperr.FieldsOf(err) {
+		rules[field.Field] = field.Rule
+	}
+	return rules
+}
+
+func TestStructValid(t *testing.T) {
+	s := valid()
+	assert.NoError(t, Struct(&s))
+
+	s.Website = "https://example.com/board"
+	s.Wallet = "0x742d35cc6641c7b2b85ce462af7c9bb7a5db8b7a"
+	s.Score = 10
+	s.Tags = []string{"a", "abc"}
+	s.Item = &item{Label: "ok"}
+	assert.NoError(t, Struct(s))
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	s := sample{
+		Name:    "x",
+		Website: "ftp://example.com",
+		Wallet:  "742d35cc6641c7b2b85ce462af7c9bb7a5db8b7a",
+		Start:   "2025-08-05T00:00:00Z",
+		End:     "2025-07-05T00:00:00Z",
+		Score:   11,
+		Tags:    []string{"a", "toolong"},
+		Item:    &item{},
+		Items:   []item{{Label: "ok"}, {Label: "toolong"}},
+	}
+	assert.Equal(t, map[string]string{
+		"name":           "min",
+		"website":        "url",
+		"wallet":         "address",
+		"end":            "after",
+		"score":          "range",
+		"tags":           "each",
+		"item.label":     "required",
+		"items[1].label": "max",
+	}, fieldsOf(t, &s))
+
+	// Fields hidden from JSON are set by the server and never checked
+	_, checked := fieldsOf(t, &s)["Secret"]
+	assert.False(t, checked)
+}
+
+func TestStructMessages(t *testing.T) {
+	s := valid()
+	s.Name = "   "
+	s.Start = "tomorrow"
+	err := Struct(&s)
+	require.Error(t, err)
+
+	fields := apperr.FieldsOf(err)
+	require.Len(t, fields, 2)
+	assert.Equal(t, models.FieldError{Field: "name", Rule: "required", Message: "Name is required"}, fields[0])
+	assert.Equal(t, "start", fields[1].Field)
+	assert.Contains(t, err.Error(), "Name is required")
+}
+
+func decode(body string, limit int64) error {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	var s sample
+	return Decode(httptest.NewRecorder(), r, &s, limit)
+}
+
+func TestDecode(t *testing.T) {
+	body := `{"name":"Board","start":"2025-07-05T00:00:00Z","end":"2025-08-05T00:00:00Z"}`
+	assert.NoError(t, decode(body, 0))
+
+	unknown := decode(`{"name":"Board","admin":true}`, 0)
+	assert.Equal(t, http.StatusBadRequest, apperr.StatusOf(unknown))
+	assert.Equal(t, []models.FieldError{{Field: "admin", Rule: "unknown", Message: "admin is not a known field"}}, apperr.FieldsOf(unknown))
+
+	wrongType := decode(`{"score":"high"}`, 0)
+	assert.Equal(t, "type", apperr.FieldsOf(wrongType)[0].Rule)
+
+	assert.Equal(t, apperr.KindBadRequest, apperr.KindOf(decode(`{"name":`, 0)))
+	assert.Equal(t, apperr.KindBadRequest, apperr.KindOf(decode(``, 0)))
+	assert.Equal(t, apperr.KindBadRequest, apperr.KindOf(decode(`{} {}`, 0)))
+
+	tooLarge := decode(`{"name":"`+strings.Repeat("x", 100)+`"}`, 64)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, apperr.StatusOf(tooLarge))
+	assert.Equal(t, models.ErrorCodePayloadTooLarge, apperr.CodeOf(tooLarge))
+}
@@ -0,0 +1,227 @@
+// Package voting runs commit-reveal audience votes on contests.
+//
+// During the commit phase a wallet submits only the hash of its vote,
+//
+//	keccak256(abi.encodePacked(contestId, contestantId, salt, voter))
+//
+// signed with personal_sign, so nobody can see who it voted for. During the
+// reveal phase it sends the contestant and the 32-byte salt, which must hash
+// to its commitment. Each wallet votes once per contest, and the tally checks
+// every reveal again so anyone holding the list of votes can reproduce it.
+package voting
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/auth"
+	"blockchain-demo/internal/lifecycle"
+	"blockchain-demo/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Policy converts the voting phases of a create request. Dates must already
+// be validated.
+func Policy(req *models.VotingPolicyRequest) *models.VotingPolicy {
+	if req == nil {
+		return nil
+	}
+	policy := &models.VotingPolicy{}
+	if commitEndsAt, err := time.Parse(time.RFC3339, req.CommitEndsAt); err == nil {
+		policy.CommitEndsAt = commitEndsAt
+	}
+	if revealEndsAt, err := time.Parse(time.RFC3339, req.RevealEndsAt); err == nil {
+		policy.RevealEndsAt = revealEndsAt
+	}
+	return policy
+}
+
+// PhaseOf returns the voting phase of a contest at now, empty if the contest
+// is not decided by vote
+func PhaseOf(contest *models.Contest, now time.Time) string {
+	switch {
+	case contest.Voting == nil:
+		return ""
+	case now.Before(contest.Voting.CommitEndsAt):
+		return models.VotingCommit
+	case now.Before(contest.Voting.RevealEndsAt):
+		return models.VotingReveal
+	default:
+		return models.VotingEnded
+	}
+}
+
+// Commitment returns the hash a voter commits to
+func Commitment(contestID, contestantID string, salt common.Hash, voter common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte(contestID), []byte(contestantID), salt.Bytes(), voter.Bytes())
+}
+
+// CommitMessage returns the message a voter signs with its commitment
+func CommitMessage(contestID, commitment string) string {
+	return fmt.Sprintf("I commit vote %s in contest %s", strings.ToLower(commitment), contestID)
+}
+
+// CheckCommit returns the vote a wallet commits to at now, or why it may
+// not. existing is the wallet's earlier vote in the contest, nil if none.
+func CheckCommit(contest *models.Contest, existing *models.Vote, req *models.CommitVoteRequest, now time.Time) (*models.Vote, error) {
+	if err := requirePhase(contest, models.VotingCommit, now); err != nil {
+		return nil, err
+	}
+	if state := lifecycle.StateOf(contest); !lifecycle.Active(state) {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is "+state)
+	}
+	if existing != nil {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyVoted, req.Voter+" already voted in this contest")
+	}
+
+	commitment, ok := parseHash(req.Commitment)
+	if !ok {
+		return nil, apperr.Fields([]models.FieldError{{Field: "commitment", Rule: "hash", Message: "Commitment must be a 32-byte hex string"}})
+	}
+	vote := &models.Vote{
+		ContestID:   contest.ID,
+		Voter:       strings.ToLower(req.Voter),
+		Commitment:  commitment.Hex(),
+		Signature:   req.Signature,
+		CommittedAt: now,
+	}
+	if !signedByVoter(vote) {
+		return nil, apperr.New(apperr.KindValidation, models.ErrorCodeInvalidSignature,
+			"signature does not match the commitment of "+req.Voter)
+	}
+	return vote, nil
+}
+
+// CheckReveal returns the committed vote revealed at now, or why the reveal
+// is refused. committed is the wallet's vote in the contest, nil if none.
+func CheckReveal(contest *models.Contest, committed *models.Vote, req *models.RevealVoteRequest, now time.Time) (*models.Vote, error) {
+	if err := requirePhase(contest, models.VotingReveal, now); err != nil {
+		return nil, err
+	}
+	if state := lifecycle.StateOf(contest); state == models.ContestCancelled {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeInvalidContestState, "contest is "+state)
+	}
+	if committed == nil {
+		return nil, apperr.NotFound("%s has no vote in contest %s", req.Voter, contest.ID)
+	}
+	if committed.ContestantID != "" {
+		return nil, apperr.New(apperr.KindConflict, models.ErrorCodeAlreadyVoted, req.Voter+" already revealed its vote")
+	}
+
+	salt, ok := parseHash(req.Salt)
+	if !ok {
+		return nil, apperr.Fields([]models.FieldError{{Field: "salt", Rule: "hash", Message: "Salt must be a 32-byte hex string"}})
+	}
+	revealed := *committed
+	revealed.ContestantID = req.ContestantID
+	revealed.Salt = salt.Hex()
+	revealed.RevealedAt = &now
+	if !matchesCommitment(&revealed) {
+		return nil, apperr.New(apperr.KindValidation, models.ErrorCodeInvalidReveal,
+			"contestant and salt do not match the commitment of "+req.Voter)
+	}
+	revealed.Verified = true
+	return &revealed, nil
+}
+
+// Verify reports whether a revealed vote was signed by its voter and matches
+// its commitment
+func Verify(vote *models.Vote) bool {
+	return vote.ContestantID != "" && signedByVoter(vote) && matchesCommitment(vote)
+}
+
+// Tally counts the revealed votes of a contest at now. Every reveal is
+// verified again; reveals that fail, or name someone who is not among the
+// registered candidates, are counted as invalid. Contestants with the same
+// number of votes share a rank.
+func Tally(contest *models.Contest, candidates []string, votes []*models.Vote, now time.Time) *models.VoteTally {
+	tally := &models.VoteTally{
+		ContestID: contest.ID,
+		Phase:     PhaseOf(contest, now),
+		Results:   make([]models.VoteCount, 0, len(candidates)),
+		Votes:     make([]*models.Vote, 0, len(votes)),
+	}
+	if contest.Voting != nil {
+		tally.CommitEndsAt = contest.Voting.CommitEndsAt
+		tally.RevealEndsAt = contest.Voting.RevealEndsAt
+	}
+	tally.Final = tally.Phase == models.VotingEnded
+
+	counts := make(map[string]int, len(candidates))
+	for _, id := range candidates {
+		counts[id] = 0
+	}
+	for _, vote := range votes {
+		checked := *vote
+		checked.Verified = false
+		tally.Committed++
+		if checked.ContestantID != "" {
+			tally.Revealed++
+			if _, candidate := counts[checked.ContestantID]; candidate && Verify(&checked) {
+				checked.Verified = true
+				counts[checked.ContestantID]++
+			} else {
+				tally.Invalid++
+			}
+		}
+		tally.Votes = append(tally.Votes, &checked)
+	}
+	sort.Slice(tally.Votes, func(i, j int) bool { return tally.Votes[i].Voter < tally.Votes[j].Voter })
+
+	for id, count := range counts {
+		tally.Results = append(tally.Results, models.VoteCount{ContestantID: id, Votes: count})
+	}
+	sort.Slice(tally.Results, func(i, j int) bool {
+		if tally.Results[i].Votes != tally.Results[j].Votes {
+			return tally.Results[i].Votes > tally.Results[j].Votes
+		}
+		return tally.Results[i].ContestantID < tally.Results[j].ContestantID
+	})
+	for i := range tally.Results {
+		tally.Results[i].Rank = i + 1
+		if i > 0 && tally.Results[i].Votes == tally.Results[i-1].Votes {
+			tally.Results[i].Rank = tally.Results[i-1].Rank
+		}
+	}
+	return tally
+}
+
+// requirePhase returns an error unless a contest's vote is in phase at now
+func requirePhase(contest *models.Contest, phase string, now time.Time) error {
+	current := PhaseOf(contest, now)
+	if current == "" {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeVotingNotEnabled, "contest "+contest.ID+" is not decided by vote")
+	}
+	if current != phase {
+		return apperr.New(apperr.KindConflict, models.ErrorCodeWrongVotingPhase,
+			fmt.Sprintf("voting is in the %s phase, not %s", current, phase))
+	}
+	return nil
+}
+
+func signedByVoter(vote *models.Vote) bool {
+	signer, err := auth.RecoverAddress([]byte(CommitMessage(vote.ContestID, vote.Commitment)), vote.Signature)
+	return err == nil && strings.EqualFold(signer.Hex(), vote.Voter)
+}
+
+func matchesCommitment(vote *models.Vote) bool {
+	salt, ok := parseHash(vote.Salt)
+	if !ok || !common.IsHexAddress(vote.Voter) {
+		return false
+	}
+	return strings.EqualFold(Commitment(vote.ContestID, vote.ContestantID, salt, common.HexToAddress(vote.Voter)).Hex(), vote.Commitment)
+}
+
+// parseHash decodes a 0x-prefixed 32-byte hex string
+func parseHash(s string) (common.Hash, bool) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(b), true
+}
@@ -0,0 +1,173 @@
+package voting
+
+import (
+	"blockchain-demo/internal/apperr"
+	"blockchain-demo/internal/models"
+	"crypto/ecdsa"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	commitEnd = time.Date(2099, 1, 1, 12, 0, 0, 0, time.UTC)
+	revealEnd = commitEnd.Add(time.Hour)
+)
+
+// wallet signs commitments like a browser wallet would
+type wallet struct {
+	key     *ecdsa.PrivateKey
+	address string
+}
+
+func newWallet(t *testing.T) *wallet {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &wallet{key: key, address: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+}
+
+// commit returns the commit request of a vote for contestantID
+func (w *wallet) commit(t *testing.T, contestID, contestantID string, salt common.Hash) *models.CommitVoteRequest {
+	t.Helper()
+	commitment := Commitment(contestID, contestantID, salt, common.HexToAddress(w.address)).Hex()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(CommitMessage(contestID, commitment))), w.key)
+	require.NoError(t, err)
+	sig[crypto.RecoveryIDOffset] += 27
+	return &models.CommitVoteRequest{ContestID: contestID, Voter: w.address, Commitment: commitment, Signature: hexutil.Encode(sig)}
+}
+
+func votingContest() *models.Contest {
+	return &models.Contest{
+		ID:     "c1",
+		State:  models.ContestRunning,
+		Voting: &models.VotingPolicy{CommitEndsAt: commitEnd, RevealEndsAt: revealEnd},
+	}
+}
+
+func TestPolicyAndPhase(t *testing.T) {
+	assert.Nil(t, Policy(nil))
+	policy := Policy(&models.VotingPolicyRequest{CommitEndsAt: "2099-01-01T12:00:00Z", RevealEndsAt: "2099-01-01T13:00:00Z"})
+	assert.Equal(t, &models.VotingPolicy{CommitEndsAt: commitEnd, RevealEndsAt: revealEnd}, policy)
+
+	contest := votingContest()
+	assert.Equal(t, models.VotingCommit, PhaseOf(contest, commitEnd.Add(-time.Second)))
+	assert.Equal(t, models.VotingReveal, PhaseOf(contest, commitEnd))
+	assert.Equal(t, models.VotingEnded, PhaseOf(contest, revealEnd))
+	assert.Equal(t, "", PhaseOf(&models.Contest{ID: "c2"}, commitEnd))
+}
+
+func TestCommitment(t *testing.T) {
+	// keccak256(abi.encodePacked("c1", "alice", bytes32(1), address(0x11..11)))
+	salt := common.BigToHash(common.Big1)
+	voter := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	packed := append([]byte("c1alice"), append(salt.Bytes(), voter.Bytes()...)...)
+	assert.Equal(t, crypto.Keccak256Hash(packed), Commitment("c1", "alice", salt, voter))
+	assert.NotEqual(t, Commitment("c1", "alice", salt, voter), Commitment("c1", "bob", salt, voter))
+}
+
+func TestCommitAndReveal(t *testing.T) {
+	contest := votingContest()
+	alice := newWallet(t)
+	salt := common.HexToHash("0x01")
+	during := commitEnd.Add(-time.Minute)
+
+	req := alice.commit(t, "c1", "x", salt)
+	vote, err := CheckCommit(contest, nil, req, during)
+	require.NoError(t, err)
+	assert.Equal(t, strings.ToLower(alice.address), vote.Voter)
+	assert.False(t, vote.Verified)
+
+	// One vote per wallet, signed by that wallet, during the commit phase
+	_, err = CheckCommit(contest, vote, req, during)
+	assert.Equal(t, models.ErrorCodeAlreadyVoted, apperr.CodeOf(err))
+	forged := *req
+	forged.Voter = newWallet(t).address
+	_, err = CheckCommit(contest, nil, &forged, during)
+	assert.Equal(t, models.ErrorCodeInvalidSignature, apperr.CodeOf(err))
+	_, err = CheckCommit(contest, nil, req, commitEnd)
+	assert.Equal(t, models.ErrorCodeWrongVotingPhase, apperr.CodeOf(err))
+	_, err = CheckCommit(&models.Contest{ID: "c2", State: models.ContestRunning}, nil, req, during)
+	assert.Equal(t, models.ErrorCodeVotingNotEnabled, apperr.CodeOf(err))
+	malformed := *req
+	malformed.Commitment = "0x1234"
+	_, err = CheckCommit(contest, nil, &malformed, during)
+	assert.Equal(t, "commitment", apperr.FieldsOf(err)[0].Field)
+
+	reveal := &models.RevealVoteRequest{ContestID: "c1", Voter: alice.address, ContestantID: "x", Salt: salt.Hex()}
+	_, err = CheckReveal(contest, vote, reveal, during)
+	assert.Equal(t, models.ErrorCodeWrongVotingPhase, apperr.CodeOf(err))
+	_, err = CheckReveal(contest, nil, reveal, commitEnd)
+	assert.Equal(t, http.StatusNotFound, apperr.StatusOf(err))
+	_, err = CheckReveal(contest, vote, &models.RevealVoteRequest{Voter: alice.address, ContestantID: "y", Salt: salt.Hex()}, commitEnd)
+	assert.Equal(t, models.ErrorCodeInvalidReveal, apperr.CodeOf(err))
+
+	revealed, err := CheckReveal(contest, vote, reveal, commitEnd)
+	require.NoError(t, err)
+	assert.True(t, revealed.Verified)
+	assert.Equal(t, "x", revealed.ContestantID)
+	assert.Empty(t, vote.ContestantID)
+	assert.True(t, Verify(revealed))
+
+	_, err = CheckReveal(contest, revealed, reveal, commitEnd)
+	assert.Equal(t, models.ErrorCodeAlreadyVoted, apperr.CodeOf(err))
+}
+
+func TestTally(t *testing.T) {
+	contest := votingContest()
+	during := commitEnd.Add(-time.Minute)
+
+	var votes []*models.Vote
+	cast := func(contestantID string, reveal bool) *models.Vote {
+		salt := common.BytesToHash(crypto.Keccak256([]byte(contestantID), []byte{byte(len(votes))}))
+		vote, err := CheckCommit(contest, nil, newWallet(t).commit(t, "c1", contestantID, salt), during)
+		require.NoError(t, err)
+		if reveal {
+			vote, err = CheckReveal(contest, vote, &models.RevealVoteRequest{Voter: vote.Voter, ContestantID: contestantID, Salt: salt.Hex()}, commitEnd)
+			require.NoError(t, err)
+		}
+		votes = append(votes, vote)
+		return vote
+	}
+	cast("alice", true)
+	cast("bob", true)
+	cast("alice", true)
+	cast("bob", true)
+	cast("alice", false)
+	cast("mallory", true) // Not registered
+	tampered := cast("bob", true)
+	tampered.ContestantID = "alice"
+
+	tally := Tally(contest, []string{"alice", "bob", "carol"}, votes, revealEnd)
+	assert.True(t, tally.Final)
+	assert.Equal(t, models.VotingEnded, tally.Phase)
+	assert.Equal(t, 7, tally.Committed)
+	assert.Equal(t, 6, tally.Revealed)
+	assert.Equal(t, 2, tally.Invalid)
+	assert.Equal(t, []models.VoteCount{
+		{Rank: 1, ContestantID: "alice", Votes: 2},
+		{Rank: 1, ContestantID: "bob", Votes: 2},
+		{Rank: 3, ContestantID: "carol", Votes: 0},
+	}, tally.Results)
+	require.Len(t, tally.Votes, 7)
+
+	verified := 0
+	for _, vote := range tally.Votes {
+		if vote.Verified {
+			verified++
+		}
+	}
+	assert.Equal(t, 4, verified)
+
+	provisional := Tally(contest, nil, nil, commitEnd)
+	assert.False(t, provisional.Final)
+	assert.Empty(t, provisional.Results)
+}
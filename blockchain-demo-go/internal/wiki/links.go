@@ -0,0 +1,216 @@
+package wiki
+
+import (
+	"blockchain-demo/internal/models"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// linkPattern matches [[Article Title]] and [[Article Title|label]]
+var linkPattern = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|[^\[\]]*)?\]\]`)
+
+// ParseLinks extracts the unique link targets of a content body in order of appearance
+func ParseLinks(body string) []string {
+	matches := linkPattern.FindAllStringSubmatch(body, -1)
+	seen := make(map[string]bool, len(matches))
+	links := make([]string, 0, len(matches))
+	for _, m := range matches {
+		target := strings.Join(strings.Fields(m[1]), " ")
+		key := NormalizeTitle(target)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, target)
+	}
+	return links
+}
+
+// NormalizeTitle returns the key used to match a link target against article titles.
+// Matching is case-insensitive, whitespace-insensitive and Unicode NFC normalized so
+// that "Hà Nội" typed with combining marks still resolves.
+func NormalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(title)), " "))
+}
+
+// page is an indexed article
+type page struct {
+	id      string
+	title   string
+	targets []string
+}
+
+// LinkGraph maintains forward links and backlinks between wiki articles
+type LinkGraph struct {
+	mu        sync.RWMutex
+	pages     map[string]*page           // contentID -> page
+	titles    map[string]string          // normalized title -> contentID
+	backlinks map[string]map[string]bool // normalized target -> source contentIDs
+}
+
+// NewLinkGraph creates an empty link graph
+func NewLinkGraph() *LinkGraph {
+	return &LinkGraph{
+		pages:     make(map[string]*page),
+		titles:    make(map[string]string),
+		backlinks: make(map[string]map[string]bool),
+	}
+}
+
+// Index parses the body of a content and (re)indexes its links
+func (g *LinkGraph) Index(id, title, body string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if old, exists := g.pages[id]; exists {
+		for _, target := range old.targets {
+			key := NormalizeTitle(target)
+			delete(g.backlinks[key], id)
+			if len(g.backlinks[key]) == 0 {
+				delete(g.backlinks, key)
+			}
+		}
+		if oldKey := NormalizeTitle(old.title); g.titles[oldKey] == id {
+			delete(g.titles, oldKey)
+		}
+	}
+
+	p := &page{id: id, title: title, targets: ParseLinks(body)}
+	g.pages[id] = p
+	g.titles[NormalizeTitle(title)] = id
+
+	for _, target := range p.targets {
+		key := NormalizeTitle(target)
+		if g.backlinks[key] == nil {
+			g.backlinks[key] = make(map[string]bool)
+		}
+		g.backlinks[key][id] = true
+	}
+}
+
+// Has reports whether a content is indexed
+func (g *LinkGraph) Has(id string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, exists := g.pages[id]
+	return exists
+}
+
+// Links returns the forward links of a content, resolving each target
+func (g *LinkGraph) Links(id string) ([]*models.WikiLink, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	p, exists := g.pages[id]
+	if !exists {
+		return nil, false
+	}
+
+	links := make([]*models.WikiLink, 0, len(p.targets))
+	for _, target := range p.targets {
+		targetID, resolved := g.titles[NormalizeTitle(target)]
+		links = append(links, &models.WikiLink{
+			Target:   target,
+			TargetID: targetID,
+			Broken:   !resolved,
+		})
+	}
+	return links, true
+}
+
+// Backlinks returns the contents linking to the given content
+func (g *LinkGraph) Backlinks(id string) ([]*models.ContentRef, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	p, exists := g.pages[id]
+	if !exists {
+		return nil, false
+	}
+
+	sources := g.backlinks[NormalizeTitle(p.title)]
+	refs := make([]*models.ContentRef, 0, len(sources))
+	for sourceID := range sources {
+		if source, ok := g.pages[sourceID]; ok {
+			refs = append(refs, &models.ContentRef{ID: source.id, Title: source.title})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Title < refs[j].Title })
+	return refs, true
+}
+
+// BrokenLinks returns every link whose target article does not exist
+func (g *LinkGraph) BrokenLinks() []*models.BrokenLink {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	broken := []*models.BrokenLink{}
+	for _, p := range g.pages {
+		for _, target := range p.targets {
+			if _, resolved := g.titles[NormalizeTitle(target)]; resolved {
+				continue
+			}
+			broken = append(broken, &models.BrokenLink{
+				SourceID:    p.id,
+				SourceTitle: p.title,
+				Target:      target,
+			})
+		}
+	}
+	sort.Slice(broken, func(i, j int) bool {
+		if broken[i].SourceTitle != broken[j].SourceTitle {
+			return broken[i].SourceTitle < broken[j].SourceTitle
+		}
+		return broken[i].Target < broken[j].Target
+	})
+	return broken
+}
+
+// Graph exports all articles and links. Targets that do not exist are exported
+// as missing nodes so the canvas can render them differently.
+func (g *LinkGraph) Graph() *models.LinkGraph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nodes := make(map[string]*models.GraphNode, len(g.pages))
+	for _, p := range g.pages {
+		nodes[p.id] = &models.GraphNode{ID: p.id, Title: p.title}
+	}
+
+	edges := []*models.GraphEdge{}
+	for _, p := range g.pages {
+		for _, target := range p.targets {
+			key := NormalizeTitle(target)
+			targetID, resolved := g.titles[key]
+			if !resolved {
+				targetID = "missing:" + key
+				if _, exists := nodes[targetID]; !exists {
+					nodes[targetID] = &models.GraphNode{ID: targetID, Title: target, Missing: true}
+				}
+			}
+			nodes[p.id].OutDegree++
+			nodes[targetID].InDegree++
+			edges = append(edges, &models.GraphEdge{Source: p.id, Target: targetID})
+		}
+	}
+
+	graph := &models.LinkGraph{
+		Nodes: make([]*models.GraphNode, 0, len(nodes)),
+		Edges: edges,
+	}
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ID < graph.Nodes[j].ID })
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Source != graph.Edges[j].Source {
+			return graph.Edges[i].Source < graph.Edges[j].Source
+		}
+		return graph.Edges[i].Target < graph.Edges[j].Target
+	})
+	return graph
+}
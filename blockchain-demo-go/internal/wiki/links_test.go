@@ -0,0 +1,56 @@
+package wiki
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	body := "See [[Hà Nội]] and [[Board Games|board games]], again [[hà  nội]] and [[ ]] or [broken]]"
+	assert.Equal(t, []string{"Hà Nội", "Board Games"}, ParseLinks(body))
+	assert.Empty(t, ParseLinks("no links here"))
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	// "ộ" precomposed vs "o" + combining marks
+	assert.Equal(t, NormalizeTitle("Hà Nội"), NormalizeTitle("  hà  nội "))
+}
+
+func TestLinkGraph(t *testing.T) {
+	g := NewLinkGraph()
+	g.Index("a", "Alpha", "Links to [[Beta]] and [[Gamma]]")
+	g.Index("b", "Beta", "Back to [[alpha]]")
+
+	links, ok := g.Links("a")
+	assert.True(t, ok)
+	assert.Len(t, links, 2)
+	assert.Equal(t, "b", links[0].TargetID)
+	assert.False(t, links[0].Broken)
+	assert.True(t, links[1].Broken)
+
+	backlinks, ok := g.Backlinks("a")
+	assert.True(t, ok)
+	assert.Len(t, backlinks, 1)
+	assert.Equal(t, "b", backlinks[0].ID)
+
+	broken := g.BrokenLinks()
+	assert.Len(t, broken, 1)
+	assert.Equal(t, "Gamma", broken[0].Target)
+
+	// Creating the missing article resolves the broken link
+	g.Index("c", "Gamma", "")
+	assert.Empty(t, g.BrokenLinks())
+
+	graph := g.Graph()
+	assert.Len(t, graph.Nodes, 3)
+	assert.Len(t, graph.Edges, 3)
+
+	// Re-indexing replaces old links
+	g.Index("b", "Beta", "nothing")
+	backlinks, _ = g.Backlinks("a")
+	assert.Empty(t, backlinks)
+
+	_, ok = g.Links("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,148 @@
+package wiki
+
+import (
+	"blockchain-demo/internal/models"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// RevisionStore keeps the body of every revision of every article. Revisions
+// are written to an embedded LevelDB database and loaded back on start, so the
+// history, the link graph and the taxonomy survive restarts.
+type RevisionStore struct {
+	mu        sync.RWMutex
+	db        *leveldb.DB
+	revisions map[string][]*models.Revision // contentID -> revisions, oldest first
+}
+
+// NewRevisionStore creates an empty in-memory revision store
+func NewRevisionStore() *RevisionStore {
+	s, _ := OpenRevisionStore("")
+	return s
+}
+
+// OpenRevisionStore opens the revisions stored at path, or an in-memory store
+// if path is empty
+func OpenRevisionStore(path string) (*RevisionStore, error) {
+	var db *leveldb.DB
+	var err error
+	if path == "" {
+		db, err = leveldb.Open(storage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open revision store: %v", err)
+	}
+
+	s := &RevisionStore{db: db, revisions: make(map[string][]*models.Revision)}
+	// Keys sort by article, then by zero-padded revision number
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		var rev models.Revision
+		if err := json.Unmarshal(iter.Value(), &rev); err != nil {
+			return nil, fmt.Errorf("corrupt revision %q: %v", iter.Key(), err)
+		}
+		s.revisions[rev.ContentID] = append(s.revisions[rev.ContentID], &rev)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to load revisions: %v", err)
+	}
+	return s, nil
+}
+
+// Close closes the database
+func (s *RevisionStore) Close() error {
+	return s.db.Close()
+}
+
+// Add appends a revision to an article and returns its revision number. The
+// revision is kept in memory even if it could not be written to disk.
+func (s *RevisionStore) Add(rev *models.Revision) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *rev
+	stored.Number = len(s.revisions[rev.ContentID]) + 1
+	s.revisions[rev.ContentID] = append(s.revisions[rev.ContentID], &stored)
+
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return stored.Number, err
+	}
+	return stored.Number, s.db.Put(revisionKey(stored.ContentID, stored.Number), data, nil)
+}
+
+// Articles returns the latest revision of every article, by content ID
+func (s *RevisionStore) Articles() []*models.Revision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	latest := make([]*models.Revision, 0, len(s.revisions))
+	for _, revs := range s.revisions {
+		latest = append(latest, revs[len(revs)-1])
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].ContentID < latest[j].ContentID })
+	return latest
+}
+
+// Has reports whether an article has at least one revision
+func (s *RevisionStore) Has(contentID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.revisions[contentID]) > 0
+}
+
+// Latest returns the number of the latest revision of an article, 0 if none
+func (s *RevisionStore) Latest(contentID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.revisions[contentID])
+}
+
+// Get returns a revision by number (1-based)
+func (s *RevisionStore) Get(contentID string, number int) (*models.Revision, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revs := s.revisions[contentID]
+	if number < 1 || number > len(revs) {
+		return nil, false
+	}
+	return revs[number-1], true
+}
+
+// List returns references to all revisions of an article, oldest first
+func (s *RevisionStore) List(contentID string) []*models.RevisionRef {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	refs := make([]*models.RevisionRef, 0, len(s.revisions[contentID]))
+	for _, rev := range s.revisions[contentID] {
+		refs = append(refs, RefOf(rev))
+	}
+	return refs
+}
+
+// RefOf returns the reference of a revision
+func RefOf(rev *models.Revision) *models.RevisionRef {
+	return &models.RevisionRef{
+		Number:     rev.Number,
+		Title:      rev.Title,
+		Creator:    rev.Creator,
+		TxHash:     rev.TxHash,
+		RecordHash: rev.RecordHash,
+		Timestamp:  rev.Timestamp,
+	}
+}
+
+// revisionKey is the database key of a revision
+func revisionKey(contentID string, number int) []byte {
+	return []byte(fmt.Sprintf("%s\x00%010d", contentID, number))
+}
@@ -0,0 +1,44 @@
+package wiki
+
+import (
+	"blockchain-demo/internal/models"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionStoreSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wiki")
+	s, err := OpenRevisionStore(path)
+	require.NoError(t, err)
+
+	for i := 0; i < 11; i++ {
+		n, err := s.Add(&models.Revision{ContentID: "a", Title: "Alpha", Content: "v"})
+		require.NoError(t, err)
+		assert.Equal(t, i+1, n)
+	}
+	_, err = s.Add(&models.Revision{ContentID: "b", Title: "Beta", Content: "[[Alpha]]", Categories: []string{"Games"}})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = OpenRevisionStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	// Revision 10 sorts after 9, not after 1
+	assert.Equal(t, 11, s.Latest("a"))
+	rev, ok := s.Get("a", 10)
+	require.True(t, ok)
+	assert.Equal(t, 10, rev.Number)
+
+	articles := s.Articles()
+	require.Len(t, articles, 2)
+	assert.Equal(t, 11, articles[0].Number)
+	assert.Equal(t, []string{"Games"}, articles[1].Categories)
+
+	n, err := s.Add(&models.Revision{ContentID: "b", Title: "Beta", Content: "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
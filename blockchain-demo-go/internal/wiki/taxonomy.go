@@ -0,0 +1,272 @@
+package wiki
+
+import (
+	"blockchain-demo/internal/models"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultNamespace is used when content does not specify a namespace
+const DefaultNamespace = "main"
+
+// CategorySeparator separates levels of a hierarchical category, e.g. "Games/Board Games"
+const CategorySeparator = "/"
+
+// NormalizeNamespace lower-cases a namespace and falls back to DefaultNamespace
+func NormalizeNamespace(namespace string) string {
+	namespace = strings.ToLower(strings.TrimSpace(norm.NFC.String(namespace)))
+	if namespace == "" {
+		return DefaultNamespace
+	}
+	return namespace
+}
+
+// NormalizeTags lower-cases tags, joins inner whitespace with "-" and removes duplicates
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(tag)), "-"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// NormalizeCategory trims every level of a hierarchical category and drops empty levels
+func NormalizeCategory(category string) string {
+	parts := strings.Split(norm.NFC.String(category), CategorySeparator)
+	levels := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			levels = append(levels, part)
+		}
+	}
+	return strings.Join(levels, CategorySeparator)
+}
+
+// NormalizeCategories normalizes categories and removes case-insensitive duplicates
+func NormalizeCategories(categories []string) []string {
+	seen := make(map[string]bool, len(categories))
+	out := make([]string, 0, len(categories))
+	for _, category := range categories {
+		category = NormalizeCategory(category)
+		key := strings.ToLower(category)
+		if category == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, category)
+	}
+	return out
+}
+
+// categoryKey is the case-insensitive lookup key of a normalized category
+func categoryKey(category string) string {
+	return strings.ToLower(NormalizeCategory(category))
+}
+
+// inCategory reports whether category equals parent or is one of its descendants
+func inCategory(category, parent string) bool {
+	category, parent = categoryKey(category), categoryKey(parent)
+	return category == parent || strings.HasPrefix(category, parent+CategorySeparator)
+}
+
+// Filter selects entities by namespace, category (including subcategories) and tag
+type Filter struct {
+	Namespace string
+	Category  string
+	Tag       string
+}
+
+// IsEmpty reports whether the filter has no criteria
+func (f Filter) IsEmpty() bool {
+	return f.Namespace == "" && f.Category == "" && f.Tag == ""
+}
+
+// Match reports whether an entity with the given classification passes the filter
+func (f Filter) Match(namespace string, categories, tags []string) bool {
+	if f.Namespace != "" && NormalizeNamespace(f.Namespace) != NormalizeNamespace(namespace) {
+		return false
+	}
+	if f.Category != "" {
+		found := false
+		for _, category := range categories {
+			if inCategory(category, f.Category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Tag != "" {
+		wanted := NormalizeTags([]string{f.Tag})
+		found := false
+		for _, tag := range tags {
+			if len(wanted) > 0 && tag == wanted[0] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Taxonomy indexes categories and tags of contents and contests
+type Taxonomy struct {
+	mu    sync.RWMutex
+	items map[string]*models.TaxonomyItem // kind:id -> item
+}
+
+// NewTaxonomy creates an empty taxonomy index
+func NewTaxonomy() *Taxonomy {
+	return &Taxonomy{
+		items: make(map[string]*models.TaxonomyItem),
+	}
+}
+
+// Index adds or replaces the classification of an entity
+func (t *Taxonomy) Index(item *models.TaxonomyItem) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	indexed := *item
+	indexed.Categories = NormalizeCategories(item.Categories)
+	indexed.Tags = NormalizeTags(item.Tags)
+	t.items[item.Kind+":"+item.ID] = &indexed
+}
+
+// Categories returns the top-level categories in use
+func (t *Taxonomy) Categories() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	seen := make(map[string]string)
+	for _, item := range t.items {
+		for _, category := range item.Categories {
+			root := strings.SplitN(category, CategorySeparator, 2)[0]
+			if _, exists := seen[strings.ToLower(root)]; !exists {
+				seen[strings.ToLower(root)] = root
+			}
+		}
+	}
+
+	roots := make([]string, 0, len(seen))
+	for _, root := range seen {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// Category returns the members of a category and of all its subcategories
+func (t *Taxonomy) Category(name string) (*models.Category, bool) {
+	name = NormalizeCategory(name)
+	if name == "" {
+		return nil, false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	depth := len(strings.Split(name, CategorySeparator))
+	children := make(map[string]string)
+	category := &models.Category{
+		Name:          name,
+		Subcategories: []string{},
+		Members:       []*models.CategoryMember{},
+	}
+	if depth > 1 {
+		category.Parent = name[:strings.LastIndex(name, CategorySeparator)]
+	}
+
+	found := false
+	for _, item := range t.items {
+		for _, itemCategory := range item.Categories {
+			if !inCategory(itemCategory, name) {
+				continue
+			}
+			found = true
+			category.Members = append(category.Members, &models.CategoryMember{
+				Kind:     item.Kind,
+				ID:       item.ID,
+				Title:    item.Title,
+				Category: itemCategory,
+			})
+			levels := strings.Split(itemCategory, CategorySeparator)
+			if len(levels) > depth {
+				child := strings.Join(levels[:depth+1], CategorySeparator)
+				children[strings.ToLower(child)] = child
+			}
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	for _, child := range children {
+		category.Subcategories = append(category.Subcategories, child)
+	}
+	sort.Strings(category.Subcategories)
+	sort.Slice(category.Members, func(i, j int) bool {
+		if category.Members[i].Title != category.Members[j].Title {
+			return category.Members[i].Title < category.Members[j].Title
+		}
+		return category.Members[i].ID < category.Members[j].ID
+	})
+	return category, true
+}
+
+// TagCloud counts tag usage, optionally restricted to one kind of entity.
+// Tags are sorted by count (most used first) and weighted from 1 to 5.
+func (t *Taxonomy) TagCloud(kind string) []*models.TagCount {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, item := range t.items {
+		if kind != "" && item.Kind != kind {
+			continue
+		}
+		for _, tag := range item.Tags {
+			counts[tag]++
+		}
+	}
+
+	cloud := make([]*models.TagCount, 0, len(counts))
+	minCount, maxCount := 0, 0
+	for tag, count := range counts {
+		cloud = append(cloud, &models.TagCount{Tag: tag, Count: count})
+		if minCount == 0 || count < minCount {
+			minCount = count
+		}
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	for _, entry := range cloud {
+		entry.Weight = 1
+		if maxCount > minCount {
+			entry.Weight = 1 + (4*(entry.Count-minCount)+(maxCount-minCount)/2)/(maxCount-minCount)
+		}
+	}
+	sort.Slice(cloud, func(i, j int) bool {
+		if cloud[i].Count != cloud[j].Count {
+			return cloud[i].Count > cloud[j].Count
+		}
+		return cloud[i].Tag < cloud[j].Tag
+	})
+	return cloud
+}
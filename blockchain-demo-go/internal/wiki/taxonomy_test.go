@@ -0,0 +1,52 @@
+package wiki
+
+import (
+	"blockchain-demo/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTaxonomy(t *testing.T) {
+	assert.Equal(t, DefaultNamespace, NormalizeNamespace("  "))
+	assert.Equal(t, "help", NormalizeNamespace("Help"))
+	assert.Equal(t, []string{"board-games", "việt-nam"}, NormalizeTags([]string{"Board  Games", "board-games", "Việt Nam", ""}))
+	assert.Equal(t, []string{"Games/Board Games"}, NormalizeCategories([]string{" Games / Board  Games/ ", "games/board games"}))
+}
+
+func TestTaxonomyCategories(t *testing.T) {
+	tx := NewTaxonomy()
+	tx.Index(&models.TaxonomyItem{Kind: "content", ID: "1", Title: "Catan", Categories: []string{"Games/Board Games/Strategy"}, Tags: []string{"classic"}})
+	tx.Index(&models.TaxonomyItem{Kind: "content", ID: "2", Title: "Chess", Categories: []string{"Games"}, Tags: []string{"classic", "2-player"}})
+	tx.Index(&models.TaxonomyItem{Kind: "contest", ID: "3", Title: "Road To ESSEN", Categories: []string{"Games/Board Games"}, Tags: []string{"Classic"}})
+
+	assert.Equal(t, []string{"Games"}, tx.Categories())
+
+	games, ok := tx.Category("games")
+	assert.True(t, ok)
+	assert.Len(t, games.Members, 3)
+	assert.Equal(t, []string{"Games/Board Games"}, games.Subcategories)
+
+	board, ok := tx.Category("Games/Board Games")
+	assert.True(t, ok)
+	assert.Equal(t, "Games", board.Parent)
+	assert.Len(t, board.Members, 2)
+
+	_, ok = tx.Category("Sports")
+	assert.False(t, ok)
+
+	cloud := tx.TagCloud("")
+	assert.Equal(t, "classic", cloud[0].Tag)
+	assert.Equal(t, 3, cloud[0].Count)
+	assert.Equal(t, 5, cloud[0].Weight)
+	assert.Equal(t, 1, cloud[1].Weight)
+	assert.Len(t, tx.TagCloud("contest"), 1)
+}
+
+func TestFilterMatch(t *testing.T) {
+	f := Filter{Category: "games", Tag: "Board Games"}
+	assert.True(t, f.Match("main", []string{"Games/Party"}, []string{"board-games"}))
+	assert.False(t, f.Match("main", []string{"Gameshows"}, []string{"board-games"}))
+	assert.False(t, Filter{Namespace: "help"}.Match("", nil, nil))
+	assert.True(t, Filter{}.IsEmpty())
+}